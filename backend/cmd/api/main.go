@@ -4,22 +4,27 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
+	_ "time/tzdata" // zonas horarias embebidas: la imagen no necesita tzdata instalado
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/autenticacion"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/database"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/http/handlers"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/http/middleware"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/http/routes"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/mailer"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/storage"
+	"github.com/sistema-ingreso-docente/backend/internal/recognition"
 )
 
 func main() {
-	// Forzar uso de UTC para todas las operaciones de tiempo
-	os.Setenv("TZ", "UTC")
-	time.Local = time.UTC
-
 	// Cargar variables de entorno desde archivo .env
 	if err := godotenv.Load("../.env"); err != nil {
 		log.Println("No se encontró archivo .env, usando variables de entorno del sistema")
@@ -32,6 +37,13 @@ func main() {
 	}
 	log.Printf("Iniciando servidor en modo: %s", env)
 
+	// Reloj en la zona horaria de la institución: define "hoy", retrasos y turnos
+	reloj, err := clock.NewFromEnv()
+	if err != nil {
+		log.Fatal("Error configurando la zona horaria:", err)
+	}
+	log.Printf("Zona horaria de la institución: %s", reloj.Location())
+
 	// Conexión a la base de datos
 	db, err := database.NewConnection()
 	if err != nil {
@@ -44,33 +56,250 @@ func main() {
 	docenteRepo := database.NewDocenteRepository(db)
 	turnoRepo := database.NewTurnoRepository(db)
 	llaveRepo := database.NewLlaveRepository(db)
-	registroRepo := database.NewRegistroRepository(db)
+	registroRepo := database.NewRegistroRepository(db, reloj)
+	intentoRepo := database.NewIntentoReconocimientoRepository(db)
+	consentimientoRepo := database.NewConsentimientoBiometricoRepository(db)
+	evidenciaRepo := database.NewEvidenciaReconocimientoRepository(db)
+	reporteDuplicadosRepo := database.NewReporteDuplicadosRepository(db)
+	calendarioRepo := database.NewCalendarioRepository(db)
+	cierreRepo := database.NewCierrePeriodoRepository(db)
+	justificacionRepo := database.NewJustificacionRepository(db)
+	licenciaRepo := database.NewLicenciaRepository(db)
+	suplenciaRepo := database.NewSuplenciaRepository(db)
+	restablecimientoRepo := database.NewRestablecimientoPasswordRepository(db)
+	dosFactoresRepo := database.NewDosFactoresRepository(db)
+
+	// Motor de reconocimiento facial, compartido por todas las peticiones
+	// dlib solo está disponible al compilar con -tags dlib; sin él se usa el motor fake
+	engineName := getEnv("FACE_ENGINE", recognition.DefaultEngine())
+	if engineName == recognition.EngineFake && env == "production" {
+		log.Fatal("El motor de reconocimiento fake no puede usarse en producción. Compile con -tags dlib")
+	}
+	faceEngine, err := recognition.NewEngine(engineName, getEnv("FACE_MODELS_DIR", "./models"))
+	if err != nil {
+		log.Fatal("Error inicializando reconocimiento facial:", err)
+	}
+	defer faceEngine.Close()
+	if engineName == recognition.EngineFake {
+		log.Println("[WARN] Usando motor de reconocimiento fake: solo reconoce imágenes generadas con cmd/genrostro")
+	}
+	log.Printf("Motor de reconocimiento facial: %s (modelo %s)", engineName, faceEngine.ModelVersion())
+
+	// Marcar para re-enrolamiento las muestras faciales generadas por otro modelo
+	if marcados, err := docenteRepo.FlagOutdatedFaceDescriptors(faceEngine.ModelVersion()); err != nil {
+		log.Printf("[WARN] No se pudieron revisar las versiones de los descriptores faciales: %v", err)
+	} else if marcados > 0 {
+		log.Printf("[WARN] %d descriptores faciales de otro modelo requieren re-enrolamiento (modelo actual: %s)", marcados, faceEngine.ModelVersion())
+	}
+
+	// Índice de reconocimiento: único componente que cifra y descifra descriptores faciales
+	biometricCipher, err := security.NewBiometricCipherFromEnv()
+	if err != nil {
+		log.Fatal("Error cargando la clave biométrica:", err)
+	}
+	faceIndex := recognition.NewIndex(docenteRepo, biometricCipher)
+
+	// Cifrar las muestras guardadas en claro antes de la migración 005
+	// La rotación de claves se hace con cmd/recifrar
+	if cifrados, err := faceIndex.Reencrypt(false); err != nil {
+		log.Printf("[WARN] No se pudieron cifrar los descriptores faciales pendientes: %v", err)
+	} else if cifrados > 0 {
+		log.Printf("[AUDIT] %d descriptores faciales cifrados con la clave %s", cifrados, biometricCipher.ActiveKeyID())
+	}
+
+	// Minutos antes de su inicio en que un turno se asigna automáticamente a un ingreso sin turno_id
+	ventanaDeteccionMin, err := strconv.Atoi(getEnv("TURNO_DETECTION_WINDOW_MINUTES", "30"))
+	if err != nil || ventanaDeteccionMin < 0 {
+		log.Fatal("TURNO_DETECTION_WINDOW_MINUTES debe ser un número de minutos mayor o igual a 0")
+	}
+	ventanaDeteccionTurno := time.Duration(ventanaDeteccionMin) * time.Minute
+
+	// Correo saliente (restablecimiento de contraseña): MAIL_DRIVER=log | smtp
+	correo, mailDriver, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatal("Error configurando el correo:", err)
+	}
+	if mailDriver == mailer.DriverLog {
+		if env == "production" {
+			log.Fatal("MAIL_DRIVER=log escribe los tokens de restablecimiento en el log y no puede usarse en producción. Configure MAIL_DRIVER=smtp")
+		}
+		log.Println("[WARN] Usando mailer de log: los correos no se envían, solo se escriben en el log")
+	}
+	if exigirTLS, _ := mailer.ExigirTLSFromEnv(); mailDriver == mailer.DriverSMTP && !exigirTLS {
+		if env == "production" {
+			log.Fatal("SMTP_REQUIRE_TLS=false enviaría los tokens de restablecimiento sin cifrar y no puede usarse en producción")
+		}
+		log.Println("[WARN] SMTP_REQUIRE_TLS=false: los correos se envían sin cifrar si el servidor no ofrece STARTTLS")
+	}
+	vigenciaRestablecimientoMin, err := strconv.Atoi(getEnv("PASSWORD_RESET_TTL_MINUTES", "30"))
+	if err != nil || vigenciaRestablecimientoMin <= 0 {
+		log.Fatal("PASSWORD_RESET_TTL_MINUTES debe ser un número de minutos mayor a 0")
+	}
+
+	// Secretos TOTP de la autenticación en dos pasos, con clave propia (TOTP_KEY)
+	totpCipher, err := security.NewTOTPCipherFromEnv()
+	if err != nil {
+		log.Fatal("Error cargando la clave de los secretos TOTP:", err)
+	}
+
+	// Autenticación: AUTH_DRIVER=local (solo contraseñas propias) | ldap (además, el directorio
+	// institucional; las cuentas locales existentes siguen usando su contraseña)
+	var directorio autenticacion.Autenticador
+	switch authDriver := getEnv("AUTH_DRIVER", autenticacion.DriverLocal); authDriver {
+	case autenticacion.DriverLocal:
+	case autenticacion.DriverLDAP:
+		configLDAP, err := autenticacion.ConfigLDAPFromEnv()
+		if err != nil {
+			log.Fatal("Error configurando LDAP:", err)
+		}
+		if !configLDAP.Cifrado() || configLDAP.InsecureSkipVerify {
+			if env == "production" {
+				log.Fatal("En producción LDAP requiere ldaps:// o LDAP_START_TLS=true, con verificación del certificado: las contraseñas viajan al directorio")
+			}
+			log.Println("[WARN] La conexión con el directorio no está cifrada o no verifica el certificado")
+		}
+		autenticadorLDAP, err := autenticacion.NewLDAP(configLDAP)
+		if err != nil {
+			log.Fatal("Error configurando LDAP:", err)
+		}
+		directorio = autenticadorLDAP
+		log.Printf("Autenticación contra el directorio %s (base %s, %d grupos mapeados a roles)", configLDAP.URL, configLDAP.BaseDN, len(configLDAP.GruposRol))
+	default:
+		log.Fatalf("AUTH_DRIVER debe ser %s o %s", autenticacion.DriverLocal, autenticacion.DriverLDAP)
+	}
+	// LDAP_PROVISION_USERS=true crea la cuenta de un usuario del directorio en su primer ingreso
+	provisionarCuentas := getEnv("LDAP_PROVISION_USERS", "false") == "true"
 
 	// Inicializar casos de uso
-	authUseCase := usecases.NewAuthUseCase(usuarioRepo)
+	dosFactoresUseCase := usecases.NewDosFactoresUseCase(dosFactoresRepo, usuarioRepo, totpCipher, reloj)
+	authUseCase := usecases.NewAuthUseCase(usuarioRepo, dosFactoresUseCase, directorio, provisionarCuentas)
 	usuarioUseCase := usecases.NewUsuarioUseCase(usuarioRepo)
 	docenteUseCase := usecases.NewDocenteUseCase(docenteRepo)
-	registroUseCase := usecases.NewRegistroUseCase(registroRepo, turnoRepo, llaveRepo)
-	turnoUseCase := usecases.NewTurnoUseCase(turnoRepo)
+	registroUseCase := usecases.NewRegistroUseCase(registroRepo, turnoRepo, llaveRepo, calendarioRepo, cierreRepo, justificacionRepo, licenciaRepo, suplenciaRepo, reloj, ventanaDeteccionTurno)
+	turnoUseCase := usecases.NewTurnoUseCase(turnoRepo, calendarioRepo, reloj)
+	calendarioUseCase := usecases.NewCalendarioUseCase(calendarioRepo, turnoRepo)
+	reporteUseCase := usecases.NewReporteUseCase(registroRepo, turnoRepo, calendarioRepo, justificacionRepo, licenciaRepo, suplenciaRepo, reloj)
+	cierreUseCase := usecases.NewCierrePeriodoUseCase(cierreRepo, reloj)
+	recuperacionPasswordUseCase := usecases.NewRecuperacionPasswordUseCase(usuarioRepo, restablecimientoRepo, correo, reloj,
+		time.Duration(vigenciaRestablecimientoMin)*time.Minute, os.Getenv("PASSWORD_RESET_URL"))
+	licenciaUseCase := usecases.NewLicenciaUseCase(licenciaRepo, docenteRepo, cierreRepo, registroUseCase, reloj)
+	suplenciaUseCase := usecases.NewSuplenciaUseCase(suplenciaRepo, docenteRepo, turnoRepo, llaveRepo, calendarioRepo, licenciaRepo, cierreRepo, registroUseCase, reloj)
 	llaveUseCase := usecases.NewLlaveUseCase(llaveRepo)
+	intentoUseCase := usecases.NewIntentoReconocimientoUseCase(intentoRepo, reloj)
+	deduplicacionUseCase := usecases.NewDeduplicacionUseCase(reporteDuplicadosRepo, faceIndex, faceEngine.ModelVersion())
+	if interrumpidos, err := deduplicacionUseCase.MarcarInterrumpidos(); err != nil {
+		log.Printf("[WARN] No se pudieron revisar los escaneos de duplicados pendientes: %v", err)
+	} else if interrumpidos > 0 {
+		log.Printf("[WARN] %d escaneos de duplicados quedaron interrumpidos por el reinicio", interrumpidos)
+	}
+
+	// Evidencia fotográfica de las identificaciones: EVIDENCE_STORE=none | file | db
+	almacenEvidencia := map[string]entities.AlmacenEvidencia{"none": "", "file": entities.AlmacenArchivo, "db": entities.AlmacenBaseDatos}
+	almacen, ok := almacenEvidencia[getEnv("EVIDENCE_STORE", "none")]
+	if !ok {
+		log.Fatal("EVIDENCE_STORE debe ser none, file o db")
+	}
+	// El directorio se abre también si ya existe, para consultar y depurar evidencias anteriores
+	var archivosEvidencia *storage.FileStore
+	evidenciaDir := getEnv("EVIDENCE_DIR", "./data/evidencias")
+	if _, statErr := os.Stat(evidenciaDir); almacen == entities.AlmacenArchivo || statErr == nil {
+		if archivosEvidencia, err = storage.NewFileStore(evidenciaDir); err != nil {
+			log.Fatal("Error inicializando el almacén de evidencias:", err)
+		}
+	}
+	evidenciaUseCase, err := usecases.NewEvidenciaReconocimientoUseCase(evidenciaRepo, biometricCipher, archivosEvidencia, almacen, reloj)
+	if err != nil {
+		log.Fatal("Error inicializando evidencias:", err)
+	}
+	consentimientoUseCase := usecases.NewConsentimientoBiometricoUseCase(consentimientoRepo, docenteRepo, evidenciaUseCase, reloj)
+
+	// Documentos de respaldo de las justificaciones (PDF o imagen)
+	archivosJustificaciones, err := storage.NewFileStore(getEnv("JUSTIFICACIONES_DIR", "./data/justificaciones"))
+	if err != nil {
+		log.Fatal("Error inicializando el almacén de justificaciones:", err)
+	}
+	justificacionUseCase := usecases.NewJustificacionUseCase(justificacionRepo, registroRepo, docenteRepo, turnoRepo, cierreRepo, registroUseCase, archivosJustificaciones, reloj)
+
+	// Retención biométrica: elimina las muestras faciales de docentes inactivos
+	// BIOMETRIC_RETENTION_DAYS=0 desactiva la depuración automática
+	if periodo := diasDeRetencion("BIOMETRIC_RETENTION_DAYS", "180"); periodo > 0 {
+		go ejecutarDiariamente(func() {
+			depurados, err := consentimientoUseCase.AplicarRetencion(periodo)
+			for _, docente := range depurados {
+				log.Printf("[AUDIT] Retención biométrica: eliminadas las muestras faciales del docente %d (inactivo desde %s)",
+					docente.ID, docente.UpdatedAt.Format("2006-01-02"))
+			}
+			if err != nil {
+				log.Printf("[ERROR] Error aplicando la retención biométrica: %v", err)
+			}
+		})
+	}
+
+	// Retención de evidencias fotográficas (EVIDENCE_RETENTION_DAYS=0 las conserva indefinidamente)
+	if periodo := diasDeRetencion("EVIDENCE_RETENTION_DAYS", "90"); periodo > 0 {
+		go ejecutarDiariamente(func() {
+			eliminadas, err := evidenciaUseCase.AplicarRetencion(periodo)
+			if eliminadas > 0 {
+				log.Printf("[AUDIT] Retención de evidencias: %d evidencias fotográficas eliminadas", eliminadas)
+			}
+			if err != nil {
+				log.Printf("[ERROR] Error aplicando la retención de evidencias: %v", err)
+			}
+		})
+	}
+
+	// Tokens de restablecimiento de contraseña vencidos
+	go ejecutarDiariamente(func() {
+		if eliminados, err := recuperacionPasswordUseCase.DepurarVencidos(); err != nil {
+			log.Printf("[ERROR] Error depurando tokens de restablecimiento vencidos: %v", err)
+		} else if eliminados > 0 {
+			log.Printf("[AUDIT] %d tokens de restablecimiento de contraseña vencidos eliminados", eliminados)
+		}
+	})
 
 	// Inicializar handlers
 	authHandler := handlers.NewAuthHandler(authUseCase)
 	usuarioHandler := handlers.NewUsuarioHandler(usuarioUseCase)
 	docenteHandler := handlers.NewDocenteHandler(docenteUseCase, usuarioUseCase)
-	registroHandler := handlers.NewRegistroHandler(registroUseCase, docenteUseCase, turnoUseCase, db)
+	// REQUIRE_FACE_VERIFICATION=true exige verificación facial 1:1 antes de registrar un ingreso
+	requiereVerificacion := getEnv("REQUIRE_FACE_VERIFICATION", "false") == "true"
+	registroHandler := handlers.NewRegistroHandler(registroUseCase, docenteUseCase, turnoUseCase, intentoUseCase, db, reloj, requiereVerificacion)
 	turnoHandler := handlers.NewTurnoHandler(turnoUseCase)
 	llaveHandler := handlers.NewLlaveHandler(llaveUseCase)
-	reconocimientoHandler := handlers.NewReconocimientoHandler(docenteRepo)
+	reconocimientoHandler := handlers.NewReconocimientoHandler(faceEngine, faceIndex, docenteRepo, intentoUseCase, consentimientoUseCase, evidenciaUseCase, reloj)
+	evidenciaHandler := handlers.NewEvidenciaHandler(evidenciaUseCase)
+	deduplicacionHandler := handlers.NewDeduplicacionHandler(deduplicacionUseCase)
+	consentimientoHandler := handlers.NewConsentimientoHandler(consentimientoUseCase, docenteUseCase)
+	calendarioHandler := handlers.NewCalendarioHandler(calendarioUseCase)
+	reporteHandler := handlers.NewReporteHandler(reporteUseCase, reloj)
+	cierreHandler := handlers.NewCierreHandler(cierreUseCase)
+	justificacionHandler := handlers.NewJustificacionHandler(justificacionUseCase)
+	licenciaHandler := handlers.NewLicenciaHandler(licenciaUseCase, reloj)
+	suplenciaHandler := handlers.NewSuplenciaHandler(suplenciaUseCase)
+	portalDocenteHandler := handlers.NewPortalDocenteHandler(docenteUseCase, registroUseCase, reporteUseCase, justificacionUseCase, reloj)
+	recuperacionPasswordHandler := handlers.NewRecuperacionPasswordHandler(recuperacionPasswordUseCase)
+	dosFactoresHandler := handlers.NewDosFactoresHandler(authUseCase, dosFactoresUseCase, usuarioUseCase)
 
 	handlersGroup := &routes.Handlers{
-		Auth:           authHandler,
-		Usuario:        usuarioHandler,
-		Docente:        docenteHandler,
-		Registro:       registroHandler,
-		Turno:          turnoHandler,
-		Llave:          llaveHandler,
-		Reconocimiento: reconocimientoHandler,
+		Auth:                 authHandler,
+		Usuario:              usuarioHandler,
+		Docente:              docenteHandler,
+		Registro:             registroHandler,
+		Turno:                turnoHandler,
+		Llave:                llaveHandler,
+		Reconocimiento:       reconocimientoHandler,
+		Consentimiento:       consentimientoHandler,
+		Evidencia:            evidenciaHandler,
+		Deduplicacion:        deduplicacionHandler,
+		Calendario:           calendarioHandler,
+		Reporte:              reporteHandler,
+		Cierre:               cierreHandler,
+		Justificacion:        justificacionHandler,
+		Licencia:             licenciaHandler,
+		Suplencia:            suplenciaHandler,
+		PortalDocente:        portalDocenteHandler,
+		RecuperacionPassword: recuperacionPasswordHandler,
+		DosFactores:          dosFactoresHandler,
 	}
 
 	// Configurar router
@@ -102,6 +331,23 @@ func main() {
 	log.Fatal(http.ListenAndServe(":"+port, handler))
 }
 
+// ejecutarDiariamente corre la tarea al iniciar y luego una vez al día
+func ejecutarDiariamente(tarea func()) {
+	for {
+		tarea()
+		time.Sleep(24 * time.Hour)
+	}
+}
+
+// diasDeRetencion lee un periodo de retención en días; 0 desactiva la depuración
+func diasDeRetencion(key, defaultValue string) time.Duration {
+	dias, err := strconv.Atoi(getEnv(key, defaultValue))
+	if err != nil || dias < 0 {
+		log.Fatalf("%s debe ser un número de días mayor o igual a 0", key)
+	}
+	return time.Duration(dias) * 24 * time.Hour
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,94 @@
+// genrostro genera imágenes JPEG de prueba para el motor de reconocimiento fake.
+// Cada imagen lleva embebidos los descriptores de la identidad indicada, por lo que
+// el API compilado sin dlib las identifica igual que a fotos reales.
+//
+// Uso: go run ./cmd/genrostro -semilla docente1 -n 3 -salida ./temp/rostros
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/sistema-ingreso-docente/backend/internal/recognition"
+)
+
+const (
+	anchoImagen = 640
+	altoImagen  = 480
+)
+
+func main() {
+	semilla := flag.String("semilla", "", "identidad de la persona (misma semilla = misma persona)")
+	cantidad := flag.Int("n", 3, "cantidad de fotos a generar")
+	rostros := flag.Int("rostros", 1, "rostros por foto (0 = sin rostro)")
+	lado := flag.Int("lado", 240, "lado en píxeles del rostro principal")
+	salida := flag.String("salida", ".", "directorio de salida")
+	flag.Parse()
+
+	if *semilla == "" {
+		log.Fatal("Debe indicar -semilla")
+	}
+	if *lado <= 0 || *lado > altoImagen {
+		log.Fatalf("-lado debe estar entre 1 y %d", altoImagen)
+	}
+	if err := os.MkdirAll(*salida, 0755); err != nil {
+		log.Fatalf("No se pudo crear el directorio de salida: %v", err)
+	}
+
+	for i := 1; i <= *cantidad; i++ {
+		caras := make([]recognition.FaceDescriptor, 0, *rostros)
+		for j := 0; j < *rostros; j++ {
+			identidad := *semilla
+			if j > 0 {
+				identidad = fmt.Sprintf("%s-acompanante-%d", *semilla, j)
+			}
+			cara := recognition.FakeDescriptor(identidad, i)
+			x := (anchoImagen-*lado)/2 + j*(*lado/2)
+			y := (altoImagen - *lado) / 2
+			cara.Rectangle = recognition.Rectangle{
+				Min: recognition.Point{X: x, Y: y},
+				Max: recognition.Point{X: x + *lado, Y: y + *lado},
+			}
+			caras = append(caras, cara)
+		}
+
+		datos, err := generarJPEG(i)
+		if err != nil {
+			log.Fatalf("Error generando imagen: %v", err)
+		}
+		datos, err = recognition.EmbedFakeFaces(datos, caras)
+		if err != nil {
+			log.Fatalf("Error embebiendo rostros: %v", err)
+		}
+
+		archivo := filepath.Join(*salida, fmt.Sprintf("%s_%d.jpg", *semilla, i))
+		if err := os.WriteFile(archivo, datos, 0644); err != nil {
+			log.Fatalf("Error escribiendo %s: %v", archivo, err)
+		}
+		fmt.Println(archivo)
+	}
+}
+
+// generarJPEG crea una imagen lisa; el contenido visual no importa al motor fake
+func generarJPEG(variante int) ([]byte, error) {
+	img := image.NewGray(image.Rect(0, 0, anchoImagen, altoImagen))
+	tono := color.Gray{Y: uint8(96 + (variante*37)%96)}
+	for y := 0; y < altoImagen; y++ {
+		for x := 0; x < anchoImagen; x++ {
+			img.SetGray(x, y, tono)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
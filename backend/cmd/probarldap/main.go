@@ -0,0 +1,66 @@
+// probarldap verifica la configuración LDAP_* del .env sin levantar el API: se autentica con el
+// usuario indicado y muestra la entrada encontrada y el rol que le corresponde por sus grupos.
+// La contraseña se lee de LDAP_TEST_PASSWORD o, si no está, de la primera línea de la entrada
+// estándar.
+//
+// Uso: go run ./cmd/probarldap -usuario jperez
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/autenticacion"
+)
+
+func main() {
+	usuario := flag.String("usuario", "", "username a autenticar contra el directorio")
+	flag.Parse()
+
+	if *usuario == "" {
+		log.Fatal("Debe indicar -usuario")
+	}
+	if err := godotenv.Load("../.env"); err != nil {
+		log.Println("No se encontró archivo .env, usando variables de entorno del sistema")
+	}
+
+	config, err := autenticacion.ConfigLDAPFromEnv()
+	if err != nil {
+		log.Fatal("Error configurando LDAP:", err)
+	}
+	directorio, err := autenticacion.NewLDAP(config)
+	if err != nil {
+		log.Fatal("Error configurando LDAP:", err)
+	}
+
+	password := os.Getenv("LDAP_TEST_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Contraseña: ")
+		linea, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		password = strings.TrimRight(linea, "\r\n")
+	}
+
+	identidad, err := directorio.Autenticar(*usuario, password)
+	if errors.Is(err, autenticacion.ErrCredencialesInvalidas) {
+		log.Fatalf("Credenciales inválidas para %q (usuario inexistente en %s o contraseña incorrecta)", *usuario, config.BaseDN)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rol := string(identidad.Rol)
+	if rol == "" {
+		rol = "(ninguno: no pertenece a un grupo de LDAP_GROUPS_*)"
+	}
+	fmt.Printf("DN:       %s\n", identidad.DN)
+	fmt.Printf("Username: %s\n", identidad.Username)
+	fmt.Printf("Nombre:   %s\n", identidad.NombreCompleto)
+	fmt.Printf("Email:    %s\n", identidad.Email)
+	fmt.Printf("Rol:      %s\n", rol)
+}
@@ -0,0 +1,65 @@
+// recifrar vuelve a cifrar los descriptores faciales con la clave biométrica activa y los secretos
+// de autenticación en dos pasos con la clave TOTP activa.
+// Se usa para rotar BIOMETRIC_KEY o TOTP_KEY: la clave nueva va en BIOMETRIC_KEY (TOTP_KEY), la
+// anterior en BIOMETRIC_PREVIOUS_KEYS (TOTP_PREVIOUS_KEYS), se ejecuta este comando y luego se
+// retira la clave anterior.
+//
+// Uso: go run ./cmd/recifrar
+package main
+
+import (
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/database"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+	"github.com/sistema-ingreso-docente/backend/internal/recognition"
+)
+
+func main() {
+	if err := godotenv.Load("../.env"); err != nil {
+		log.Println("No se encontró archivo .env, usando variables de entorno del sistema")
+	}
+
+	reloj, err := clock.NewFromEnv()
+	if err != nil {
+		log.Fatal("Error configurando la zona horaria:", err)
+	}
+
+	db, err := database.NewConnection()
+	if err != nil {
+		log.Fatal("Error conectando a la base de datos:", err)
+	}
+	defer db.Close()
+
+	biometricCipher, err := security.NewBiometricCipherFromEnv()
+	if err != nil {
+		log.Fatal("Error cargando la clave biométrica:", err)
+	}
+	totpCipher, err := security.NewTOTPCipherFromEnv()
+	if err != nil {
+		log.Fatal("Error cargando la clave TOTP:", err)
+	}
+
+	index := recognition.NewIndex(database.NewDocenteRepository(db), biometricCipher)
+	actualizados, err := index.Reencrypt(true)
+	if err != nil {
+		log.Fatalf("Re-cifrado interrumpido tras %d descriptores: %v", actualizados, err)
+	}
+
+	log.Printf("[AUDIT] %d descriptores faciales re-cifrados con la clave %s", actualizados, biometricCipher.ActiveKeyID())
+	log.Println("Los descriptores ya no dependen de BIOMETRIC_PREVIOUS_KEYS. Conserve las claves anteriores " +
+		"mientras existan evidencias fotográficas cifradas con ellas (EVIDENCE_RETENTION_DAYS)")
+
+	dosFactoresUseCase := usecases.NewDosFactoresUseCase(
+		database.NewDosFactoresRepository(db), database.NewUsuarioRepository(db), totpCipher, reloj)
+	secretos, err := dosFactoresUseCase.Recifrar()
+	if err != nil {
+		log.Fatalf("Re-cifrado interrumpido tras %d secretos TOTP: %v", secretos, err)
+	}
+
+	log.Printf("[AUDIT] %d secretos TOTP re-cifrados con la clave %s", secretos, totpCipher.ActiveKeyID())
+	log.Println("Los secretos TOTP ya no dependen de TOTP_PREVIOUS_KEYS; puede retirar las claves anteriores")
+}
@@ -11,7 +11,61 @@ type LoginResponse struct {
 }
 
 type UserProfile struct {
-	ID       int    `json:"id"`
+	ID                 int    `json:"id"`
+	Username           string `json:"username"`
+	Rol                string `json:"rol"`
+	MustChangePassword bool   `json:"must_change_password"`
+	EnrolarDosFactores bool   `json:"enrolar_2fa"`
+}
+
+// DesafioDosFactoresResponse es la respuesta de /login cuando el usuario tiene segundo factor
+type DesafioDosFactoresResponse struct {
+	RequiereDosFactores bool   `json:"requiere_2fa"`
+	DesafioToken        string `json:"desafio_token"`
+	ExpiraEn            int    `json:"expira_en"` // Segundos
+}
+
+// VerificarDosFactoresRequest canjea el desafío y un código TOTP o de recuperación por la sesión
+type VerificarDosFactoresRequest struct {
+	DesafioToken string `json:"desafio_token"`
+	Codigo       string `json:"codigo"`
+}
+
+// CodigoDosFactoresRequest lleva un código TOTP (o de recuperación donde se acepte)
+type CodigoDosFactoresRequest struct {
+	Codigo string `json:"codigo"`
+}
+
+// EnrolamientoDosFactoresResponse son los datos para agregar la cuenta a la aplicación autenticadora
+type EnrolamientoDosFactoresResponse struct {
+	Secreto    string `json:"secreto"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCode     string `json:"qr_code"` // PNG como data URI
+}
+
+// ActivacionDosFactoresResponse entrega los códigos de recuperación y una sesión sin restricciones
+type ActivacionDosFactoresResponse struct {
+	CodigosRecuperacion []string `json:"codigos_recuperacion"`
+	LoginResponse
+}
+
+type PoliticaDosFactoresRequest struct {
+	Requerido bool `json:"requerido"`
+}
+
+// CambiarPasswordRequest para que el usuario autenticado reemplace su contraseña
+type CambiarPasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// OlvidoPasswordRequest para solicitar el correo de restablecimiento de contraseña
+type OlvidoPasswordRequest struct {
 	Username string `json:"username"`
-	Rol      string `json:"rol"`
+}
+
+// RestablecerPasswordRequest para elegir una nueva contraseña con el token recibido por correo
+type RestablecerPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
 }
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// RegistrarConsentimientoRequest para registrar el consentimiento biométrico de un docente
+type RegistrarConsentimientoRequest struct {
+	VersionDocumento    string     `json:"version_documento"`
+	FechaConsentimiento *time.Time `json:"fecha_consentimiento,omitempty"` // Por defecto, el momento del registro
+}
+
+// RevocarConsentimientoRequest para revocar el consentimiento biométrico (cuerpo opcional)
+type RevocarConsentimientoRequest struct {
+	Motivo string `json:"motivo"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import "encoding/json"
+
+// VeredictoImagen describe el resultado del control de calidad de una imagen de enrolamiento
+type VeredictoImagen struct {
+	Indice             int      `json:"indice"`
+	Archivo            string   `json:"archivo"`
+	Veredicto          string   `json:"veredicto"`
+	Motivo             string   `json:"motivo,omitempty"`
+	RostrosDetectados  int      `json:"rostros_detectados"`
+	DocenteDuplicadoID *int     `json:"docente_duplicado_id,omitempty"`
+	Distancia          *float32 `json:"distancia,omitempty"`
+}
+
+// MarcarIntentoRequest para señalar una identificación facial incorrecta
+type MarcarIntentoRequest struct {
+	DocenteRealID *int   `json:"docente_real_id,omitempty"`
+	Motivo        string `json:"motivo"`
+}
+
+// VerificacionFacialResponse resultado de comparar un rostro con el docente declarado (1:1)
+type VerificacionFacialResponse struct {
+	DocenteID         int     `json:"docente_id"`
+	NombreCompleto    string  `json:"nombre_completo"`
+	Verificado        bool    `json:"verificado"`
+	Score             float32 `json:"score"` // Fracción de muestras que coinciden
+	MatchCount        int     `json:"match_count"`
+	TotalDescriptors  int     `json:"total_descriptors"`
+	Distance          float32 `json:"distance"`
+	IntentoID         int     `json:"intento_id"`
+	VerificacionToken string  `json:"verificacion_token,omitempty"`
+	ExpiraEn          int     `json:"expira_en,omitempty"` // Segundos de validez del token
+}
+
+// ImagenRequest permite enviar la imagen como data URL base64, por ejemplo un cuadro
+// capturado por la webcam del navegador ("data:image/jpeg;base64,...")
+type ImagenRequest struct {
+	Imagen   string      `json:"imagen"`
+	CI       json.Number `json:"ci,omitempty"` // Solo para verificación 1:1
+	Terminal string      `json:"terminal,omitempty"`
+}
+
+// EnrolamientoRequest permite enviar las fotos de enrolamiento como data URLs base64
+type EnrolamientoRequest struct {
+	Imagenes []string `json:"imagenes"`
+}
+
+// RevisarParDuplicadoRequest para registrar la revisión de un par de docentes con rostros coincidentes
+type RevisarParDuplicadoRequest struct {
+	Estado string `json:"estado"` // pendiente, confirmado, descartado
+	Nota   string `json:"nota"`
+}
@@ -6,6 +6,10 @@ type RegistroIngresoRequest struct {
 	TurnoID       *int    `json:"turno_id,omitempty"`
 	LlaveID       *int    `json:"llave_id,omitempty"`
 	Observaciones *string `json:"observaciones,omitempty"`
+	// IntentoReconocimientoID vincula el registro con la identificación facial que lo originó
+	IntentoReconocimientoID *int `json:"intento_reconocimiento_id,omitempty"`
+	// VerificacionToken acredita una verificación facial 1:1 reciente del docente
+	VerificacionToken *string `json:"verificacion_token,omitempty"`
 }
 
 type RegistroSalidaRequest struct {
@@ -14,6 +18,8 @@ type RegistroSalidaRequest struct {
 	TurnoID       *int    `json:"turno_id,omitempty"`
 	LlaveID       *int    `json:"llave_id,omitempty"`
 	Observaciones *string `json:"observaciones,omitempty"`
+	// IntentoReconocimientoID vincula el registro con la identificación facial que lo originó
+	IntentoReconocimientoID *int `json:"intento_reconocimiento_id,omitempty"`
 }
 
 // RegistroUpdateRequest para edición de registros por bibliotecario/jefe de carrera
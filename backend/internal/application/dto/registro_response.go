@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
 
 type RegistroHoyResponse struct {
 	ID             int       `json:"id"`
@@ -17,5 +21,18 @@ type RegistroHoyResponse struct {
 	FechaHora      time.Time `json:"fecha_hora"`
 	MinutosRetraso int       `json:"minutos_retraso"`
 	MinutosExtra   int       `json:"minutos_extra"`
+	Clasificacion  string    `json:"clasificacion"`
 	EsExcepcional  bool      `json:"es_excepcional"`
+	SuplenciaID    *int      `json:"suplencia_id,omitempty"`
+}
+
+// RegistroCreadoResponse es el registro creado junto con la forma en que se eligió su turno
+// y, en las salidas, la sesión que cierra
+type RegistroCreadoResponse struct {
+	*entities.Registro
+	TurnoSeleccion *entities.SeleccionTurno `json:"turno_seleccion"`
+	Sesion         *entities.Sesion         `json:"sesion,omitempty"`
+	// Licencia vigente del docente; el registro se acepta pero el operador debe verla
+	Licencia     *entities.Licencia `json:"licencia,omitempty"`
+	Advertencias []string           `json:"advertencias,omitempty"`
 }
@@ -0,0 +1,182 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// PeriodoAcademico es un semestre (u otro periodo lectivo) de una gestión. Cuando hay periodos
+// cargados, los días fuera de todos ellos no son laborables
+type PeriodoAcademico struct {
+	ID          int       `json:"id"`
+	Gestion     int       `json:"gestion"`
+	Nombre      string    `json:"nombre"`
+	FechaInicio Fecha     `json:"fecha_inicio"`
+	FechaFin    Fecha     `json:"fecha_fin"`
+	Descripcion *string   `json:"descripcion,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Cubre indica si la fecha está dentro del periodo (ambos extremos incluidos)
+func (p *PeriodoAcademico) Cubre(fecha Fecha) bool {
+	return !fecha.Antes(p.FechaInicio) && !p.FechaFin.Antes(fecha)
+}
+
+// TipoEventoCalendario clasifica los días especiales del calendario académico
+type TipoEventoCalendario string
+
+const (
+	// EventoFeriado: no hay actividades
+	EventoFeriado TipoEventoCalendario = "feriado"
+	// EventoReceso: vacaciones o receso entre semestres, no hay actividades
+	EventoReceso TipoEventoCalendario = "receso"
+	// EventoExamenes: semana de exámenes, los turnos se dictan con normalidad
+	EventoExamenes TipoEventoCalendario = "examenes"
+	// EventoHorarioEspecial: el turno se dicta con otro horario
+	EventoHorarioEspecial TipoEventoCalendario = "horario_especial"
+)
+
+var TiposEventoCalendarioValidos = map[TipoEventoCalendario]bool{
+	EventoFeriado:         true,
+	EventoReceso:          true,
+	EventoExamenes:        true,
+	EventoHorarioEspecial: true,
+}
+
+func (t TipoEventoCalendario) IsValid() bool {
+	return TiposEventoCalendarioValidos[t]
+}
+
+// SuspendeActividades indica si el evento hace no laborables los días que cubre
+func (t TipoEventoCalendario) SuspendeActividades() bool {
+	return t == EventoFeriado || t == EventoReceso
+}
+
+// EventoCalendario es un día o rango de días especial. Sin TurnoID afecta a todos los turnos.
+// Los de tipo horario_especial indican el horario con que se dicta el turno esos días
+type EventoCalendario struct {
+	ID          int                  `json:"id"`
+	Tipo        TipoEventoCalendario `json:"tipo"`
+	Nombre      string               `json:"nombre"`
+	FechaInicio Fecha                `json:"fecha_inicio"`
+	FechaFin    Fecha                `json:"fecha_fin"`
+	TurnoID     *int                 `json:"turno_id,omitempty"`
+	HoraInicio  *HoraDelDia          `json:"hora_inicio,omitempty"`
+	HoraFin     *HoraDelDia          `json:"hora_fin,omitempty"`
+	Descripcion *string              `json:"descripcion,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// Cubre indica si el evento abarca la fecha (ambos extremos incluidos)
+func (e *EventoCalendario) Cubre(fecha Fecha) bool {
+	return !fecha.Antes(e.FechaInicio) && !e.FechaFin.Antes(fecha)
+}
+
+// AfectaTurno indica si el evento aplica al turno
+func (e *EventoCalendario) AfectaTurno(turnoID int) bool {
+	return e.TurnoID == nil || *e.TurnoID == turnoID
+}
+
+// DiaCalendario es la respuesta a "¿la fecha es laborable para el turno?", con el horario que
+// rige ese día y los periodos y eventos que lo determinan
+type DiaCalendario struct {
+	Fecha       Fecha               `json:"fecha"`
+	TurnoID     int                 `json:"turno_id"`
+	TurnoNombre string              `json:"turno_nombre"`
+	Laborable   bool                `json:"laborable"`
+	Motivo      string              `json:"motivo,omitempty"`
+	HoraInicio  HoraDelDia          `json:"hora_inicio"`
+	HoraFin     HoraDelDia          `json:"hora_fin"`
+	Periodo     *PeriodoAcademico   `json:"periodo,omitempty"`
+	Eventos     []*EventoCalendario `json:"eventos"`
+}
+
+// Calendario reúne los periodos y eventos de un rango de fechas para evaluar días sin volver a
+// consultar la base de datos
+type Calendario struct {
+	Periodos []*PeriodoAcademico
+	Eventos  []*EventoCalendario
+}
+
+// Dia evalúa si el turno se dicta en la fecha y con qué horario. Un calendario nil solo
+// considera los días de la semana y la vigencia del turno
+func (c *Calendario) Dia(turno *Turno, fecha Fecha) *DiaCalendario {
+	dia := &DiaCalendario{
+		Fecha:       fecha,
+		TurnoID:     turno.ID,
+		TurnoNombre: turno.Nombre,
+		Laborable:   true,
+		HoraInicio:  turno.HoraInicio,
+		HoraFin:     turno.HoraFin,
+		Eventos:     []*EventoCalendario{},
+	}
+
+	if !turno.AplicaEn(fecha.Time) {
+		dia.Laborable = false
+		dia.Motivo = "El turno no se dicta este día"
+	}
+	if c == nil {
+		return dia
+	}
+
+	for _, periodo := range c.Periodos {
+		if periodo.Cubre(fecha) {
+			dia.Periodo = periodo
+			break
+		}
+	}
+	if dia.Laborable && len(c.Periodos) > 0 && dia.Periodo == nil {
+		dia.Laborable = false
+		dia.Motivo = "Fuera de los periodos académicos"
+	}
+
+	for _, evento := range c.Eventos {
+		if !evento.Cubre(fecha) || !evento.AfectaTurno(turno.ID) {
+			continue
+		}
+		dia.Eventos = append(dia.Eventos, evento)
+
+		if !dia.Laborable {
+			continue
+		}
+		if evento.Tipo.SuspendeActividades() {
+			dia.Laborable = false
+			dia.Motivo = fmt.Sprintf("%s: %s", tituloEvento(evento.Tipo), evento.Nombre)
+			continue
+		}
+		if evento.Tipo == EventoHorarioEspecial && evento.HoraInicio != nil && evento.HoraFin != nil {
+			dia.HoraInicio, dia.HoraFin = *evento.HoraInicio, *evento.HoraFin
+		}
+	}
+	return dia
+}
+
+// TurnoDelDia retorna el turno con el horario que rige en la fecha, o false si ese día no se dicta
+func (c *Calendario) TurnoDelDia(turno *Turno, fecha Fecha) (*Turno, bool) {
+	dia := c.Dia(turno, fecha)
+	if !dia.Laborable {
+		return nil, false
+	}
+	if dia.HoraInicio == turno.HoraInicio && dia.HoraFin == turno.HoraFin {
+		return turno, true
+	}
+	efectivo := *turno
+	efectivo.HoraInicio, efectivo.HoraFin = dia.HoraInicio, dia.HoraFin
+	return &efectivo, true
+}
+
+func tituloEvento(tipo TipoEventoCalendario) string {
+	switch tipo {
+	case EventoFeriado:
+		return "Feriado"
+	case EventoReceso:
+		return "Receso"
+	case EventoExamenes:
+		return "Exámenes"
+	case EventoHorarioEspecial:
+		return "Horario especial"
+	}
+	return string(tipo)
+}
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// MesContable identifica un mes calendario en la zona horaria de la institución; es la unidad
+// que el jefe de carrera cierra para congelar los registros
+type MesContable struct {
+	Anio int `json:"anio"`
+	Mes  int `json:"mes"`
+}
+
+// MesDe retorna el mes de t en su propia zona horaria
+func MesDe(t time.Time) MesContable {
+	return MesContable{Anio: t.Year(), Mes: int(t.Month())}
+}
+
+// Valido indica si el mes está dentro de los rangos admitidos
+func (m MesContable) Valido() bool {
+	return m.Anio >= 2000 && m.Anio <= 2100 && m.Mes >= 1 && m.Mes <= 12
+}
+
+// Antes indica si m es un mes anterior a otro
+func (m MesContable) Antes(otro MesContable) bool {
+	return m.Anio < otro.Anio || (m.Anio == otro.Anio && m.Mes < otro.Mes)
+}
+
+func (m MesContable) String() string {
+	return fmt.Sprintf("%04d-%02d", m.Anio, m.Mes)
+}
+
+// AccionCierre es el tipo de movimiento en el historial de cierres
+type AccionCierre string
+
+const (
+	AccionCerrar  AccionCierre = "cierre"
+	AccionReabrir AccionCierre = "reapertura"
+)
+
+var AccionesCierreValidas = map[AccionCierre]bool{
+	AccionCerrar:  true,
+	AccionReabrir: true,
+}
+
+func (a AccionCierre) IsValid() bool {
+	return AccionesCierreValidas[a]
+}
+
+// MovimientoCierre es una fila del historial de cierres: quién cerró o reabrió un mes, cuándo
+// y por qué. El historial no se modifica; el estado de un mes es su último movimiento
+type MovimientoCierre struct {
+	ID        int          `json:"id"`
+	Anio      int          `json:"anio"`
+	Mes       int          `json:"mes"`
+	Accion    AccionCierre `json:"accion"`
+	UsuarioID int          `json:"usuario_id"`
+	Usuario   string       `json:"usuario,omitempty"`
+	Motivo    *string      `json:"motivo,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// MesContable retorna el mes al que se refiere el movimiento
+func (m *MovimientoCierre) MesContable() MesContable {
+	return MesContable{Anio: m.Anio, Mes: m.Mes}
+}
+
+// EstadoCierre es el estado actual de un mes y, en la consulta de detalle, su historial completo
+type EstadoCierre struct {
+	Anio             int                 `json:"anio"`
+	Mes              int                 `json:"mes"`
+	Cerrado          bool                `json:"cerrado"`
+	UltimoMovimiento *MovimientoCierre   `json:"ultimo_movimiento,omitempty"`
+	Historial        []*MovimientoCierre `json:"historial,omitempty"`
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// ConsentimientoBiometrico registra la autorización de un docente para el uso de su rostro
+// Un docente tiene a lo sumo un consentimiento vigente; los revocados se conservan como historial
+type ConsentimientoBiometrico struct {
+	ID                   int        `json:"id"`
+	DocenteID            int        `json:"docente_id"`
+	VersionDocumento     string     `json:"version_documento"`
+	FechaConsentimiento  time.Time  `json:"fecha_consentimiento"`
+	RecolectadoPor       int        `json:"recolectado_por"`
+	RecolectadoPorNombre string     `json:"recolectado_por_nombre,omitempty"`
+	RevocadoAt           *time.Time `json:"revocado_at,omitempty"`
+	RevocadoPor          *int       `json:"revocado_por,omitempty"`
+	MotivoRevocacion     *string    `json:"motivo_revocacion,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
+
+// Vigente indica si el consentimiento no fue revocado
+func (c *ConsentimientoBiometrico) Vigente() bool {
+	return c.RevocadoAt == nil
+}
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// DosFactores es la autenticación en dos pasos (TOTP) de un usuario. El secreto se guarda cifrado;
+// mientras Activo es false el enrolamiento está iniciado pero no confirmado con un código
+type DosFactores struct {
+	UsuarioID      int        `json:"usuario_id"`
+	SecretoCifrado []byte     `json:"-"`
+	ClaveID        string     `json:"-"`
+	Activo         bool       `json:"activo"`
+	UltimoPaso     int64      `json:"-"` // Último paso TOTP aceptado, para rechazar códigos repetidos
+	ActivadoAt     *time.Time `json:"activado_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PoliticaDosFactores indica si un rol exige autenticación en dos pasos
+type PoliticaDosFactores struct {
+	Rol            Rol       `json:"rol"`
+	Requerido      bool      `json:"requerido"`
+	ActualizadoPor *int      `json:"actualizado_por,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// EstadoDosFactores resume la autenticación en dos pasos de un usuario
+type EstadoDosFactores struct {
+	Activo              bool       `json:"activo"`
+	Requerido           bool       `json:"requerido"` // Lo exige el rol del usuario
+	ActivadoAt          *time.Time `json:"activado_at,omitempty"`
+	CodigosRecuperacion int        `json:"codigos_recuperacion"` // Códigos sin usar
+}
@@ -0,0 +1,38 @@
+package entities
+
+import "time"
+
+// AlmacenEvidencia indica dónde se guarda la imagen de una evidencia
+type AlmacenEvidencia string
+
+const (
+	AlmacenArchivo   AlmacenEvidencia = "archivo"
+	AlmacenBaseDatos AlmacenEvidencia = "base_datos"
+)
+
+// AlmacenesEvidenciaValidos contiene los almacenes válidos
+var AlmacenesEvidenciaValidos = map[AlmacenEvidencia]bool{
+	AlmacenArchivo:   true,
+	AlmacenBaseDatos: true,
+}
+
+// IsValid verifica si el almacén es válido
+func (a AlmacenEvidencia) IsValid() bool {
+	return AlmacenesEvidenciaValidos[a]
+}
+
+// EvidenciaReconocimiento es el recorte del rostro capturado en una identificación exitosa
+// Se asocia al intento; el registro resultante la obtiene a través de intentos_reconocimiento.registro_id
+// La imagen se guarda cifrada, en un archivo local (Archivo) o en la propia fila (Imagen)
+type EvidenciaReconocimiento struct {
+	ID         int              `json:"id"`
+	IntentoID  int              `json:"intento_id"`
+	DocenteID  int              `json:"docente_id"`
+	RegistroID *int             `json:"registro_id,omitempty"`
+	Almacen    AlmacenEvidencia `json:"almacen"`
+	Archivo    *string          `json:"-"`
+	Imagen     []byte           `json:"-"`
+	ClaveID    string           `json:"-"`
+	Tamano     int              `json:"tamano"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
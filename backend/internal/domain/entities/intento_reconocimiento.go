@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// ModoReconocimiento distingue la identificación 1:N de la verificación 1:1
+type ModoReconocimiento string
+
+const (
+	ModoIdentificacion ModoReconocimiento = "identificacion"
+	ModoVerificacion   ModoReconocimiento = "verificacion"
+)
+
+// IsValid verifica si el modo es válido
+func (m ModoReconocimiento) IsValid() bool {
+	return m == ModoIdentificacion || m == ModoVerificacion
+}
+
+type DecisionReconocimiento string
+
+const (
+	DecisionIdentificado   DecisionReconocimiento = "identificado"
+	DecisionNoIdentificado DecisionReconocimiento = "no_identificado"
+	DecisionSinRostro      DecisionReconocimiento = "sin_rostro"
+	DecisionError          DecisionReconocimiento = "error"
+)
+
+// DecisionesReconocimientoValidas contiene todas las decisiones válidas
+var DecisionesReconocimientoValidas = map[DecisionReconocimiento]bool{
+	DecisionIdentificado:   true,
+	DecisionNoIdentificado: true,
+	DecisionSinRostro:      true,
+	DecisionError:          true,
+}
+
+// IsValid verifica si la decisión es válida
+func (d DecisionReconocimiento) IsValid() bool {
+	return DecisionesReconocimientoValidas[d]
+}
+
+// CandidatoReconocimiento es un docente evaluado durante un intento de identificación
+type CandidatoReconocimiento struct {
+	DocenteID         int     `json:"docente_id"`
+	NombreCompleto    string  `json:"nombre_completo"`
+	Coincidencias     int     `json:"coincidencias"`
+	TotalDescriptores int     `json:"total_descriptores"`
+	MejorDistancia    float32 `json:"mejor_distancia"`
+}
+
+// IntentoReconocimiento registra cada intento de identificación o verificación facial para auditoría
+// En modo verificación, la decisión "identificado" indica que el rostro coincidió con el docente declarado
+type IntentoReconocimiento struct {
+	ID                int                       `json:"id"`
+	FechaHora         time.Time                 `json:"fecha_hora"`
+	Modo              ModoReconocimiento        `json:"modo"`
+	OperadorID        *int                      `json:"operador_id,omitempty"`
+	Terminal          string                    `json:"terminal"`
+	RostrosDetectados int                       `json:"rostros_detectados"`
+	Candidatos        []CandidatoReconocimiento `json:"candidatos"`
+	Decision          DecisionReconocimiento    `json:"decision"`
+	DocenteID         *int                      `json:"docente_id,omitempty"`
+	RegistroID        *int                      `json:"registro_id,omitempty"`
+	Detalle           *string                   `json:"detalle,omitempty"`
+	MarcadoIncorrecto bool                      `json:"marcado_incorrecto"`
+	DocenteRealID     *int                      `json:"docente_real_id,omitempty"`
+	MotivoMarca       *string                   `json:"motivo_marca,omitempty"`
+	MarcadoPor        *int                      `json:"marcado_por,omitempty"`
+	MarcadoAt         *time.Time                `json:"marcado_at,omitempty"`
+	// VerificacionUsadaAt es cuándo se consumió el token de la verificación al registrar el ingreso
+	VerificacionUsadaAt *time.Time `json:"verificacion_usada_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
@@ -0,0 +1,86 @@
+package entities
+
+import "time"
+
+// CategoriaJustificacion es el motivo general de una justificación
+type CategoriaJustificacion string
+
+const (
+	CategoriaSalud          CategoriaJustificacion = "salud"
+	CategoriaFamiliar       CategoriaJustificacion = "familiar"
+	CategoriaTramiteOficial CategoriaJustificacion = "tramite_oficial"
+	CategoriaTransporte     CategoriaJustificacion = "transporte"
+	CategoriaFuerzaMayor    CategoriaJustificacion = "fuerza_mayor"
+	CategoriaOtro           CategoriaJustificacion = "otro"
+)
+
+var CategoriasJustificacionValidas = map[CategoriaJustificacion]bool{
+	CategoriaSalud:          true,
+	CategoriaFamiliar:       true,
+	CategoriaTramiteOficial: true,
+	CategoriaTransporte:     true,
+	CategoriaFuerzaMayor:    true,
+	CategoriaOtro:           true,
+}
+
+func (c CategoriaJustificacion) IsValid() bool {
+	return CategoriasJustificacionValidas[c]
+}
+
+// EstadoJustificacion es la etapa de la revisión por el jefe de carrera
+type EstadoJustificacion string
+
+const (
+	JustificacionPendiente EstadoJustificacion = "pendiente"
+	JustificacionAprobada  EstadoJustificacion = "aprobada"
+	JustificacionRechazada EstadoJustificacion = "rechazada"
+)
+
+var EstadosJustificacionValidos = map[EstadoJustificacion]bool{
+	JustificacionPendiente: true,
+	JustificacionAprobada:  true,
+	JustificacionRechazada: true,
+}
+
+func (e EstadoJustificacion) IsValid() bool {
+	return EstadosJustificacionValidos[e]
+}
+
+// Justificacion explica un retraso, una salida anticipada o una ausencia. Se adjunta a un
+// registro concreto o a una fecha, en cuyo caso cubre un turno o, sin TurnoID, todo el día.
+// Fecha es el día de la ocurrencia del turno, también cuando se adjunta a un registro.
+// Aprobada, los registros que cubre se clasifican como justificados
+type Justificacion struct {
+	ID                  int                    `json:"id"`
+	DocenteID           int                    `json:"docente_id"`
+	DocenteNombre       string                 `json:"docente_nombre,omitempty"`
+	DocenteCI           string                 `json:"docente_ci,omitempty"`
+	RegistroID          *int                   `json:"registro_id,omitempty"`
+	Fecha               Fecha                  `json:"fecha"`
+	TurnoID             *int                   `json:"turno_id,omitempty"`
+	Categoria           CategoriaJustificacion `json:"categoria"`
+	Descripcion         string                 `json:"descripcion"`
+	DocumentoNombre     *string                `json:"documento_nombre,omitempty"`
+	DocumentoTipo       *string                `json:"documento_tipo,omitempty"`
+	DocumentoTamano     *int                   `json:"documento_tamano,omitempty"`
+	DocumentoArchivo    *string                `json:"-"`
+	Estado              EstadoJustificacion    `json:"estado"`
+	CreadoPor           int                    `json:"creado_por"`
+	RevisadoPor         *int                   `json:"revisado_por,omitempty"`
+	RevisadoAt          *time.Time             `json:"revisado_at,omitempty"`
+	ObservacionRevision *string                `json:"observacion_revision,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+}
+
+// Cubre indica si la justificación abarca un registro del docente en la ocurrencia del turno
+// que empieza en fecha
+func (j *Justificacion) Cubre(registro *Registro, fecha Fecha) bool {
+	if j.DocenteID != registro.DocenteID {
+		return false
+	}
+	if j.RegistroID != nil {
+		return *j.RegistroID == registro.ID
+	}
+	return j.Fecha.String() == fecha.String() && (j.TurnoID == nil || *j.TurnoID == registro.TurnoID)
+}
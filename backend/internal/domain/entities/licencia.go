@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// TipoLicencia clasifica las ausencias planificadas
+type TipoLicencia string
+
+const (
+	LicenciaEnfermedad TipoLicencia = "enfermedad"
+	LicenciaVacaciones TipoLicencia = "vacaciones"
+	LicenciaComision   TipoLicencia = "comision"
+)
+
+var TiposLicenciaValidos = map[TipoLicencia]bool{
+	LicenciaEnfermedad: true,
+	LicenciaVacaciones: true,
+	LicenciaComision:   true,
+}
+
+func (t TipoLicencia) IsValid() bool {
+	return TiposLicenciaValidos[t]
+}
+
+// Licencia es una ausencia planificada del docente (baja médica, vacaciones o comisión oficial)
+// entre dos fechas inclusive. Durante la licencia no se cuentan retrasos ni faltas
+type Licencia struct {
+	ID            int          `json:"id"`
+	DocenteID     int          `json:"docente_id"`
+	DocenteNombre string       `json:"docente_nombre,omitempty"`
+	DocenteCI     string       `json:"docente_ci,omitempty"`
+	Tipo          TipoLicencia `json:"tipo"`
+	FechaInicio   Fecha        `json:"fecha_inicio"`
+	FechaFin      Fecha        `json:"fecha_fin"`
+	Descripcion   *string      `json:"descripcion,omitempty"`
+	CreadoPor     int          `json:"creado_por"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// Cubre indica si la licencia abarca la fecha (ambos extremos incluidos)
+func (l *Licencia) Cubre(fecha Fecha) bool {
+	return !fecha.Antes(l.FechaInicio) && !l.FechaFin.Antes(fecha)
+}
+
+// Advertencia describe la licencia para avisar al operador que registra al docente
+func (l *Licencia) Advertencia() string {
+	return fmt.Sprintf("El docente está con licencia por %s del %s al %s", tituloLicencia(l.Tipo), l.FechaInicio, l.FechaFin)
+}
+
+func tituloLicencia(tipo TipoLicencia) string {
+	switch tipo {
+	case LicenciaEnfermedad:
+		return "enfermedad"
+	case LicenciaVacaciones:
+		return "vacaciones"
+	case LicenciaComision:
+		return "comisión oficial"
+	}
+	return string(tipo)
+}
@@ -20,18 +20,56 @@ func (t TipoRegistro) IsValid() bool {
 	return TiposRegistroValidos[t]
 }
 
+// ClasificacionRegistro resume el cumplimiento del horario del turno
+type ClasificacionRegistro string
+
+const (
+	ClasificacionPuntual          ClasificacionRegistro = "puntual"
+	ClasificacionTarde            ClasificacionRegistro = "tarde"
+	ClasificacionFalta            ClasificacionRegistro = "falta"
+	ClasificacionSalidaAnticipada ClasificacionRegistro = "salida_anticipada"
+	// ClasificacionJustificada: retraso, falta o salida anticipada con justificación aprobada o
+	// durante una licencia
+	ClasificacionJustificada ClasificacionRegistro = "justificada"
+)
+
+// ClasificacionesRegistroValidas contiene las clasificaciones válidas
+var ClasificacionesRegistroValidas = map[ClasificacionRegistro]bool{
+	ClasificacionPuntual:          true,
+	ClasificacionTarde:            true,
+	ClasificacionFalta:            true,
+	ClasificacionSalidaAnticipada: true,
+	ClasificacionJustificada:      true,
+}
+
+// IsValid verifica si la clasificación es válida
+func (c ClasificacionRegistro) IsValid() bool {
+	return ClasificacionesRegistroValidas[c]
+}
+
+// Justificable indica si la clasificación es un incumplimiento que una justificación puede cubrir
+func (c ClasificacionRegistro) Justificable() bool {
+	return c == ClasificacionTarde || c == ClasificacionFalta || c == ClasificacionSalidaAnticipada
+}
+
+// Registro es un ingreso o una salida. Cada salida referencia en IngresoID el ingreso que cierra
+// (nil en ingresos y en salidas anteriores al emparejamiento). SuplenciaID vincula los registros
+// de un suplente a la suplencia que cubren
 type Registro struct {
-	ID             int          `json:"id"`
-	DocenteID      int          `json:"docente_id"`
-	TurnoID        int          `json:"turno_id"`
-	LlaveID        *int         `json:"llave_id,omitempty"`
-	Tipo           TipoRegistro `json:"tipo"`
-	FechaHora      time.Time    `json:"fecha_hora"`
-	MinutosRetraso int          `json:"minutos_retraso"`
-	MinutosExtra   int          `json:"minutos_extra"`
-	EsExcepcional  bool         `json:"es_excepcional"`
-	Observaciones  *string      `json:"observaciones,omitempty"`
-	EditadoPor     *int         `json:"editado_por,omitempty"`
-	CreatedAt      time.Time    `json:"created_at"`
-	UpdatedAt      time.Time    `json:"updated_at"`
+	ID             int                   `json:"id"`
+	DocenteID      int                   `json:"docente_id"`
+	TurnoID        int                   `json:"turno_id"`
+	LlaveID        *int                  `json:"llave_id,omitempty"`
+	Tipo           TipoRegistro          `json:"tipo"`
+	FechaHora      time.Time             `json:"fecha_hora"`
+	MinutosRetraso int                   `json:"minutos_retraso"`
+	MinutosExtra   int                   `json:"minutos_extra"`
+	Clasificacion  ClasificacionRegistro `json:"clasificacion"`
+	IngresoID      *int                  `json:"ingreso_id,omitempty"`
+	SuplenciaID    *int                  `json:"suplencia_id,omitempty"`
+	EsExcepcional  bool                  `json:"es_excepcional"`
+	Observaciones  *string               `json:"observaciones,omitempty"`
+	EditadoPor     *int                  `json:"editado_por,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
 }
@@ -0,0 +1,57 @@
+package entities
+
+// ResumenAsistencia acumula las sesiones de un docente en el rango de un reporte. Las sesiones en
+// días no laborables según el calendario académico se cuentan aparte y no suman retrasos ni faltas.
+// Justificadas cuenta las llegadas con retraso o falta justificadas, que tampoco suman retraso.
+// Las sesiones durante una licencia se cuentan en EnLicencia y tampoco se califican. Las sesiones
+// en que el docente suplía a otro suman a sus horas y además se cuentan en Suplencias
+type ResumenAsistencia struct {
+	DocenteID          int    `json:"docente_id"`
+	DocenteNombre      string `json:"docente_nombre"`
+	DocenteCI          string `json:"docente_ci"`
+	Sesiones           int    `json:"sesiones"`
+	SesionesAbiertas   int    `json:"sesiones_abiertas"`
+	MinutosTrabajados  int    `json:"minutos_trabajados"`
+	Puntuales          int    `json:"puntuales"`
+	Tardes             int    `json:"tardes"`
+	Faltas             int    `json:"faltas"`
+	SalidasAnticipadas int    `json:"salidas_anticipadas"`
+	Justificadas       int    `json:"justificadas"`
+	MinutosRetraso     int    `json:"minutos_retraso"`
+	MinutosExtra       int    `json:"minutos_extra"`
+	EnDiasNoLaborables int    `json:"en_dias_no_laborables"`
+	EnLicencia         int    `json:"en_licencia"`
+	// DiasLicencia cuenta los días del rango cubiertos por licencias del docente
+	DiasLicencia     int `json:"dias_licencia"`
+	Suplencias       int `json:"suplencias"`
+	MinutosSuplencia int `json:"minutos_suplencia"`
+	// Reemplazos cuenta las clases del docente que dictó un suplente; no son ausencias
+	Reemplazos int `json:"reemplazos"`
+	// JustificacionesAprobadas cuenta las justificaciones aprobadas del rango, tengan o no sesión
+	JustificacionesAprobadas int `json:"justificaciones_aprobadas"`
+}
+
+// DiasLaborablesTurno cuenta los días del rango en que se dicta un turno según el calendario
+type DiasLaborablesTurno struct {
+	TurnoID        int    `json:"turno_id"`
+	TurnoNombre    string `json:"turno_nombre"`
+	DiasLaborables int    `json:"dias_laborables"`
+}
+
+// ResumenMensual es la asistencia de un docente en un mes; el mes en curso se cuenta hasta hoy
+type ResumenMensual struct {
+	Anio    int                    `json:"anio"`
+	Mes     int                    `json:"mes"`
+	Desde   Fecha                  `json:"desde"`
+	Hasta   Fecha                  `json:"hasta"`
+	Turnos  []*DiasLaborablesTurno `json:"turnos"`
+	Resumen *ResumenAsistencia     `json:"resumen"`
+}
+
+// ReporteAsistencia resume la asistencia de los docentes entre dos fechas (inclusive)
+type ReporteAsistencia struct {
+	Desde    Fecha                  `json:"desde"`
+	Hasta    Fecha                  `json:"hasta"`
+	Turnos   []*DiasLaborablesTurno `json:"turnos"`
+	Docentes []*ResumenAsistencia   `json:"docentes"`
+}
@@ -0,0 +1,69 @@
+package entities
+
+import "time"
+
+// EstadoReporteDuplicados indica el avance de un escaneo de rostros duplicados
+type EstadoReporteDuplicados string
+
+const (
+	EstadoReporteEnProceso  EstadoReporteDuplicados = "en_proceso"
+	EstadoReporteCompletado EstadoReporteDuplicados = "completado"
+	EstadoReporteError      EstadoReporteDuplicados = "error"
+)
+
+// EstadoParDuplicado es la revisión humana de un par de docentes con rostros coincidentes
+type EstadoParDuplicado string
+
+const (
+	EstadoParPendiente  EstadoParDuplicado = "pendiente"
+	EstadoParConfirmado EstadoParDuplicado = "confirmado" // Misma persona: registros duplicados o enrolamiento erróneo
+	EstadoParDescartado EstadoParDuplicado = "descartado" // Personas distintas (falso positivo)
+)
+
+// EstadosParDuplicadoValidos contiene los estados válidos de revisión
+var EstadosParDuplicadoValidos = map[EstadoParDuplicado]bool{
+	EstadoParPendiente:  true,
+	EstadoParConfirmado: true,
+	EstadoParDescartado: true,
+}
+
+// IsValid verifica si el estado es válido
+func (e EstadoParDuplicado) IsValid() bool {
+	return EstadosParDuplicadoValidos[e]
+}
+
+// ReporteDuplicados es el resultado de comparar las muestras faciales de todos los docentes entre sí
+type ReporteDuplicados struct {
+	ID                 int                     `json:"id"`
+	Estado             EstadoReporteDuplicados `json:"estado"`
+	IniciadoPor        int                     `json:"iniciado_por"`
+	ModeloVersion      string                  `json:"modelo_version"`
+	Umbral             float32                 `json:"umbral"`
+	DocentesAnalizados int                     `json:"docentes_analizados"`
+	MuestrasAnalizadas int                     `json:"muestras_analizadas"`
+	ParesEncontrados   int                     `json:"pares_encontrados"`
+	Error              *string                 `json:"error,omitempty"`
+	IniciadoAt         time.Time               `json:"iniciado_at"`
+	FinalizadoAt       *time.Time              `json:"finalizado_at,omitempty"`
+	Pares              []*ParDuplicado         `json:"pares,omitempty"`
+}
+
+// ParDuplicado es un par de docentes distintos cuyos rostros coinciden
+type ParDuplicado struct {
+	ID                int                `json:"id"`
+	ReporteID         int                `json:"reporte_id"`
+	Grupo             int                `json:"grupo"`
+	DocenteAID        int                `json:"docente_a_id"`
+	DocenteANombre    string             `json:"docente_a_nombre,omitempty"`
+	DocenteBID        int                `json:"docente_b_id"`
+	DocenteBNombre    string             `json:"docente_b_nombre,omitempty"`
+	Coincidencias     int                `json:"coincidencias"`
+	Comparaciones     int                `json:"comparaciones"`
+	MejorDistancia    float32            `json:"mejor_distancia"`
+	DistanciaPromedio float32            `json:"distancia_promedio"`
+	Concluyente       bool               `json:"concluyente"`
+	Estado            EstadoParDuplicado `json:"estado"`
+	RevisadoPor       *int               `json:"revisado_por,omitempty"`
+	RevisadoAt        *time.Time         `json:"revisado_at,omitempty"`
+	Nota              *string            `json:"nota,omitempty"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// RestablecimientoPassword es una solicitud de restablecimiento de contraseña. Solo se guarda el
+// hash SHA-256 del token enviado por correo; el token sirve una vez y hasta ExpiraAt
+type RestablecimientoPassword struct {
+	ID        int        `json:"id"`
+	UsuarioID int        `json:"usuario_id"`
+	TokenHash string     `json:"-"`
+	ExpiraAt  time.Time  `json:"expira_at"`
+	UsadoAt   *time.Time `json:"usado_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Vigente indica si el token aún puede usarse en el instante dado
+func (r *RestablecimientoPassword) Vigente(ahora time.Time) bool {
+	return r.UsadoAt == nil && ahora.Before(r.ExpiraAt)
+}
@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+// OrigenRostro indica cómo se obtuvo una muestra facial
+type OrigenRostro string
+
+const (
+	OrigenEnrolamiento OrigenRostro = "enrolamiento"
+	OrigenMigracion    OrigenRostro = "migracion"
+)
+
+// OrigenesRostroValidos contiene los orígenes válidos
+var OrigenesRostroValidos = map[OrigenRostro]bool{
+	OrigenEnrolamiento: true,
+	OrigenMigracion:    true,
+}
+
+// IsValid verifica si el origen es válido
+func (o OrigenRostro) IsValid() bool {
+	return OrigenesRostroValidos[o]
+}
+
+// RostroDocente es una muestra facial (descriptor de 128 valores) de un docente
+// El descriptor se guarda cifrado (DescriptorCifrado + ClaveID) y nunca se serializa;
+// solo el índice de reconocimiento lo descifra. Descriptor conserva las muestras
+// anteriores al cifrado hasta que se cifran al iniciar el servidor
+// RequiereReenrolamiento se activa cuando la muestra fue generada por otro modelo
+// y ya no es comparable con los descriptores actuales
+type RostroDocente struct {
+	ID                     int          `json:"id"`
+	DocenteID              int          `json:"docente_id"`
+	DescriptorCifrado      []byte       `json:"-"`
+	ClaveID                *string      `json:"clave_id,omitempty"`
+	Descriptor             []float32    `json:"-"`
+	ModeloVersion          string       `json:"modelo_version"`
+	Calidad                *float32     `json:"calidad,omitempty"`
+	Origen                 OrigenRostro `json:"origen"`
+	RequiereReenrolamiento bool         `json:"requiere_reenrolamiento"`
+	CreatedAt              time.Time    `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+// Sesion es un ingreso junto con la salida que lo cierra, si ya existe.
+// Una sesión sin salida está abierta: el docente sigue en el aula y, si retiró llave, la tiene.
+type Sesion struct {
+	IngresoID            int                    `json:"ingreso_id"`
+	SalidaID             *int                   `json:"salida_id,omitempty"`
+	DocenteID            int                    `json:"docente_id"`
+	DocenteNombre        string                 `json:"docente_nombre"`
+	DocenteCI            string                 `json:"docente_ci"`
+	TurnoID              int                    `json:"turno_id"`
+	TurnoNombre          string                 `json:"turno_nombre"`
+	LlaveID              *int                   `json:"llave_id,omitempty"`
+	LlaveCodigo          *string                `json:"llave_codigo,omitempty"`
+	AulaCodigo           *string                `json:"aula_codigo,omitempty"`
+	AulaNombre           *string                `json:"aula_nombre,omitempty"`
+	HoraIngreso          time.Time              `json:"hora_ingreso"`
+	HoraSalida           *time.Time             `json:"hora_salida,omitempty"`
+	DuracionMinutos      *int                   `json:"duracion_minutos,omitempty"`
+	MinutosRetraso       int                    `json:"minutos_retraso"`
+	MinutosExtra         int                    `json:"minutos_extra"`
+	ClasificacionIngreso ClasificacionRegistro  `json:"clasificacion_ingreso"`
+	ClasificacionSalida  *ClasificacionRegistro `json:"clasificacion_salida,omitempty"`
+	Abierta              bool                   `json:"abierta"`
+	// SuplenciaID es la suplencia que cubre la sesión, si el docente reemplazaba al titular
+	SuplenciaID *int `json:"suplencia_id,omitempty"`
+}
+
+// CalcularDuracion completa la duración de la sesión a partir de sus horas de ingreso y salida
+func (s *Sesion) CalcularDuracion() {
+	s.Abierta = s.HoraSalida == nil
+	if s.Abierta {
+		s.DuracionMinutos = nil
+		return
+	}
+	minutos := int(s.HoraSalida.Sub(s.HoraIngreso).Minutes())
+	s.DuracionMinutos = &minutos
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Suplencia asigna a un docente suplente la clase de otro (el titular) en una fecha, un turno y
+// un aula. Los registros del suplente en esa clase se vinculan a la suplencia, y el reporte de
+// asistencia acredita las horas al suplente y cuenta al titular como reemplazado, no ausente
+type Suplencia struct {
+	ID             int       `json:"id"`
+	Fecha          Fecha     `json:"fecha"`
+	TurnoID        int       `json:"turno_id"`
+	TurnoNombre    string    `json:"turno_nombre,omitempty"`
+	AulaCodigo     string    `json:"aula_codigo"`
+	TitularID      int       `json:"titular_id"`
+	TitularNombre  string    `json:"titular_nombre,omitempty"`
+	TitularCI      string    `json:"titular_ci,omitempty"`
+	SuplenteID     int       `json:"suplente_id"`
+	SuplenteNombre string    `json:"suplente_nombre,omitempty"`
+	SuplenteCI     string    `json:"suplente_ci,omitempty"`
+	Motivo         *string   `json:"motivo,omitempty"`
+	AsignadoPor    int       `json:"asignado_por"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
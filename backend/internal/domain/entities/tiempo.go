@@ -0,0 +1,176 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// HoraDelDia es una hora del día sin fecha ni zona horaria, en segundos desde la medianoche.
+// Se serializa como "15:04:05" y se lee y escribe directamente en columnas TIME
+type HoraDelDia int
+
+const segundosPorDia = 24 * 60 * 60
+
+// HoraSinDefinir marca una hora que el request no incluyó
+const HoraSinDefinir HoraDelDia = -1
+
+// NuevaHoraDelDia construye una hora del día; no valida los rangos
+func NuevaHoraDelDia(hora, minuto, segundo int) HoraDelDia {
+	return HoraDelDia(hora*3600 + minuto*60 + segundo)
+}
+
+// ParseHoraDelDia acepta "15:04:05" o "15:04"
+func ParseHoraDelDia(valor string) (HoraDelDia, error) {
+	for _, formato := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(formato, valor); err == nil {
+			return NuevaHoraDelDia(t.Hour(), t.Minute(), t.Second()), nil
+		}
+	}
+	return 0, fmt.Errorf("hora inválida %q, se espera HH:MM o HH:MM:SS", valor)
+}
+
+// Valida indica si la hora está dentro del día
+func (h HoraDelDia) Valida() bool {
+	return h >= 0 && h < segundosPorDia
+}
+
+func (h HoraDelDia) String() string {
+	s := int(h)
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s%3600/60, s%60)
+}
+
+// Corta retorna la hora como "15:04", para mensajes
+func (h HoraDelDia) Corta() string {
+	return h.String()[:5]
+}
+
+// En retorna el instante de esta hora en la fecha de calendario de dia, en la zona loc
+func (h HoraDelDia) En(dia time.Time, loc *time.Location) time.Time {
+	s := int(h)
+	return time.Date(dia.Year(), dia.Month(), dia.Day(), s/3600, s%3600/60, s%60, 0, loc)
+}
+
+func (h HoraDelDia) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.String())
+}
+
+func (h *HoraDelDia) UnmarshalJSON(data []byte) error {
+	var valor string
+	if err := json.Unmarshal(data, &valor); err != nil {
+		return fmt.Errorf("hora inválida: %w", err)
+	}
+	hora, err := ParseHoraDelDia(valor)
+	if err != nil {
+		return err
+	}
+	*h = hora
+	return nil
+}
+
+// Scan lee una columna TIME; lib/pq la entrega como time.Time del año 0
+func (h *HoraDelDia) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*h = NuevaHoraDelDia(v.Hour(), v.Minute(), v.Second())
+		return nil
+	case []byte:
+		return h.scanTexto(string(v))
+	case string:
+		return h.scanTexto(v)
+	}
+	return fmt.Errorf("no se puede leer %T como hora del día", src)
+}
+
+func (h *HoraDelDia) scanTexto(valor string) error {
+	// Descartar fracciones de segundo si la columna las tiene
+	if len(valor) > 8 {
+		valor = valor[:8]
+	}
+	hora, err := ParseHoraDelDia(valor)
+	if err != nil {
+		return err
+	}
+	*h = hora
+	return nil
+}
+
+func (h HoraDelDia) Value() (driver.Value, error) {
+	return h.String(), nil
+}
+
+// Fecha es un día de calendario sin hora; se serializa como "2006-01-02" y se guarda en columnas DATE
+type Fecha struct {
+	time.Time
+}
+
+// ParseFecha acepta "2006-01-02"
+func ParseFecha(valor string) (Fecha, error) {
+	t, err := time.Parse("2006-01-02", valor)
+	if err != nil {
+		return Fecha{}, fmt.Errorf("fecha inválida %q, se espera AAAA-MM-DD", valor)
+	}
+	return Fecha{t}, nil
+}
+
+// FechaDe retorna el día de calendario de t en su propia zona horaria
+func FechaDe(t time.Time) Fecha {
+	return Fecha{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (f Fecha) String() string {
+	return f.Format("2006-01-02")
+}
+
+// Antes indica si f es un día anterior a otra
+func (f Fecha) Antes(otra Fecha) bool {
+	return f.String() < otra.String()
+}
+
+func (f Fecha) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.String())
+}
+
+func (f *Fecha) UnmarshalJSON(data []byte) error {
+	var valor string
+	if err := json.Unmarshal(data, &valor); err != nil {
+		return fmt.Errorf("fecha inválida: %w", err)
+	}
+	fecha, err := ParseFecha(valor)
+	if err != nil {
+		return err
+	}
+	*f = fecha
+	return nil
+}
+
+// Scan lee una columna DATE
+func (f *Fecha) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*f = FechaDe(v)
+		return nil
+	case []byte:
+		return f.scanTexto(string(v))
+	case string:
+		return f.scanTexto(v)
+	}
+	return fmt.Errorf("no se puede leer %T como fecha", src)
+}
+
+func (f *Fecha) scanTexto(valor string) error {
+	if len(valor) > 10 {
+		valor = valor[:10]
+	}
+	fecha, err := ParseFecha(valor)
+	if err != nil {
+		return err
+	}
+	*f = fecha
+	return nil
+}
+
+func (f Fecha) Value() (driver.Value, error) {
+	return f.String(), nil
+}
@@ -1,14 +1,166 @@
 package entities
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
+// Valores por defecto de los márgenes de un turno, en minutos
+const (
+	ToleranciaIngresoPorDefecto = 10
+	RetrasoMaximoPorDefecto     = 30
+	SalidaAnticipadaPorDefecto  = 10
+)
+
+// DiasSemana es la máscara de días en que aplica un turno: el bit i corresponde a time.Weekday(i)
+// (0 = domingo). En JSON se representa como la lista de días, p. ej. [1,2,3,4,5] de lunes a viernes
+type DiasSemana uint8
+
+// TodosLosDias aplica el turno toda la semana
+const TodosLosDias DiasSemana = 1<<7 - 1
+
+// NuevosDiasSemana construye la máscara a partir de una lista de días
+func NuevosDiasSemana(dias ...time.Weekday) (DiasSemana, error) {
+	var mascara DiasSemana
+	for _, dia := range dias {
+		if dia < time.Sunday || dia > time.Saturday {
+			return 0, fmt.Errorf("día de la semana inválido: %d (0 = domingo ... 6 = sábado)", dia)
+		}
+		mascara |= 1 << uint(dia)
+	}
+	return mascara, nil
+}
+
+// Incluye indica si el día está en la máscara
+func (d DiasSemana) Incluye(dia time.Weekday) bool {
+	return d&(1<<uint(dia)) != 0
+}
+
+// Dias retorna los días de la máscara en orden
+func (d DiasSemana) Dias() []time.Weekday {
+	dias := []time.Weekday{}
+	for dia := time.Sunday; dia <= time.Saturday; dia++ {
+		if d.Incluye(dia) {
+			dias = append(dias, dia)
+		}
+	}
+	return dias
+}
+
+func (d DiasSemana) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Dias())
+}
+
+func (d *DiasSemana) UnmarshalJSON(data []byte) error {
+	var dias []int
+	if err := json.Unmarshal(data, &dias); err != nil {
+		return fmt.Errorf("dias_semana debe ser una lista de días (0 = domingo ... 6 = sábado)")
+	}
+	semana := make([]time.Weekday, len(dias))
+	for i, dia := range dias {
+		semana[i] = time.Weekday(dia)
+	}
+	mascara, err := NuevosDiasSemana(semana...)
+	if err != nil {
+		return err
+	}
+	*d = mascara
+	return nil
+}
+
+// Turno define un horario y sus márgenes (en minutos):
+//   - ToleranciaIngresoMin: tras hora_inicio, el ingreso aún es puntual
+//   - RetrasoMaximoMin: desde hora_inicio, a partir de este retraso el ingreso cuenta como falta
+//   - SalidaAnticipadaMin: antes de hora_fin, desde cuándo la salida ya no es anticipada
+//
+// Si HoraFin no es posterior a HoraInicio el turno cruza la medianoche y termina al día
+// siguiente. DiasSemana y la vigencia se evalúan sobre el día en que el turno empieza
 type Turno struct {
-	ID          int       `json:"id"`
-	Nombre      string    `json:"nombre"`
-	HoraInicio  string    `json:"hora_inicio"`
-	HoraFin     string    `json:"hora_fin"`
-	Descripcion *string   `json:"descripcion,omitempty"`
-	Activo      bool      `json:"activo"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                   int        `json:"id"`
+	Nombre               string     `json:"nombre"`
+	HoraInicio           HoraDelDia `json:"hora_inicio"`
+	HoraFin              HoraDelDia `json:"hora_fin"`
+	DiasSemana           DiasSemana `json:"dias_semana"`
+	VigenteDesde         *Fecha     `json:"vigente_desde"`
+	VigenteHasta         *Fecha     `json:"vigente_hasta"`
+	Descripcion          *string    `json:"descripcion,omitempty"`
+	ToleranciaIngresoMin int        `json:"tolerancia_ingreso_min"`
+	RetrasoMaximoMin     int        `json:"retraso_maximo_min"`
+	SalidaAnticipadaMin  int        `json:"salida_anticipada_min"`
+	Activo               bool       `json:"activo"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// NuevoTurno retorna un turno con los márgenes por defecto, aplicable todos los días y sin
+// límite de vigencia, listo para completar con el request
+func NuevoTurno() Turno {
+	return Turno{
+		HoraInicio:           HoraSinDefinir,
+		HoraFin:              HoraSinDefinir,
+		DiasSemana:           TodosLosDias,
+		ToleranciaIngresoMin: ToleranciaIngresoPorDefecto,
+		RetrasoMaximoMin:     RetrasoMaximoPorDefecto,
+		SalidaAnticipadaMin:  SalidaAnticipadaPorDefecto,
+	}
+}
+
+// CruzaMedianoche indica si el turno termina al día siguiente de empezar
+func (t *Turno) CruzaMedianoche() bool {
+	return t.HoraFin <= t.HoraInicio
+}
+
+// Duracion retorna cuánto dura el turno, contando el cruce de medianoche
+func (t *Turno) Duracion() time.Duration {
+	segundos := int(t.HoraFin - t.HoraInicio)
+	if t.CruzaMedianoche() {
+		segundos += segundosPorDia
+	}
+	return time.Duration(segundos) * time.Second
+}
+
+// Ocurrencia retorna el inicio y el fin del turno que empieza en la fecha de calendario de dia,
+// en la zona horaria loc
+func (t *Turno) Ocurrencia(dia time.Time, loc *time.Location) (time.Time, time.Time) {
+	inicio := t.HoraInicio.En(dia, loc)
+	return inicio, inicio.Add(t.Duracion())
+}
+
+// AplicaEn indica si el turno se dicta en la fecha de calendario de dia: por día de la
+// semana y dentro de su vigencia
+func (t *Turno) AplicaEn(dia time.Time) bool {
+	if !t.DiasSemana.Incluye(dia.Weekday()) {
+		return false
+	}
+	fecha := FechaDe(dia)
+	if t.VigenteDesde != nil && fecha.Antes(*t.VigenteDesde) {
+		return false
+	}
+	if t.VigenteHasta != nil && t.VigenteHasta.Antes(fecha) {
+		return false
+	}
+	return true
+}
+
+// MetodoSeleccionTurno indica cómo se eligió el turno de un registro
+type MetodoSeleccionTurno string
+
+const (
+	// SeleccionManual: el turno vino en el request
+	SeleccionManual MetodoSeleccionTurno = "manual"
+	// SeleccionIngresoAbierto: la salida toma el turno del ingreso del docente que sigue abierto
+	SeleccionIngresoAbierto MetodoSeleccionTurno = "ingreso_abierto"
+	// SeleccionProximo: el turno empieza dentro de la ventana de detección
+	SeleccionProximo MetodoSeleccionTurno = "proximo"
+	// SeleccionEnCurso: el turno está en curso (o terminó hace menos que su tolerancia)
+	SeleccionEnCurso MetodoSeleccionTurno = "en_curso"
+)
+
+// SeleccionTurno describe el turno asignado a un registro y el motivo
+type SeleccionTurno struct {
+	Metodo      MetodoSeleccionTurno `json:"metodo"`
+	TurnoID     int                  `json:"turno_id"`
+	TurnoNombre string               `json:"turno_nombre"`
+	Detalle     string               `json:"detalle"`
 }
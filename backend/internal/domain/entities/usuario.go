@@ -26,6 +26,18 @@ func (r Rol) IsValid() bool {
 	return RolesValidos[r]
 }
 
+// OrigenUsuario indica quién valida la contraseña del usuario
+type OrigenUsuario string
+
+const (
+	OrigenLocal OrigenUsuario = "local" // Hash bcrypt en usuarios.password
+	OrigenLDAP  OrigenUsuario = "ldap"  // Bind contra el directorio institucional
+)
+
+func (o OrigenUsuario) IsValid() bool {
+	return o == OrigenLocal || o == OrigenLDAP
+}
+
 type Usuario struct {
 	ID             int       `json:"id"`
 	Username       string    `json:"username"`
@@ -36,4 +48,9 @@ type Usuario struct {
 	Activo         bool      `json:"activo"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+
+	// MustChangePassword restringe la sesión al cambio de contraseña hasta que el usuario elija la suya
+	MustChangePassword bool `json:"must_change_password"`
+	// Origen local o ldap; la contraseña de una cuenta ldap se administra en el directorio
+	Origen OrigenUsuario `json:"origen"`
 }
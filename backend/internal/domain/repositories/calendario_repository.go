@@ -0,0 +1,18 @@
+package repositories
+
+import "github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+
+type CalendarioRepository interface {
+	FindPeriodos() ([]*entities.PeriodoAcademico, error)
+	FindPeriodoByID(id int) (*entities.PeriodoAcademico, error)
+	CreatePeriodo(periodo *entities.PeriodoAcademico) error
+	UpdatePeriodo(periodo *entities.PeriodoAcademico) error
+	DeletePeriodo(id int) error
+
+	// FindEventos retorna los eventos que se cruzan con el rango [desde, hasta]; nil no limita
+	FindEventos(desde, hasta *entities.Fecha) ([]*entities.EventoCalendario, error)
+	FindEventoByID(id int) (*entities.EventoCalendario, error)
+	CreateEvento(evento *entities.EventoCalendario) error
+	UpdateEvento(evento *entities.EventoCalendario) error
+	DeleteEvento(id int) error
+}
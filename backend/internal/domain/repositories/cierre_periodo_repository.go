@@ -0,0 +1,17 @@
+package repositories
+
+import "github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+
+// CierrePeriodoRepository guarda el historial de cierres y reaperturas de meses; solo se agregan filas
+type CierrePeriodoRepository interface {
+	CreateMovimiento(movimiento *entities.MovimientoCierre) error
+
+	// EstaCerrado indica si el último movimiento del mes es un cierre
+	EstaCerrado(mes entities.MesContable) (bool, error)
+
+	// FindUltimosMovimientos retorna el último movimiento de cada mes con historial, del más reciente al más antiguo
+	FindUltimosMovimientos() ([]*entities.MovimientoCierre, error)
+
+	// FindHistorial retorna los movimientos del mes en orden cronológico
+	FindHistorial(mes entities.MesContable) ([]*entities.MovimientoCierre, error)
+}
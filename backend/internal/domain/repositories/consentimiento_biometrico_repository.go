@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+// ErrConsentimientoNoEncontrado indica que el docente no tiene un consentimiento vigente
+var ErrConsentimientoNoEncontrado = errors.New("consentimiento no encontrado")
+
+type ConsentimientoBiometricoRepository interface {
+	Create(consentimiento *entities.ConsentimientoBiometrico) error
+	// FindVigente retorna el consentimiento no revocado del docente
+	FindVigente(docenteID int) (*entities.ConsentimientoBiometrico, error)
+	// FindByDocente retorna el historial de consentimientos del docente, del más reciente al más antiguo
+	FindByDocente(docenteID int) ([]*entities.ConsentimientoBiometrico, error)
+	Revocar(id int, revocadoPor int, motivo *string) error
+}
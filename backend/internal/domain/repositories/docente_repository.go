@@ -1,17 +1,40 @@
 package repositories
 
-import "github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+import (
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
 
 type DocenteRepository interface {
 	FindByID(id int) (*entities.Docente, error)
 	FindByCI(ci int64) (*entities.Docente, error)
+	// FindByUsuarioID retorna el docente vinculado a la cuenta de usuario
+	FindByUsuarioID(usuarioID int) (*entities.Docente, error)
 	SearchByCI(ciPartial string) ([]*entities.Docente, error)
 	FindAll() ([]*entities.Docente, error)
 	Create(docente *entities.Docente) error
 	Update(docente *entities.Docente) error
 	Delete(id int) error
-	AddFaceDescriptor(id int, descriptorJSON string) error
-	GetFaceDescriptors(id int) ([]string, error)
-	RemoveFaceDescriptor(id int, index int) error
-	ClearFaceDescriptors(id int) error
+	AddFaceDescriptor(rostro *entities.RostroDocente) error
+	GetFaceDescriptors(docenteID int) ([]*entities.RostroDocente, error)
+	// GetAllFaceDescriptors retorna las muestras vigentes de todos los docentes activos
+	GetAllFaceDescriptors() ([]*entities.RostroDocente, error)
+	RemoveFaceDescriptor(docenteID int, rostroID int) error
+	ClearFaceDescriptors(docenteID int) error
+	// ClearOutdatedFaceDescriptors elimina las muestras del docente marcadas para re-enrolamiento
+	ClearOutdatedFaceDescriptors(docenteID int) error
+	// FlagOutdatedFaceDescriptors marca para re-enrolamiento las muestras de otro modelo
+	// y retorna cuántas se marcaron
+	FlagOutdatedFaceDescriptors(modeloVersion string) (int64, error)
+	// FindFaceDescriptorsToEncrypt retorna las muestras sin cifrar y, si includeOtherKeys,
+	// las cifradas con una clave distinta de activeKeyID
+	FindFaceDescriptorsToEncrypt(activeKeyID string, includeOtherKeys bool) ([]*entities.RostroDocente, error)
+	// UpdateFaceDescriptorCipher reemplaza el descriptor de la muestra por su versión cifrada
+	UpdateFaceDescriptorCipher(rostroID int, descriptorCifrado []byte, claveID string) error
+	// FindRequiringReenrollment retorna los docentes activos con muestras marcadas para re-enrolamiento
+	FindRequiringReenrollment() ([]*entities.Docente, error)
+	// FindInactiveWithFaceDescriptors retorna los docentes desactivados antes de inactivoDesde
+	// que aún conservan muestras faciales
+	FindInactiveWithFaceDescriptors(inactivoDesde time.Time) ([]*entities.Docente, error)
 }
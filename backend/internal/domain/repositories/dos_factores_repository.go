@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+// ErrDosFactoresNoConfigurado indica que el usuario nunca inició el enrolamiento
+var ErrDosFactoresNoConfigurado = errors.New("autenticación en dos pasos no configurada")
+
+type DosFactoresRepository interface {
+	FindByUsuarioID(usuarioID int) (*entities.DosFactores, error)
+	// Guardar inicia o reinicia el enrolamiento con un secreto nuevo, inactivo hasta Activar
+	Guardar(dosFactores *entities.DosFactores) error
+	// Activar confirma el enrolamiento y reemplaza los códigos de recuperación por los hashes dados
+	Activar(usuarioID int, paso int64, hashesRecuperacion []string) error
+	// Delete desactiva la autenticación en dos pasos y elimina los códigos de recuperación
+	Delete(usuarioID int) error
+	// FindConOtraClave retorna las configuraciones cuyo secreto no está cifrado con la clave activa
+	FindConOtraClave(claveActiva string) ([]*entities.DosFactores, error)
+	// ActualizarCifrado reemplaza el secreto cifrado sin cambiar el estado del enrolamiento; no
+	// hace nada si mientras tanto el secreto cambió de clave (por ejemplo, por un re-enrolamiento)
+	ActualizarCifrado(usuarioID int, claveAnterior string, secretoCifrado []byte, claveID string) error
+	// RegistrarPaso acepta el paso TOTP solo si es posterior al último aceptado, de forma atómica
+	RegistrarPaso(usuarioID int, paso int64) (bool, error)
+
+	// UsarCodigoRecuperacion consume el código si existe y no fue usado
+	UsarCodigoRecuperacion(usuarioID int, hash string) (bool, error)
+	ReemplazarCodigosRecuperacion(usuarioID int, hashes []string) error
+	CountCodigosRecuperacion(usuarioID int) (int, error)
+
+	FindPoliticas() ([]*entities.PoliticaDosFactores, error)
+	FindPolitica(rol entities.Rol) (*entities.PoliticaDosFactores, error)
+	SavePolitica(politica *entities.PoliticaDosFactores) error
+}
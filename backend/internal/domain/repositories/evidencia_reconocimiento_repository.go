@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+type EvidenciaReconocimientoRepository interface {
+	Create(evidencia *entities.EvidenciaReconocimiento) error
+	// FindByRegistro retorna la evidencia (con su imagen cifrada) del intento vinculado al registro
+	FindByRegistro(registroID int) (*entities.EvidenciaReconocimiento, error)
+	// DeleteBefore elimina las evidencias creadas antes de la fecha y retorna las eliminadas
+	// para que se borren sus archivos
+	DeleteBefore(antes time.Time) ([]*entities.EvidenciaReconocimiento, error)
+	// DeleteByDocente elimina todas las evidencias del docente y retorna las eliminadas
+	DeleteByDocente(docenteID int) ([]*entities.EvidenciaReconocimiento, error)
+}
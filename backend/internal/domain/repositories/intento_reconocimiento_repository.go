@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+// ErrVerificacionUtilizada indica que el token de esa verificación facial ya registró un ingreso
+var ErrVerificacionUtilizada = errors.New("la verificación facial ya fue utilizada")
+
+// FiltroIntentosReconocimiento agrupa los criterios de búsqueda de intentos de reconocimiento
+type FiltroIntentosReconocimiento struct {
+	Desde           *time.Time
+	Hasta           *time.Time
+	Modo            *entities.ModoReconocimiento
+	Decision        *entities.DecisionReconocimiento
+	DocenteID       *int
+	SoloIncorrectos bool
+	Limite          int
+}
+
+type IntentoReconocimientoRepository interface {
+	FindByID(id int) (*entities.IntentoReconocimiento, error)
+	FindAll(filtro FiltroIntentosReconocimiento) ([]*entities.IntentoReconocimiento, error)
+	Create(intento *entities.IntentoReconocimiento) error
+	VincularRegistro(id int, registroID int) error
+	// ConsumirVerificacion marca como usada la verificación exitosa del docente; retorna
+	// ErrVerificacionUtilizada si ya lo estaba o si el intento no es una verificación del docente
+	ConsumirVerificacion(id int, docenteID int) error
+	// LiberarVerificacion deshace ConsumirVerificacion cuando el registro no se pudo crear
+	LiberarVerificacion(id int) error
+	MarcarIncorrecto(id int, marcadoPor int, docenteRealID *int, motivo string) error
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import "github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+
+// FiltroJustificaciones agrupa los criterios de búsqueda de justificaciones
+type FiltroJustificaciones struct {
+	DocenteID *int
+	Estado    *entities.EstadoJustificacion
+	Desde     *entities.Fecha // Fecha desde (inclusive)
+	Hasta     *entities.Fecha // Fecha hasta (inclusive)
+}
+
+type JustificacionRepository interface {
+	FindByID(id int) (*entities.Justificacion, error)
+	Find(filtro FiltroJustificaciones) ([]*entities.Justificacion, error)
+	// FindAprobadas retorna las justificaciones aprobadas del docente para la fecha, adjuntas o no a un registro
+	FindAprobadas(docenteID int, fecha entities.Fecha) ([]*entities.Justificacion, error)
+	Create(justificacion *entities.Justificacion) error
+	UpdateRevision(justificacion *entities.Justificacion) error
+	UpdateDocumento(justificacion *entities.Justificacion) error
+	Delete(id int) error
+}
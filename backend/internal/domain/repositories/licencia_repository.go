@@ -0,0 +1,19 @@
+package repositories
+
+import "github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+
+// FiltroLicencias agrupa los criterios de búsqueda de licencias
+type FiltroLicencias struct {
+	DocenteID *int
+	Desde     *entities.Fecha // Licencias que terminan desde esta fecha (inclusive)
+	Hasta     *entities.Fecha // Licencias que empiezan hasta esta fecha (inclusive)
+}
+
+type LicenciaRepository interface {
+	FindByID(id int) (*entities.Licencia, error)
+	// Find retorna las licencias que se cruzan con el rango del filtro
+	Find(filtro FiltroLicencias) ([]*entities.Licencia, error)
+	Create(licencia *entities.Licencia) error
+	Update(licencia *entities.Licencia) error
+	Delete(id int) error
+}
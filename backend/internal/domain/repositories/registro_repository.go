@@ -5,16 +5,31 @@ import (
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 )
 
+// FiltroSesiones agrupa los criterios de búsqueda de sesiones (ingreso + salida)
+type FiltroSesiones struct {
+	Desde        *time.Time // Hora de ingreso desde (inclusive)
+	Hasta        *time.Time // Hora de ingreso hasta (exclusive)
+	DocenteID    *int
+	IngresoID    *int
+	SoloAbiertas bool
+	SoloConLlave bool
+}
+
 type RegistroRepository interface {
 	FindByID(id int) (*entities.Registro, error)
 	FindAll() ([]*entities.Registro, error)
 	FindByDocente(docenteID int) ([]*entities.Registro, error)
 	FindByFecha(fecha time.Time) ([]*entities.Registro, error)
 	FindByDocenteYFecha(docenteID int, fecha time.Time) ([]*entities.Registro, error)
+	// FindByDocenteYRango retorna los registros del docente con fecha_hora en [desde, hasta)
+	FindByDocenteYRango(docenteID int, desde, hasta time.Time) ([]*entities.Registro, error)
 	FindRegistrosHoy() ([]*entities.Registro, error)
+	// Un ingreso está abierto mientras ninguna salida lo referencia
 	FindUltimoIngresoConLlave(docenteID int) (*entities.Registro, error)
-	// DocenteTieneLlave verifica si un docente tiene una llave específica (ingreso sin salida correspondiente)
-	DocenteTieneLlave(docenteID int, llaveID int) (bool, error)
+	FindIngresoAbierto(docenteID int) (*entities.Registro, error)
+	FindIngresoAbiertoConLlave(docenteID, llaveID int) (*entities.Registro, error)
+	FindSalidaDeIngreso(ingresoID int) (*entities.Registro, error)
+	FindSesiones(filtro FiltroSesiones) ([]*entities.Sesion, error)
 	Create(registro *entities.Registro) error
 	Update(registro *entities.Registro) error
 	Delete(id int) error
@@ -0,0 +1,17 @@
+package repositories
+
+import "github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+
+type ReporteDuplicadosRepository interface {
+	Create(reporte *entities.ReporteDuplicados) error
+	// Completar guarda los pares encontrados y marca el reporte como completado
+	Completar(reporte *entities.ReporteDuplicados, pares []*entities.ParDuplicado) error
+	MarcarError(id int, mensaje string) error
+	// MarcarInterrumpidos marca con error los reportes que quedaron en proceso (por ejemplo, tras un reinicio)
+	MarcarInterrumpidos() (int64, error)
+	// FindAll retorna los reportes más recientes, sin sus pares
+	FindAll(limite int) ([]*entities.ReporteDuplicados, error)
+	// FindByID retorna el reporte con sus pares
+	FindByID(id int) (*entities.ReporteDuplicados, error)
+	RevisarPar(parID int, estado entities.EstadoParDuplicado, revisadoPor int, nota *string) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+type RestablecimientoPasswordRepository interface {
+	Create(restablecimiento *entities.RestablecimientoPassword) error
+	FindByTokenHash(tokenHash string) (*entities.RestablecimientoPassword, error)
+	// Consumir marca el token como usado solo si sigue vigente, de forma atómica: de dos
+	// peticiones simultáneas con el mismo token, solo una lo consume
+	Consumir(id int, ahora time.Time) error
+	// DeletePendientes elimina los tokens sin usar del usuario
+	DeletePendientes(usuarioID int) error
+	// DeleteVencidos elimina los tokens que expiraron antes del instante dado, usados o no
+	DeleteVencidos(antes time.Time) (int64, error)
+}
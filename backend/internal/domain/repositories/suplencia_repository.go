@@ -0,0 +1,22 @@
+package repositories
+
+import "github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+
+// FiltroSuplencias agrupa los criterios de búsqueda de suplencias
+type FiltroSuplencias struct {
+	DocenteID  *int // Titular o suplente
+	TitularID  *int
+	SuplenteID *int
+	TurnoID    *int
+	Desde      *entities.Fecha
+	Hasta      *entities.Fecha
+}
+
+type SuplenciaRepository interface {
+	FindByID(id int) (*entities.Suplencia, error)
+	Find(filtro FiltroSuplencias) ([]*entities.Suplencia, error)
+	Create(suplencia *entities.Suplencia) error
+	Delete(id int) error
+	// CountRegistros cuenta los registros vinculados a la suplencia
+	CountRegistros(id int) (int, error)
+}
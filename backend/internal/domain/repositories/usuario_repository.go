@@ -9,4 +9,9 @@ type UsuarioRepository interface {
 	Update(usuario *entities.Usuario) error
 	Delete(id int) error
 	FindAll() ([]*entities.Usuario, error)
+	// UpdatePassword reemplaza el hash de la contraseña y lo agrega al historial, del que solo
+	// conserva las últimas historial entradas
+	UpdatePassword(usuarioID int, password string, mustChange bool, historial int) error
+	// FindPasswordHistorial retorna los últimos hashes del usuario, del más reciente al más antiguo
+	FindPasswordHistorial(usuarioID int, limite int) ([]string, error)
 }
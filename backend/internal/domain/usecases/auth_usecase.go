@@ -1,62 +1,304 @@
 package usecases
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
 
 	"golang.org/x/crypto/bcrypt"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/autenticacion"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
 )
 
 type AuthUseCase struct {
-	usuarioRepo repositories.UsuarioRepository
+	usuarioRepo        repositories.UsuarioRepository
+	dosFactoresUseCase *DosFactoresUseCase
+	local              autenticacion.Autenticador
+	directorio         autenticacion.Autenticador
+	provisionar        bool
 }
 
-func NewAuthUseCase(usuarioRepo repositories.UsuarioRepository) *AuthUseCase {
-	return &AuthUseCase{usuarioRepo: usuarioRepo}
+// NewAuthUseCase crea el caso de uso. directorio es el autenticador LDAP, o nil si
+// AUTH_DRIVER=local; con provisionar, las cuentas del directorio que no existen en usuarios se
+// crean en su primer ingreso
+func NewAuthUseCase(
+	usuarioRepo repositories.UsuarioRepository,
+	dosFactoresUseCase *DosFactoresUseCase,
+	directorio autenticacion.Autenticador,
+	provisionar bool,
+) *AuthUseCase {
+	return &AuthUseCase{
+		usuarioRepo:        usuarioRepo,
+		dosFactoresUseCase: dosFactoresUseCase,
+		local:              autenticacion.NewLocal(usuarioRepo),
+		directorio:         directorio,
+		provisionar:        provisionar,
+	}
+}
+
+// ResultadoLogin es el resultado de un paso del login. Con segundo factor activo, el primer paso
+// solo entrega DesafioToken, que se canjea por el token de sesión en VerificarSegundoFactor
+type ResultadoLogin struct {
+	Token              string
+	DesafioToken       string
+	Usuario            *entities.Usuario
+	EnrolarDosFactores bool // El rol exige segundo factor y el token solo sirve para activarlo
+}
+
+// ErrPasswordEnDirectorio indica que la cuenta se autentica contra el directorio, donde se cambia
+// su contraseña
+var ErrPasswordEnDirectorio = errors.New("la contraseña de esta cuenta se administra en el directorio institucional")
+
+// Login verifica usuario y contraseña. Si el usuario activó el segundo factor retorna solo el
+// token de desafío; si no, el token de sesión
+func (uc *AuthUseCase) Login(username, password string) (*ResultadoLogin, error) {
+	usuario, err := uc.autenticar(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar que el usuario esté activo
+	if !usuario.Activo {
+		return nil, fmt.Errorf("usuario desactivado")
+	}
+
+	activo, err := uc.dosFactoresUseCase.Activo(usuario.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando el segundo factor: %w", err)
+	}
+	if activo {
+		desafio, err := jwt.GenerateDesafioToken(usuario.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error generando token: %w", err)
+		}
+		return &ResultadoLogin{DesafioToken: desafio, Usuario: usuario}, nil
+	}
+
+	return uc.emitirSesion(usuario)
+}
+
+// VerificarSegundoFactor canjea el token de desafío y un código TOTP o de recuperación por el
+// token de sesión
+func (uc *AuthUseCase) VerificarSegundoFactor(desafioToken, codigo string) (*ResultadoLogin, error) {
+	usuarioID, err := jwt.ValidateDesafioToken(desafioToken)
+	if err != nil {
+		return nil, fmt.Errorf("el desafío es inválido o expiró; inicie sesión nuevamente")
+	}
+	usuario, err := uc.usuarioRepo.FindByID(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if !usuario.Activo {
+		return nil, fmt.Errorf("usuario desactivado")
+	}
+	if err := uc.dosFactoresUseCase.Verificar(usuarioID, codigo); err != nil {
+		return nil, err
+	}
+	return uc.emitirSesion(usuario)
 }
 
-// dummyHash es un hash bcrypt pre-calculado usado para prevenir timing attacks
-// cuando el usuario no existe, aún ejecutamos bcrypt.CompareHashAndPassword
-// para que el tiempo de respuesta sea consistente
-var dummyHash = []byte("$2a$10$dummyhashfortimingatttackprevention1234567890")
+// RenovarSesion emite un token nuevo con el estado actual del usuario, por ejemplo tras activar
+// el segundo factor que exigía su rol
+func (uc *AuthUseCase) RenovarSesion(usuarioID int) (*ResultadoLogin, error) {
+	usuario, err := uc.usuarioRepo.FindByID(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	if !usuario.Activo {
+		return nil, fmt.Errorf("usuario desactivado")
+	}
+	return uc.emitirSesion(usuario)
+}
 
-func (uc *AuthUseCase) Login(username, password string) (string, *entities.Usuario, error) {
+// autenticar valida las credenciales con el autenticador del origen de la cuenta. Las cuentas
+// locales (como el administrador inicial) conservan su contraseña aun con AUTH_DRIVER=ldap; un
+// username que no existe se busca en el directorio, si hay uno configurado
+func (uc *AuthUseCase) autenticar(username, password string) (*entities.Usuario, error) {
 	usuario, err := uc.usuarioRepo.FindByUsername(username)
+	if err != nil {
+		usuario = nil
+	}
 
-	// Siempre ejecutar bcrypt.CompareHashAndPassword para prevenir timing attacks
-	// Si el usuario no existe, usamos un hash dummy para que el tiempo sea consistente
-	var hashToCompare []byte
-	if err != nil || usuario == nil {
-		hashToCompare = dummyHash
-	} else {
-		hashToCompare = []byte(usuario.Password)
+	if (usuario != nil && usuario.Origen == entities.OrigenLDAP) || (usuario == nil && uc.directorio != nil) {
+		return uc.autenticarEnDirectorio(username, password, usuario)
+	}
+	// Sin usuario, Local igual compara contra un hash para que el tiempo de respuesta no lo revele
+	if _, err := uc.local.Autenticar(username, password); err != nil || usuario == nil {
+		return nil, fmt.Errorf("credenciales inválidas")
 	}
+	return usuario, nil
+}
 
-	// Ejecutar comparación siempre (incluso si usuario no existe)
-	bcryptErr := bcrypt.CompareHashAndPassword(hashToCompare, []byte(password))
+func (uc *AuthUseCase) autenticarEnDirectorio(username, password string, usuario *entities.Usuario) (*entities.Usuario, error) {
+	if uc.directorio == nil {
+		log.Printf("[WARN] El usuario %d (%s) se autentica contra el directorio, pero AUTH_DRIVER no es ldap", usuario.ID, usuario.Username)
+		return nil, fmt.Errorf("credenciales inválidas")
+	}
 
-	// Ahora verificamos los errores después de la comparación
-	if err != nil || usuario == nil {
-		return "", nil, fmt.Errorf("credenciales inválidas")
+	identidad, err := uc.directorio.Autenticar(username, password)
+	if err != nil {
+		if !errors.Is(err, autenticacion.ErrCredencialesInvalidas) {
+			log.Printf("[ERROR] Error autenticando %q contra el directorio: %v", username, err)
+		}
+		return nil, fmt.Errorf("credenciales inválidas")
 	}
 
-	if bcryptErr != nil {
-		return "", nil, fmt.Errorf("credenciales inválidas")
+	// El directorio no distingue mayúsculas: la cuenta se busca también con el username que él
+	// retorna, para no duplicarla si el usuario lo escribió distinto
+	if usuario == nil && identidad.Username != username {
+		usuario, _ = uc.usuarioRepo.FindByUsername(identidad.Username)
+	}
+	if usuario == nil {
+		return uc.provisionarCuenta(identidad)
+	}
+	if usuario.Origen != entities.OrigenLDAP {
+		log.Printf("[SECURITY] %q se autenticó en el directorio como %s, que es una cuenta local: se rechaza", username, identidad.Username)
+		return nil, fmt.Errorf("credenciales inválidas")
 	}
+	return uc.sincronizarRol(usuario, identidad)
+}
 
-	// Verificar que el usuario esté activo
+// provisionarCuenta crea la cuenta de un usuario del directorio en su primer ingreso. Exige que
+// pertenezca a un grupo mapeado a un rol
+func (uc *AuthUseCase) provisionarCuenta(identidad *autenticacion.Identidad) (*entities.Usuario, error) {
+	if !uc.provisionar {
+		log.Printf("[WARN] %s se autenticó en el directorio pero no tiene cuenta en el sistema (LDAP_PROVISION_USERS=false)", identidad.Username)
+		return nil, fmt.Errorf("credenciales inválidas")
+	}
+	if identidad.Rol == "" {
+		log.Printf("[WARN] %s (%s) se autenticó en el directorio pero no pertenece a ningún grupo mapeado a un rol", identidad.Username, identidad.DN)
+		return nil, fmt.Errorf("credenciales inválidas")
+	}
+	if len(identidad.Username) > 50 {
+		log.Printf("[WARN] El username %q del directorio excede 50 caracteres", identidad.Username)
+		return nil, fmt.Errorf("credenciales inválidas")
+	}
+
+	// La contraseña local nunca se usa: la valida el directorio
+	password, err := passwordInutilizable()
+	if err != nil {
+		return nil, fmt.Errorf("error generando la cuenta: %w", err)
+	}
+	nombre := identidad.NombreCompleto
+	if nombre == "" {
+		nombre = identidad.Username
+	}
+	if runas := []rune(nombre); len(runas) > 100 {
+		nombre = string(runas[:100])
+	}
+	usuario := &entities.Usuario{
+		Username:       identidad.Username,
+		Password:       password,
+		Rol:            identidad.Rol,
+		NombreCompleto: nombre,
+		Email:          identidad.Email,
+		Activo:         true,
+		Origen:         entities.OrigenLDAP,
+	}
+	if err := uc.usuarioRepo.Create(usuario); err != nil {
+		// También ocurre si existe una cuenta desactivada con ese username
+		log.Printf("[ERROR] No se pudo crear la cuenta de %s desde el directorio: %v", identidad.Username, err)
+		return nil, fmt.Errorf("credenciales inválidas")
+	}
+
+	log.Printf("[AUDIT] Cuenta %d (%s) creada desde el directorio con rol %s (%s)", usuario.ID, usuario.Username, usuario.Rol, identidad.DN)
+	return usuario, nil
+}
+
+// sincronizarRol aplica el rol que dan los grupos del directorio. Si el usuario ya no pertenece a
+// ningún grupo mapeado se rechaza el ingreso, como en la provisión: quitarlo de los grupos en el
+// directorio revoca su acceso
+func (uc *AuthUseCase) sincronizarRol(usuario *entities.Usuario, identidad *autenticacion.Identidad) (*entities.Usuario, error) {
+	if identidad.Rol == "" {
+		log.Printf("[SECURITY] El usuario %d (%s, %s) ya no pertenece a ningún grupo mapeado a un rol: se rechaza el ingreso", usuario.ID, usuario.Username, identidad.DN)
+		return nil, fmt.Errorf("credenciales inválidas")
+	}
+	if identidad.Rol == usuario.Rol {
+		return usuario, nil
+	}
+
+	anterior := usuario.Rol
+	usuario.Rol = identidad.Rol
+	if err := uc.usuarioRepo.Update(usuario); err != nil {
+		return nil, fmt.Errorf("error actualizando el rol desde el directorio: %w", err)
+	}
+	log.Printf("[AUDIT] Rol del usuario %d (%s) actualizado desde el directorio: %s -> %s", usuario.ID, usuario.Username, anterior, usuario.Rol)
+	return usuario, nil
+}
+
+// passwordInutilizable es el hash de una contraseña aleatoria que nadie conoce
+func passwordInutilizable() (string, error) {
+	aleatorio := make([]byte, 32)
+	if _, err := rand.Read(aleatorio); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(base64.RawStdEncoding.EncodeToString(aleatorio)), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// emitirSesion genera el token de sesión, restringido a activar el segundo factor si el rol lo
+// exige y el usuario aún no lo hizo
+func (uc *AuthUseCase) emitirSesion(usuario *entities.Usuario) (*ResultadoLogin, error) {
+	// El cambio de contraseña obligatorio no aplica a las cuentas del directorio
+	if usuario.Origen == entities.OrigenLDAP {
+		usuario.MustChangePassword = false
+	}
+	// Con el cambio de contraseña pendiente el token solo sirve para cambiarla; el enrolamiento
+	// se exige en el token que emite CambiarPassword
+	enrolar := false
+	if !usuario.MustChangePassword {
+		var err error
+		if enrolar, err = uc.dosFactoresUseCase.EnrolamientoPendiente(usuario); err != nil {
+			return nil, fmt.Errorf("error consultando el segundo factor: %w", err)
+		}
+	}
+	token, err := jwt.GenerateToken(usuario, enrolar)
+	if err != nil {
+		return nil, fmt.Errorf("error generando token: %w", err)
+	}
+	return &ResultadoLogin{Token: token, Usuario: usuario, EnrolarDosFactores: enrolar}, nil
+}
+
+// ErrPasswordActualIncorrecta indica que la contraseña actual enviada al cambiarla no coincide
+var ErrPasswordActualIncorrecta = errors.New("la contraseña actual es incorrecta")
+
+// CambiarPassword reemplaza la contraseña del propio usuario tras verificar la actual. La nueva no
+// puede coincidir con ninguna de las últimas del historial. Retorna un token nuevo, ya sin la
+// restricción de cambio obligatorio
+func (uc *AuthUseCase) CambiarPassword(usuarioID int, actual, nueva string) (*ResultadoLogin, error) {
+	usuario, err := uc.usuarioRepo.FindByID(usuarioID)
+	if err != nil {
+		return nil, err
+	}
 	if !usuario.Activo {
-		return "", nil, fmt.Errorf("usuario desactivado")
+		return nil, fmt.Errorf("usuario desactivado")
+	}
+	if usuario.Origen == entities.OrigenLDAP {
+		return nil, ErrPasswordEnDirectorio
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(usuario.Password), []byte(actual)); err != nil {
+		return nil, ErrPasswordActualIncorrecta
+	}
+
+	if err := verificarPasswordNoReutilizada(uc.usuarioRepo, usuario, nueva); err != nil {
+		return nil, err
 	}
 
-	token, err := jwt.GenerateToken(usuario)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(nueva), bcrypt.DefaultCost)
 	if err != nil {
-		return "", nil, fmt.Errorf("error generando token: %w", err)
+		return nil, fmt.Errorf("error hasheando contraseña: %w", err)
+	}
+	if err := uc.usuarioRepo.UpdatePassword(usuarioID, string(hashedPassword), false, passwordsEnHistorial); err != nil {
+		return nil, fmt.Errorf("error actualizando contraseña: %w", err)
 	}
+	usuario.Password = string(hashedPassword)
+	usuario.MustChangePassword = false
 
-	return token, usuario, nil
+	return uc.emitirSesion(usuario)
 }
 
 func (uc *AuthUseCase) Register(username, password string, rol entities.Rol) (*entities.Usuario, error) {
@@ -70,6 +312,7 @@ func (uc *AuthUseCase) Register(username, password string, rol entities.Rol) (*e
 		Password: string(hashedPassword),
 		Rol:      rol,
 		Activo:   true,
+		Origen:   entities.OrigenLocal,
 	}
 
 	if err := uc.usuarioRepo.Create(usuario); err != nil {
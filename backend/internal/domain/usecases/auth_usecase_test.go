@@ -0,0 +1,223 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/autenticacion"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// directorioFalso acepta cualquier contraseña y retorna siempre la misma identidad
+type directorioFalso struct {
+	identidad *autenticacion.Identidad
+}
+
+func (d directorioFalso) Autenticar(username, password string) (*autenticacion.Identidad, error) {
+	return d.identidad, nil
+}
+
+// usuariosEnMemoria busca el username exacto, como la consulta del repositorio
+type usuariosEnMemoria struct {
+	repositories.UsuarioRepository
+	usuarios []*entities.Usuario
+	creados  []*entities.Usuario
+}
+
+func (r *usuariosEnMemoria) FindByUsername(username string) (*entities.Usuario, error) {
+	for _, usuario := range r.usuarios {
+		if usuario.Username == username {
+			return usuario, nil
+		}
+	}
+	return nil, errors.New("usuario no encontrado")
+}
+
+func (r *usuariosEnMemoria) Create(usuario *entities.Usuario) error {
+	usuario.ID = 100 + len(r.creados)
+	r.creados = append(r.creados, usuario)
+	return nil
+}
+
+func (r *usuariosEnMemoria) FindByID(id int) (*entities.Usuario, error) {
+	for _, usuario := range r.usuarios {
+		if usuario.ID == id {
+			return usuario, nil
+		}
+	}
+	return nil, errors.New("usuario no encontrado")
+}
+
+func (r *usuariosEnMemoria) Update(usuario *entities.Usuario) error {
+	return nil
+}
+
+func (r *usuariosEnMemoria) UpdatePassword(usuarioID int, password string, mustChange bool, historial int) error {
+	usuario, err := r.FindByID(usuarioID)
+	if err != nil {
+		return err
+	}
+	usuario.Password, usuario.MustChangePassword = password, mustChange
+	return nil
+}
+
+func (r *usuariosEnMemoria) FindPasswordHistorial(usuarioID int, limite int) ([]string, error) {
+	return nil, nil
+}
+
+func TestAutenticarEnDirectorio(t *testing.T) {
+	admin := func() *entities.Usuario {
+		return &entities.Usuario{ID: 1, Username: "admin", Rol: entities.RolAdministrador, Activo: true, Origen: entities.OrigenLocal}
+	}
+	jperez := func() *entities.Usuario {
+		return &entities.Usuario{ID: 2, Username: "jperez", Rol: entities.RolDocente, Activo: true, Origen: entities.OrigenLDAP}
+	}
+
+	casos := []struct {
+		nombre      string
+		username    string
+		existente   *entities.Usuario // Cuenta que autenticar encontró con el username escrito
+		usuarios    []*entities.Usuario
+		identidad   autenticacion.Identidad
+		provisionar bool
+		esperaError bool
+		esperaRol   entities.Rol
+		esperaCrear bool
+	}{
+		{
+			// "ADMIN" no existe tal cual, pero el directorio lo devuelve como "admin", que es local
+			nombre:      "el directorio no puede tomar una cuenta local",
+			username:    "ADMIN",
+			usuarios:    []*entities.Usuario{admin()},
+			identidad:   autenticacion.Identidad{Username: "admin", Rol: entities.RolAdministrador, DN: "uid=admin,dc=uni,dc=edu"},
+			provisionar: true,
+			esperaError: true,
+		},
+		{
+			nombre:      "provisión sin grupo mapeado",
+			username:    "mrojas",
+			identidad:   autenticacion.Identidad{Username: "mrojas", DN: "uid=mrojas,dc=uni,dc=edu"},
+			provisionar: true,
+			esperaError: true,
+		},
+		{
+			nombre:      "provisión desactivada",
+			username:    "mrojas",
+			identidad:   autenticacion.Identidad{Username: "mrojas", Rol: entities.RolDocente, DN: "uid=mrojas,dc=uni,dc=edu"},
+			provisionar: false,
+			esperaError: true,
+		},
+		{
+			nombre:      "provisión con grupo mapeado",
+			username:    "mrojas",
+			identidad:   autenticacion.Identidad{Username: "mrojas", NombreCompleto: "María Rojas", Rol: entities.RolBecario, DN: "uid=mrojas,dc=uni,dc=edu"},
+			provisionar: true,
+			esperaRol:   entities.RolBecario,
+			esperaCrear: true,
+		},
+		{
+			nombre:      "cuenta del directorio escrita en mayúsculas no se duplica",
+			username:    "JPEREZ",
+			usuarios:    []*entities.Usuario{jperez()},
+			identidad:   autenticacion.Identidad{Username: "jperez", Rol: entities.RolDocente, DN: "uid=jperez,dc=uni,dc=edu"},
+			provisionar: true,
+			esperaRol:   entities.RolDocente,
+		},
+		{
+			// Quitarlo de los grupos en el directorio revoca el acceso aunque conserve su rol en el sistema
+			nombre:      "cuenta existente sin grupo mapeado",
+			username:    "jperez",
+			existente:   &entities.Usuario{ID: 2, Username: "jperez", Rol: entities.RolAdministrador, Activo: true, Origen: entities.OrigenLDAP},
+			identidad:   autenticacion.Identidad{Username: "jperez", DN: "uid=jperez,dc=uni,dc=edu"},
+			provisionar: true,
+			esperaError: true,
+		},
+		{
+			nombre:      "el rol se sincroniza con los grupos",
+			username:    "jperez",
+			existente:   jperez(),
+			identidad:   autenticacion.Identidad{Username: "jperez", Rol: entities.RolJefeCarrera, DN: "uid=jperez,dc=uni,dc=edu"},
+			provisionar: true,
+			esperaRol:   entities.RolJefeCarrera,
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			repo := &usuariosEnMemoria{usuarios: c.usuarios}
+			identidad := c.identidad
+			uc := NewAuthUseCase(repo, nil, directorioFalso{identidad: &identidad}, c.provisionar)
+
+			usuario, err := uc.autenticarEnDirectorio(c.username, "secreto", c.existente)
+			if c.esperaError {
+				if err == nil {
+					t.Fatalf("se esperaba error, se autenticó como %s (%s)", usuario.Username, usuario.Rol)
+				}
+				if !strings.Contains(err.Error(), "credenciales inválidas") {
+					t.Errorf("el error %q revela el motivo del rechazo", err)
+				}
+				if len(repo.creados) != 0 {
+					t.Errorf("se crearon %d cuentas", len(repo.creados))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if usuario.Rol != c.esperaRol {
+				t.Errorf("rol = %s, se esperaba %s", usuario.Rol, c.esperaRol)
+			}
+			if creado := len(repo.creados) == 1; creado != c.esperaCrear {
+				t.Errorf("cuentas creadas = %d", len(repo.creados))
+			}
+			if c.esperaCrear && usuario.Origen != entities.OrigenLDAP {
+				t.Errorf("origen = %s, se esperaba ldap", usuario.Origen)
+			}
+		})
+	}
+}
+
+// Una cuenta creada por el administrador, con un rol que exige segundo factor, debe poder cambiar
+// su contraseña temporal y después activar el segundo factor
+func TestPrimerIngresoConPasswordTemporalYSegundoFactor(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("Temporal#2024"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usuarios := &usuariosEnMemoria{usuarios: []*entities.Usuario{{
+		ID: 7, Username: "jefe", Password: string(hash), Rol: entities.RolJefeCarrera,
+		Activo: true, Origen: entities.OrigenLocal, MustChangePassword: true,
+	}}}
+	dosFactores := &dosFactoresEnMemoria{requeridos: map[entities.Rol]bool{entities.RolJefeCarrera: true}}
+	uc := NewAuthUseCase(usuarios, NewDosFactoresUseCase(dosFactores, usuarios, nil, nil), nil, false)
+
+	login, err := uc.Login("jefe", "Temporal#2024")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := jwt.ValidateToken(login.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.MustChangePassword || claims.EnrolarDosFactores {
+		t.Fatalf("token del login: must_change_password=%t enrolar_2fa=%t, se esperaba solo el cambio de contraseña",
+			claims.MustChangePassword, claims.EnrolarDosFactores)
+	}
+
+	cambio, err := uc.CambiarPassword(7, "Temporal#2024", "Definitiva#2024")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err = jwt.ValidateToken(cambio.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.MustChangePassword || !claims.EnrolarDosFactores || !cambio.EnrolarDosFactores {
+		t.Errorf("token tras el cambio: must_change_password=%t enrolar_2fa=%t, se esperaba solo el enrolamiento",
+			claims.MustChangePassword, claims.EnrolarDosFactores)
+	}
+}
@@ -0,0 +1,255 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+// MaxDiasConsultaCalendario limita el rango de GET /calendario/dias y de los reportes
+const MaxDiasConsultaCalendario = 366
+
+type CalendarioUseCase struct {
+	calendarioRepo repositories.CalendarioRepository
+	turnoRepo      repositories.TurnoRepository
+}
+
+func NewCalendarioUseCase(calendarioRepo repositories.CalendarioRepository, turnoRepo repositories.TurnoRepository) *CalendarioUseCase {
+	return &CalendarioUseCase{calendarioRepo: calendarioRepo, turnoRepo: turnoRepo}
+}
+
+// ==================== PERIODOS ====================
+
+func (uc *CalendarioUseCase) GetPeriodos() ([]*entities.PeriodoAcademico, error) {
+	return uc.calendarioRepo.FindPeriodos()
+}
+
+func (uc *CalendarioUseCase) GetPeriodo(id int) (*entities.PeriodoAcademico, error) {
+	return uc.calendarioRepo.FindPeriodoByID(id)
+}
+
+func (uc *CalendarioUseCase) CreatePeriodo(periodo *entities.PeriodoAcademico) error {
+	if err := uc.validarPeriodo(periodo); err != nil {
+		return err
+	}
+	return uc.calendarioRepo.CreatePeriodo(periodo)
+}
+
+func (uc *CalendarioUseCase) UpdatePeriodo(periodo *entities.PeriodoAcademico) error {
+	if _, err := uc.calendarioRepo.FindPeriodoByID(periodo.ID); err != nil {
+		return err
+	}
+	if err := uc.validarPeriodo(periodo); err != nil {
+		return err
+	}
+	return uc.calendarioRepo.UpdatePeriodo(periodo)
+}
+
+func (uc *CalendarioUseCase) DeletePeriodo(id int) error {
+	return uc.calendarioRepo.DeletePeriodo(id)
+}
+
+// validarPeriodo verifica los datos del periodo y que no se cruce con otro periodo
+func (uc *CalendarioUseCase) validarPeriodo(periodo *entities.PeriodoAcademico) error {
+	periodo.Nombre = strings.TrimSpace(periodo.Nombre)
+	if periodo.Nombre == "" {
+		return fmt.Errorf("nombre requerido")
+	}
+	if periodo.Gestion < 2000 || periodo.Gestion > 2100 {
+		return fmt.Errorf("gestión inválida")
+	}
+	if periodo.FechaInicio.IsZero() || periodo.FechaFin.IsZero() {
+		return fmt.Errorf("fecha_inicio y fecha_fin requeridas")
+	}
+	if periodo.FechaFin.Antes(periodo.FechaInicio) {
+		return fmt.Errorf("el periodo termina antes de empezar")
+	}
+
+	periodos, err := uc.calendarioRepo.FindPeriodos()
+	if err != nil {
+		return fmt.Errorf("error obteniendo periodos: %w", err)
+	}
+	for _, otro := range periodos {
+		if otro.ID == periodo.ID {
+			continue
+		}
+		if !periodo.FechaFin.Antes(otro.FechaInicio) && !otro.FechaFin.Antes(periodo.FechaInicio) {
+			return fmt.Errorf("el periodo se cruza con %s (%s a %s)", otro.Nombre, otro.FechaInicio, otro.FechaFin)
+		}
+	}
+	return nil
+}
+
+// ==================== EVENTOS ====================
+
+// GetEventos lista los eventos que se cruzan con el rango; nil no limita
+func (uc *CalendarioUseCase) GetEventos(desde, hasta *entities.Fecha) ([]*entities.EventoCalendario, error) {
+	return uc.calendarioRepo.FindEventos(desde, hasta)
+}
+
+func (uc *CalendarioUseCase) GetEvento(id int) (*entities.EventoCalendario, error) {
+	return uc.calendarioRepo.FindEventoByID(id)
+}
+
+func (uc *CalendarioUseCase) CreateEvento(evento *entities.EventoCalendario) error {
+	if err := uc.validarEvento(evento); err != nil {
+		return err
+	}
+	return uc.calendarioRepo.CreateEvento(evento)
+}
+
+func (uc *CalendarioUseCase) UpdateEvento(evento *entities.EventoCalendario) error {
+	if _, err := uc.calendarioRepo.FindEventoByID(evento.ID); err != nil {
+		return err
+	}
+	if err := uc.validarEvento(evento); err != nil {
+		return err
+	}
+	return uc.calendarioRepo.UpdateEvento(evento)
+}
+
+func (uc *CalendarioUseCase) DeleteEvento(id int) error {
+	return uc.calendarioRepo.DeleteEvento(id)
+}
+
+// validarEvento verifica tipo, fechas y turno; solo los eventos de horario especial llevan horas,
+// y estas se aplican a un turno concreto
+func (uc *CalendarioUseCase) validarEvento(evento *entities.EventoCalendario) error {
+	evento.Nombre = strings.TrimSpace(evento.Nombre)
+	if evento.Nombre == "" {
+		return fmt.Errorf("nombre requerido")
+	}
+	if !evento.Tipo.IsValid() {
+		return fmt.Errorf("tipo de evento inválido: debe ser feriado, receso, examenes u horario_especial")
+	}
+	if evento.FechaInicio.IsZero() {
+		return fmt.Errorf("fecha_inicio requerida")
+	}
+	// Un evento de un solo día puede omitir fecha_fin
+	if evento.FechaFin.IsZero() {
+		evento.FechaFin = evento.FechaInicio
+	}
+	if evento.FechaFin.Antes(evento.FechaInicio) {
+		return fmt.Errorf("el evento termina antes de empezar")
+	}
+	if evento.TurnoID != nil {
+		if _, err := uc.turnoRepo.FindByID(*evento.TurnoID); err != nil {
+			return fmt.Errorf("turno no encontrado")
+		}
+	}
+
+	if evento.Tipo != entities.EventoHorarioEspecial {
+		if evento.HoraInicio != nil || evento.HoraFin != nil {
+			return fmt.Errorf("solo los eventos de horario especial llevan hora_inicio y hora_fin")
+		}
+		return nil
+	}
+	if evento.TurnoID == nil {
+		return fmt.Errorf("un horario especial requiere turno_id")
+	}
+	if evento.HoraInicio == nil || evento.HoraFin == nil {
+		return fmt.Errorf("un horario especial requiere hora_inicio y hora_fin")
+	}
+	if *evento.HoraInicio == *evento.HoraFin {
+		return fmt.Errorf("la hora de inicio y la de fin no pueden ser iguales")
+	}
+	return nil
+}
+
+// ==================== CONSULTAS ====================
+
+// ConsultarDia responde si la fecha es laborable para el turno y con qué horario
+func (uc *CalendarioUseCase) ConsultarDia(fecha entities.Fecha, turnoID int) (*entities.DiaCalendario, error) {
+	turno, err := uc.turnoRepo.FindByID(turnoID)
+	if err != nil {
+		return nil, err
+	}
+	calendario, err := cargarCalendario(uc.calendarioRepo, fecha, fecha)
+	if err != nil {
+		return nil, err
+	}
+	return calendario.Dia(turno, fecha), nil
+}
+
+// ConsultarRango evalúa cada día del rango para el turno indicado o, con turnoID nil, para
+// todos los turnos activos
+func (uc *CalendarioUseCase) ConsultarRango(desde, hasta entities.Fecha, turnoID *int) ([]*entities.DiaCalendario, error) {
+	if err := validarRangoCalendario(desde, hasta); err != nil {
+		return nil, err
+	}
+
+	turnos, err := uc.turnosConsultados(turnoID)
+	if err != nil {
+		return nil, err
+	}
+	calendario, err := cargarCalendario(uc.calendarioRepo, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	dias := []*entities.DiaCalendario{}
+	for fecha := desde; !hasta.Antes(fecha); fecha = siguienteDia(fecha) {
+		for _, turno := range turnos {
+			dias = append(dias, calendario.Dia(turno, fecha))
+		}
+	}
+	return dias, nil
+}
+
+func (uc *CalendarioUseCase) turnosConsultados(turnoID *int) ([]*entities.Turno, error) {
+	if turnoID != nil {
+		turno, err := uc.turnoRepo.FindByID(*turnoID)
+		if err != nil {
+			return nil, err
+		}
+		return []*entities.Turno{turno}, nil
+	}
+
+	todos, err := uc.turnoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	activos := []*entities.Turno{}
+	for _, turno := range todos {
+		if turno.Activo {
+			activos = append(activos, turno)
+		}
+	}
+	return activos, nil
+}
+
+// cargarCalendario obtiene los periodos y los eventos del rango para evaluar días en memoria
+func cargarCalendario(repo repositories.CalendarioRepository, desde, hasta entities.Fecha) (*entities.Calendario, error) {
+	periodos, err := repo.FindPeriodos()
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo periodos académicos: %w", err)
+	}
+	eventos, err := repo.FindEventos(&desde, &hasta)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo eventos del calendario: %w", err)
+	}
+	return &entities.Calendario{Periodos: periodos, Eventos: eventos}, nil
+}
+
+// cargarCalendarioEntorno carga el calendario del día anterior al siguiente de un instante, lo
+// que necesitan la detección de turnos y la clasificación (turnos que cruzan la medianoche)
+func cargarCalendarioEntorno(repo repositories.CalendarioRepository, instante time.Time) (*entities.Calendario, error) {
+	return cargarCalendario(repo, entities.FechaDe(instante.AddDate(0, 0, -1)), entities.FechaDe(instante.AddDate(0, 0, 1)))
+}
+
+func validarRangoCalendario(desde, hasta entities.Fecha) error {
+	if hasta.Antes(desde) {
+		return fmt.Errorf("el rango termina antes de empezar")
+	}
+	if hasta.Sub(desde.Time) > MaxDiasConsultaCalendario*24*time.Hour {
+		return fmt.Errorf("el rango no puede superar %d días", MaxDiasConsultaCalendario)
+	}
+	return nil
+}
+
+func siguienteDia(fecha entities.Fecha) entities.Fecha {
+	return entities.Fecha{Time: fecha.AddDate(0, 0, 1)}
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+// ErrPeriodoCerrado indica que la operación modificaría registros de un mes cerrado
+var ErrPeriodoCerrado = errors.New("periodo cerrado")
+
+type CierrePeriodoUseCase struct {
+	cierreRepo repositories.CierrePeriodoRepository
+	clock      clock.Clock
+}
+
+func NewCierrePeriodoUseCase(cierreRepo repositories.CierrePeriodoRepository, reloj clock.Clock) *CierrePeriodoUseCase {
+	return &CierrePeriodoUseCase{cierreRepo: cierreRepo, clock: reloj}
+}
+
+// Cerrar congela los registros del mes. Solo se cierran meses concluidos, para no bloquear los
+// ingresos y salidas del mes en curso
+func (uc *CierrePeriodoUseCase) Cerrar(mes entities.MesContable, usuarioID int, observacion *string) (*entities.MovimientoCierre, error) {
+	if !mes.Valido() {
+		return nil, fmt.Errorf("mes inválido")
+	}
+	actual := entities.MesDe(uc.clock.Now().In(uc.clock.Location()))
+	if !mes.Antes(actual) {
+		return nil, fmt.Errorf("solo se pueden cerrar meses concluidos")
+	}
+
+	cerrado, err := uc.cierreRepo.EstaCerrado(mes)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando el cierre: %w", err)
+	}
+	if cerrado {
+		return nil, fmt.Errorf("el periodo %s ya está cerrado", mes)
+	}
+
+	movimiento := &entities.MovimientoCierre{
+		Anio:      mes.Anio,
+		Mes:       mes.Mes,
+		Accion:    entities.AccionCerrar,
+		UsuarioID: usuarioID,
+		Motivo:    textoOpcional(observacion),
+	}
+	if err := uc.cierreRepo.CreateMovimiento(movimiento); err != nil {
+		return nil, fmt.Errorf("error registrando el cierre: %w", err)
+	}
+	return movimiento, nil
+}
+
+// Reabrir vuelve a permitir cambios en un mes cerrado; el motivo es obligatorio y queda en el historial
+func (uc *CierrePeriodoUseCase) Reabrir(mes entities.MesContable, usuarioID int, motivo string) (*entities.MovimientoCierre, error) {
+	if !mes.Valido() {
+		return nil, fmt.Errorf("mes inválido")
+	}
+	motivo = strings.TrimSpace(motivo)
+	if motivo == "" {
+		return nil, fmt.Errorf("el motivo de la reapertura es requerido")
+	}
+
+	cerrado, err := uc.cierreRepo.EstaCerrado(mes)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando el cierre: %w", err)
+	}
+	if !cerrado {
+		return nil, fmt.Errorf("el periodo %s no está cerrado", mes)
+	}
+
+	movimiento := &entities.MovimientoCierre{
+		Anio:      mes.Anio,
+		Mes:       mes.Mes,
+		Accion:    entities.AccionReabrir,
+		UsuarioID: usuarioID,
+		Motivo:    &motivo,
+	}
+	if err := uc.cierreRepo.CreateMovimiento(movimiento); err != nil {
+		return nil, fmt.Errorf("error registrando la reapertura: %w", err)
+	}
+	return movimiento, nil
+}
+
+// GetEstados lista los meses que alguna vez se cerraron, con su estado actual
+func (uc *CierrePeriodoUseCase) GetEstados() ([]*entities.EstadoCierre, error) {
+	movimientos, err := uc.cierreRepo.FindUltimosMovimientos()
+	if err != nil {
+		return nil, err
+	}
+	estados := []*entities.EstadoCierre{}
+	for _, movimiento := range movimientos {
+		estados = append(estados, &entities.EstadoCierre{
+			Anio:             movimiento.Anio,
+			Mes:              movimiento.Mes,
+			Cerrado:          movimiento.Accion == entities.AccionCerrar,
+			UltimoMovimiento: movimiento,
+		})
+	}
+	return estados, nil
+}
+
+// GetEstado retorna el estado del mes con su historial completo; un mes sin historial está abierto
+func (uc *CierrePeriodoUseCase) GetEstado(mes entities.MesContable) (*entities.EstadoCierre, error) {
+	if !mes.Valido() {
+		return nil, fmt.Errorf("mes inválido")
+	}
+	historial, err := uc.cierreRepo.FindHistorial(mes)
+	if err != nil {
+		return nil, err
+	}
+	estado := &entities.EstadoCierre{Anio: mes.Anio, Mes: mes.Mes, Historial: historial}
+	if len(historial) > 0 {
+		estado.UltimoMovimiento = historial[len(historial)-1]
+		estado.Cerrado = estado.UltimoMovimiento.Accion == entities.AccionCerrar
+	}
+	return estado, nil
+}
+
+// verificarPeriodoAbierto retorna ErrPeriodoCerrado si el instante cae en un mes cerrado. Los
+// casos de uso que corrigen registros lo llaman con la fecha original y con la nueva
+func verificarPeriodoAbierto(repo repositories.CierrePeriodoRepository, reloj clock.Clock, instantes ...time.Time) error {
+	for _, instante := range instantes {
+		mes := entities.MesDe(instante.In(reloj.Location()))
+		cerrado, err := repo.EstaCerrado(mes)
+		if err != nil {
+			return fmt.Errorf("error consultando el cierre de %s: %w", mes, err)
+		}
+		if cerrado {
+			return fmt.Errorf("%w: %s; un administrador debe reabrirlo para modificar sus registros", ErrPeriodoCerrado, mes)
+		}
+	}
+	return nil
+}
+
+// verificarRangoAbierto retorna ErrPeriodoCerrado si algún mes entre desde y hasta (inclusive) está cerrado
+func verificarRangoAbierto(repo repositories.CierrePeriodoRepository, reloj clock.Clock, desde, hasta entities.Fecha) error {
+	instantes := []time.Time{}
+	for mes := time.Date(desde.Year(), desde.Month(), 1, 0, 0, 0, 0, time.UTC); !hasta.Before(mes); mes = mes.AddDate(0, 1, 0) {
+		inicio, _ := clock.Dia(reloj, mes)
+		instantes = append(instantes, inicio)
+	}
+	return verificarPeriodoAbierto(repo, reloj, instantes...)
+}
+
+// textoOpcional descarta los textos vacíos
+func textoOpcional(texto *string) *string {
+	if texto == nil {
+		return nil
+	}
+	limpio := strings.TrimSpace(*texto)
+	if limpio == "" {
+		return nil
+	}
+	return &limpio
+}
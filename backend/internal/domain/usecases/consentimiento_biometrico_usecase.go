@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+// maxVersionDocumento coincide con el tamaño de la columna version_documento
+const maxVersionDocumento = 50
+
+type ConsentimientoBiometricoUseCase struct {
+	consentimientoRepo repositories.ConsentimientoBiometricoRepository
+	docenteRepo        repositories.DocenteRepository
+	evidenciaUseCase   *EvidenciaReconocimientoUseCase
+	clock              clock.Clock
+}
+
+func NewConsentimientoBiometricoUseCase(consentimientoRepo repositories.ConsentimientoBiometricoRepository, docenteRepo repositories.DocenteRepository, evidenciaUseCase *EvidenciaReconocimientoUseCase, reloj clock.Clock) *ConsentimientoBiometricoUseCase {
+	return &ConsentimientoBiometricoUseCase{
+		consentimientoRepo: consentimientoRepo,
+		docenteRepo:        docenteRepo,
+		evidenciaUseCase:   evidenciaUseCase,
+		clock:              reloj,
+	}
+}
+
+// Registrar guarda el consentimiento firmado por el docente
+// fecha es opcional: por defecto se usa el momento del registro
+func (uc *ConsentimientoBiometricoUseCase) Registrar(docenteID int, versionDocumento string, fecha *time.Time, recolectadoPor int) (*entities.ConsentimientoBiometrico, error) {
+	versionDocumento = strings.TrimSpace(versionDocumento)
+	if versionDocumento == "" {
+		return nil, fmt.Errorf("la versión del documento de consentimiento es requerida")
+	}
+	if len(versionDocumento) > maxVersionDocumento {
+		return nil, fmt.Errorf("la versión del documento no puede exceder %d caracteres", maxVersionDocumento)
+	}
+
+	fechaConsentimiento := uc.clock.Now()
+	if fecha != nil {
+		if fecha.After(fechaConsentimiento) {
+			return nil, fmt.Errorf("la fecha de consentimiento no puede ser futura")
+		}
+		fechaConsentimiento = *fecha
+	}
+
+	docente, err := uc.docenteRepo.FindByID(docenteID)
+	if err != nil {
+		return nil, err
+	}
+	if !docente.Activo {
+		return nil, fmt.Errorf("el docente está inactivo")
+	}
+
+	if _, err := uc.consentimientoRepo.FindVigente(docenteID); err == nil {
+		return nil, fmt.Errorf("el docente ya tiene un consentimiento vigente")
+	} else if !errors.Is(err, repositories.ErrConsentimientoNoEncontrado) {
+		return nil, err
+	}
+
+	consentimiento := &entities.ConsentimientoBiometrico{
+		DocenteID:           docenteID,
+		VersionDocumento:    versionDocumento,
+		FechaConsentimiento: fechaConsentimiento,
+		RecolectadoPor:      recolectadoPor,
+	}
+	if err := uc.consentimientoRepo.Create(consentimiento); err != nil {
+		return nil, err
+	}
+	return consentimiento, nil
+}
+
+func (uc *ConsentimientoBiometricoUseCase) GetVigente(docenteID int) (*entities.ConsentimientoBiometrico, error) {
+	return uc.consentimientoRepo.FindVigente(docenteID)
+}
+
+func (uc *ConsentimientoBiometricoUseCase) GetHistorial(docenteID int) ([]*entities.ConsentimientoBiometrico, error) {
+	return uc.consentimientoRepo.FindByDocente(docenteID)
+}
+
+// TieneConsentimiento indica si el docente tiene un consentimiento vigente
+func (uc *ConsentimientoBiometricoUseCase) TieneConsentimiento(docenteID int) bool {
+	_, err := uc.consentimientoRepo.FindVigente(docenteID)
+	return err == nil
+}
+
+// Revocar elimina todas las muestras faciales y las evidencias fotográficas del docente y luego
+// revoca su consentimiento. Si la eliminación falla el consentimiento sigue vigente, así la
+// revocación puede reintentarse
+func (uc *ConsentimientoBiometricoUseCase) Revocar(docenteID int, revocadoPor int, motivo string) (*entities.ConsentimientoBiometrico, error) {
+	consentimiento, err := uc.consentimientoRepo.FindVigente(docenteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.docenteRepo.ClearFaceDescriptors(docenteID); err != nil {
+		return nil, fmt.Errorf("error eliminando muestras faciales: %w", err)
+	}
+	// Los recortes de rostro de las identificaciones también son datos biométricos: no esperan
+	// a EVIDENCE_RETENTION_DAYS
+	if _, err := uc.evidenciaUseCase.EliminarDeDocente(docenteID); err != nil {
+		return nil, fmt.Errorf("error eliminando evidencias fotográficas: %w", err)
+	}
+
+	var motivoRevocacion *string
+	if motivo = strings.TrimSpace(motivo); motivo != "" {
+		motivoRevocacion = &motivo
+	}
+	if err := uc.consentimientoRepo.Revocar(consentimiento.ID, revocadoPor, motivoRevocacion); err != nil {
+		return nil, err
+	}
+	return consentimiento, nil
+}
+
+// AplicarRetencion elimina las muestras faciales de los docentes inactivos desde hace más de
+// periodo y retorna los docentes afectados
+func (uc *ConsentimientoBiometricoUseCase) AplicarRetencion(periodo time.Duration) ([]*entities.Docente, error) {
+	docentes, err := uc.docenteRepo.FindInactiveWithFaceDescriptors(uc.clock.Now().Add(-periodo))
+	if err != nil {
+		return nil, err
+	}
+
+	depurados := make([]*entities.Docente, 0, len(docentes))
+	for _, docente := range docentes {
+		if err := uc.docenteRepo.ClearFaceDescriptors(docente.ID); err != nil {
+			return depurados, fmt.Errorf("docente %d: %w", docente.ID, err)
+		}
+		depurados = append(depurados, docente)
+	}
+	return depurados, nil
+}
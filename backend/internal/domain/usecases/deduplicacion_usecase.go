@@ -0,0 +1,142 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/recognition"
+)
+
+const (
+	// limiteReportesDuplicados es la cantidad de reportes listados
+	limiteReportesDuplicados = 50
+	// maxNotaRevision limita la nota de revisión de un par
+	maxNotaRevision = 500
+)
+
+// DeduplicacionUseCase busca docentes distintos con el mismo rostro (CI duplicado o
+// enrolamiento con la foto de otra persona). El escaneo corre en segundo plano y su
+// resultado queda como un reporte revisable
+type DeduplicacionUseCase struct {
+	reporteRepo   repositories.ReporteDuplicadosRepository
+	index         *recognition.Index
+	modeloVersion string
+
+	mu      sync.Mutex
+	enCurso bool
+}
+
+func NewDeduplicacionUseCase(reporteRepo repositories.ReporteDuplicadosRepository, index *recognition.Index, modeloVersion string) *DeduplicacionUseCase {
+	return &DeduplicacionUseCase{
+		reporteRepo:   reporteRepo,
+		index:         index,
+		modeloVersion: modeloVersion,
+	}
+}
+
+// IniciarEscaneo crea el reporte y lanza la comparación en segundo plano
+// Solo se permite un escaneo a la vez
+func (uc *DeduplicacionUseCase) IniciarEscaneo(iniciadoPor int) (*entities.ReporteDuplicados, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.enCurso {
+		return nil, fmt.Errorf("ya hay un escaneo de duplicados en proceso")
+	}
+
+	reporte := &entities.ReporteDuplicados{
+		Estado:        entities.EstadoReporteEnProceso,
+		IniciadoPor:   iniciadoPor,
+		ModeloVersion: uc.modeloVersion,
+		Umbral:        recognition.MatchTolerance,
+	}
+	if err := uc.reporteRepo.Create(reporte); err != nil {
+		return nil, err
+	}
+
+	uc.enCurso = true
+	go uc.escanear(reporte)
+	return reporte, nil
+}
+
+func (uc *DeduplicacionUseCase) escanear(reporte *entities.ReporteDuplicados) {
+	defer func() {
+		uc.mu.Lock()
+		uc.enCurso = false
+		uc.mu.Unlock()
+	}()
+
+	if err := uc.compararDocentes(reporte); err != nil {
+		log.Printf("[ERROR] Escaneo de duplicados %d fallido: %v", reporte.ID, err)
+		if err := uc.reporteRepo.MarcarError(reporte.ID, err.Error()); err != nil {
+			log.Printf("[ERROR] No se pudo marcar el reporte de duplicados %d: %v", reporte.ID, err)
+		}
+		return
+	}
+
+	log.Printf("[AUDIT] Escaneo de duplicados %d completado: %d docentes, %d pares coincidentes",
+		reporte.ID, reporte.DocentesAnalizados, reporte.ParesEncontrados)
+}
+
+func (uc *DeduplicacionUseCase) compararDocentes(reporte *entities.ReporteDuplicados) error {
+	muestras, err := uc.index.AllDescriptors()
+	if err != nil {
+		return fmt.Errorf("error obteniendo descriptores: %w", err)
+	}
+
+	for _, descriptores := range muestras {
+		reporte.DocentesAnalizados++
+		reporte.MuestrasAnalizadas += len(descriptores)
+	}
+
+	duplicados := recognition.FindDuplicates(muestras)
+	pares := make([]*entities.ParDuplicado, 0, len(duplicados))
+	for _, duplicado := range duplicados {
+		pares = append(pares, &entities.ParDuplicado{
+			Grupo:             duplicado.Group,
+			DocenteAID:        duplicado.DocenteA,
+			DocenteBID:        duplicado.DocenteB,
+			Coincidencias:     duplicado.Matches,
+			Comparaciones:     duplicado.Comparisons,
+			MejorDistancia:    duplicado.BestDistance,
+			DistanciaPromedio: duplicado.MeanDistance,
+			Concluyente:       duplicado.Conclusive,
+		})
+	}
+
+	return uc.reporteRepo.Completar(reporte, pares)
+}
+
+// MarcarInterrumpidos cierra con error los reportes que quedaron en proceso al detenerse el servidor
+func (uc *DeduplicacionUseCase) MarcarInterrumpidos() (int64, error) {
+	return uc.reporteRepo.MarcarInterrumpidos()
+}
+
+func (uc *DeduplicacionUseCase) GetReportes() ([]*entities.ReporteDuplicados, error) {
+	return uc.reporteRepo.FindAll(limiteReportesDuplicados)
+}
+
+func (uc *DeduplicacionUseCase) GetReporte(id int) (*entities.ReporteDuplicados, error) {
+	return uc.reporteRepo.FindByID(id)
+}
+
+// RevisarPar registra la conclusión de la revisión humana de un par
+func (uc *DeduplicacionUseCase) RevisarPar(parID int, estado entities.EstadoParDuplicado, revisadoPor int, nota string) error {
+	if !estado.IsValid() {
+		return fmt.Errorf("estado inválido. Valores permitidos: pendiente, confirmado, descartado")
+	}
+	nota = strings.TrimSpace(nota)
+	if len(nota) > maxNotaRevision {
+		return fmt.Errorf("la nota no puede exceder %d caracteres", maxNotaRevision)
+	}
+
+	var notaRevision *string
+	if nota != "" {
+		notaRevision = &nota
+	}
+	return uc.reporteRepo.RevisarPar(parID, estado, revisadoPor, notaRevision)
+}
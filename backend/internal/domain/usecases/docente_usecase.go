@@ -27,6 +27,11 @@ func (uc *DocenteUseCase) GetByCI(ci int64) (*entities.Docente, error) {
 	return uc.docenteRepo.FindByCI(ci)
 }
 
+// GetByUsuarioID obtiene el docente vinculado a la cuenta de usuario
+func (uc *DocenteUseCase) GetByUsuarioID(usuarioID int) (*entities.Docente, error) {
+	return uc.docenteRepo.FindByUsuarioID(usuarioID)
+}
+
 func (uc *DocenteUseCase) SearchByCI(ciPartial string) ([]*entities.Docente, error) {
 	if len(ciPartial) < 1 {
 		return []*entities.Docente{}, nil
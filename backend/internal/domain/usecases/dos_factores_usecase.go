@@ -0,0 +1,337 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+const (
+	// emisorTOTP es el nombre con el que la cuenta aparece en la aplicación autenticadora
+	emisorTOTP = "Sistema Ingreso Docente"
+	// codigosRecuperacion es la cantidad de códigos que se entregan al activar o regenerar
+	codigosRecuperacion = 10
+)
+
+// ErrCodigoDosFactoresInvalido indica un código TOTP o de recuperación incorrecto o ya usado
+var ErrCodigoDosFactoresInvalido = errors.New("código de verificación inválido")
+
+// EnrolamientoDosFactores son los datos para registrar la cuenta en la aplicación autenticadora
+type EnrolamientoDosFactores struct {
+	Secreto string
+	URL     string // otpauth://, el contenido del código QR
+}
+
+// DosFactoresUseCase gestiona la autenticación en dos pasos TOTP (RFC 6238) y los roles que la exigen
+type DosFactoresUseCase struct {
+	dosFactoresRepo repositories.DosFactoresRepository
+	usuarioRepo     repositories.UsuarioRepository
+	cipher          *security.Cipher
+	clock           clock.Clock
+}
+
+func NewDosFactoresUseCase(
+	dosFactoresRepo repositories.DosFactoresRepository,
+	usuarioRepo repositories.UsuarioRepository,
+	cipher *security.Cipher,
+	reloj clock.Clock,
+) *DosFactoresUseCase {
+	return &DosFactoresUseCase{
+		dosFactoresRepo: dosFactoresRepo,
+		usuarioRepo:     usuarioRepo,
+		cipher:          cipher,
+		clock:           reloj,
+	}
+}
+
+// Activo indica si el usuario confirmó su segundo factor
+func (uc *DosFactoresUseCase) Activo(usuarioID int) (bool, error) {
+	dosFactores, err := uc.configuracion(usuarioID)
+	if err != nil || dosFactores == nil {
+		return false, err
+	}
+	return dosFactores.Activo, nil
+}
+
+// Requerido indica si el rol exige segundo factor
+func (uc *DosFactoresUseCase) Requerido(rol entities.Rol) (bool, error) {
+	politica, err := uc.dosFactoresRepo.FindPolitica(rol)
+	if err != nil {
+		return false, err
+	}
+	return politica.Requerido, nil
+}
+
+// EnrolamientoPendiente indica que el rol del usuario exige segundo factor y aún no lo activó
+func (uc *DosFactoresUseCase) EnrolamientoPendiente(usuario *entities.Usuario) (bool, error) {
+	requerido, err := uc.Requerido(usuario.Rol)
+	if err != nil || !requerido {
+		return false, err
+	}
+	activo, err := uc.Activo(usuario.ID)
+	return !activo, err
+}
+
+func (uc *DosFactoresUseCase) Estado(usuario *entities.Usuario) (*entities.EstadoDosFactores, error) {
+	estado := &entities.EstadoDosFactores{}
+	var err error
+	if estado.Requerido, err = uc.Requerido(usuario.Rol); err != nil {
+		return nil, err
+	}
+
+	dosFactores, err := uc.configuracion(usuario.ID)
+	if err != nil {
+		return nil, err
+	}
+	if dosFactores == nil || !dosFactores.Activo {
+		return estado, nil
+	}
+	estado.Activo = true
+	estado.ActivadoAt = dosFactores.ActivadoAt
+	if estado.CodigosRecuperacion, err = uc.dosFactoresRepo.CountCodigosRecuperacion(usuario.ID); err != nil {
+		return nil, err
+	}
+	return estado, nil
+}
+
+// IniciarEnrolamiento genera un secreto nuevo, todavía inactivo. Si ya hay uno activo hay que
+// desactivarlo primero, para que nadie con la sesión abierta pueda reemplazarlo en silencio
+func (uc *DosFactoresUseCase) IniciarEnrolamiento(usuario *entities.Usuario) (*EnrolamientoDosFactores, error) {
+	if activo, err := uc.Activo(usuario.ID); err != nil {
+		return nil, err
+	} else if activo {
+		return nil, fmt.Errorf("la autenticación en dos pasos ya está activa; desactívela para volver a enrolar")
+	}
+
+	secreto, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	cifrado, claveID, err := uc.cipher.Encrypt([]byte(secreto), datoAsociadoTOTP(usuario.ID))
+	if err != nil {
+		return nil, fmt.Errorf("error cifrando el secreto: %w", err)
+	}
+	if err := uc.dosFactoresRepo.Guardar(&entities.DosFactores{
+		UsuarioID:      usuario.ID,
+		SecretoCifrado: cifrado,
+		ClaveID:        claveID,
+	}); err != nil {
+		return nil, fmt.Errorf("error guardando el secreto: %w", err)
+	}
+
+	return &EnrolamientoDosFactores{
+		Secreto: secreto,
+		URL:     security.TOTPURL(emisorTOTP, usuario.Username, secreto),
+	}, nil
+}
+
+// Activar confirma el enrolamiento con un código de la aplicación y retorna los códigos de
+// recuperación, que solo se muestran esta vez
+func (uc *DosFactoresUseCase) Activar(usuarioID int, codigo string) ([]string, error) {
+	dosFactores, err := uc.dosFactoresRepo.FindByUsuarioID(usuarioID)
+	if err != nil {
+		return nil, fmt.Errorf("inicie el enrolamiento antes de activarlo")
+	}
+	if dosFactores.Activo {
+		return nil, fmt.Errorf("la autenticación en dos pasos ya está activa")
+	}
+	paso, ok, err := uc.validarTOTP(dosFactores, codigo)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCodigoDosFactoresInvalido
+	}
+
+	codigos, hashes, err := generarCodigosRecuperacion()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.dosFactoresRepo.Activar(usuarioID, paso, hashes); err != nil {
+		return nil, err
+	}
+	return codigos, nil
+}
+
+// Verificar acepta un código TOTP no usado antes o un código de recuperación sin usar
+func (uc *DosFactoresUseCase) Verificar(usuarioID int, codigo string) error {
+	dosFactores, err := uc.dosFactoresRepo.FindByUsuarioID(usuarioID)
+	if err != nil || !dosFactores.Activo {
+		return ErrCodigoDosFactoresInvalido
+	}
+
+	codigo = strings.TrimSpace(codigo)
+	if len(codigo) == security.TOTPDigits {
+		paso, ok, err := uc.validarTOTP(dosFactores, codigo)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrCodigoDosFactoresInvalido
+		}
+		// Un código interceptado no puede reutilizarse dentro de su ventana de validez
+		aceptado, err := uc.dosFactoresRepo.RegistrarPaso(usuarioID, paso)
+		if err != nil {
+			return fmt.Errorf("error registrando el código: %w", err)
+		}
+		if !aceptado {
+			return ErrCodigoDosFactoresInvalido
+		}
+		return nil
+	}
+
+	usado, err := uc.dosFactoresRepo.UsarCodigoRecuperacion(usuarioID, hashCodigoRecuperacion(codigo))
+	if err != nil {
+		return fmt.Errorf("error verificando el código de recuperación: %w", err)
+	}
+	if !usado {
+		return ErrCodigoDosFactoresInvalido
+	}
+	return nil
+}
+
+// RegenerarCodigos reemplaza los códigos de recuperación; exige un código válido
+func (uc *DosFactoresUseCase) RegenerarCodigos(usuarioID int, codigo string) ([]string, error) {
+	if err := uc.Verificar(usuarioID, codigo); err != nil {
+		return nil, err
+	}
+	codigos, hashes, err := generarCodigosRecuperacion()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.dosFactoresRepo.ReemplazarCodigosRecuperacion(usuarioID, hashes); err != nil {
+		return nil, err
+	}
+	return codigos, nil
+}
+
+// Desactivar quita el segundo factor del propio usuario con un código válido, salvo que su rol lo exija
+func (uc *DosFactoresUseCase) Desactivar(usuario *entities.Usuario, codigo string) error {
+	requerido, err := uc.Requerido(usuario.Rol)
+	if err != nil {
+		return err
+	}
+	if requerido {
+		return fmt.Errorf("su rol requiere autenticación en dos pasos")
+	}
+	if err := uc.Verificar(usuario.ID, codigo); err != nil {
+		return err
+	}
+	return uc.dosFactoresRepo.Delete(usuario.ID)
+}
+
+// Restablecer quita el segundo factor de un usuario que perdió la aplicación y sus códigos. Si su
+// rol lo exige, deberá enrolarse de nuevo en el próximo ingreso
+func (uc *DosFactoresUseCase) Restablecer(usuarioID int) error {
+	return uc.dosFactoresRepo.Delete(usuarioID)
+}
+
+func (uc *DosFactoresUseCase) Politicas() ([]*entities.PoliticaDosFactores, error) {
+	return uc.dosFactoresRepo.FindPoliticas()
+}
+
+// DefinirPolitica exige o no el segundo factor a un rol. Las sesiones ya abiertas no cambian: la
+// exigencia se aplica desde el próximo ingreso de cada usuario
+func (uc *DosFactoresUseCase) DefinirPolitica(rol entities.Rol, requerido bool, actualizadoPor int) (*entities.PoliticaDosFactores, error) {
+	if !rol.IsValid() {
+		return nil, fmt.Errorf("rol inválido")
+	}
+	politica := &entities.PoliticaDosFactores{Rol: rol, Requerido: requerido, ActualizadoPor: &actualizadoPor}
+	if err := uc.dosFactoresRepo.SavePolitica(politica); err != nil {
+		return nil, err
+	}
+	return politica, nil
+}
+
+// Recifrar vuelve a cifrar con la clave activa (TOTP_KEY) los secretos cifrados con claves
+// anteriores, para poder retirar TOTP_PREVIOUS_KEYS tras una rotación. Retorna cuántos se actualizaron
+func (uc *DosFactoresUseCase) Recifrar() (int, error) {
+	configuraciones, err := uc.dosFactoresRepo.FindConOtraClave(uc.cipher.ActiveKeyID())
+	if err != nil {
+		return 0, err
+	}
+
+	actualizados := 0
+	for _, dosFactores := range configuraciones {
+		aad := datoAsociadoTOTP(dosFactores.UsuarioID)
+		secreto, err := uc.cipher.Decrypt(dosFactores.SecretoCifrado, dosFactores.ClaveID, aad)
+		if err != nil {
+			return actualizados, fmt.Errorf("usuario %d: %w", dosFactores.UsuarioID, err)
+		}
+		cifrado, claveID, err := uc.cipher.Encrypt(secreto, aad)
+		if err != nil {
+			return actualizados, err
+		}
+		if err := uc.dosFactoresRepo.ActualizarCifrado(dosFactores.UsuarioID, dosFactores.ClaveID, cifrado, claveID); err != nil {
+			return actualizados, fmt.Errorf("usuario %d: %w", dosFactores.UsuarioID, err)
+		}
+		actualizados++
+	}
+	return actualizados, nil
+}
+
+// configuracion retorna nil sin error si el usuario nunca inició el enrolamiento; cualquier otro
+// error debe cortar el login en lugar de tratarse como "sin segundo factor"
+func (uc *DosFactoresUseCase) configuracion(usuarioID int) (*entities.DosFactores, error) {
+	dosFactores, err := uc.dosFactoresRepo.FindByUsuarioID(usuarioID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrDosFactoresNoConfigurado) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return dosFactores, nil
+}
+
+// validarTOTP descifra el secreto y verifica el código. El paso debe ser posterior al último aceptado
+func (uc *DosFactoresUseCase) validarTOTP(dosFactores *entities.DosFactores, codigo string) (int64, bool, error) {
+	secreto, err := uc.cipher.Decrypt(dosFactores.SecretoCifrado, dosFactores.ClaveID, datoAsociadoTOTP(dosFactores.UsuarioID))
+	if err != nil {
+		return 0, false, fmt.Errorf("error descifrando el secreto del usuario %d: %w", dosFactores.UsuarioID, err)
+	}
+	paso, ok := security.ValidateTOTP(string(secreto), codigo, uc.clock.Now())
+	if !ok || paso <= dosFactores.UltimoPaso {
+		return 0, false, nil
+	}
+	return paso, true, nil
+}
+
+// datoAsociadoTOTP ata el secreto cifrado a su usuario: copiado a otra fila no se descifra
+func datoAsociadoTOTP(usuarioID int) []byte {
+	return []byte("totp:" + strconv.Itoa(usuarioID))
+}
+
+// generarCodigosRecuperacion genera códigos de 80 bits con el formato XXXX-XXXX-XXXX-XXXX y sus hashes
+func generarCodigosRecuperacion() ([]string, []string, error) {
+	codigos := make([]string, 0, codigosRecuperacion)
+	hashes := make([]string, 0, codigosRecuperacion)
+	for i := 0; i < codigosRecuperacion; i++ {
+		bytes := make([]byte, 10)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, nil, fmt.Errorf("error generando códigos de recuperación: %w", err)
+		}
+		texto := base32.StdEncoding.EncodeToString(bytes)
+		codigo := texto[0:4] + "-" + texto[4:8] + "-" + texto[8:12] + "-" + texto[12:16]
+		codigos = append(codigos, codigo)
+		hashes = append(hashes, hashCodigoRecuperacion(codigo))
+	}
+	return codigos, hashes, nil
+}
+
+// hashCodigoRecuperacion ignora guiones, espacios y mayúsculas. Como en los tokens de
+// restablecimiento, SHA-256 basta porque el código es aleatorio
+func hashCodigoRecuperacion(codigo string) string {
+	normalizado := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(codigo)))
+	suma := sha256.Sum256([]byte(normalizado))
+	return hex.EncodeToString(suma[:])
+}
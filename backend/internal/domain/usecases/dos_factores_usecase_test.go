@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+// dosFactoresEnMemoria guarda la configuración de un usuario y la política de cada rol
+type dosFactoresEnMemoria struct {
+	repositories.DosFactoresRepository
+	configuraciones map[int]*entities.DosFactores
+	requeridos      map[entities.Rol]bool
+}
+
+func (r *dosFactoresEnMemoria) FindByUsuarioID(usuarioID int) (*entities.DosFactores, error) {
+	dosFactores, ok := r.configuraciones[usuarioID]
+	if !ok {
+		return nil, repositories.ErrDosFactoresNoConfigurado
+	}
+	return dosFactores, nil
+}
+
+func (r *dosFactoresEnMemoria) FindPolitica(rol entities.Rol) (*entities.PoliticaDosFactores, error) {
+	return &entities.PoliticaDosFactores{Rol: rol, Requerido: r.requeridos[rol]}, nil
+}
+
+// RegistrarPaso reproduce la condición atómica del repositorio: solo acepta pasos posteriores
+func (r *dosFactoresEnMemoria) RegistrarPaso(usuarioID int, paso int64) (bool, error) {
+	dosFactores, ok := r.configuraciones[usuarioID]
+	if !ok || paso <= dosFactores.UltimoPaso {
+		return false, nil
+	}
+	dosFactores.UltimoPaso = paso
+	return true, nil
+}
+
+func (r *dosFactoresEnMemoria) Activar(usuarioID int, paso int64, hashesRecuperacion []string) error {
+	dosFactores := r.configuraciones[usuarioID]
+	dosFactores.Activo, dosFactores.UltimoPaso = true, paso
+	return nil
+}
+
+func (r *dosFactoresEnMemoria) UsarCodigoRecuperacion(usuarioID int, hash string) (bool, error) {
+	return false, nil
+}
+
+// Un código TOTP sigue siendo válido durante su ventana, pero solo puede usarse una vez
+func TestVerificarRechazaCodigoRepetido(t *testing.T) {
+	cipher, err := security.NewCipher(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secreto, err := security.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cifrado, claveID, err := cipher.Encrypt([]byte(secreto), datoAsociadoTOTP(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &dosFactoresEnMemoria{configuraciones: map[int]*entities.DosFactores{
+		7: {UsuarioID: 7, SecretoCifrado: cifrado, ClaveID: claveID},
+	}}
+
+	inicio := time.Unix(1700000010, 0)
+	casoDeUso := func(instante time.Time) *DosFactoresUseCase {
+		return NewDosFactoresUseCase(repo, nil, cipher, clock.Fixed(instante, time.UTC))
+	}
+	codigoDe := func(instante time.Time) string {
+		codigo, err := security.TOTPCode(secreto, security.TOTPStep(instante))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return codigo
+	}
+
+	// El código con el que se activa ya no sirve para iniciar sesión
+	if _, err := casoDeUso(inicio).Activar(7, codigoDe(inicio)); err != nil {
+		t.Fatal(err)
+	}
+	if err := casoDeUso(inicio.Add(5*time.Second)).Verificar(7, codigoDe(inicio)); !errors.Is(err, ErrCodigoDosFactoresInvalido) {
+		t.Errorf("código de la activación reutilizado: error = %v", err)
+	}
+
+	siguiente := inicio.Add(security.TOTPPeriod)
+	if err := casoDeUso(siguiente).Verificar(7, codigoDe(siguiente)); err != nil {
+		t.Fatalf("código del paso siguiente: %v", err)
+	}
+	// Repetido dentro de su ventana de ±1 paso, por ejemplo interceptado
+	for _, instante := range []time.Time{siguiente.Add(10 * time.Second), siguiente.Add(security.TOTPPeriod)} {
+		if err := casoDeUso(instante).Verificar(7, codigoDe(siguiente)); !errors.Is(err, ErrCodigoDosFactoresInvalido) {
+			t.Errorf("código repetido a las %s: error = %v", instante.Format("15:04:05"), err)
+		}
+	}
+	// El paso anterior todavía está en la ventana, pero es previo al último aceptado
+	if err := casoDeUso(siguiente).Verificar(7, codigoDe(inicio)); !errors.Is(err, ErrCodigoDosFactoresInvalido) {
+		t.Errorf("código de un paso anterior al aceptado: error = %v", err)
+	}
+
+	posterior := siguiente.Add(security.TOTPPeriod)
+	if err := casoDeUso(posterior).Verificar(7, codigoDe(posterior)); err != nil {
+		t.Errorf("código de un paso posterior: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/storage"
+)
+
+type EvidenciaReconocimientoUseCase struct {
+	evidenciaRepo repositories.EvidenciaReconocimientoRepository
+	cipher        *security.Cipher
+	archivos      *storage.FileStore
+	almacen       entities.AlmacenEvidencia
+	clock         clock.Clock
+}
+
+// NewEvidenciaReconocimientoUseCase crea el caso de uso de evidencias
+// almacen vacío deshabilita la captura; las evidencias existentes se pueden seguir consultando
+// archivos puede ser nil si nunca se usó el almacén de archivos
+func NewEvidenciaReconocimientoUseCase(evidenciaRepo repositories.EvidenciaReconocimientoRepository, cipher *security.Cipher, archivos *storage.FileStore, almacen entities.AlmacenEvidencia, reloj clock.Clock) (*EvidenciaReconocimientoUseCase, error) {
+	if almacen != "" && !almacen.IsValid() {
+		return nil, fmt.Errorf("almacén de evidencias inválido: %s", almacen)
+	}
+	if almacen == entities.AlmacenArchivo && archivos == nil {
+		return nil, fmt.Errorf("el almacén de evidencias en archivos requiere un directorio")
+	}
+	return &EvidenciaReconocimientoUseCase{
+		evidenciaRepo: evidenciaRepo,
+		cipher:        cipher,
+		archivos:      archivos,
+		almacen:       almacen,
+		clock:         reloj,
+	}, nil
+}
+
+// CapturaHabilitada indica si se deben guardar evidencias de las nuevas identificaciones
+func (uc *EvidenciaReconocimientoUseCase) CapturaHabilitada() bool {
+	return uc.almacen != ""
+}
+
+// Guardar cifra y guarda el recorte del rostro de un intento de reconocimiento exitoso
+func (uc *EvidenciaReconocimientoUseCase) Guardar(intentoID int, docenteID int, imagen []byte) error {
+	if !uc.CapturaHabilitada() {
+		return nil
+	}
+
+	cifrada, claveID, err := uc.cipher.Encrypt(imagen, evidenciaAAD(intentoID))
+	if err != nil {
+		return err
+	}
+
+	evidencia := &entities.EvidenciaReconocimiento{
+		IntentoID: intentoID,
+		DocenteID: docenteID,
+		Almacen:   uc.almacen,
+		ClaveID:   claveID,
+		Tamano:    len(imagen),
+	}
+
+	if uc.almacen == entities.AlmacenArchivo {
+		nombre := fmt.Sprintf("intento_%d.jpg.enc", intentoID)
+		if err := uc.archivos.Save(nombre, cifrada); err != nil {
+			return fmt.Errorf("error guardando la evidencia: %w", err)
+		}
+		evidencia.Archivo = &nombre
+	} else {
+		evidencia.Imagen = cifrada
+	}
+
+	if err := uc.evidenciaRepo.Create(evidencia); err != nil {
+		if evidencia.Archivo != nil {
+			uc.archivos.Delete(*evidencia.Archivo)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetImagenPorRegistro retorna la evidencia del registro y su imagen JPEG descifrada
+func (uc *EvidenciaReconocimientoUseCase) GetImagenPorRegistro(registroID int) (*entities.EvidenciaReconocimiento, []byte, error) {
+	evidencia, err := uc.evidenciaRepo.FindByRegistro(registroID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cifrada := evidencia.Imagen
+	if evidencia.Almacen == entities.AlmacenArchivo {
+		if uc.archivos == nil || evidencia.Archivo == nil {
+			return nil, nil, fmt.Errorf("almacén de archivos de evidencias no configurado")
+		}
+		if cifrada, err = uc.archivos.Read(*evidencia.Archivo); err != nil {
+			return nil, nil, fmt.Errorf("error leyendo la evidencia: %w", err)
+		}
+	}
+
+	imagen, err := uc.cipher.Decrypt(cifrada, evidencia.ClaveID, evidenciaAAD(evidencia.IntentoID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return evidencia, imagen, nil
+}
+
+// AplicarRetencion elimina las evidencias con más antigüedad que periodo y retorna cuántas se eliminaron
+func (uc *EvidenciaReconocimientoUseCase) AplicarRetencion(periodo time.Duration) (int, error) {
+	eliminadas, err := uc.evidenciaRepo.DeleteBefore(uc.clock.Now().Add(-periodo))
+	if err != nil {
+		return 0, err
+	}
+	return len(eliminadas), uc.borrarArchivos(eliminadas)
+}
+
+// EliminarDeDocente elimina todas las evidencias del docente, por ejemplo al revocar su
+// consentimiento biométrico, y retorna cuántas se eliminaron
+func (uc *EvidenciaReconocimientoUseCase) EliminarDeDocente(docenteID int) (int, error) {
+	eliminadas, err := uc.evidenciaRepo.DeleteByDocente(docenteID)
+	if err != nil {
+		return 0, err
+	}
+	return len(eliminadas), uc.borrarArchivos(eliminadas)
+}
+
+// borrarArchivos borra del almacén de archivos las imágenes de evidencias ya eliminadas de la base
+func (uc *EvidenciaReconocimientoUseCase) borrarArchivos(eliminadas []*entities.EvidenciaReconocimiento) error {
+	for _, evidencia := range eliminadas {
+		if evidencia.Archivo == nil {
+			continue
+		}
+		if uc.archivos == nil {
+			return fmt.Errorf("no se pudo borrar %s: almacén de archivos no configurado", *evidencia.Archivo)
+		}
+		if err := uc.archivos.Delete(*evidencia.Archivo); err != nil {
+			return fmt.Errorf("no se pudo borrar %s: %w", *evidencia.Archivo, err)
+		}
+	}
+	return nil
+}
+
+// evidenciaAAD vincula la imagen cifrada a su intento de reconocimiento
+func evidenciaAAD(intentoID int) []byte {
+	return []byte(fmt.Sprintf("evidencias_reconocimiento:%d", intentoID))
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+type IntentoReconocimientoUseCase struct {
+	intentoRepo repositories.IntentoReconocimientoRepository
+	clock       clock.Clock
+}
+
+func NewIntentoReconocimientoUseCase(intentoRepo repositories.IntentoReconocimientoRepository, reloj clock.Clock) *IntentoReconocimientoUseCase {
+	return &IntentoReconocimientoUseCase{intentoRepo: intentoRepo, clock: reloj}
+}
+
+// Registrar guarda un intento de identificación o verificación facial
+func (uc *IntentoReconocimientoUseCase) Registrar(intento *entities.IntentoReconocimiento) error {
+	if intento.Modo == "" {
+		intento.Modo = entities.ModoIdentificacion
+	}
+	if !intento.Modo.IsValid() {
+		return fmt.Errorf("modo de reconocimiento inválido")
+	}
+	if !intento.Decision.IsValid() {
+		return fmt.Errorf("decisión de reconocimiento inválida")
+	}
+	if intento.FechaHora.IsZero() {
+		intento.FechaHora = uc.clock.Now()
+	}
+	return uc.intentoRepo.Create(intento)
+}
+
+func (uc *IntentoReconocimientoUseCase) GetAll(filtro repositories.FiltroIntentosReconocimiento) ([]*entities.IntentoReconocimiento, error) {
+	return uc.intentoRepo.FindAll(filtro)
+}
+
+func (uc *IntentoReconocimientoUseCase) GetByID(id int) (*entities.IntentoReconocimiento, error) {
+	return uc.intentoRepo.FindByID(id)
+}
+
+// VincularRegistro asocia el registro de ingreso/salida que resultó de una identificación
+// Solo se permite si el intento identificó al mismo docente del registro
+func (uc *IntentoReconocimientoUseCase) VincularRegistro(intentoID int, registro *entities.Registro) error {
+	intento, err := uc.intentoRepo.FindByID(intentoID)
+	if err != nil {
+		return err
+	}
+	if intento.Decision != entities.DecisionIdentificado || intento.DocenteID == nil {
+		return fmt.Errorf("el intento %d no identificó a ningún docente", intentoID)
+	}
+	if *intento.DocenteID != registro.DocenteID {
+		return fmt.Errorf("el intento %d identificó a otro docente", intentoID)
+	}
+	return uc.intentoRepo.VincularRegistro(intentoID, registro.ID)
+}
+
+// ConsumirVerificacion marca como usado el token de una verificación facial antes de crear el
+// registro que acredita. Retorna repositories.ErrVerificacionUtilizada si ya se usó
+func (uc *IntentoReconocimientoUseCase) ConsumirVerificacion(intentoID int, docenteID int) error {
+	return uc.intentoRepo.ConsumirVerificacion(intentoID, docenteID)
+}
+
+// LiberarVerificacion permite reintentar con el mismo token si el registro no se creó
+func (uc *IntentoReconocimientoUseCase) LiberarVerificacion(intentoID int) error {
+	return uc.intentoRepo.LiberarVerificacion(intentoID)
+}
+
+// MarcarIncorrecto señala un intento como identificación errónea para su revisión
+func (uc *IntentoReconocimientoUseCase) MarcarIncorrecto(id int, marcadoPor int, docenteRealID *int, motivo string) error {
+	if motivo == "" {
+		return fmt.Errorf("motivo requerido")
+	}
+	if docenteRealID != nil && *docenteRealID <= 0 {
+		return fmt.Errorf("docente_real_id inválido")
+	}
+	return uc.intentoRepo.MarcarIncorrecto(id, marcadoPor, docenteRealID, motivo)
+}
@@ -0,0 +1,265 @@
+package usecases
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/storage"
+)
+
+// MaxDocumentoJustificacion es el tamaño máximo del documento adjunto a una justificación
+const MaxDocumentoJustificacion = 5 << 20
+
+type JustificacionUseCase struct {
+	justificacionRepo repositories.JustificacionRepository
+	registroRepo      repositories.RegistroRepository
+	docenteRepo       repositories.DocenteRepository
+	turnoRepo         repositories.TurnoRepository
+	cierreRepo        repositories.CierrePeriodoRepository
+	registroUseCase   *RegistroUseCase
+	archivos          *storage.FileStore
+	clock             clock.Clock
+}
+
+func NewJustificacionUseCase(
+	justificacionRepo repositories.JustificacionRepository,
+	registroRepo repositories.RegistroRepository,
+	docenteRepo repositories.DocenteRepository,
+	turnoRepo repositories.TurnoRepository,
+	cierreRepo repositories.CierrePeriodoRepository,
+	registroUseCase *RegistroUseCase,
+	archivos *storage.FileStore,
+	reloj clock.Clock,
+) *JustificacionUseCase {
+	return &JustificacionUseCase{
+		justificacionRepo: justificacionRepo,
+		registroRepo:      registroRepo,
+		docenteRepo:       docenteRepo,
+		turnoRepo:         turnoRepo,
+		cierreRepo:        cierreRepo,
+		registroUseCase:   registroUseCase,
+		archivos:          archivos,
+		clock:             reloj,
+	}
+}
+
+func (uc *JustificacionUseCase) GetByID(id int) (*entities.Justificacion, error) {
+	return uc.justificacionRepo.FindByID(id)
+}
+
+func (uc *JustificacionUseCase) Listar(filtro repositories.FiltroJustificaciones) ([]*entities.Justificacion, error) {
+	return uc.justificacionRepo.Find(filtro)
+}
+
+// Crear registra una justificación pendiente de revisión. Si se adjunta a un registro, el
+// docente, el turno y la fecha se toman de él
+func (uc *JustificacionUseCase) Crear(justificacion *entities.Justificacion) error {
+	if !justificacion.Categoria.IsValid() {
+		return fmt.Errorf("categoría inválida: debe ser salud, familiar, tramite_oficial, transporte, fuerza_mayor u otro")
+	}
+	justificacion.Descripcion = strings.TrimSpace(justificacion.Descripcion)
+	if justificacion.Descripcion == "" {
+		return fmt.Errorf("la descripción es requerida")
+	}
+
+	if justificacion.RegistroID != nil {
+		if err := uc.completarDesdeRegistro(justificacion); err != nil {
+			return err
+		}
+	} else {
+		if justificacion.DocenteID <= 0 {
+			return fmt.Errorf("docente_id o registro_id requerido")
+		}
+		if _, err := uc.docenteRepo.FindByID(justificacion.DocenteID); err != nil {
+			return fmt.Errorf("docente no encontrado")
+		}
+		if justificacion.Fecha.IsZero() {
+			return fmt.Errorf("fecha requerida")
+		}
+		if justificacion.TurnoID != nil {
+			if _, err := uc.turnoRepo.FindByID(*justificacion.TurnoID); err != nil {
+				return fmt.Errorf("turno no encontrado")
+			}
+		}
+	}
+
+	justificacion.Estado = entities.JustificacionPendiente
+	justificacion.RevisadoPor = nil
+	justificacion.RevisadoAt = nil
+	justificacion.ObservacionRevision = nil
+	return uc.justificacionRepo.Create(justificacion)
+}
+
+func (uc *JustificacionUseCase) completarDesdeRegistro(justificacion *entities.Justificacion) error {
+	registro, err := uc.registroRepo.FindByID(*justificacion.RegistroID)
+	if err != nil {
+		return fmt.Errorf("registro no encontrado")
+	}
+	if justificacion.DocenteID != 0 && justificacion.DocenteID != registro.DocenteID {
+		return fmt.Errorf("el registro pertenece a otro docente")
+	}
+	if !registro.Clasificacion.Justificable() {
+		return fmt.Errorf("el registro no tiene retraso, falta ni salida anticipada que justificar")
+	}
+
+	fecha, err := uc.registroUseCase.FechaDeTurno(registro)
+	if err != nil {
+		return err
+	}
+	justificacion.DocenteID = registro.DocenteID
+	justificacion.TurnoID = &registro.TurnoID
+	justificacion.Fecha = fecha
+	return nil
+}
+
+// Revisar aprueba o rechaza una justificación pendiente. Al aprobarla se reclasifican los
+// registros que cubre, por lo que su mes no puede estar cerrado. El rechazo requiere observación.
+// Retorna cuántos registros quedaron justificados
+func (uc *JustificacionUseCase) Revisar(id int, estado entities.EstadoJustificacion, revisorID int, observacion *string) (*entities.Justificacion, int, error) {
+	if estado != entities.JustificacionAprobada && estado != entities.JustificacionRechazada {
+		return nil, 0, fmt.Errorf("estado inválido: debe ser aprobada o rechazada")
+	}
+	observacion = textoOpcional(observacion)
+	if estado == entities.JustificacionRechazada && observacion == nil {
+		return nil, 0, fmt.Errorf("indique el motivo del rechazo en la observación")
+	}
+
+	justificacion, err := uc.justificacionRepo.FindByID(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if justificacion.Estado != entities.JustificacionPendiente {
+		return nil, 0, fmt.Errorf("la justificación ya fue revisada")
+	}
+	if estado == entities.JustificacionAprobada {
+		inicio, _ := clock.Dia(uc.clock, justificacion.Fecha.Time)
+		if err := verificarPeriodoAbierto(uc.cierreRepo, uc.clock, inicio); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	ahora := uc.clock.Now()
+	justificacion.Estado = estado
+	justificacion.RevisadoPor = &revisorID
+	justificacion.RevisadoAt = &ahora
+	justificacion.ObservacionRevision = observacion
+	if err := uc.justificacionRepo.UpdateRevision(justificacion); err != nil {
+		return nil, 0, err
+	}
+	if estado != entities.JustificacionAprobada {
+		return justificacion, 0, nil
+	}
+
+	justificados, err := uc.registroUseCase.AplicarJustificacion(justificacion)
+	if err != nil {
+		// La aprobación queda registrada; los registros se justifican al volver a clasificarse
+		log.Printf("[WARN] Justificación %d aprobada sin reclasificar sus registros: %v", justificacion.ID, err)
+		return justificacion, 0, nil
+	}
+	return justificacion, justificados, nil
+}
+
+// Eliminar borra una justificación pendiente y su documento
+func (uc *JustificacionUseCase) Eliminar(id int) error {
+	justificacion, err := uc.justificacionRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if justificacion.Estado != entities.JustificacionPendiente {
+		return fmt.Errorf("solo se pueden eliminar justificaciones pendientes")
+	}
+	if err := uc.justificacionRepo.Delete(id); err != nil {
+		return err
+	}
+	if justificacion.DocumentoArchivo != nil {
+		if err := uc.archivos.Delete(*justificacion.DocumentoArchivo); err != nil {
+			log.Printf("[WARN] No se pudo borrar el documento de la justificación %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// AdjuntarDocumento guarda un PDF o una imagen JPEG/PNG como respaldo de una justificación
+// pendiente; reemplaza el documento anterior
+func (uc *JustificacionUseCase) AdjuntarDocumento(id int, nombre string, datos []byte) (*entities.Justificacion, error) {
+	if len(datos) == 0 {
+		return nil, fmt.Errorf("el documento está vacío")
+	}
+	if len(datos) > MaxDocumentoJustificacion {
+		return nil, fmt.Errorf("el documento supera el máximo de %d MB", MaxDocumentoJustificacion>>20)
+	}
+	tipo, extension, ok := tipoDocumento(datos)
+	if !ok {
+		return nil, fmt.Errorf("el documento debe ser PDF, JPEG o PNG")
+	}
+
+	justificacion, err := uc.justificacionRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if justificacion.Estado != entities.JustificacionPendiente {
+		return nil, fmt.Errorf("solo se pueden adjuntar documentos a justificaciones pendientes")
+	}
+
+	archivo := fmt.Sprintf("justificacion_%d%s", id, extension)
+	if err := uc.archivos.Save(archivo, datos); err != nil {
+		return nil, fmt.Errorf("error guardando el documento: %w", err)
+	}
+	anterior := justificacion.DocumentoArchivo
+
+	nombre = strings.TrimSpace(filepath.Base(nombre))
+	if nombre == "" || nombre == "." || len(nombre) > 255 {
+		nombre = archivo
+	}
+	tamano := len(datos)
+	justificacion.DocumentoNombre = &nombre
+	justificacion.DocumentoTipo = &tipo
+	justificacion.DocumentoTamano = &tamano
+	justificacion.DocumentoArchivo = &archivo
+	if err := uc.justificacionRepo.UpdateDocumento(justificacion); err != nil {
+		return nil, err
+	}
+
+	if anterior != nil && *anterior != archivo {
+		if err := uc.archivos.Delete(*anterior); err != nil {
+			log.Printf("[WARN] No se pudo borrar el documento anterior de la justificación %d: %v", id, err)
+		}
+	}
+	return justificacion, nil
+}
+
+// GetDocumento retorna la justificación y el contenido de su documento
+func (uc *JustificacionUseCase) GetDocumento(id int) (*entities.Justificacion, []byte, error) {
+	justificacion, err := uc.justificacionRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if justificacion.DocumentoArchivo == nil {
+		return nil, nil, fmt.Errorf("documento no encontrado")
+	}
+	datos, err := uc.archivos.Read(*justificacion.DocumentoArchivo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error leyendo el documento: %w", err)
+	}
+	return justificacion, datos, nil
+}
+
+// tipoDocumento reconoce PDF, JPEG y PNG por su firma, sin confiar en el nombre ni en el
+// Content-Type del cliente
+func tipoDocumento(datos []byte) (string, string, bool) {
+	switch {
+	case bytes.HasPrefix(datos, []byte("%PDF-")):
+		return "application/pdf", ".pdf", true
+	case bytes.HasPrefix(datos, []byte{0xFF, 0xD8, 0xFF}):
+		return "image/jpeg", ".jpg", true
+	case bytes.HasPrefix(datos, []byte("\x89PNG\r\n\x1a\n")):
+		return "image/png", ".png", true
+	}
+	return "", "", false
+}
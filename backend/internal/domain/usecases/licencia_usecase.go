@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+type LicenciaUseCase struct {
+	licenciaRepo    repositories.LicenciaRepository
+	docenteRepo     repositories.DocenteRepository
+	cierreRepo      repositories.CierrePeriodoRepository
+	registroUseCase *RegistroUseCase
+	clock           clock.Clock
+}
+
+func NewLicenciaUseCase(
+	licenciaRepo repositories.LicenciaRepository,
+	docenteRepo repositories.DocenteRepository,
+	cierreRepo repositories.CierrePeriodoRepository,
+	registroUseCase *RegistroUseCase,
+	reloj clock.Clock,
+) *LicenciaUseCase {
+	return &LicenciaUseCase{
+		licenciaRepo:    licenciaRepo,
+		docenteRepo:     docenteRepo,
+		cierreRepo:      cierreRepo,
+		registroUseCase: registroUseCase,
+		clock:           reloj,
+	}
+}
+
+func (uc *LicenciaUseCase) GetByID(id int) (*entities.Licencia, error) {
+	return uc.licenciaRepo.FindByID(id)
+}
+
+// Listar retorna las licencias que se cruzan con el rango del filtro
+func (uc *LicenciaUseCase) Listar(filtro repositories.FiltroLicencias) ([]*entities.Licencia, error) {
+	return uc.licenciaRepo.Find(filtro)
+}
+
+// Crear registra una licencia. Como cambia el conteo de asistencia, no puede tocar meses cerrados
+func (uc *LicenciaUseCase) Crear(licencia *entities.Licencia) error {
+	if err := uc.validar(licencia); err != nil {
+		return err
+	}
+	if err := verificarRangoAbierto(uc.cierreRepo, uc.clock, licencia.FechaInicio, licencia.FechaFin); err != nil {
+		return err
+	}
+	if err := uc.licenciaRepo.Create(licencia); err != nil {
+		return err
+	}
+	uc.reclasificar(licencia.DocenteID, licencia.FechaInicio, licencia.FechaFin)
+	return nil
+}
+
+// Actualizar cambia tipo, fechas y descripción; el docente no cambia. Ni el rango anterior ni
+// el nuevo pueden tocar meses cerrados
+func (uc *LicenciaUseCase) Actualizar(licencia *entities.Licencia) error {
+	anterior, err := uc.licenciaRepo.FindByID(licencia.ID)
+	if err != nil {
+		return err
+	}
+	licencia.DocenteID = anterior.DocenteID
+	licencia.CreadoPor = anterior.CreadoPor
+	if err := uc.validar(licencia); err != nil {
+		return err
+	}
+	if err := verificarRangoAbierto(uc.cierreRepo, uc.clock, anterior.FechaInicio, anterior.FechaFin); err != nil {
+		return err
+	}
+	if err := verificarRangoAbierto(uc.cierreRepo, uc.clock, licencia.FechaInicio, licencia.FechaFin); err != nil {
+		return err
+	}
+	if err := uc.licenciaRepo.Update(licencia); err != nil {
+		return err
+	}
+	uc.reclasificar(anterior.DocenteID, anterior.FechaInicio, anterior.FechaFin)
+	uc.reclasificar(licencia.DocenteID, licencia.FechaInicio, licencia.FechaFin)
+	return nil
+}
+
+func (uc *LicenciaUseCase) Eliminar(id int) error {
+	licencia, err := uc.licenciaRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := verificarRangoAbierto(uc.cierreRepo, uc.clock, licencia.FechaInicio, licencia.FechaFin); err != nil {
+		return err
+	}
+	if err := uc.licenciaRepo.Delete(id); err != nil {
+		return err
+	}
+	uc.reclasificar(licencia.DocenteID, licencia.FechaInicio, licencia.FechaFin)
+	return nil
+}
+
+// reclasificar actualiza la clasificación de los registros del docente en el rango, para que
+// coincida con el reporte de asistencia
+func (uc *LicenciaUseCase) reclasificar(docenteID int, desde, hasta entities.Fecha) {
+	if _, err := uc.registroUseCase.ReclasificarLicencia(docenteID, desde, hasta); err != nil {
+		// La licencia queda guardada; los registros se reclasifican al volver a editarse
+		log.Printf("[WARN] Registros del docente %d del %s al %s sin reclasificar tras cambiar una licencia: %v", docenteID, desde, hasta, err)
+	}
+}
+
+// validar verifica los datos y que la licencia no se cruce con otra del mismo docente
+func (uc *LicenciaUseCase) validar(licencia *entities.Licencia) error {
+	if !licencia.Tipo.IsValid() {
+		return fmt.Errorf("tipo de licencia inválido: debe ser enfermedad, vacaciones o comision")
+	}
+	if licencia.FechaInicio.IsZero() || licencia.FechaFin.IsZero() {
+		return fmt.Errorf("fecha_inicio y fecha_fin requeridas")
+	}
+	if err := validarRangoCalendario(licencia.FechaInicio, licencia.FechaFin); err != nil {
+		return fmt.Errorf("licencia inválida: %w", err)
+	}
+	if _, err := uc.docenteRepo.FindByID(licencia.DocenteID); err != nil {
+		return fmt.Errorf("docente no encontrado")
+	}
+	licencia.Descripcion = textoOpcional(licencia.Descripcion)
+
+	otras, err := uc.licenciaRepo.Find(repositories.FiltroLicencias{
+		DocenteID: &licencia.DocenteID,
+		Desde:     &licencia.FechaInicio,
+		Hasta:     &licencia.FechaFin,
+	})
+	if err != nil {
+		return fmt.Errorf("error obteniendo licencias: %w", err)
+	}
+	for _, otra := range otras {
+		if otra.ID != licencia.ID {
+			return fmt.Errorf("se cruza con la licencia por %s del %s al %s", otra.Tipo, otra.FechaInicio, otra.FechaFin)
+		}
+	}
+	return nil
+}
+
+// licenciasPorDocente agrupa licencias para consultarlas por docente y fecha
+type licenciasPorDocente map[int][]*entities.Licencia
+
+func agruparLicencias(licencias []*entities.Licencia) licenciasPorDocente {
+	agrupadas := licenciasPorDocente{}
+	for _, licencia := range licencias {
+		agrupadas[licencia.DocenteID] = append(agrupadas[licencia.DocenteID], licencia)
+	}
+	return agrupadas
+}
+
+// En retorna la licencia del docente que cubre la fecha, o nil
+func (l licenciasPorDocente) En(docenteID int, fecha entities.Fecha) *entities.Licencia {
+	for _, licencia := range l[docenteID] {
+		if licencia.Cubre(fecha) {
+			return licencia
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrTokenRestablecimientoInvalido agrupa token inexistente, usado y vencido, para no revelar cuál
+var ErrTokenRestablecimientoInvalido = errors.New("el enlace de restablecimiento es inválido o expiró; solicite uno nuevo")
+
+// RecuperacionPasswordUseCase restablece contraseñas olvidadas con un token enviado por correo
+type RecuperacionPasswordUseCase struct {
+	usuarioRepo          repositories.UsuarioRepository
+	restablecimientoRepo repositories.RestablecimientoPasswordRepository
+	mailer               mailer.Mailer
+	clock                clock.Clock
+	vigencia             time.Duration
+	urlRestablecer       string
+}
+
+// NewRecuperacionPasswordUseCase crea el caso de uso. urlRestablecer es la página del frontend que
+// recibe el token en ?token=; si está vacía, el correo incluye solo el token
+func NewRecuperacionPasswordUseCase(
+	usuarioRepo repositories.UsuarioRepository,
+	restablecimientoRepo repositories.RestablecimientoPasswordRepository,
+	mailer mailer.Mailer,
+	reloj clock.Clock,
+	vigencia time.Duration,
+	urlRestablecer string,
+) *RecuperacionPasswordUseCase {
+	return &RecuperacionPasswordUseCase{
+		usuarioRepo:          usuarioRepo,
+		restablecimientoRepo: restablecimientoRepo,
+		mailer:               mailer,
+		clock:                reloj,
+		vigencia:             vigencia,
+		urlRestablecer:       urlRestablecer,
+	}
+}
+
+// Solicitar envía un token de restablecimiento al correo del usuario y anula los anteriores. Un
+// usuario inexistente, desactivado o sin correo no es un error: el llamador responde igual en
+// todos los casos para no revelar qué cuentas existen
+func (uc *RecuperacionPasswordUseCase) Solicitar(username string) error {
+	usuario, err := uc.usuarioRepo.FindByUsername(strings.TrimSpace(username))
+	if err != nil || !usuario.Activo {
+		log.Printf("[WARN] Restablecimiento de contraseña solicitado para una cuenta inexistente o desactivada: %q", username)
+		return nil
+	}
+	if strings.TrimSpace(usuario.Email) == "" {
+		log.Printf("[WARN] Restablecimiento de contraseña solicitado por el usuario %d (%s), que no tiene correo", usuario.ID, usuario.Username)
+		return nil
+	}
+	if usuario.Origen == entities.OrigenLDAP {
+		log.Printf("[WARN] Restablecimiento de contraseña solicitado por el usuario %d (%s), que se autentica contra el directorio", usuario.ID, usuario.Username)
+		return nil
+	}
+
+	token, err := generarTokenRestablecimiento()
+	if err != nil {
+		return fmt.Errorf("error generando el token: %w", err)
+	}
+	if err := uc.restablecimientoRepo.DeletePendientes(usuario.ID); err != nil {
+		return fmt.Errorf("error anulando tokens anteriores: %w", err)
+	}
+	restablecimiento := &entities.RestablecimientoPassword{
+		UsuarioID: usuario.ID,
+		TokenHash: hashTokenRestablecimiento(token),
+		ExpiraAt:  uc.clock.Now().Add(uc.vigencia),
+	}
+	if err := uc.restablecimientoRepo.Create(restablecimiento); err != nil {
+		return fmt.Errorf("error guardando el token: %w", err)
+	}
+
+	if err := uc.mailer.Enviar(uc.mensaje(usuario, token)); err != nil {
+		return fmt.Errorf("error enviando el correo al usuario %d: %w", usuario.ID, err)
+	}
+	log.Printf("[AUDIT] Token de restablecimiento de contraseña enviado al usuario %d (%s), vence %s",
+		usuario.ID, usuario.Username, restablecimiento.ExpiraAt.Format(time.RFC3339))
+	return nil
+}
+
+// Restablecer consume el token y reemplaza la contraseña del usuario, que ya no tiene que
+// cambiarla al ingresar. Retorna el usuario para la auditoría
+func (uc *RecuperacionPasswordUseCase) Restablecer(token, nueva string) (*entities.Usuario, error) {
+	restablecimiento, err := uc.restablecimientoRepo.FindByTokenHash(hashTokenRestablecimiento(strings.TrimSpace(token)))
+	if err != nil || !restablecimiento.Vigente(uc.clock.Now()) {
+		return nil, ErrTokenRestablecimientoInvalido
+	}
+	usuario, err := uc.usuarioRepo.FindByID(restablecimiento.UsuarioID)
+	if err != nil || !usuario.Activo {
+		return nil, ErrTokenRestablecimientoInvalido
+	}
+	if usuario.Origen == entities.OrigenLDAP {
+		return nil, ErrPasswordEnDirectorio
+	}
+	if err := verificarPasswordNoReutilizada(uc.usuarioRepo, usuario, nueva); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(nueva), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("error hasheando contraseña: %w", err)
+	}
+	// Se consume antes de cambiar la contraseña: si otra petición ya lo usó, esta no hace nada
+	if err := uc.restablecimientoRepo.Consumir(restablecimiento.ID, uc.clock.Now()); err != nil {
+		return nil, ErrTokenRestablecimientoInvalido
+	}
+	if err := uc.usuarioRepo.UpdatePassword(usuario.ID, string(hashedPassword), false, passwordsEnHistorial); err != nil {
+		return nil, fmt.Errorf("error actualizando contraseña: %w", err)
+	}
+	if err := uc.restablecimientoRepo.DeletePendientes(usuario.ID); err != nil {
+		log.Printf("[WARN] No se pudieron anular los tokens pendientes del usuario %d: %v", usuario.ID, err)
+	}
+	return usuario, nil
+}
+
+// DepurarVencidos elimina los tokens que vencieron hace más de un día
+func (uc *RecuperacionPasswordUseCase) DepurarVencidos() (int64, error) {
+	return uc.restablecimientoRepo.DeleteVencidos(uc.clock.Now().Add(-24 * time.Hour))
+}
+
+func (uc *RecuperacionPasswordUseCase) mensaje(usuario *entities.Usuario, token string) mailer.Mensaje {
+	instrucciones := "Use este código en la página de restablecimiento:\n\n" + token
+	if uc.urlRestablecer != "" {
+		instrucciones = "Abra este enlace para elegir una nueva contraseña:\n\n" + uc.urlRestablecer + "?token=" + url.QueryEscape(token)
+	}
+
+	nombre := usuario.NombreCompleto
+	if nombre == "" {
+		nombre = usuario.Username
+	}
+	cuerpo := fmt.Sprintf(`Hola %s,
+
+Recibimos una solicitud para restablecer la contraseña de la cuenta %s en el Sistema de Ingreso Docente.
+
+%s
+
+Vence en %d minutos y solo puede usarse una vez.
+
+Si no solicitó el cambio, ignore este correo: su contraseña actual sigue siendo válida.
+`, nombre, usuario.Username, instrucciones, int(uc.vigencia.Minutes()))
+
+	return mailer.Mensaje{
+		Para:   usuario.Email,
+		Asunto: "Restablecimiento de contraseña",
+		Cuerpo: cuerpo,
+	}
+}
+
+// generarTokenRestablecimiento genera 256 bits aleatorios en base64 apto para URL
+func generarTokenRestablecimiento() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// hashTokenRestablecimiento es lo único que se guarda del token. Basta SHA-256 sin sal: el token
+// es aleatorio, no una contraseña elegida por una persona
+func hashTokenRestablecimiento(token string) string {
+	suma := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(suma[:])
+}
@@ -1,72 +1,106 @@
 package usecases
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
 )
 
+// ErrIngresoAbiertoEnTurno indica que el docente ya registró su ingreso a esa ocurrencia del turno
+// y aún no registró la salida
+var ErrIngresoAbiertoEnTurno = errors.New("el docente ya tiene un ingreso abierto en este turno")
+
 type RegistroUseCase struct {
-	registroRepo repositories.RegistroRepository
-	turnoRepo    repositories.TurnoRepository
-	llaveRepo    repositories.LlaveRepository
+	registroRepo      repositories.RegistroRepository
+	turnoRepo         repositories.TurnoRepository
+	llaveRepo         repositories.LlaveRepository
+	calendarioRepo    repositories.CalendarioRepository
+	cierreRepo        repositories.CierrePeriodoRepository
+	justificacionRepo repositories.JustificacionRepository
+	licenciaRepo      repositories.LicenciaRepository
+	suplenciaRepo     repositories.SuplenciaRepository
+	clock             clock.Clock
+
+	// ventanaDeteccion es cuánto antes de su inicio un turno puede asignarse automáticamente a un ingreso
+	ventanaDeteccion time.Duration
 }
 
 func NewRegistroUseCase(
 	registroRepo repositories.RegistroRepository,
 	turnoRepo repositories.TurnoRepository,
 	llaveRepo repositories.LlaveRepository,
+	calendarioRepo repositories.CalendarioRepository,
+	cierreRepo repositories.CierrePeriodoRepository,
+	justificacionRepo repositories.JustificacionRepository,
+	licenciaRepo repositories.LicenciaRepository,
+	suplenciaRepo repositories.SuplenciaRepository,
+	reloj clock.Clock,
+	ventanaDeteccion time.Duration,
 ) *RegistroUseCase {
 	return &RegistroUseCase{
-		registroRepo: registroRepo,
-		turnoRepo:    turnoRepo,
-		llaveRepo:    llaveRepo,
+		registroRepo:      registroRepo,
+		turnoRepo:         turnoRepo,
+		llaveRepo:         llaveRepo,
+		calendarioRepo:    calendarioRepo,
+		cierreRepo:        cierreRepo,
+		justificacionRepo: justificacionRepo,
+		licenciaRepo:      licenciaRepo,
+		suplenciaRepo:     suplenciaRepo,
+		clock:             reloj,
+
+		ventanaDeteccion: ventanaDeteccion,
 	}
 }
 
-func (uc *RegistroUseCase) RegistrarIngreso(docenteID, turnoID int, llaveID *int, observaciones *string) (*entities.Registro, error) {
+// RegistrarIngreso registra la llegada del docente. Si turnoID es nil, el turno se detecta con
+// resolverTurno y la selección retornada explica cómo se eligió
+func (uc *RegistroUseCase) RegistrarIngreso(docenteID int, turnoID *int, llaveID *int, observaciones *string) (*entities.Registro, *entities.SeleccionTurno, error) {
 	// Validar que la llave no esté ya en uso
 	if llaveID != nil {
 		llave, err := uc.llaveRepo.FindByID(*llaveID)
 		if err != nil {
-			return nil, fmt.Errorf("llave no encontrada: %w", err)
+			return nil, nil, fmt.Errorf("llave no encontrada: %w", err)
 		}
 		if llave.Estado == entities.EstadoEnUso {
-			return nil, fmt.Errorf("la llave %s ya está en uso", llave.Codigo)
+			return nil, nil, fmt.Errorf("la llave %s ya está en uso", llave.Codigo)
 		}
 		if llave.Estado == entities.EstadoExtraviada {
-			return nil, fmt.Errorf("la llave %s está marcada como extraviada", llave.Codigo)
+			return nil, nil, fmt.Errorf("la llave %s está marcada como extraviada", llave.Codigo)
 		}
 		if llave.Estado == entities.EstadoInactiva {
-			return nil, fmt.Errorf("la llave %s está inactiva", llave.Codigo)
+			return nil, nil, fmt.Errorf("la llave %s está inactiva", llave.Codigo)
 		}
 	}
 
-	// Obtener turno para calcular retraso
-	turno, err := uc.turnoRepo.FindByID(turnoID)
+	// Obtener turno para calcular retraso y clasificar el ingreso
+	turno, seleccion, err := uc.resolverTurno(turnoID)
 	if err != nil {
-		return nil, fmt.Errorf("turno no encontrado: %w", err)
+		return nil, nil, err
 	}
 
-	ahora := time.Now()
-	minutosRetraso := uc.calcularRetraso(ahora, turno.HoraInicio)
-
 	registro := &entities.Registro{
-		DocenteID:      docenteID,
-		TurnoID:        turnoID,
-		LlaveID:        llaveID,
-		Tipo:           entities.TipoIngreso,
-		FechaHora:      ahora,
-		MinutosRetraso: minutosRetraso,
-		MinutosExtra:   0,
-		EsExcepcional:  false,
-		Observaciones:  observaciones,
+		DocenteID:     docenteID,
+		TurnoID:       turno.ID,
+		LlaveID:       llaveID,
+		Tipo:          entities.TipoIngreso,
+		FechaHora:     uc.clock.Now(),
+		EsExcepcional: false,
+		Observaciones: observaciones,
+	}
+	// Dos ingresos en la misma ocurrencia harían ambiguo a cuál corresponde la salida
+	if err := uc.verificarSinIngresoAbierto(registro); err != nil {
+		return nil, nil, err
 	}
+	uc.clasificar(registro, turno)
+	uc.vincularSuplencia(registro)
 
 	if err := uc.registroRepo.Create(registro); err != nil {
-		return nil, fmt.Errorf("error creando registro: %w", err)
+		return nil, nil, fmt.Errorf("error creando registro: %w", err)
 	}
 
 	// Actualizar estado de llave a "en_uso"
@@ -74,56 +108,106 @@ func (uc *RegistroUseCase) RegistrarIngreso(docenteID, turnoID int, llaveID *int
 		_ = uc.llaveRepo.UpdateEstado(*llaveID, entities.EstadoEnUso)
 	}
 
-	return registro, nil
+	return registro, seleccion, nil
 }
 
-func (uc *RegistroUseCase) RegistrarSalida(docenteID, turnoID int, llaveID *int, observaciones *string) (*entities.Registro, error) {
-	// Validar que el docente tenga la llave antes de devolverla
-	if llaveID != nil {
-		tieneLlave, err := uc.registroRepo.DocenteTieneLlave(docenteID, *llaveID)
+// verificarSinIngresoAbierto retorna ErrIngresoAbiertoEnTurno si el docente tiene un ingreso sin
+// salida en la misma ocurrencia del turno que el ingreso nuevo. Un ingreso abierto de otro turno o
+// de otro día (una salida olvidada) no lo impide
+func (uc *RegistroUseCase) verificarSinIngresoAbierto(ingreso *entities.Registro) error {
+	fecha, err := uc.FechaDeTurno(ingreso)
+	if err != nil {
+		return err
+	}
+	// Los ingresos de la ocurrencia empiezan a más tardar el día anterior (turnos nocturnos)
+	desde, _ := clock.Dia(uc.clock, fecha.Time.AddDate(0, 0, -1))
+	abiertas, err := uc.registroRepo.FindSesiones(repositories.FiltroSesiones{
+		DocenteID:    &ingreso.DocenteID,
+		Desde:        &desde,
+		SoloAbiertas: true,
+	})
+	if err != nil {
+		return fmt.Errorf("error consultando los ingresos abiertos: %w", err)
+	}
+
+	for _, sesion := range abiertas {
+		if sesion.TurnoID != ingreso.TurnoID {
+			continue
+		}
+		otra, err := uc.FechaDeTurno(&entities.Registro{TurnoID: sesion.TurnoID, Tipo: entities.TipoIngreso, FechaHora: sesion.HoraIngreso})
 		if err != nil {
-			return nil, fmt.Errorf("error verificando llave: %w", err)
+			return err
 		}
-		if !tieneLlave {
+		if otra.String() == fecha.String() {
+			return fmt.Errorf("%w (ingreso de las %s); registre la salida antes de otro ingreso",
+				ErrIngresoAbiertoEnTurno, sesion.HoraIngreso.In(uc.clock.Location()).Format("15:04"))
+		}
+	}
+	return nil
+}
+
+// RegistrarSalida cierra el ingreso abierto del docente: con llaveID, el que retiró esa llave;
+// sin ella, el más reciente. Sin ingreso abierto la salida se rechaza. La salida hereda la llave
+// y, si no se indica turnoID, el turno del ingreso
+func (uc *RegistroUseCase) RegistrarSalida(docenteID int, turnoID *int, llaveID *int, observaciones *string) (*entities.Registro, *entities.SeleccionTurno, error) {
+	var ingreso *entities.Registro
+	var err error
+	if llaveID != nil {
+		// Validar que el docente tenga la llave antes de devolverla
+		ingreso, err = uc.registroRepo.FindIngresoAbiertoConLlave(docenteID, *llaveID)
+		if err != nil {
 			llave, _ := uc.llaveRepo.FindByID(*llaveID)
 			if llave != nil {
-				return nil, fmt.Errorf("el docente no tiene la llave %s en su poder", llave.Codigo)
+				return nil, nil, fmt.Errorf("el docente no tiene la llave %s en su poder", llave.Codigo)
 			}
-			return nil, fmt.Errorf("el docente no tiene esa llave en su poder")
+			return nil, nil, fmt.Errorf("el docente no tiene esa llave en su poder")
+		}
+	} else {
+		ingreso, err = uc.registroRepo.FindIngresoAbierto(docenteID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("el docente no tiene un ingreso abierto")
 		}
 	}
 
-	// Obtener turno para calcular minutos extra
-	turno, err := uc.turnoRepo.FindByID(turnoID)
+	// Obtener turno para calcular minutos extra y clasificar la salida
+	var turno *entities.Turno
+	var seleccion *entities.SeleccionTurno
+	if turnoID != nil {
+		turno, seleccion, err = uc.resolverTurno(turnoID)
+	} else {
+		turno, err = uc.turnoRepo.FindByID(ingreso.TurnoID)
+		if err == nil {
+			detalle := fmt.Sprintf("Turno del ingreso de las %s", ingreso.FechaHora.In(uc.clock.Location()).Format("15:04"))
+			seleccion = nuevaSeleccion(turno, entities.SeleccionIngresoAbierto, detalle)
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("turno no encontrado: %w", err)
+		return nil, nil, err
 	}
 
-	ahora := time.Now()
-	minutosExtra := uc.calcularMinutosExtra(ahora, turno.HoraFin)
-
 	registro := &entities.Registro{
-		DocenteID:      docenteID,
-		TurnoID:        turnoID,
-		LlaveID:        llaveID,
-		Tipo:           entities.TipoSalida,
-		FechaHora:      ahora,
-		MinutosRetraso: 0,
-		MinutosExtra:   minutosExtra,
-		EsExcepcional:  false,
-		Observaciones:  observaciones,
+		DocenteID:     docenteID,
+		TurnoID:       turno.ID,
+		LlaveID:       ingreso.LlaveID,
+		Tipo:          entities.TipoSalida,
+		FechaHora:     uc.clock.Now(),
+		IngresoID:     &ingreso.ID,
+		SuplenciaID:   ingreso.SuplenciaID,
+		EsExcepcional: false,
+		Observaciones: observaciones,
 	}
+	uc.clasificar(registro, turno)
 
 	if err := uc.registroRepo.Create(registro); err != nil {
-		return nil, fmt.Errorf("error creando registro: %w", err)
+		return nil, nil, fmt.Errorf("error creando registro: %w", err)
 	}
 
 	// Actualizar estado de llave a "disponible"
-	if llaveID != nil {
-		_ = uc.llaveRepo.UpdateEstado(*llaveID, entities.EstadoDisponible)
+	if registro.LlaveID != nil {
+		_ = uc.llaveRepo.UpdateEstado(*registro.LlaveID, entities.EstadoDisponible)
 	}
 
-	return registro, nil
+	return registro, seleccion, nil
 }
 
 func (uc *RegistroUseCase) GetByFecha(fecha time.Time) ([]*entities.Registro, error) {
@@ -134,6 +218,17 @@ func (uc *RegistroUseCase) GetByDocente(docenteID int) ([]*entities.Registro, er
 	return uc.registroRepo.FindByDocente(docenteID)
 }
 
+// GetByDocenteEnRango lista los registros del docente entre desde y hasta (días de la
+// institución, inclusive)
+func (uc *RegistroUseCase) GetByDocenteEnRango(docenteID int, desde, hasta entities.Fecha) ([]*entities.Registro, error) {
+	if err := validarRangoCalendario(desde, hasta); err != nil {
+		return nil, err
+	}
+	inicio, _ := clock.Dia(uc.clock, desde.Time)
+	_, fin := clock.Dia(uc.clock, hasta.Time)
+	return uc.registroRepo.FindByDocenteYRango(docenteID, inicio, fin)
+}
+
 func (uc *RegistroUseCase) GetRegistrosHoy() ([]*entities.Registro, error) {
 	return uc.registroRepo.FindRegistrosHoy()
 }
@@ -146,6 +241,42 @@ func (uc *RegistroUseCase) GetLlaveActualDocente(docenteID int) (*int, error) {
 	return registro.LlaveID, nil
 }
 
+// GetSesiones lista ingresos emparejados con su salida, con duración, retraso y minutos extra
+func (uc *RegistroUseCase) GetSesiones(filtro repositories.FiltroSesiones) ([]*entities.Sesion, error) {
+	return uc.registroRepo.FindSesiones(filtro)
+}
+
+// GetSesion obtiene la sesión iniciada por un ingreso
+func (uc *RegistroUseCase) GetSesion(ingresoID int) (*entities.Sesion, error) {
+	sesiones, err := uc.registroRepo.FindSesiones(repositories.FiltroSesiones{IngresoID: &ingresoID})
+	if err != nil {
+		return nil, err
+	}
+	if len(sesiones) == 0 {
+		return nil, fmt.Errorf("sesión no encontrada")
+	}
+	return sesiones[0], nil
+}
+
+// GetLlavesEnUso lista las sesiones abiertas con llave, es decir, las llaves que aún no se devolvieron
+func (uc *RegistroUseCase) GetLlavesEnUso() ([]*entities.Sesion, error) {
+	return uc.registroRepo.FindSesiones(repositories.FiltroSesiones{SoloAbiertas: true, SoloConLlave: true})
+}
+
+// LicenciaVigente retorna la licencia que cubre el día de hoy del docente, o nil. El registro de
+// un docente con licencia no se impide, pero el operador debe ser advertido
+func (uc *RegistroUseCase) LicenciaVigente(docenteID int) (*entities.Licencia, error) {
+	hoy := entities.FechaDe(uc.clock.Now().In(uc.clock.Location()))
+	licencias, err := uc.licenciaRepo.Find(repositories.FiltroLicencias{DocenteID: &docenteID, Desde: &hoy, Hasta: &hoy})
+	if err != nil {
+		return nil, err
+	}
+	if len(licencias) == 0 {
+		return nil, nil
+	}
+	return licencias[0], nil
+}
+
 func (uc *RegistroUseCase) GetByID(id int) (*entities.Registro, error) {
 	return uc.registroRepo.FindByID(id)
 }
@@ -154,6 +285,9 @@ func (uc *RegistroUseCase) Update(registro *entities.Registro) error {
 	if registro.ID <= 0 {
 		return fmt.Errorf("ID de registro inválido")
 	}
+	if err := verificarPeriodoAbierto(uc.cierreRepo, uc.clock, registro.FechaHora); err != nil {
+		return err
+	}
 	return uc.registroRepo.Update(registro)
 }
 
@@ -164,6 +298,11 @@ func (uc *RegistroUseCase) UpdateConSincronizacionLlaves(registroAnterior, regis
 		return fmt.Errorf("ID de registro inválido")
 	}
 
+	// Los meses cerrados no admiten correcciones, ni desde ni hacia ellos
+	if err := verificarPeriodoAbierto(uc.cierreRepo, uc.clock, registroAnterior.FechaHora, registroNuevo.FechaHora); err != nil {
+		return err
+	}
+
 	// Detectar cambios
 	llaveAnteriorID := registroAnterior.LlaveID
 	llaveNuevaID := registroNuevo.LlaveID
@@ -182,6 +321,37 @@ func (uc *RegistroUseCase) UpdateConSincronizacionLlaves(registroAnterior, regis
 
 	cambioTipo := tipoAnterior != tipoNuevo
 
+	// Mantener el emparejamiento de sesiones: solo las salidas referencian un ingreso
+	if tipoNuevo == entities.TipoIngreso {
+		registroNuevo.IngresoID = nil
+	}
+	if cambioTipo && tipoAnterior == entities.TipoIngreso {
+		if _, err := uc.registroRepo.FindSalidaDeIngreso(registroNuevo.ID); err == nil {
+			return fmt.Errorf("el ingreso ya tiene una salida registrada; elimine o corrija la salida primero")
+		}
+	}
+	if registroNuevo.IngresoID != nil {
+		ingreso, err := uc.registroRepo.FindByID(*registroNuevo.IngresoID)
+		if err == nil && registroNuevo.FechaHora.Before(ingreso.FechaHora) {
+			return fmt.Errorf("la salida no puede ser anterior a su ingreso")
+		}
+	}
+
+	// Si cambió algo que afecta el horario, recalcular retraso, minutos extra y clasificación
+	if cambioTipo || registroAnterior.TurnoID != registroNuevo.TurnoID || !registroAnterior.FechaHora.Equal(registroNuevo.FechaHora) {
+		turno, err := uc.turnoRepo.FindByID(registroNuevo.TurnoID)
+		if err != nil {
+			return fmt.Errorf("turno no encontrado: %w", err)
+		}
+		uc.clasificar(registroNuevo, turno)
+	}
+
+	// La suplencia depende del docente, el turno y la fecha; las salidas siguen a su ingreso
+	if cambioTipo || registroAnterior.DocenteID != registroNuevo.DocenteID || registroAnterior.TurnoID != registroNuevo.TurnoID ||
+		!registroAnterior.FechaHora.Equal(registroNuevo.FechaHora) {
+		uc.vincularSuplencia(registroNuevo)
+	}
+
 	// VALIDACION: Si se está asignando una llave nueva a un registro de tipo ingreso,
 	// verificar que la llave no esté ya en uso
 	if llaveNuevaID != nil && tipoNuevo == entities.TipoIngreso {
@@ -253,6 +423,9 @@ func (uc *RegistroUseCase) DeleteConSincronizacionLlave(registro *entities.Regis
 	if registro.ID <= 0 {
 		return fmt.Errorf("ID de registro inválido")
 	}
+	if err := verificarPeriodoAbierto(uc.cierreRepo, uc.clock, registro.FechaHora); err != nil {
+		return err
+	}
 
 	// Eliminar el registro
 	if err := uc.registroRepo.Delete(registro.ID); err != nil {
@@ -267,55 +440,342 @@ func (uc *RegistroUseCase) DeleteConSincronizacionLlave(registro *entities.Regis
 	return nil
 }
 
-func (uc *RegistroUseCase) calcularRetraso(ahora time.Time, horaInicio string) int {
-	// Parsear hora de inicio del turno (puede venir como "15:04:05" o "0000-01-01T15:00:00Z")
-	var inicio time.Time
-	var err error
+// resolverTurno obtiene el turno de un ingreso. Sin turnoID, elige el turno que empieza más
+// pronto dentro de la ventana de detección y, si no hay, el turno en curso. Se prefiere el
+// próximo porque quien llega antes de un turno viene a él, aunque el anterior no haya terminado
+func (uc *RegistroUseCase) resolverTurno(turnoID *int) (*entities.Turno, *entities.SeleccionTurno, error) {
+	if turnoID != nil {
+		turno, err := uc.turnoRepo.FindByID(*turnoID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("turno no encontrado: %w", err)
+		}
+		return turno, nuevaSeleccion(turno, entities.SeleccionManual, "Turno indicado en la solicitud"), nil
+	}
+
+	turnos, err := uc.turnoRepo.FindAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error obteniendo turnos: %w", err)
+	}
+	ahora := uc.clock.Now().In(uc.clock.Location())
+	// Los feriados, recesos y horarios especiales del calendario académico se respetan
+	calendario, err := cargarCalendarioEntorno(uc.calendarioRepo, ahora)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if turno := turnoProximo(uc.clock, calendario, turnos, ahora, uc.ventanaDeteccion); turno != nil {
+		detalle := fmt.Sprintf("Empieza a las %s, dentro de la ventana de %d minutos", turno.HoraInicio.Corta(), int(uc.ventanaDeteccion.Minutes()))
+		return turno, nuevaSeleccion(turno, entities.SeleccionProximo, detalle), nil
+	}
+
+	if turno := turnoEnCurso(uc.clock, calendario, turnos, ahora); turno != nil {
+		detalle := fmt.Sprintf("En curso de %s a %s", turno.HoraInicio.Corta(), turno.HoraFin.Corta())
+		return turno, nuevaSeleccion(turno, entities.SeleccionEnCurso, detalle), nil
+	}
+
+	return nil, nil, fmt.Errorf("no hay un turno en curso ni que empiece en los próximos %d minutos; especifique turno_id", int(uc.ventanaDeteccion.Minutes()))
+}
+
+func nuevaSeleccion(turno *entities.Turno, metodo entities.MetodoSeleccionTurno, detalle string) *entities.SeleccionTurno {
+	return &entities.SeleccionTurno{
+		Metodo:      metodo,
+		TurnoID:     turno.ID,
+		TurnoNombre: turno.Nombre,
+		Detalle:     detalle,
+	}
+}
+
+// clasificar calcula minutos de retraso, minutos extra y la clasificación del registro según
+// los márgenes del turno, contra la ocurrencia del turno más cercana al registro (la de la noche
+// anterior si el turno cruza la medianoche) con el horario que fija el calendario académico
+func (uc *RegistroUseCase) clasificar(registro *entities.Registro, turno *entities.Turno) {
+	calendario, err := cargarCalendarioEntorno(uc.calendarioRepo, registro.FechaHora.In(uc.clock.Location()))
+	if err != nil {
+		// Sin calendario se clasifica con el horario habitual del turno
+		log.Printf("[WARN] Clasificando el registro sin calendario académico: %v", err)
+	}
+	inicioTurno, finTurno := ocurrenciaCercana(uc.clock, calendario, turno, registro.FechaHora)
+	registro.MinutosRetraso = 0
+	registro.MinutosExtra = 0
+	registro.Clasificacion = entities.ClasificacionPuntual
+
+	if registro.Tipo == entities.TipoIngreso {
+		registro.MinutosRetraso = minutosDespues(registro.FechaHora, inicioTurno)
+		switch {
+		case registro.MinutosRetraso > turno.RetrasoMaximoMin:
+			registro.Clasificacion = entities.ClasificacionFalta
+		case registro.MinutosRetraso > turno.ToleranciaIngresoMin:
+			registro.Clasificacion = entities.ClasificacionTarde
+		default:
+			// Dentro de la tolerancia no se cuenta retraso
+			registro.MinutosRetraso = 0
+		}
+	} else {
+		registro.MinutosExtra = minutosDespues(registro.FechaHora, finTurno)
+		if minutosDespues(finTurno, registro.FechaHora) > turno.SalidaAnticipadaMin {
+			registro.Clasificacion = entities.ClasificacionSalidaAnticipada
+		}
+	}
+
+	// Los minutos de retraso se conservan; solo cambia la clasificación. Durante una licencia la
+	// llegada no se califica, igual que en el reporte de asistencia
+	fecha := entities.FechaDe(inicioTurno)
+	if registro.Clasificacion.Justificable() && (uc.enLicencia(registro.DocenteID, fecha) || uc.justificado(registro, fecha)) {
+		registro.Clasificacion = entities.ClasificacionJustificada
+	}
+}
+
+// enLicencia indica si una licencia del docente cubre la fecha. Si no se puede consultar, el
+// registro se clasifica como si no la hubiera
+func (uc *RegistroUseCase) enLicencia(docenteID int, fecha entities.Fecha) bool {
+	licencias, err := uc.licenciaRepo.Find(repositories.FiltroLicencias{DocenteID: &docenteID, Desde: &fecha, Hasta: &fecha})
+	if err != nil {
+		log.Printf("[WARN] No se pudieron consultar las licencias del docente %d: %v", docenteID, err)
+		return false
+	}
+	return len(licencias) > 0
+}
+
+// justificado indica si una justificación aprobada cubre el registro en la ocurrencia del turno
+// que empieza en fecha. Si no se puede consultar, el registro queda sin justificar
+func (uc *RegistroUseCase) justificado(registro *entities.Registro, fecha entities.Fecha) bool {
+	aprobadas, err := uc.justificacionRepo.FindAprobadas(registro.DocenteID, fecha)
+	if err != nil {
+		log.Printf("[WARN] No se pudieron consultar las justificaciones del docente %d: %v", registro.DocenteID, err)
+		return false
+	}
+	for _, justificacion := range aprobadas {
+		if justificacion.Cubre(registro, fecha) {
+			return true
+		}
+	}
+	return false
+}
+
+// vincularSuplencia asocia el registro a la suplencia que el docente cubre en la ocurrencia de su
+// turno. Una salida hereda la suplencia de su ingreso. Si la llave es de otra aula el registro se
+// vincula igual, porque el suplente tiene una sola suplencia por turno, pero queda en el log
+func (uc *RegistroUseCase) vincularSuplencia(registro *entities.Registro) {
+	registro.SuplenciaID = nil
+	if registro.Tipo == entities.TipoSalida {
+		if registro.IngresoID != nil {
+			if ingreso, err := uc.registroRepo.FindByID(*registro.IngresoID); err == nil {
+				registro.SuplenciaID = ingreso.SuplenciaID
+			}
+		}
+		return
+	}
 
-	// Intentar parsear como timestamp ISO primero
-	inicio, err = time.Parse(time.RFC3339, horaInicio)
+	fecha, err := uc.FechaDeTurno(registro)
+	if err != nil {
+		log.Printf("[WARN] No se pudo ubicar la suplencia del docente %d: %v", registro.DocenteID, err)
+		return
+	}
+	suplencias, err := uc.suplenciaRepo.Find(repositories.FiltroSuplencias{
+		SuplenteID: &registro.DocenteID,
+		TurnoID:    &registro.TurnoID,
+		Desde:      &fecha,
+		Hasta:      &fecha,
+	})
+	if err != nil {
+		log.Printf("[WARN] No se pudieron consultar las suplencias del docente %d: %v", registro.DocenteID, err)
+		return
+	}
+	if len(suplencias) == 0 {
+		return
+	}
+
+	suplencia := suplencias[0]
+	registro.SuplenciaID = &suplencia.ID
+	uc.advertirAulaDistinta(registro, suplencia)
+}
+
+// AplicarSuplencia vincula a una suplencia recién asignada los registros que el suplente ya hizo
+// en esa ocurrencia del turno (la suplencia se carga después de la clase) y retorna cuántos
+// vinculó. Cada salida sigue a su ingreso
+func (uc *RegistroUseCase) AplicarSuplencia(suplencia *entities.Suplencia) (int, error) {
+	// La ocurrencia puede terminar al día siguiente (turnos nocturnos)
+	registros, err := uc.registroRepo.FindByDocenteYFecha(suplencia.SuplenteID, suplencia.Fecha.Time)
+	if err != nil {
+		return 0, err
+	}
+	siguientes, err := uc.registroRepo.FindByDocenteYFecha(suplencia.SuplenteID, siguienteDia(suplencia.Fecha).Time)
 	if err != nil {
-		// Si falla, intentar como hora simple
-		inicio, err = time.Parse("15:04:05", horaInicio)
+		return 0, err
+	}
+	registros = append(registros, siguientes...)
+
+	ingresos := map[int]bool{}
+	afectados := []*entities.Registro{}
+	for _, registro := range registros {
+		if registro.Tipo != entities.TipoIngreso || registro.TurnoID != suplencia.TurnoID || registro.SuplenciaID != nil {
+			continue
+		}
+		fecha, err := uc.FechaDeTurno(registro)
 		if err != nil {
-			return 0
+			return 0, err
+		}
+		if fecha.String() == suplencia.Fecha.String() {
+			ingresos[registro.ID] = true
+			afectados = append(afectados, registro)
+			uc.advertirAulaDistinta(registro, suplencia)
+		}
+	}
+	for _, registro := range registros {
+		if registro.Tipo == entities.TipoSalida && registro.IngresoID != nil && ingresos[*registro.IngresoID] && registro.SuplenciaID == nil {
+			afectados = append(afectados, registro)
 		}
 	}
 
-	// Combinar fecha actual con hora de inicio del turno
-	horaInicioTurno := time.Date(ahora.Year(), ahora.Month(), ahora.Day(),
-		inicio.Hour(), inicio.Minute(), inicio.Second(), 0, ahora.Location())
+	for _, registro := range afectados {
+		registro.SuplenciaID = &suplencia.ID
+		if err := uc.registroRepo.Update(registro); err != nil {
+			return 0, fmt.Errorf("error actualizando el registro %d: %w", registro.ID, err)
+		}
+	}
+	return len(afectados), nil
+}
 
-	// Si llegó después de la hora de inicio, calcular retraso
-	if ahora.After(horaInicioTurno) {
-		return int(ahora.Sub(horaInicioTurno).Minutes())
+// advertirAulaDistinta deja en el log los registros de una suplencia hechos con la llave de otra aula
+func (uc *RegistroUseCase) advertirAulaDistinta(registro *entities.Registro, suplencia *entities.Suplencia) {
+	if registro.LlaveID == nil {
+		return
+	}
+	if llave, err := uc.llaveRepo.FindByID(*registro.LlaveID); err == nil && llave.AulaCodigo != suplencia.AulaCodigo {
+		log.Printf("[WARN] El docente %d cubre la suplencia %d del aula %s con la llave %s del aula %s",
+			registro.DocenteID, suplencia.ID, suplencia.AulaCodigo, llave.Codigo, llave.AulaCodigo)
 	}
+}
 
-	return 0
+// FechaDeTurno retorna el día en que empieza la ocurrencia del turno a la que corresponde el
+// registro; para una salida de un turno nocturno es el día anterior
+func (uc *RegistroUseCase) FechaDeTurno(registro *entities.Registro) (entities.Fecha, error) {
+	turno, err := uc.turnoRepo.FindByID(registro.TurnoID)
+	if err != nil {
+		return entities.Fecha{}, fmt.Errorf("turno no encontrado: %w", err)
+	}
+	calendario, err := cargarCalendarioEntorno(uc.calendarioRepo, registro.FechaHora.In(uc.clock.Location()))
+	if err != nil {
+		log.Printf("[WARN] Ubicando el registro sin calendario académico: %v", err)
+	}
+	inicio, _ := ocurrenciaCercana(uc.clock, calendario, turno, registro.FechaHora)
+	return entities.FechaDe(inicio), nil
 }
 
-func (uc *RegistroUseCase) calcularMinutosExtra(ahora time.Time, horaFin string) int {
-	// Parsear hora de fin del turno (puede venir como "18:00:00" o "0000-01-01T18:00:00Z")
-	var fin time.Time
-	var err error
+// AplicarJustificacion clasifica como justificados los registros con retraso, falta o salida
+// anticipada que cubre una justificación recién aprobada y retorna cuántos cambiaron. Si alguno
+// cae en un mes cerrado no se modifica ninguno
+func (uc *RegistroUseCase) AplicarJustificacion(justificacion *entities.Justificacion) (int, error) {
+	candidatos, err := uc.registrosJustificables(justificacion)
+	if err != nil {
+		return 0, err
+	}
 
-	// Intentar parsear como timestamp ISO primero
-	fin, err = time.Parse(time.RFC3339, horaFin)
+	afectados := []*entities.Registro{}
+	instantes := []time.Time{}
+	for _, registro := range candidatos {
+		if !registro.Clasificacion.Justificable() {
+			continue
+		}
+		fecha, err := uc.FechaDeTurno(registro)
+		if err != nil {
+			return 0, err
+		}
+		if justificacion.Cubre(registro, fecha) {
+			afectados = append(afectados, registro)
+			instantes = append(instantes, registro.FechaHora)
+		}
+	}
+
+	if err := verificarPeriodoAbierto(uc.cierreRepo, uc.clock, instantes...); err != nil {
+		return 0, err
+	}
+	for _, registro := range afectados {
+		registro.Clasificacion = entities.ClasificacionJustificada
+		if err := uc.registroRepo.Update(registro); err != nil {
+			return 0, fmt.Errorf("error actualizando el registro %d: %w", registro.ID, err)
+		}
+	}
+	return len(afectados), nil
+}
+
+// ReclasificarLicencia vuelve a clasificar los registros del docente en las ocurrencias de turno
+// que empiezan entre desde y hasta, después de crear, modificar o eliminar una licencia que las
+// cubre, y retorna cuántos cambiaron. Si alguno cae en un mes cerrado no se modifica ninguno
+func (uc *RegistroUseCase) ReclasificarLicencia(docenteID int, desde, hasta entities.Fecha) (int, error) {
+	// La última ocurrencia puede terminar al día siguiente (turnos nocturnos)
+	inicio, _ := clock.Dia(uc.clock, desde.Time)
+	_, fin := clock.Dia(uc.clock, siguienteDia(hasta).Time)
+	registros, err := uc.registroRepo.FindByDocenteYRango(docenteID, inicio, fin)
 	if err != nil {
-		// Si falla, intentar como hora simple
-		fin, err = time.Parse("15:04:05", horaFin)
+		return 0, err
+	}
+
+	turnos := map[int]*entities.Turno{}
+	afectados := []*entities.Registro{}
+	instantes := []time.Time{}
+	for _, registro := range registros {
+		turno, ok := turnos[registro.TurnoID]
+		if !ok {
+			if turno, err = uc.turnoRepo.FindByID(registro.TurnoID); err != nil {
+				return 0, fmt.Errorf("turno no encontrado: %w", err)
+			}
+			turnos[registro.TurnoID] = turno
+		}
+		fecha, err := uc.FechaDeTurno(registro)
 		if err != nil {
-			return 0
+			return 0, err
+		}
+		if fecha.Antes(desde) || hasta.Antes(fecha) {
+			continue
+		}
+
+		anterior := *registro
+		uc.clasificar(registro, turno)
+		if registro.Clasificacion != anterior.Clasificacion || registro.MinutosRetraso != anterior.MinutosRetraso ||
+			registro.MinutosExtra != anterior.MinutosExtra {
+			afectados = append(afectados, registro)
+			instantes = append(instantes, registro.FechaHora)
 		}
 	}
 
-	horaFinTurno := time.Date(ahora.Year(), ahora.Month(), ahora.Day(),
-		fin.Hour(), fin.Minute(), fin.Second(), 0, ahora.Location())
+	if err := verificarPeriodoAbierto(uc.cierreRepo, uc.clock, instantes...); err != nil {
+		return 0, err
+	}
+	for _, registro := range afectados {
+		if err := uc.registroRepo.Update(registro); err != nil {
+			return 0, fmt.Errorf("error actualizando el registro %d: %w", registro.ID, err)
+		}
+	}
+	return len(afectados), nil
+}
 
-	// Si salió después de la hora de fin, calcular minutos extra
-	if ahora.After(horaFinTurno) {
-		return int(ahora.Sub(horaFinTurno).Minutes())
+// registrosJustificables retorna los registros que la justificación podría cubrir: el registro
+// adjunto o los del docente en la fecha y el día siguiente (salidas de turnos nocturnos)
+func (uc *RegistroUseCase) registrosJustificables(justificacion *entities.Justificacion) ([]*entities.Registro, error) {
+	if justificacion.RegistroID != nil {
+		registro, err := uc.registroRepo.FindByID(*justificacion.RegistroID)
+		if err != nil {
+			return nil, err
+		}
+		return []*entities.Registro{registro}, nil
+	}
+
+	registros, err := uc.registroRepo.FindByDocenteYFecha(justificacion.DocenteID, justificacion.Fecha.Time)
+	if err != nil {
+		return nil, err
 	}
+	siguientes, err := uc.registroRepo.FindByDocenteYFecha(justificacion.DocenteID, siguienteDia(justificacion.Fecha).Time)
+	if err != nil {
+		return nil, err
+	}
+	return append(registros, siguientes...), nil
+}
 
+// minutosDespues retorna cuántos minutos completos es instante posterior a referencia, o 0
+func minutosDespues(instante, referencia time.Time) int {
+	if instante.After(referencia) {
+		return int(instante.Sub(referencia).Minutes())
+	}
 	return 0
 }
@@ -0,0 +1,383 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+// calendarioVacio no define periodos ni eventos: todos los días del turno son laborables
+type calendarioVacio struct {
+	repositories.CalendarioRepository
+}
+
+func (calendarioVacio) FindPeriodos() ([]*entities.PeriodoAcademico, error) {
+	return nil, nil
+}
+
+func (calendarioVacio) FindEventos(desde, hasta *entities.Fecha) ([]*entities.EventoCalendario, error) {
+	return nil, nil
+}
+
+type sinJustificaciones struct {
+	repositories.JustificacionRepository
+}
+
+func (sinJustificaciones) FindAprobadas(docenteID int, fecha entities.Fecha) ([]*entities.Justificacion, error) {
+	return nil, nil
+}
+
+// licenciasEnMemoria retorna las licencias del docente que se cruzan con el rango, como el repositorio
+type licenciasEnMemoria struct {
+	repositories.LicenciaRepository
+	licencias []*entities.Licencia
+}
+
+func (r *licenciasEnMemoria) Find(filtro repositories.FiltroLicencias) ([]*entities.Licencia, error) {
+	var encontradas []*entities.Licencia
+	for _, licencia := range r.licencias {
+		if filtro.DocenteID != nil && licencia.DocenteID != *filtro.DocenteID {
+			continue
+		}
+		if (filtro.Desde != nil && licencia.FechaFin.Antes(*filtro.Desde)) || (filtro.Hasta != nil && filtro.Hasta.Antes(licencia.FechaInicio)) {
+			continue
+		}
+		encontradas = append(encontradas, licencia)
+	}
+	return encontradas, nil
+}
+
+type registrosEnMemoria struct {
+	repositories.RegistroRepository
+	registros    []*entities.Registro
+	actualizados []int
+}
+
+func (r *registrosEnMemoria) FindByDocenteYRango(docenteID int, desde, hasta time.Time) ([]*entities.Registro, error) {
+	var encontrados []*entities.Registro
+	for _, registro := range r.registros {
+		if registro.DocenteID == docenteID && !registro.FechaHora.Before(desde) && registro.FechaHora.Before(hasta) {
+			copia := *registro
+			encontrados = append(encontrados, &copia)
+		}
+	}
+	return encontrados, nil
+}
+
+func (r *registrosEnMemoria) Update(registro *entities.Registro) error {
+	for i := range r.registros {
+		if r.registros[i].ID == registro.ID {
+			copia := *registro
+			r.registros[i] = &copia
+			r.actualizados = append(r.actualizados, registro.ID)
+			return nil
+		}
+	}
+	return fmt.Errorf("registro %d no encontrado", registro.ID)
+}
+
+func (r *registrosEnMemoria) Create(registro *entities.Registro) error {
+	registro.ID = len(r.registros) + 1
+	r.registros = append(r.registros, registro)
+	return nil
+}
+
+// FindSesiones arma las sesiones a partir de los ingresos; una salida cierra el ingreso al que apunta
+func (r *registrosEnMemoria) FindSesiones(filtro repositories.FiltroSesiones) ([]*entities.Sesion, error) {
+	cerrados := map[int]bool{}
+	for _, registro := range r.registros {
+		if registro.Tipo == entities.TipoSalida && registro.IngresoID != nil {
+			cerrados[*registro.IngresoID] = true
+		}
+	}
+	var sesiones []*entities.Sesion
+	for _, registro := range r.registros {
+		if registro.Tipo != entities.TipoIngreso || (filtro.SoloAbiertas && cerrados[registro.ID]) {
+			continue
+		}
+		if (filtro.DocenteID != nil && registro.DocenteID != *filtro.DocenteID) || (filtro.Desde != nil && registro.FechaHora.Before(*filtro.Desde)) {
+			continue
+		}
+		sesiones = append(sesiones, &entities.Sesion{
+			IngresoID: registro.ID, DocenteID: registro.DocenteID, TurnoID: registro.TurnoID,
+			HoraIngreso: registro.FechaHora, Abierta: !cerrados[registro.ID],
+		})
+	}
+	return sesiones, nil
+}
+
+type sinSuplencias struct {
+	repositories.SuplenciaRepository
+}
+
+func (sinSuplencias) Find(filtro repositories.FiltroSuplencias) ([]*entities.Suplencia, error) {
+	return nil, nil
+}
+
+type turnosEnMemoria struct {
+	repositories.TurnoRepository
+	turnos map[int]*entities.Turno
+}
+
+func (r turnosEnMemoria) FindByID(id int) (*entities.Turno, error) {
+	if turno, ok := r.turnos[id]; ok {
+		return turno, nil
+	}
+	return nil, fmt.Errorf("turno %d no encontrado", id)
+}
+
+type mesesAbiertos struct {
+	repositories.CierrePeriodoRepository
+}
+
+func (mesesAbiertos) EstaCerrado(mes entities.MesContable) (bool, error) {
+	return false, nil
+}
+
+// nuevoTurno crea un turno con tolerancia de 10 minutos, retraso máximo de 30 y salida anticipada de 10
+func nuevoTurno(id int, inicio, fin entities.HoraDelDia) *entities.Turno {
+	turno := entities.NuevoTurno()
+	turno.ID = id
+	turno.HoraInicio, turno.HoraFin = inicio, fin
+	turno.ToleranciaIngresoMin = 10
+	turno.RetrasoMaximoMin = 30
+	turno.SalidaAnticipadaMin = 10
+	return &turno
+}
+
+func TestClasificar(t *testing.T) {
+	laPaz, err := time.LoadLocation(clock.DefaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloj := clock.Fixed(time.Date(2024, 1, 15, 12, 0, 0, 0, laPaz), laPaz)
+	// El docente 2 está con licencia el 16 de enero
+	licencias := &licenciasEnMemoria{licencias: []*entities.Licencia{{
+		ID: 1, DocenteID: 2, Tipo: entities.LicenciaEnfermedad,
+		FechaInicio: entities.FechaDe(time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)),
+		FechaFin:    entities.FechaDe(time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)),
+	}}}
+	uc := NewRegistroUseCase(nil, nil, nil, calendarioVacio{}, nil, sinJustificaciones{}, licencias, nil, reloj, 30*time.Minute)
+
+	manana := nuevoTurno(1, entities.NuevaHoraDelDia(8, 0, 0), entities.NuevaHoraDelDia(12, 0, 0))
+	noche := nuevoTurno(1, entities.NuevaHoraDelDia(19, 0, 0), entities.NuevaHoraDelDia(22, 0, 0))
+
+	casos := []struct {
+		nombre        string
+		docente       int
+		turno         *entities.Turno
+		tipo          entities.TipoRegistro
+		instante      time.Time
+		clasificacion entities.ClasificacionRegistro
+		retraso       int
+		extra         int
+	}{
+		{"ingreso antes de la hora", 1, manana, entities.TipoIngreso, time.Date(2024, 1, 15, 7, 50, 0, 0, laPaz), entities.ClasificacionPuntual, 0, 0},
+		{"ingreso en el límite de la tolerancia", 1, manana, entities.TipoIngreso, time.Date(2024, 1, 15, 8, 10, 0, 0, laPaz), entities.ClasificacionPuntual, 0, 0},
+		{"ingreso pasada la tolerancia", 1, manana, entities.TipoIngreso, time.Date(2024, 1, 15, 8, 11, 0, 0, laPaz), entities.ClasificacionTarde, 11, 0},
+		{"ingreso en el retraso máximo", 1, manana, entities.TipoIngreso, time.Date(2024, 1, 15, 8, 30, 0, 0, laPaz), entities.ClasificacionTarde, 30, 0},
+		{"ingreso pasado el retraso máximo", 1, manana, entities.TipoIngreso, time.Date(2024, 1, 15, 8, 31, 0, 0, laPaz), entities.ClasificacionFalta, 31, 0},
+		{"salida dentro del margen", 1, manana, entities.TipoSalida, time.Date(2024, 1, 15, 11, 50, 0, 0, laPaz), entities.ClasificacionPuntual, 0, 0},
+		{"salida anticipada", 1, manana, entities.TipoSalida, time.Date(2024, 1, 15, 11, 49, 0, 0, laPaz), entities.ClasificacionSalidaAnticipada, 0, 0},
+		{"salida después del fin", 1, manana, entities.TipoSalida, time.Date(2024, 1, 15, 12, 25, 0, 0, laPaz), entities.ClasificacionPuntual, 0, 25},
+		// 19:15 en La Paz son las 23:15 UTC; 21:55 ya es el día siguiente en UTC
+		{"ingreso nocturno con el reloj en UTC", 1, noche, entities.TipoIngreso, time.Date(2024, 1, 15, 23, 15, 0, 0, time.UTC), entities.ClasificacionTarde, 15, 0},
+		{"salida nocturna pasada la medianoche UTC", 1, noche, entities.TipoSalida, time.Date(2024, 1, 16, 1, 55, 0, 0, time.UTC), entities.ClasificacionPuntual, 0, 0},
+		// La licencia no borra los minutos, pero la llegada no se califica
+		{"falta durante una licencia", 2, manana, entities.TipoIngreso, time.Date(2024, 1, 16, 8, 31, 0, 0, laPaz), entities.ClasificacionJustificada, 31, 0},
+		{"salida anticipada durante una licencia", 2, manana, entities.TipoSalida, time.Date(2024, 1, 16, 11, 0, 0, 0, laPaz), entities.ClasificacionJustificada, 0, 0},
+		{"puntual durante una licencia", 2, manana, entities.TipoIngreso, time.Date(2024, 1, 16, 8, 0, 0, 0, laPaz), entities.ClasificacionPuntual, 0, 0},
+		{"falta del mismo docente fuera de la licencia", 2, manana, entities.TipoIngreso, time.Date(2024, 1, 17, 8, 31, 0, 0, laPaz), entities.ClasificacionFalta, 31, 0},
+		// La salida de las 01:30 del 16 pertenece al turno nocturno del 15, sin licencia
+		{"salida de la noche anterior a la licencia", 2, noche, entities.TipoSalida, time.Date(2024, 1, 16, 1, 30, 0, 0, laPaz), entities.ClasificacionPuntual, 0, 210},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			registro := &entities.Registro{DocenteID: c.docente, Tipo: c.tipo, FechaHora: c.instante}
+			uc.clasificar(registro, c.turno)
+
+			if registro.Clasificacion != c.clasificacion {
+				t.Errorf("clasificación = %s, se esperaba %s", registro.Clasificacion, c.clasificacion)
+			}
+			if registro.MinutosRetraso != c.retraso {
+				t.Errorf("minutos de retraso = %d, se esperaban %d", registro.MinutosRetraso, c.retraso)
+			}
+			if registro.MinutosExtra != c.extra {
+				t.Errorf("minutos extra = %d, se esperaban %d", registro.MinutosExtra, c.extra)
+			}
+		})
+	}
+}
+
+func TestReclasificarLicencia(t *testing.T) {
+	laPaz, err := time.LoadLocation(clock.DefaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloj := clock.Fixed(time.Date(2024, 1, 20, 12, 0, 0, 0, laPaz), laPaz)
+	turnos := turnosEnMemoria{turnos: map[int]*entities.Turno{
+		1: nuevoTurno(1, entities.NuevaHoraDelDia(8, 0, 0), entities.NuevaHoraDelDia(12, 0, 0)),
+		2: nuevoTurno(2, entities.NuevaHoraDelDia(19, 0, 0), entities.NuevaHoraDelDia(22, 0, 0)),
+	}}
+	registros := &registrosEnMemoria{}
+	licencias := &licenciasEnMemoria{}
+	uc := NewRegistroUseCase(registros, turnos, nil, calendarioVacio{}, mesesAbiertos{}, sinJustificaciones{}, licencias, nil, reloj, 30*time.Minute)
+
+	// Registros clasificados antes de cargar la licencia del 16 al 17
+	registrar := func(id, turnoID int, tipo entities.TipoRegistro, instante time.Time) {
+		registro := &entities.Registro{ID: id, DocenteID: 2, TurnoID: turnoID, Tipo: tipo, FechaHora: instante}
+		uc.clasificar(registro, turnos.turnos[turnoID])
+		registros.registros = append(registros.registros, registro)
+	}
+	registrar(1, 1, entities.TipoIngreso, time.Date(2024, 1, 16, 8, 20, 0, 0, laPaz))  // tarde
+	registrar(2, 1, entities.TipoSalida, time.Date(2024, 1, 16, 11, 0, 0, 0, laPaz))   // salida anticipada
+	registrar(3, 2, entities.TipoIngreso, time.Date(2024, 1, 17, 19, 40, 0, 0, laPaz)) // falta, turno del 17
+	registrar(4, 2, entities.TipoSalida, time.Date(2024, 1, 18, 0, 30, 0, 0, laPaz))   // puntual, turno del 17
+	registrar(5, 2, entities.TipoIngreso, time.Date(2024, 1, 15, 19, 20, 0, 0, laPaz)) // tarde, turno del 15
+	registrar(6, 1, entities.TipoIngreso, time.Date(2024, 1, 18, 8, 45, 0, 0, laPaz))  // falta, fuera de la licencia
+
+	clasificaciones := func() map[int]entities.ClasificacionRegistro {
+		resultado := map[int]entities.ClasificacionRegistro{}
+		for _, registro := range registros.registros {
+			resultado[registro.ID] = registro.Clasificacion
+		}
+		return resultado
+	}
+	originales := clasificaciones()
+
+	desde := entities.FechaDe(time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC))
+	hasta := entities.FechaDe(time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC))
+	licencias.licencias = []*entities.Licencia{{ID: 1, DocenteID: 2, Tipo: entities.LicenciaComision, FechaInicio: desde, FechaFin: hasta}}
+
+	cambiados, err := uc.ReclasificarLicencia(2, desde, hasta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cambiados != 3 {
+		t.Errorf("registros reclasificados = %d, se esperaban 3", cambiados)
+	}
+	despues := clasificaciones()
+	for id, espera := range map[int]entities.ClasificacionRegistro{
+		1: entities.ClasificacionJustificada,
+		2: entities.ClasificacionJustificada,
+		3: entities.ClasificacionJustificada,
+		4: entities.ClasificacionPuntual,
+		5: entities.ClasificacionTarde,
+		6: entities.ClasificacionFalta,
+	} {
+		if despues[id] != espera {
+			t.Errorf("registro %d: %s, se esperaba %s", id, despues[id], espera)
+		}
+	}
+	if registros.registros[0].MinutosRetraso != 20 {
+		t.Errorf("minutos de retraso del registro 1 = %d, se conservan 20", registros.registros[0].MinutosRetraso)
+	}
+
+	// Al eliminar la licencia los registros vuelven a su clasificación
+	licencias.licencias = nil
+	if cambiados, err = uc.ReclasificarLicencia(2, desde, hasta); err != nil {
+		t.Fatal(err)
+	}
+	if cambiados != 3 {
+		t.Errorf("registros reclasificados al eliminar = %d, se esperaban 3", cambiados)
+	}
+	for id, clasificacion := range clasificaciones() {
+		if clasificacion != originales[id] {
+			t.Errorf("registro %d: %s, se esperaba %s", id, clasificacion, originales[id])
+		}
+	}
+}
+
+func TestRegistrarIngresoConIngresoAbierto(t *testing.T) {
+	laPaz, err := time.LoadLocation(clock.DefaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	turnos := turnosEnMemoria{turnos: map[int]*entities.Turno{
+		1: nuevoTurno(1, entities.NuevaHoraDelDia(8, 0, 0), entities.NuevaHoraDelDia(12, 0, 0)),
+		2: nuevoTurno(2, entities.NuevaHoraDelDia(14, 0, 0), entities.NuevaHoraDelDia(18, 0, 0)),
+		3: nuevoTurno(3, entities.NuevaHoraDelDia(19, 0, 0), entities.NuevaHoraDelDia(22, 0, 0)),
+	}}
+	ingreso := func(id, turnoID int, instante time.Time) *entities.Registro {
+		return &entities.Registro{ID: id, DocenteID: 2, TurnoID: turnoID, Tipo: entities.TipoIngreso, FechaHora: instante}
+	}
+	salida := func(id, ingresoID int, instante time.Time) *entities.Registro {
+		return &entities.Registro{ID: id, DocenteID: 2, TurnoID: 1, Tipo: entities.TipoSalida, FechaHora: instante, IngresoID: &ingresoID}
+	}
+
+	casos := []struct {
+		nombre      string
+		ahora       time.Time
+		turnoID     int
+		previos     []*entities.Registro
+		esperaError bool
+	}{
+		{
+			nombre:      "ingreso abierto en la misma ocurrencia",
+			ahora:       time.Date(2024, 1, 15, 9, 0, 0, 0, laPaz),
+			turnoID:     1,
+			previos:     []*entities.Registro{ingreso(1, 1, time.Date(2024, 1, 15, 7, 55, 0, 0, laPaz))},
+			esperaError: true,
+		},
+		{
+			nombre:      "turno nocturno pasada la medianoche",
+			ahora:       time.Date(2024, 1, 16, 0, 30, 0, 0, laPaz),
+			turnoID:     3,
+			previos:     []*entities.Registro{ingreso(1, 3, time.Date(2024, 1, 15, 19, 5, 0, 0, laPaz))},
+			esperaError: true,
+		},
+		{
+			nombre:  "ingreso ya cerrado con su salida",
+			ahora:   time.Date(2024, 1, 15, 11, 0, 0, 0, laPaz),
+			turnoID: 1,
+			previos: []*entities.Registro{
+				ingreso(1, 1, time.Date(2024, 1, 15, 7, 55, 0, 0, laPaz)),
+				salida(2, 1, time.Date(2024, 1, 15, 10, 0, 0, 0, laPaz)),
+			},
+		},
+		{
+			nombre:  "salida olvidada el día anterior",
+			ahora:   time.Date(2024, 1, 16, 8, 0, 0, 0, laPaz),
+			turnoID: 1,
+			previos: []*entities.Registro{ingreso(1, 1, time.Date(2024, 1, 15, 7, 55, 0, 0, laPaz))},
+		},
+		{
+			nombre:  "ingreso abierto en otro turno",
+			ahora:   time.Date(2024, 1, 15, 14, 0, 0, 0, laPaz),
+			turnoID: 2,
+			previos: []*entities.Registro{ingreso(1, 1, time.Date(2024, 1, 15, 7, 55, 0, 0, laPaz))},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			registros := &registrosEnMemoria{registros: c.previos}
+			reloj := clock.Fixed(c.ahora, laPaz)
+			uc := NewRegistroUseCase(registros, turnos, nil, calendarioVacio{}, mesesAbiertos{}, sinJustificaciones{}, &licenciasEnMemoria{}, sinSuplencias{}, reloj, 30*time.Minute)
+
+			turnoID := c.turnoID
+			registro, _, err := uc.RegistrarIngreso(2, &turnoID, nil, nil)
+			if c.esperaError {
+				if !errors.Is(err, ErrIngresoAbiertoEnTurno) {
+					t.Fatalf("error = %v, se esperaba ErrIngresoAbiertoEnTurno", err)
+				}
+				if len(registros.registros) != len(c.previos) {
+					t.Error("se creó el ingreso duplicado")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if registro.ID == 0 || len(registros.registros) != len(c.previos)+1 {
+				t.Error("no se creó el ingreso")
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package usecases
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+type ReporteUseCase struct {
+	registroRepo      repositories.RegistroRepository
+	turnoRepo         repositories.TurnoRepository
+	calendarioRepo    repositories.CalendarioRepository
+	justificacionRepo repositories.JustificacionRepository
+	licenciaRepo      repositories.LicenciaRepository
+	suplenciaRepo     repositories.SuplenciaRepository
+	clock             clock.Clock
+}
+
+func NewReporteUseCase(
+	registroRepo repositories.RegistroRepository,
+	turnoRepo repositories.TurnoRepository,
+	calendarioRepo repositories.CalendarioRepository,
+	justificacionRepo repositories.JustificacionRepository,
+	licenciaRepo repositories.LicenciaRepository,
+	suplenciaRepo repositories.SuplenciaRepository,
+	reloj clock.Clock,
+) *ReporteUseCase {
+	return &ReporteUseCase{
+		registroRepo:      registroRepo,
+		turnoRepo:         turnoRepo,
+		calendarioRepo:    calendarioRepo,
+		justificacionRepo: justificacionRepo,
+		licenciaRepo:      licenciaRepo,
+		suplenciaRepo:     suplenciaRepo,
+		clock:             reloj,
+	}
+}
+
+// ReporteAsistencia resume las sesiones de cada docente entre desde y hasta (días de la
+// institución, inclusive). Con docenteID solo se incluye ese docente
+func (uc *ReporteUseCase) ReporteAsistencia(desde, hasta entities.Fecha, docenteID *int) (*entities.ReporteAsistencia, error) {
+	if err := validarRangoCalendario(desde, hasta); err != nil {
+		return nil, err
+	}
+
+	turnos, err := uc.turnoRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	turnosPorID := make(map[int]*entities.Turno, len(turnos))
+	for _, turno := range turnos {
+		turnosPorID[turno.ID] = turno
+	}
+
+	calendario, err := cargarCalendario(uc.calendarioRepo, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	inicio, _ := clock.Dia(uc.clock, desde.Time)
+	_, fin := clock.Dia(uc.clock, hasta.Time)
+	sesiones, err := uc.registroRepo.FindSesiones(repositories.FiltroSesiones{
+		Desde:     &inicio,
+		Hasta:     &fin,
+		DocenteID: docenteID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reporte := &entities.ReporteAsistencia{
+		Desde:    desde,
+		Hasta:    hasta,
+		Turnos:   []*entities.DiasLaborablesTurno{},
+		Docentes: []*entities.ResumenAsistencia{},
+	}
+
+	for _, turno := range turnos {
+		if !turno.Activo {
+			continue
+		}
+		dias := &entities.DiasLaborablesTurno{TurnoID: turno.ID, TurnoNombre: turno.Nombre}
+		for fecha := desde; !hasta.Antes(fecha); fecha = siguienteDia(fecha) {
+			if calendario.Dia(turno, fecha).Laborable {
+				dias.DiasLaborables++
+			}
+		}
+		reporte.Turnos = append(reporte.Turnos, dias)
+	}
+
+	licencias, err := uc.licenciaRepo.Find(repositories.FiltroLicencias{DocenteID: docenteID, Desde: &desde, Hasta: &hasta})
+	if err != nil {
+		return nil, err
+	}
+	licenciasDe := agruparLicencias(licencias)
+
+	resumenes := map[int]*entities.ResumenAsistencia{}
+	resumenDe := func(docenteID int, nombre, ci string) *entities.ResumenAsistencia {
+		resumen, ok := resumenes[docenteID]
+		if !ok {
+			resumen = &entities.ResumenAsistencia{DocenteID: docenteID, DocenteNombre: nombre, DocenteCI: ci}
+			resumenes[docenteID] = resumen
+			reporte.Docentes = append(reporte.Docentes, resumen)
+		}
+		return resumen
+	}
+
+	// Las sesiones vienen de la más reciente a la más antigua; se recorren al revés para que los
+	// docentes queden en orden de su primera sesión
+	for i := len(sesiones) - 1; i >= 0; i-- {
+		sesion := sesiones[i]
+		resumen := resumenDe(sesion.DocenteID, sesion.DocenteNombre, sesion.DocenteCI)
+
+		fecha := entities.FechaDe(sesion.HoraIngreso.In(uc.clock.Location()))
+		laborable := true
+		if turno, ok := turnosPorID[sesion.TurnoID]; ok {
+			laborable = calendario.Dia(turno, fecha).Laborable
+		}
+		acumularSesion(resumen, sesion, laborable, licenciasDe.En(sesion.DocenteID, fecha) != nil)
+	}
+
+	// Las licencias se recorren de la más antigua a la más reciente, como las sesiones
+	for i := len(licencias) - 1; i >= 0; i-- {
+		licencia := licencias[i]
+		resumen := resumenDe(licencia.DocenteID, licencia.DocenteNombre, licencia.DocenteCI)
+		for fecha := desde; !hasta.Antes(fecha); fecha = siguienteDia(fecha) {
+			if licencia.Cubre(fecha) {
+				resumen.DiasLicencia++
+			}
+		}
+	}
+
+	// El titular reemplazado figura con sus reemplazos; las horas ya están en las sesiones del suplente
+	suplencias, err := uc.suplenciaRepo.Find(repositories.FiltroSuplencias{TitularID: docenteID, Desde: &desde, Hasta: &hasta})
+	if err != nil {
+		return nil, err
+	}
+	for i := len(suplencias) - 1; i >= 0; i-- {
+		suplencia := suplencias[i]
+		resumenDe(suplencia.TitularID, suplencia.TitularNombre, suplencia.TitularCI).Reemplazos++
+	}
+
+	aprobada := entities.JustificacionAprobada
+	justificaciones, err := uc.justificacionRepo.Find(repositories.FiltroJustificaciones{
+		DocenteID: docenteID,
+		Estado:    &aprobada,
+		Desde:     &desde,
+		Hasta:     &hasta,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := len(justificaciones) - 1; i >= 0; i-- {
+		justificacion := justificaciones[i]
+		resumenDe(justificacion.DocenteID, justificacion.DocenteNombre, justificacion.DocenteCI).JustificacionesAprobadas++
+	}
+
+	return reporte, nil
+}
+
+// ResumenMensual resume la asistencia del docente en el mes. El mes en curso se cuenta hasta hoy;
+// sin sesiones ni novedades el resumen queda en cero
+func (uc *ReporteUseCase) ResumenMensual(docente *entities.Docente, mes entities.MesContable) (*entities.ResumenMensual, error) {
+	if !mes.Valido() {
+		return nil, fmt.Errorf("mes inválido")
+	}
+	hoy := entities.FechaDe(uc.clock.Now().In(uc.clock.Location()))
+	if entities.MesDe(hoy.Time).Antes(mes) {
+		return nil, fmt.Errorf("el mes %s aún no empieza", mes)
+	}
+
+	desde := entities.Fecha{Time: time.Date(mes.Anio, time.Month(mes.Mes), 1, 0, 0, 0, 0, time.UTC)}
+	hasta := entities.Fecha{Time: desde.AddDate(0, 1, -1)}
+	if hoy.Antes(hasta) {
+		hasta = hoy
+	}
+
+	reporte, err := uc.ReporteAsistencia(desde, hasta, &docente.ID)
+	if err != nil {
+		return nil, err
+	}
+	resumen := &entities.ResumenAsistencia{
+		DocenteID:     docente.ID,
+		DocenteNombre: docente.NombreCompleto,
+		DocenteCI:     strconv.FormatInt(docente.DocumentoIdentidad, 10),
+	}
+	if len(reporte.Docentes) > 0 {
+		resumen = reporte.Docentes[0]
+	}
+	return &entities.ResumenMensual{
+		Anio:    mes.Anio,
+		Mes:     mes.Mes,
+		Desde:   desde,
+		Hasta:   hasta,
+		Turnos:  reporte.Turnos,
+		Resumen: resumen,
+	}, nil
+}
+
+// acumularSesion suma una sesión al resumen del docente. En días no laborables y durante una
+// licencia el tiempo trabajado y los minutos extra cuentan, pero la llegada no se califica
+func acumularSesion(resumen *entities.ResumenAsistencia, sesion *entities.Sesion, laborable, enLicencia bool) {
+	resumen.Sesiones++
+	if sesion.Abierta {
+		resumen.SesionesAbiertas++
+	}
+	if sesion.DuracionMinutos != nil {
+		resumen.MinutosTrabajados += *sesion.DuracionMinutos
+	}
+	resumen.MinutosExtra += sesion.MinutosExtra
+	if sesion.SuplenciaID != nil {
+		resumen.Suplencias++
+		if sesion.DuracionMinutos != nil {
+			resumen.MinutosSuplencia += *sesion.DuracionMinutos
+		}
+	}
+
+	if !laborable {
+		resumen.EnDiasNoLaborables++
+		return
+	}
+	if enLicencia {
+		resumen.EnLicencia++
+		return
+	}
+
+	switch sesion.ClasificacionIngreso {
+	case entities.ClasificacionTarde:
+		resumen.Tardes++
+		resumen.MinutosRetraso += sesion.MinutosRetraso
+	case entities.ClasificacionFalta:
+		resumen.Faltas++
+		resumen.MinutosRetraso += sesion.MinutosRetraso
+	case entities.ClasificacionJustificada:
+		resumen.Justificadas++
+	default:
+		resumen.Puntuales++
+	}
+	if sesion.ClasificacionSalida != nil && *sesion.ClasificacionSalida == entities.ClasificacionSalidaAnticipada {
+		resumen.SalidasAnticipadas++
+	}
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+type SuplenciaUseCase struct {
+	suplenciaRepo   repositories.SuplenciaRepository
+	docenteRepo     repositories.DocenteRepository
+	turnoRepo       repositories.TurnoRepository
+	llaveRepo       repositories.LlaveRepository
+	calendarioRepo  repositories.CalendarioRepository
+	licenciaRepo    repositories.LicenciaRepository
+	cierreRepo      repositories.CierrePeriodoRepository
+	registroUseCase *RegistroUseCase
+	clock           clock.Clock
+}
+
+func NewSuplenciaUseCase(
+	suplenciaRepo repositories.SuplenciaRepository,
+	docenteRepo repositories.DocenteRepository,
+	turnoRepo repositories.TurnoRepository,
+	llaveRepo repositories.LlaveRepository,
+	calendarioRepo repositories.CalendarioRepository,
+	licenciaRepo repositories.LicenciaRepository,
+	cierreRepo repositories.CierrePeriodoRepository,
+	registroUseCase *RegistroUseCase,
+	reloj clock.Clock,
+) *SuplenciaUseCase {
+	return &SuplenciaUseCase{
+		suplenciaRepo:   suplenciaRepo,
+		docenteRepo:     docenteRepo,
+		turnoRepo:       turnoRepo,
+		llaveRepo:       llaveRepo,
+		calendarioRepo:  calendarioRepo,
+		licenciaRepo:    licenciaRepo,
+		cierreRepo:      cierreRepo,
+		registroUseCase: registroUseCase,
+		clock:           reloj,
+	}
+}
+
+func (uc *SuplenciaUseCase) GetByID(id int) (*entities.Suplencia, error) {
+	return uc.suplenciaRepo.FindByID(id)
+}
+
+func (uc *SuplenciaUseCase) Listar(filtro repositories.FiltroSuplencias) ([]*entities.Suplencia, error) {
+	return uc.suplenciaRepo.Find(filtro)
+}
+
+// Crear asigna el suplente y le vincula los registros que ya hizo en ese turno. Como cambia el
+// conteo de asistencia, la fecha no puede caer en un mes cerrado. Retorna cuántos registros vinculó
+func (uc *SuplenciaUseCase) Crear(suplencia *entities.Suplencia) (int, error) {
+	if err := uc.validar(suplencia); err != nil {
+		return 0, err
+	}
+	if err := verificarRangoAbierto(uc.cierreRepo, uc.clock, suplencia.Fecha, suplencia.Fecha); err != nil {
+		return 0, err
+	}
+	if err := uc.suplenciaRepo.Create(suplencia); err != nil {
+		return 0, err
+	}
+
+	vinculados, err := uc.registroUseCase.AplicarSuplencia(suplencia)
+	if err != nil {
+		// La suplencia queda asignada; sus registros se vinculan al volver a editarse
+		log.Printf("[WARN] Suplencia %d asignada sin vincular los registros existentes del suplente: %v", suplencia.ID, err)
+		return 0, nil
+	}
+	return vinculados, nil
+}
+
+// Eliminar quita una suplencia que aún no tiene registros; con registros vinculados el suplente
+// ya dictó la clase y la suplencia es parte de su asistencia
+func (uc *SuplenciaUseCase) Eliminar(id int) error {
+	suplencia, err := uc.suplenciaRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := verificarRangoAbierto(uc.cierreRepo, uc.clock, suplencia.Fecha, suplencia.Fecha); err != nil {
+		return err
+	}
+	registros, err := uc.suplenciaRepo.CountRegistros(id)
+	if err != nil {
+		return fmt.Errorf("error consultando los registros de la suplencia: %w", err)
+	}
+	if registros > 0 {
+		return fmt.Errorf("la suplencia tiene %d registros vinculados; corrija o elimine los registros primero", registros)
+	}
+	return uc.suplenciaRepo.Delete(id)
+}
+
+// validar verifica docentes, turno y aula, que la clase se dicte ese día y que ni el titular ni el
+// suplente tengan otra suplencia en el mismo turno
+func (uc *SuplenciaUseCase) validar(suplencia *entities.Suplencia) error {
+	if suplencia.Fecha.IsZero() {
+		return fmt.Errorf("fecha requerida")
+	}
+	if suplencia.TitularID == suplencia.SuplenteID {
+		return fmt.Errorf("el suplente debe ser un docente distinto del titular")
+	}
+	if _, err := uc.docenteRepo.FindByID(suplencia.TitularID); err != nil {
+		return fmt.Errorf("docente titular no encontrado")
+	}
+	if _, err := uc.docenteRepo.FindByID(suplencia.SuplenteID); err != nil {
+		return fmt.Errorf("docente suplente no encontrado")
+	}
+
+	suplencia.AulaCodigo = strings.TrimSpace(suplencia.AulaCodigo)
+	if suplencia.AulaCodigo == "" {
+		return fmt.Errorf("aula_codigo requerido")
+	}
+	llaves, err := uc.llaveRepo.FindByAulaCodigo(suplencia.AulaCodigo)
+	if err != nil {
+		return fmt.Errorf("error obteniendo el aula: %w", err)
+	}
+	if len(llaves) == 0 {
+		return fmt.Errorf("aula %s no encontrada", suplencia.AulaCodigo)
+	}
+
+	turno, err := uc.turnoRepo.FindByID(suplencia.TurnoID)
+	if err != nil {
+		return fmt.Errorf("turno no encontrado")
+	}
+	calendario, err := cargarCalendario(uc.calendarioRepo, suplencia.Fecha, suplencia.Fecha)
+	if err != nil {
+		return err
+	}
+	if dia := calendario.Dia(turno, suplencia.Fecha); !dia.Laborable {
+		return fmt.Errorf("el turno %s no tiene clases el %s: %s", turno.Nombre, suplencia.Fecha, dia.Motivo)
+	}
+
+	licencias, err := uc.licenciaRepo.Find(repositories.FiltroLicencias{
+		DocenteID: &suplencia.SuplenteID,
+		Desde:     &suplencia.Fecha,
+		Hasta:     &suplencia.Fecha,
+	})
+	if err != nil {
+		return fmt.Errorf("error obteniendo licencias: %w", err)
+	}
+	if len(licencias) > 0 {
+		return fmt.Errorf("el suplente está con licencia por %s el %s", licencias[0].Tipo, suplencia.Fecha)
+	}
+
+	suplencia.Motivo = textoOpcional(suplencia.Motivo)
+
+	otras, err := uc.suplenciaRepo.Find(repositories.FiltroSuplencias{
+		TurnoID: &suplencia.TurnoID,
+		Desde:   &suplencia.Fecha,
+		Hasta:   &suplencia.Fecha,
+	})
+	if err != nil {
+		return fmt.Errorf("error obteniendo suplencias: %w", err)
+	}
+	for _, otra := range otras {
+		switch {
+		case otra.SuplenteID == suplencia.SuplenteID:
+			return fmt.Errorf("el suplente ya cubre el aula %s en ese turno", otra.AulaCodigo)
+		case otra.TitularID == suplencia.TitularID && otra.AulaCodigo == suplencia.AulaCodigo:
+			return fmt.Errorf("la clase ya tiene como suplente a %s", otra.SuplenteNombre)
+		case otra.TitularID == suplencia.SuplenteID:
+			return fmt.Errorf("el suplente también es reemplazado en ese turno")
+		}
+	}
+	return nil
+}
@@ -6,14 +6,17 @@ import (
 
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
 )
 
 type TurnoUseCase struct {
-	turnoRepo repositories.TurnoRepository
+	turnoRepo      repositories.TurnoRepository
+	calendarioRepo repositories.CalendarioRepository
+	clock          clock.Clock
 }
 
-func NewTurnoUseCase(turnoRepo repositories.TurnoRepository) *TurnoUseCase {
-	return &TurnoUseCase{turnoRepo: turnoRepo}
+func NewTurnoUseCase(turnoRepo repositories.TurnoRepository, calendarioRepo repositories.CalendarioRepository, reloj clock.Clock) *TurnoUseCase {
+	return &TurnoUseCase{turnoRepo: turnoRepo, calendarioRepo: calendarioRepo, clock: reloj}
 }
 
 func (uc *TurnoUseCase) GetAll() ([]*entities.Turno, error) {
@@ -28,11 +31,10 @@ func (uc *TurnoUseCase) Create(turno *entities.Turno) error {
 	if turno.Nombre == "" {
 		return fmt.Errorf("nombre requerido")
 	}
-	if turno.HoraInicio == "" || turno.HoraFin == "" {
-		return fmt.Errorf("horarios requeridos")
-	}
-
 	turno.Activo = true
+	if err := uc.validar(turno); err != nil {
+		return err
+	}
 	return uc.turnoRepo.Create(turno)
 }
 
@@ -40,69 +42,231 @@ func (uc *TurnoUseCase) Update(turno *entities.Turno) error {
 	if turno.ID <= 0 {
 		return fmt.Errorf("ID inválido")
 	}
+	if err := uc.validar(turno); err != nil {
+		return err
+	}
 	return uc.turnoRepo.Update(turno)
 }
 
-func (uc *TurnoUseCase) Delete(id int) error {
-	return uc.turnoRepo.Delete(id)
+// validar verifica horario, días, vigencia y márgenes del turno, y que un turno activo no se
+// solape con otro turno activo
+func (uc *TurnoUseCase) validar(turno *entities.Turno) error {
+	if err := validarHorario(turno); err != nil {
+		return err
+	}
+	if err := validarMargenes(turno); err != nil {
+		return err
+	}
+	if !turno.Activo {
+		return nil
+	}
+
+	turnos, err := uc.turnoRepo.FindAll()
+	if err != nil {
+		return fmt.Errorf("error obteniendo turnos: %w", err)
+	}
+	for _, otro := range turnos {
+		if otro.ID == turno.ID || !otro.Activo {
+			continue
+		}
+		if turnosSeSolapan(turno, otro) {
+			return fmt.Errorf("el horario se solapa con el turno %s (%s - %s)", otro.Nombre, otro.HoraInicio.Corta(), otro.HoraFin.Corta())
+		}
+	}
+	return nil
 }
 
-// GetTurnoActual obtiene el turno que corresponde a la hora actual de Bolivia (UTC-4)
-// Incluye un margen de tolerancia de 10 minutos después del fin del turno
-func (uc *TurnoUseCase) GetTurnoActual() (*entities.Turno, error) {
-	// Obtener hora UTC actual
-	nowUTC := time.Now().UTC()
+// validarHorario verifica que las horas estén dentro del día y sean distintas, que el turno
+// aplique al menos un día y que la vigencia sea un rango válido
+func validarHorario(turno *entities.Turno) error {
+	if turno.HoraInicio == entities.HoraSinDefinir || turno.HoraFin == entities.HoraSinDefinir {
+		return fmt.Errorf("horarios requeridos")
+	}
+	if !turno.HoraInicio.Valida() || !turno.HoraFin.Valida() {
+		return fmt.Errorf("horarios inválidos")
+	}
+	if turno.HoraInicio == turno.HoraFin {
+		return fmt.Errorf("la hora de inicio y la de fin no pueden ser iguales")
+	}
+	if len(turno.DiasSemana.Dias()) == 0 {
+		return fmt.Errorf("el turno debe aplicar al menos un día de la semana")
+	}
+	if turno.VigenteDesde != nil && turno.VigenteHasta != nil && turno.VigenteHasta.Antes(*turno.VigenteDesde) {
+		return fmt.Errorf("la vigencia termina antes de empezar")
+	}
+	return nil
+}
 
-	// Convertir a hora de Bolivia (UTC-4)
-	boliviaLocation, err := time.LoadLocation("America/La_Paz")
-	if err != nil {
-		return nil, fmt.Errorf("error cargando timezone de Bolivia: %v", err)
+// validarMargenes verifica que los márgenes del turno sean coherentes entre sí
+func validarMargenes(turno *entities.Turno) error {
+	if turno.ToleranciaIngresoMin < 0 || turno.RetrasoMaximoMin < 0 || turno.SalidaAnticipadaMin < 0 {
+		return fmt.Errorf("los márgenes del turno no pueden ser negativos")
+	}
+	if turno.RetrasoMaximoMin < turno.ToleranciaIngresoMin {
+		return fmt.Errorf("el retraso máximo no puede ser menor que la tolerancia de ingreso")
+	}
+	return nil
+}
+
+// turnosSeSolapan indica si dos turnos con vigencias que se cruzan ocupan algún instante de la
+// semana en común. Cada turno se ubica en minutos desde el domingo 00:00 por cada día en que
+// empieza; el que cruza la medianoche del sábado continúa al inicio de la semana siguiente
+func turnosSeSolapan(a, b *entities.Turno) bool {
+	if !vigenciasSeCruzan(a, b) {
+		return false
 	}
 
-	nowBolivia := nowUTC.In(boliviaLocation)
+	const semana = 7 * 24 * time.Hour
+	for _, diaA := range a.DiasSemana.Dias() {
+		inicioA := time.Duration(diaA)*24*time.Hour + time.Duration(a.HoraInicio)*time.Second
+		finA := inicioA + a.Duracion()
+		for _, diaB := range b.DiasSemana.Dias() {
+			inicioB := time.Duration(diaB)*24*time.Hour + time.Duration(b.HoraInicio)*time.Second
+			finB := inicioB + b.Duracion()
+			for _, desfase := range []time.Duration{-semana, 0, semana} {
+				if inicioA < finB+desfase && inicioB+desfase < finA {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// vigenciasSeCruzan indica si los rangos de vigencia de dos turnos tienen algún día en común
+func vigenciasSeCruzan(a, b *entities.Turno) bool {
+	if a.VigenteHasta != nil && b.VigenteDesde != nil && a.VigenteHasta.Antes(*b.VigenteDesde) {
+		return false
+	}
+	if b.VigenteHasta != nil && a.VigenteDesde != nil && b.VigenteHasta.Antes(*a.VigenteDesde) {
+		return false
+	}
+	return true
+}
+
+func (uc *TurnoUseCase) Delete(id int) error {
+	return uc.turnoRepo.Delete(id)
+}
 
-	// Obtener todos los turnos activos
+// GetTurnoActual obtiene el turno que corresponde a la hora actual de la institución, según el
+// calendario académico (feriados, recesos y horarios especiales)
+// Tras hora_fin, el turno sigue siendo el actual durante su tolerancia de ingreso, para que las
+// salidas registradas justo después del fin se asocien al turno correcto
+func (uc *TurnoUseCase) GetTurnoActual() (*entities.Turno, error) {
 	turnos, err := uc.turnoRepo.FindAll()
 	if err != nil {
 		return nil, err
 	}
+	ahora := uc.clock.Now().In(uc.clock.Location())
+	calendario, err := cargarCalendarioEntorno(uc.calendarioRepo, ahora)
+	if err != nil {
+		return nil, err
+	}
 
-	// Margen de tolerancia: 10 minutos después del fin del turno
-	margenTolerancia := 10 * time.Minute
+	// Si no se encuentra turno, devolver nil sin error
+	return turnoEnCurso(uc.clock, calendario, turnos, ahora), nil
+}
+
+// turnoEnCurso retorna el turno activo cuyo horario del día, extendido por su tolerancia tras el
+// fin, contiene el instante indicado. Se consideran los turnos que empezaron hoy y los de ayer que
+// cruzan la medianoche; si dos coinciden (uno en su tolerancia final), gana el que empezó después.
+// Un calendario nil solo considera los días de la semana y la vigencia de cada turno
+func turnoEnCurso(reloj clock.Clock, calendario *entities.Calendario, turnos []*entities.Turno, ahora time.Time) *entities.Turno {
+	ahora = ahora.In(reloj.Location())
+	var actual *entities.Turno
+	var inicioActual time.Time
 
-	// Buscar el turno que corresponde a la hora actual
 	for _, turno := range turnos {
 		if !turno.Activo {
 			continue
 		}
 
-		// Parsear hora_inicio y hora_fin
-		horaInicio, err := time.Parse("15:04:05", turno.HoraInicio)
-		if err != nil {
-			continue
+		for _, dia := range []time.Time{ahora.AddDate(0, 0, -1), ahora} {
+			delDia, ok := calendario.TurnoDelDia(turno, entities.FechaDe(dia))
+			if !ok {
+				continue
+			}
+			inicioTurno, finTurno := delDia.Ocurrencia(dia, reloj.Location())
+
+			// Añadir la tolerancia del turno a su fin
+			finTurnoConMargen := finTurno.Add(time.Duration(turno.ToleranciaIngresoMin) * time.Minute)
+
+			if !ahora.Before(inicioTurno) && ahora.Before(finTurnoConMargen) && (actual == nil || inicioTurno.After(inicioActual)) {
+				actual, inicioActual = turno, inicioTurno
+			}
 		}
+	}
+	return actual
+}
 
-		horaFin, err := time.Parse("15:04:05", turno.HoraFin)
-		if err != nil {
+// turnoProximo retorna el turno activo que empieza más pronto después del instante indicado,
+// siempre que empiece dentro de la ventana, aunque sea pasada la medianoche
+func turnoProximo(reloj clock.Clock, calendario *entities.Calendario, turnos []*entities.Turno, ahora time.Time, ventana time.Duration) *entities.Turno {
+	ahora = ahora.In(reloj.Location())
+	var proximo *entities.Turno
+	var faltaProximo time.Duration
+
+	for _, turno := range turnos {
+		if !turno.Activo {
 			continue
 		}
 
-		// Construir tiempos completos para hoy con las horas del turno
-		inicioTurno := time.Date(nowBolivia.Year(), nowBolivia.Month(), nowBolivia.Day(),
-			horaInicio.Hour(), horaInicio.Minute(), horaInicio.Second(), 0, boliviaLocation)
+		for _, dia := range []time.Time{ahora, ahora.AddDate(0, 0, 1)} {
+			delDia, ok := calendario.TurnoDelDia(turno, entities.FechaDe(dia))
+			if !ok {
+				continue
+			}
+			inicioTurno, _ := delDia.Ocurrencia(dia, reloj.Location())
+			if !inicioTurno.After(ahora) {
+				continue
+			}
 
-		finTurno := time.Date(nowBolivia.Year(), nowBolivia.Month(), nowBolivia.Day(),
-			horaFin.Hour(), horaFin.Minute(), horaFin.Second(), 0, boliviaLocation)
+			falta := inicioTurno.Sub(ahora)
+			if falta > ventana {
+				continue
+			}
+			if proximo == nil || falta < faltaProximo {
+				proximo, faltaProximo = turno, falta
+			}
+		}
+	}
+	return proximo
+}
 
-		// Añadir margen de tolerancia al fin del turno
-		finTurnoConMargen := finTurno.Add(margenTolerancia)
+// ocurrenciaCercana retorna el inicio y el fin de la ocurrencia del turno (que empieza ayer, hoy
+// o mañana) cuyo centro está más cerca del instante. Así una salida a las 06:10 de un turno
+// 22:00 - 06:00 se mide contra el turno que empezó la noche anterior. Se usa el horario de cada
+// día según el calendario y se descartan los días en que el turno no se dicta, salvo que no se
+// dicte en ninguno de los tres
+func ocurrenciaCercana(reloj clock.Clock, calendario *entities.Calendario, turno *entities.Turno, instante time.Time) (time.Time, time.Time) {
+	instante = instante.In(reloj.Location())
+	var inicio, fin time.Time
+	var distancia time.Duration
+	encontrada := false
 
-		// Verificar si la hora actual está dentro del rango (incluyendo margen)
-		if nowBolivia.After(inicioTurno) && nowBolivia.Before(finTurnoConMargen) {
-			return turno, nil
+	for _, soloLaborables := range []bool{true, false} {
+		for _, desfase := range []int{-1, 0, 1} {
+			dia := instante.AddDate(0, 0, desfase)
+			delDia, ok := calendario.TurnoDelDia(turno, entities.FechaDe(dia))
+			if !ok {
+				if soloLaborables {
+					continue
+				}
+				delDia = turno
+			}
+
+			ini, f := delDia.Ocurrencia(dia, reloj.Location())
+			d := instante.Sub(ini.Add(f.Sub(ini) / 2))
+			if d < 0 {
+				d = -d
+			}
+			if !encontrada || d < distancia {
+				inicio, fin, distancia, encontrada = ini, f, d, true
+			}
+		}
+		if encontrada {
+			break
 		}
 	}
-
-	// Si no se encuentra turno exacto, devolver nil sin error
-	return nil, nil
+	return inicio, fin
 }
@@ -2,12 +2,32 @@ package usecases
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// passwordsEnHistorial es la cantidad de contraseñas recientes que un usuario no puede reutilizar
+const passwordsEnHistorial = 5
+
+// verificarPasswordNoReutilizada rechaza la nueva contraseña si coincide con la actual o con
+// alguna del historial
+func verificarPasswordNoReutilizada(repo repositories.UsuarioRepository, usuario *entities.Usuario, nueva string) error {
+	historial, err := repo.FindPasswordHistorial(usuario.ID, passwordsEnHistorial)
+	if err != nil {
+		return fmt.Errorf("error obteniendo el historial de contraseñas: %w", err)
+	}
+	// La actual puede no estar en el historial si es anterior a él
+	for _, hash := range append([]string{usuario.Password}, historial...) {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(nueva)) == nil {
+			return fmt.Errorf("la nueva contraseña no puede ser ninguna de las últimas %d utilizadas", passwordsEnHistorial)
+		}
+	}
+	return nil
+}
+
 type UsuarioUseCase struct {
 	repo repositories.UsuarioRepository
 }
@@ -39,8 +59,15 @@ func (uc *UsuarioUseCase) Create(usuario *entities.Usuario) error {
 		return errors.New("el username es requerido")
 	}
 
-	// Validar que el password no esté vacío
-	if usuario.Password == "" {
+	if usuario.Origen == "" {
+		usuario.Origen = entities.OrigenLocal
+	}
+	if !usuario.Origen.IsValid() {
+		return errors.New("origen inválido")
+	}
+
+	// Validar que el password no esté vacío; una cuenta del directorio no tiene contraseña local
+	if usuario.Password == "" && usuario.Origen == entities.OrigenLocal {
 		return errors.New("la contraseña es requerida")
 	}
 
@@ -50,6 +77,20 @@ func (uc *UsuarioUseCase) Create(usuario *entities.Usuario) error {
 		return errors.New("el username ya existe")
 	}
 
+	// Por defecto, el usuario está activo
+	usuario.Activo = true
+
+	if usuario.Origen == entities.OrigenLDAP {
+		// La valida el directorio: se guarda el hash de una contraseña que nadie conoce
+		password, err := passwordInutilizable()
+		if err != nil {
+			return errors.New("error al generar la contraseña")
+		}
+		usuario.Password = password
+		usuario.MustChangePassword = false
+		return uc.repo.Create(usuario)
+	}
+
 	// Hashear la contraseña
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(usuario.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -57,8 +98,8 @@ func (uc *UsuarioUseCase) Create(usuario *entities.Usuario) error {
 	}
 	usuario.Password = string(hashedPassword)
 
-	// Por defecto, el usuario está activo
-	usuario.Activo = true
+	// La contraseña la eligió otra persona: el usuario debe reemplazarla en su primer ingreso
+	usuario.MustChangePassword = true
 
 	return uc.repo.Create(usuario)
 }
@@ -99,6 +140,9 @@ func (uc *UsuarioUseCase) ChangePassword(id int, newPassword string) error {
 	if err != nil {
 		return errors.New("usuario no encontrado")
 	}
+	if usuario.Origen == entities.OrigenLDAP {
+		return ErrPasswordEnDirectorio
+	}
 
 	// Hashear la nueva contraseña
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
@@ -106,8 +150,8 @@ func (uc *UsuarioUseCase) ChangePassword(id int, newPassword string) error {
 		return errors.New("error al hashear la contraseña")
 	}
 
-	usuario.Password = string(hashedPassword)
-	return uc.repo.Update(usuario)
+	// La asigna el administrador, así que el usuario debe cambiarla al ingresar
+	return uc.repo.UpdatePassword(id, string(hashedPassword), true, passwordsEnHistorial)
 }
 
 func (uc *UsuarioUseCase) ToggleActive(id int) error {
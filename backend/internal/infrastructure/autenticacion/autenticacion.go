@@ -0,0 +1,33 @@
+package autenticacion
+
+import (
+	"errors"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+const (
+	DriverLocal = "local"
+	DriverLDAP  = "ldap"
+)
+
+// ErrCredencialesInvalidas agrupa usuario inexistente y contraseña incorrecta. Cualquier otro
+// error (directorio caído, configuración inválida) no dice nada de las credenciales
+var ErrCredencialesInvalidas = errors.New("credenciales inválidas")
+
+// Identidad es el usuario que un Autenticador dio por válido
+type Identidad struct {
+	Username       string
+	NombreCompleto string
+	Email          string
+	// Rol que corresponde a sus grupos del directorio; vacío si no pertenece a ninguno mapeado
+	// o si el autenticador no maneja grupos
+	Rol entities.Rol
+	// DN de la entrada en el directorio, solo para auditoría
+	DN string
+}
+
+// Autenticador valida usuario y contraseña contra una fuente de credenciales
+type Autenticador interface {
+	Autenticar(username, password string) (*Identidad, error)
+}
@@ -0,0 +1,327 @@
+package autenticacion
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+// prioridadRoles define qué rol gana si el usuario pertenece a grupos de varios roles
+var prioridadRoles = []entities.Rol{
+	entities.RolAdministrador,
+	entities.RolJefeCarrera,
+	entities.RolBibliotecario,
+	entities.RolBecario,
+	entities.RolDocente,
+}
+
+// GrupoRol asigna un rol a los miembros de un grupo del directorio
+type GrupoRol struct {
+	DN  *ldap.DN
+	Rol entities.Rol
+}
+
+// ConfigLDAP es la conexión al directorio y la forma de buscar usuarios y grupos
+type ConfigLDAP struct {
+	URL                string // ldap://, ldaps://
+	StartTLS           bool
+	InsecureSkipVerify bool   // Solo para pruebas contra un servidor local con certificado propio
+	CAFile             string // CA adicional en PEM para validar el certificado del servidor
+	Timeout            time.Duration
+
+	// Cuenta de servicio para buscar usuarios; vacía = búsqueda anónima
+	BindDN       string
+	BindPassword string
+
+	BaseDN string
+	// FiltroUsuario con un único %s, que se reemplaza por el username escapado
+	FiltroUsuario   string
+	AtributoUsuario string
+	AtributoNombre  string
+	AtributoEmail   string
+
+	// AtributoGrupos lista los grupos en la entrada del usuario (memberOf en Active Directory y
+	// OpenLDAP con el overlay memberof)
+	AtributoGrupos string
+	// FiltroGrupos, si no está vacío, busca además los grupos bajo BaseGrupos; su único %s se
+	// reemplaza por el DN del usuario escapado. Ejemplo: (member=%s)
+	FiltroGrupos string
+	BaseGrupos   string
+
+	GruposRol []GrupoRol
+}
+
+// Cifrado indica si la contraseña viaja protegida por TLS hasta el directorio
+func (c ConfigLDAP) Cifrado() bool {
+	return strings.HasPrefix(strings.ToLower(c.URL), "ldaps://") || c.StartTLS
+}
+
+// ConfigLDAPFromEnv lee la configuración de las variables LDAP_*. Los grupos de cada rol van en
+// LDAP_GROUPS_<ROL> (por ejemplo LDAP_GROUPS_JEFE_CARRERA), varios DN separados por ";"
+func ConfigLDAPFromEnv() (ConfigLDAP, error) {
+	config := ConfigLDAP{
+		URL:                strings.TrimSpace(os.Getenv("LDAP_URL")),
+		StartTLS:           os.Getenv("LDAP_START_TLS") == "true",
+		InsecureSkipVerify: os.Getenv("LDAP_INSECURE_SKIP_VERIFY") == "true",
+		CAFile:             os.Getenv("LDAP_CA_FILE"),
+		Timeout:            10 * time.Second,
+		BindDN:             os.Getenv("LDAP_BIND_DN"),
+		BindPassword:       os.Getenv("LDAP_BIND_PASSWORD"),
+		BaseDN:             os.Getenv("LDAP_BASE_DN"),
+		FiltroUsuario:      envODefecto("LDAP_USER_FILTER", "(uid=%s)"),
+		AtributoUsuario:    envODefecto("LDAP_USERNAME_ATTRIBUTE", "uid"),
+		AtributoNombre:     envODefecto("LDAP_NAME_ATTRIBUTE", "cn"),
+		AtributoEmail:      envODefecto("LDAP_EMAIL_ATTRIBUTE", "mail"),
+		AtributoGrupos:     envODefecto("LDAP_GROUP_ATTRIBUTE", "memberOf"),
+		FiltroGrupos:       os.Getenv("LDAP_GROUP_FILTER"),
+		BaseGrupos:         os.Getenv("LDAP_GROUP_BASE_DN"),
+	}
+
+	if valor := os.Getenv("LDAP_TIMEOUT_SECONDS"); valor != "" {
+		segundos, err := strconv.Atoi(valor)
+		if err != nil || segundos <= 0 {
+			return config, fmt.Errorf("LDAP_TIMEOUT_SECONDS inválido: %q", valor)
+		}
+		config.Timeout = time.Duration(segundos) * time.Second
+	}
+
+	for _, rol := range prioridadRoles {
+		variable := "LDAP_GROUPS_" + strings.ToUpper(string(rol))
+		for _, valor := range strings.Split(os.Getenv(variable), ";") {
+			if strings.TrimSpace(valor) == "" {
+				continue
+			}
+			dn, err := ldap.ParseDN(strings.TrimSpace(valor))
+			if err != nil {
+				return config, fmt.Errorf("%s: DN inválido %q: %w", variable, valor, err)
+			}
+			config.GruposRol = append(config.GruposRol, GrupoRol{DN: dn, Rol: rol})
+		}
+	}
+	return config, nil
+}
+
+// LDAP autentica con un bind del usuario contra el directorio: busca su entrada con la cuenta
+// de servicio, se conecta con su DN y contraseña y traduce sus grupos a un rol
+type LDAP struct {
+	config    ConfigLDAP
+	tlsConfig *tls.Config
+}
+
+func NewLDAP(config ConfigLDAP) (*LDAP, error) {
+	direccion, err := url.Parse(config.URL)
+	if err != nil || (direccion.Scheme != "ldap" && direccion.Scheme != "ldaps") || direccion.Host == "" {
+		return nil, fmt.Errorf("LDAP_URL debe tener la forma ldap://host:389 o ldaps://host:636")
+	}
+	if strings.TrimSpace(config.BaseDN) == "" {
+		return nil, fmt.Errorf("LDAP_BASE_DN no configurado")
+	}
+	if strings.Count(config.FiltroUsuario, "%s") != 1 {
+		return nil, fmt.Errorf("LDAP_USER_FILTER debe contener exactamente un %%s")
+	}
+	if config.FiltroGrupos != "" && strings.Count(config.FiltroGrupos, "%s") != 1 {
+		return nil, fmt.Errorf("LDAP_GROUP_FILTER debe contener exactamente un %%s")
+	}
+	if config.StartTLS && direccion.Scheme == "ldaps" {
+		return nil, fmt.Errorf("LDAP_START_TLS no aplica a ldaps://")
+	}
+	// El rol sale siempre de los grupos: sin ninguno mapeado nadie podría ingresar
+	if len(config.GruposRol) == 0 {
+		return nil, fmt.Errorf("configure al menos un grupo en LDAP_GROUPS_<ROL>")
+	}
+	if config.BaseGrupos == "" {
+		config.BaseGrupos = config.BaseDN
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         direccion.Hostname(),
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo LDAP_CA_FILE: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("LDAP_CA_FILE no contiene certificados PEM")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &LDAP{config: config, tlsConfig: tlsConfig}, nil
+}
+
+func (a *LDAP) Autenticar(username, password string) (*Identidad, error) {
+	// Un bind con contraseña vacía es un bind anónimo que muchos servidores aceptan
+	if strings.TrimSpace(username) == "" || password == "" {
+		return nil, ErrCredencialesInvalidas
+	}
+
+	conn, err := a.conectar()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := a.bindServicio(conn); err != nil {
+		return nil, err
+	}
+	entrada, err := a.buscarUsuario(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entrada.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrCredencialesInvalidas
+		}
+		return nil, fmt.Errorf("error verificando la contraseña de %s: %w", entrada.DN, err)
+	}
+
+	grupos := entrada.GetAttributeValues(a.config.AtributoGrupos)
+	if a.config.FiltroGrupos != "" {
+		// Los grupos se buscan con la cuenta de servicio: el usuario puede no tener permiso de lectura
+		if err := a.bindServicio(conn); err != nil {
+			return nil, err
+		}
+		encontrados, err := a.buscarGrupos(conn, entrada.DN)
+		if err != nil {
+			return nil, err
+		}
+		grupos = append(grupos, encontrados...)
+	}
+
+	identidad := &Identidad{
+		Username:       entrada.GetAttributeValue(a.config.AtributoUsuario),
+		NombreCompleto: entrada.GetAttributeValue(a.config.AtributoNombre),
+		Email:          entrada.GetAttributeValue(a.config.AtributoEmail),
+		Rol:            a.rolDeGrupos(grupos),
+		DN:             entrada.DN,
+	}
+	if identidad.Username == "" {
+		identidad.Username = username
+	}
+	return identidad, nil
+}
+
+func (a *LDAP) conectar() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(a.config.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: a.config.Timeout}),
+		ldap.DialWithTLSConfig(a.tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("error conectando al directorio: %w", err)
+	}
+	conn.SetTimeout(a.config.Timeout)
+
+	if a.config.StartTLS {
+		if err := conn.StartTLS(a.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error iniciando TLS con el directorio: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (a *LDAP) bindServicio(conn *ldap.Conn) error {
+	var err error
+	if a.config.BindDN == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(a.config.BindDN, a.config.BindPassword)
+	}
+	if err != nil {
+		return fmt.Errorf("error autenticando la cuenta de servicio del directorio: %w", err)
+	}
+	return nil
+}
+
+// buscarUsuario exige exactamente una entrada: con varias no se sabe a quién corresponde la
+// contraseña
+func (a *LDAP) buscarUsuario(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	atributos := []string{a.config.AtributoUsuario, a.config.AtributoNombre, a.config.AtributoEmail, a.config.AtributoGrupos}
+	busqueda := ldap.NewSearchRequest(
+		a.config.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, int(a.config.Timeout.Seconds()), false,
+		fmt.Sprintf(a.config.FiltroUsuario, ldap.EscapeFilter(username)),
+		atributos,
+		nil,
+	)
+
+	resultado, err := conn.Search(busqueda)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("error buscando el usuario en el directorio: %w", err)
+	}
+	switch {
+	case resultado == nil || len(resultado.Entries) == 0:
+		return nil, ErrCredencialesInvalidas
+	case len(resultado.Entries) > 1:
+		return nil, fmt.Errorf("LDAP_USER_FILTER encontró más de una entrada para %q", username)
+	}
+	return resultado.Entries[0], nil
+}
+
+func (a *LDAP) buscarGrupos(conn *ldap.Conn, usuarioDN string) ([]string, error) {
+	busqueda := ldap.NewSearchRequest(
+		a.config.BaseGrupos,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, int(a.config.Timeout.Seconds()), false,
+		fmt.Sprintf(a.config.FiltroGrupos, ldap.EscapeFilter(usuarioDN)),
+		[]string{"1.1"}, // Solo el DN, sin atributos
+		nil,
+	)
+
+	resultado, err := conn.Search(busqueda)
+	if err != nil {
+		return nil, fmt.Errorf("error buscando los grupos en el directorio: %w", err)
+	}
+	grupos := make([]string, 0, len(resultado.Entries))
+	for _, entrada := range resultado.Entries {
+		grupos = append(grupos, entrada.DN)
+	}
+	return grupos, nil
+}
+
+// rolDeGrupos retorna el rol de mayor prioridad entre los grupos mapeados del usuario. Los DN se
+// comparan sin distinguir mayúsculas, como hace el directorio
+func (a *LDAP) rolDeGrupos(grupos []string) entities.Rol {
+	var rol entities.Rol
+	prioridad := len(prioridadRoles)
+	for _, grupo := range grupos {
+		dn, err := ldap.ParseDN(grupo)
+		if err != nil {
+			continue
+		}
+		for _, mapeo := range a.config.GruposRol {
+			if !mapeo.DN.EqualFold(dn) {
+				continue
+			}
+			for i, candidato := range prioridadRoles[:prioridad] {
+				if candidato == mapeo.Rol {
+					rol, prioridad = candidato, i
+					break
+				}
+			}
+		}
+	}
+	return rol
+}
+
+func envODefecto(variable, defecto string) string {
+	if valor := os.Getenv(variable); valor != "" {
+		return valor
+	}
+	return defecto
+}
@@ -0,0 +1,521 @@
+package autenticacion
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+func grupoRol(t *testing.T, dn string, rol entities.Rol) GrupoRol {
+	t.Helper()
+	parseado, err := ldap.ParseDN(dn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return GrupoRol{DN: parseado, Rol: rol}
+}
+
+func TestRolDeGrupos(t *testing.T) {
+	a := &LDAP{config: ConfigLDAP{GruposRol: []GrupoRol{
+		grupoRol(t, "cn=docentes,ou=grupos,dc=uni,dc=edu", entities.RolDocente),
+		grupoRol(t, "cn=jefes,ou=grupos,dc=uni,dc=edu", entities.RolJefeCarrera),
+		grupoRol(t, "cn=ti,ou=grupos,dc=uni,dc=edu", entities.RolAdministrador),
+		grupoRol(t, "cn=biblioteca,ou=grupos,dc=uni,dc=edu", entities.RolBibliotecario),
+	}}}
+
+	casos := []struct {
+		nombre string
+		grupos []string
+		espera entities.Rol
+	}{
+		{"sin grupos", nil, ""},
+		{"grupo no mapeado", []string{"cn=alumnos,ou=grupos,dc=uni,dc=edu"}, ""},
+		{"un grupo", []string{"cn=docentes,ou=grupos,dc=uni,dc=edu"}, entities.RolDocente},
+		{"gana el de mayor prioridad", []string{"cn=docentes,ou=grupos,dc=uni,dc=edu", "cn=jefes,ou=grupos,dc=uni,dc=edu"}, entities.RolJefeCarrera},
+		{"la prioridad no depende del orden", []string{"cn=ti,ou=grupos,dc=uni,dc=edu", "cn=biblioteca,ou=grupos,dc=uni,dc=edu", "cn=docentes,ou=grupos,dc=uni,dc=edu"}, entities.RolAdministrador},
+		{"un grupo de menor prioridad no reemplaza al mayor", []string{"cn=jefes,ou=grupos,dc=uni,dc=edu", "cn=biblioteca,ou=grupos,dc=uni,dc=edu"}, entities.RolJefeCarrera},
+		{"DN en mayúsculas", []string{"CN=Jefes,OU=Grupos,DC=UNI,DC=EDU"}, entities.RolJefeCarrera},
+		{"DN con espacios tras las comas", []string{"cn=docentes, ou=grupos, dc=uni, dc=edu"}, entities.RolDocente},
+		{"DN inválido se ignora", []string{"no es un dn", "cn=docentes,ou=grupos,dc=uni,dc=edu"}, entities.RolDocente},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			if rol := a.rolDeGrupos(c.grupos); rol != c.espera {
+				t.Errorf("rol = %q, se esperaba %q", rol, c.espera)
+			}
+		})
+	}
+}
+
+func TestConfigLDAPFromEnvGrupos(t *testing.T) {
+	casos := []struct {
+		nombre      string
+		variables   map[string]string
+		espera      map[entities.Rol]int
+		esperaError bool
+	}{
+		{
+			nombre:    "sin grupos",
+			variables: map[string]string{},
+			espera:    map[entities.Rol]int{},
+		},
+		{
+			nombre: "varios DN separados por punto y coma",
+			variables: map[string]string{
+				"LDAP_GROUPS_DOCENTE":       "cn=docentes,dc=uni,dc=edu; cn=auxiliares,dc=uni,dc=edu ;",
+				"LDAP_GROUPS_JEFE_CARRERA":  "cn=jefes,dc=uni,dc=edu",
+				"LDAP_GROUPS_ADMINISTRADOR": "",
+			},
+			espera: map[entities.Rol]int{entities.RolDocente: 2, entities.RolJefeCarrera: 1},
+		},
+		{
+			nombre:      "DN inválido",
+			variables:   map[string]string{"LDAP_GROUPS_BECARIO": "cn=becarios,dc=uni,dc=edu;sin-igual"},
+			esperaError: true,
+		},
+		{
+			nombre:      "timeout inválido",
+			variables:   map[string]string{"LDAP_TIMEOUT_SECONDS": "0"},
+			esperaError: true,
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			for _, rol := range prioridadRoles {
+				t.Setenv("LDAP_GROUPS_"+strings.ToUpper(string(rol)), "")
+			}
+			t.Setenv("LDAP_TIMEOUT_SECONDS", "")
+			for variable, valor := range c.variables {
+				t.Setenv(variable, valor)
+			}
+
+			config, err := ConfigLDAPFromEnv()
+			if c.esperaError {
+				if err == nil {
+					t.Fatal("se esperaba error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obtenidos := map[entities.Rol]int{}
+			for _, mapeo := range config.GruposRol {
+				obtenidos[mapeo.Rol]++
+			}
+			if len(obtenidos) != len(c.espera) {
+				t.Errorf("grupos por rol = %v, se esperaban %v", obtenidos, c.espera)
+			}
+			for rol, cantidad := range c.espera {
+				if obtenidos[rol] != cantidad {
+					t.Errorf("rol %s: %d grupos, se esperaban %d", rol, obtenidos[rol], cantidad)
+				}
+			}
+		})
+	}
+}
+
+func TestNewLDAP(t *testing.T) {
+	valida := func() ConfigLDAP {
+		return ConfigLDAP{
+			URL:           "ldap://directorio.uni.edu:389",
+			BaseDN:        "dc=uni,dc=edu",
+			FiltroUsuario: "(uid=%s)",
+			GruposRol:     []GrupoRol{grupoRol(t, "cn=docentes,dc=uni,dc=edu", entities.RolDocente)},
+		}
+	}
+
+	casos := []struct {
+		nombre      string
+		modificar   func(*ConfigLDAP)
+		esperaError bool
+	}{
+		{"configuración mínima", func(*ConfigLDAP) {}, false},
+		{"ldaps", func(c *ConfigLDAP) { c.URL = "ldaps://directorio.uni.edu:636" }, false},
+		{"StartTLS sobre ldap", func(c *ConfigLDAP) { c.StartTLS = true }, false},
+		{"filtro de grupos", func(c *ConfigLDAP) { c.FiltroGrupos = "(member=%s)" }, false},
+		{"URL sin esquema ldap", func(c *ConfigLDAP) { c.URL = "http://directorio.uni.edu" }, true},
+		{"URL sin host", func(c *ConfigLDAP) { c.URL = "ldap://" }, true},
+		{"sin base DN", func(c *ConfigLDAP) { c.BaseDN = " " }, true},
+		{"filtro de usuario sin %s", func(c *ConfigLDAP) { c.FiltroUsuario = "(uid=admin)" }, true},
+		{"filtro de usuario con dos %s", func(c *ConfigLDAP) { c.FiltroUsuario = "(|(uid=%s)(mail=%s))" }, true},
+		{"filtro de grupos sin %s", func(c *ConfigLDAP) { c.FiltroGrupos = "(objectClass=group)" }, true},
+		{"StartTLS sobre ldaps", func(c *ConfigLDAP) { c.URL = "ldaps://directorio.uni.edu"; c.StartTLS = true }, true},
+		{"CA inexistente", func(c *ConfigLDAP) { c.CAFile = "/no/existe/ca.pem" }, true},
+		{"sin grupos mapeados", func(c *ConfigLDAP) { c.GruposRol = nil }, true},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			config := valida()
+			c.modificar(&config)
+			a, err := NewLDAP(config)
+			if c.esperaError {
+				if err == nil {
+					t.Fatal("se esperaba error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.config.BaseGrupos != config.BaseDN {
+				t.Errorf("BaseGrupos = %q, se esperaba el BaseDN", a.config.BaseGrupos)
+			}
+			if a.tlsConfig.ServerName != "directorio.uni.edu" {
+				t.Errorf("ServerName = %q", a.tlsConfig.ServerName)
+			}
+		})
+	}
+}
+
+// entradaFalsa es una entrada del directorio falso; password vacía = no admite bind
+type entradaFalsa struct {
+	dn        string
+	password  string
+	atributos map[string][]string
+}
+
+// directorioLDAPFalso atiende LDAPv3 sin TLS en 127.0.0.1 con lo mínimo que usa LDAP.Autenticar:
+// bind simple, búsqueda por igualdad de un atributo y unbind. Solo la cuenta de servicio puede
+// buscar, como en un directorio que no permite lecturas a los usuarios. Registra las operaciones
+// en orden
+type directorioLDAPFalso struct {
+	listener  net.Listener
+	servicio  string
+	entradas  []entradaFalsa
+	mu        sync.Mutex
+	registro  []string
+	filtroIgu *regexp.Regexp
+}
+
+func nuevoDirectorioLDAPFalso(t *testing.T, servicio string, entradas []entradaFalsa) *directorioLDAPFalso {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &directorioLDAPFalso{
+		listener:  listener,
+		servicio:  servicio,
+		entradas:  entradas,
+		filtroIgu: regexp.MustCompile(`^\(([A-Za-z]+)=(.*)\)$`),
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go d.atender(conn)
+		}
+	}()
+	return d
+}
+
+func (d *directorioLDAPFalso) url() string {
+	return "ldap://" + d.listener.Addr().String()
+}
+
+func (d *directorioLDAPFalso) operaciones() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.registro...)
+}
+
+func (d *directorioLDAPFalso) anotar(formato string, args ...interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.registro = append(d.registro, fmt.Sprintf(formato, args...))
+}
+
+func (d *directorioLDAPFalso) atender(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	autenticado := ""
+
+	for {
+		paquete, err := ber.ReadPacket(conn)
+		if err != nil || len(paquete.Children) < 2 {
+			return
+		}
+		id, _ := paquete.Children[0].Value.(int64)
+		operacion := paquete.Children[1]
+
+		switch operacion.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := operacion.Children[1].Data.String()
+			password := operacion.Children[2].Data.String()
+			d.anotar("bind %s", dn)
+			codigo := uint16(ldap.LDAPResultInvalidCredentials)
+			if dn == "" && password == "" {
+				codigo, autenticado = ldap.LDAPResultSuccess, ""
+			} else if entrada := d.buscarDN(dn); entrada != nil && entrada.password != "" && entrada.password == password {
+				codigo, autenticado = ldap.LDAPResultSuccess, entrada.dn
+			}
+			d.responder(conn, id, ldap.ApplicationBindResponse, codigo)
+		case ldap.ApplicationSearchRequest:
+			filtro, err := ldap.DecompileFilter(operacion.Children[6])
+			if err != nil {
+				return
+			}
+			d.anotar("search %s", filtro)
+			if autenticado != d.servicio {
+				d.responder(conn, id, ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights)
+				continue
+			}
+			for _, entrada := range d.filtrar(filtro) {
+				d.enviarEntrada(conn, id, entrada)
+			}
+			d.responder(conn, id, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func (d *directorioLDAPFalso) buscarDN(dn string) *entradaFalsa {
+	for i := range d.entradas {
+		if strings.EqualFold(d.entradas[i].dn, dn) {
+			return &d.entradas[i]
+		}
+	}
+	return nil
+}
+
+// filtrar entiende solo (atributo=valor), sin comodines; los valores escapados por EscapeFilter
+// no coinciden con ningún valor real
+func (d *directorioLDAPFalso) filtrar(filtro string) []entradaFalsa {
+	partes := d.filtroIgu.FindStringSubmatch(filtro)
+	if partes == nil {
+		return nil
+	}
+	var encontradas []entradaFalsa
+	for _, entrada := range d.entradas {
+		for _, valor := range entrada.atributos[partes[1]] {
+			if strings.EqualFold(valor, partes[2]) {
+				encontradas = append(encontradas, entrada)
+				break
+			}
+		}
+	}
+	return encontradas
+}
+
+func (d *directorioLDAPFalso) responder(conn net.Conn, id int64, aplicacion ber.Tag, codigo uint16) {
+	respuesta := ber.Encode(ber.ClassApplication, ber.TypeConstructed, aplicacion, nil, "")
+	respuesta.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(codigo), ""))
+	respuesta.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	respuesta.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	d.enviar(conn, id, respuesta)
+}
+
+func (d *directorioLDAPFalso) enviarEntrada(conn net.Conn, id int64, entrada entradaFalsa) {
+	respuesta := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+	respuesta.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entrada.dn, ""))
+	atributos := ber.NewSequence("")
+	for nombre, valores := range entrada.atributos {
+		atributo := ber.NewSequence("")
+		atributo.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, nombre, ""))
+		conjunto := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		for _, valor := range valores {
+			conjunto.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, valor, ""))
+		}
+		atributo.AppendChild(conjunto)
+		atributos.AppendChild(atributo)
+	}
+	respuesta.AppendChild(atributos)
+	d.enviar(conn, id, respuesta)
+}
+
+func (d *directorioLDAPFalso) enviar(conn net.Conn, id int64, operacion *ber.Packet) {
+	mensaje := ber.NewSequence("")
+	mensaje.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+	mensaje.AppendChild(operacion)
+	conn.Write(mensaje.Bytes())
+}
+
+func TestLDAPAutenticar(t *testing.T) {
+	const (
+		servicio = "cn=servicio,dc=uni,dc=edu"
+		jperez   = "uid=jperez,ou=personas,dc=uni,dc=edu"
+	)
+	directorio := nuevoDirectorioLDAPFalso(t, servicio, []entradaFalsa{
+		{dn: servicio, password: "clave-servicio"},
+		{dn: jperez, password: "clave-jperez", atributos: map[string][]string{
+			"uid":      {"jperez"},
+			"cn":       {"Juan Perez"},
+			"mail":     {"jperez@uni.edu", "compartido@uni.edu"},
+			"memberOf": {"cn=docentes,ou=grupos,dc=uni,dc=edu"},
+		}},
+		{dn: "uid=mrojas,ou=personas,dc=uni,dc=edu", password: "clave-mrojas", atributos: map[string][]string{
+			"uid":  {"mrojas"},
+			"mail": {"compartido@uni.edu"},
+		}},
+		{dn: "cn=jefes,ou=grupos,dc=uni,dc=edu", atributos: map[string][]string{
+			"member": {jperez},
+		}},
+	})
+
+	configuracion := func(modificar func(*ConfigLDAP)) *LDAP {
+		config := ConfigLDAP{
+			URL:             directorio.url(),
+			Timeout:         5 * time.Second,
+			BindDN:          servicio,
+			BindPassword:    "clave-servicio",
+			BaseDN:          "dc=uni,dc=edu",
+			FiltroUsuario:   "(uid=%s)",
+			AtributoUsuario: "uid",
+			AtributoNombre:  "cn",
+			AtributoEmail:   "mail",
+			AtributoGrupos:  "memberOf",
+			GruposRol: []GrupoRol{
+				grupoRol(t, "cn=docentes,ou=grupos,dc=uni,dc=edu", entities.RolDocente),
+				grupoRol(t, "cn=jefes,ou=grupos,dc=uni,dc=edu", entities.RolJefeCarrera),
+			},
+		}
+		if modificar != nil {
+			modificar(&config)
+		}
+		a, err := NewLDAP(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	casos := []struct {
+		nombre      string
+		ldap        *LDAP
+		username    string
+		password    string
+		esperaRol   entities.Rol
+		esperaError error // nil con esperaFalla = un error que no es de credenciales
+		esperaFalla bool
+		esperaOrden []string
+	}{
+		{
+			nombre:    "grupos del atributo memberOf",
+			ldap:      configuracion(nil),
+			username:  "jperez",
+			password:  "clave-jperez",
+			esperaRol: entities.RolDocente,
+			esperaOrden: []string{
+				"bind " + servicio,
+				"search (uid=jperez)",
+				"bind " + jperez,
+			},
+		},
+		{
+			// La búsqueda de grupos vuelve a la cuenta de servicio: el usuario no puede buscar
+			nombre:    "grupos buscados con LDAP_GROUP_FILTER",
+			ldap:      configuracion(func(c *ConfigLDAP) { c.FiltroGrupos = "(member=%s)" }),
+			username:  "jperez",
+			password:  "clave-jperez",
+			esperaRol: entities.RolJefeCarrera,
+			esperaOrden: []string{
+				"bind " + servicio,
+				"search (uid=jperez)",
+				"bind " + jperez,
+				"bind " + servicio,
+				"search (member=" + jperez + ")",
+			},
+		},
+		{
+			nombre:      "contraseña incorrecta",
+			ldap:        configuracion(nil),
+			username:    "jperez",
+			password:    "otra",
+			esperaError: ErrCredencialesInvalidas,
+			esperaOrden: []string{"bind " + servicio, "search (uid=jperez)", "bind " + jperez},
+		},
+		{
+			nombre:      "usuario inexistente",
+			ldap:        configuracion(nil),
+			username:    "nadie",
+			password:    "clave",
+			esperaError: ErrCredencialesInvalidas,
+			esperaOrden: []string{"bind " + servicio, "search (uid=nadie)"},
+		},
+		{
+			// Un comodín escapado no coincide con jperez
+			nombre:      "username con comodín",
+			ldap:        configuracion(nil),
+			username:    "jp*",
+			password:    "clave-jperez",
+			esperaError: ErrCredencialesInvalidas,
+			esperaOrden: []string{"bind " + servicio, `search (uid=jp\2a)`},
+		},
+		{
+			// Un bind con contraseña vacía sería anónimo: no llega al directorio
+			nombre:      "contraseña vacía",
+			ldap:        configuracion(nil),
+			username:    "jperez",
+			password:    "",
+			esperaError: ErrCredencialesInvalidas,
+		},
+		{
+			nombre:      "cuenta de servicio rechazada",
+			ldap:        configuracion(func(c *ConfigLDAP) { c.BindPassword = "vencida" }),
+			username:    "jperez",
+			password:    "clave-jperez",
+			esperaFalla: true,
+			esperaOrden: []string{"bind " + servicio},
+		},
+		{
+			nombre:      "filtro con más de una entrada",
+			ldap:        configuracion(func(c *ConfigLDAP) { c.FiltroUsuario = "(mail=%s)" }),
+			username:    "compartido@uni.edu",
+			password:    "clave-jperez",
+			esperaFalla: true,
+			esperaOrden: []string{"bind " + servicio, "search (mail=compartido@uni.edu)"},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			antes := len(directorio.operaciones())
+			identidad, err := c.ldap.Autenticar(c.username, c.password)
+
+			switch {
+			case c.esperaError != nil:
+				if !errors.Is(err, c.esperaError) {
+					t.Fatalf("error = %v, se esperaba %v", err, c.esperaError)
+				}
+			case c.esperaFalla:
+				if err == nil || errors.Is(err, ErrCredencialesInvalidas) {
+					t.Fatalf("error = %v, se esperaba una falla que no sea de credenciales", err)
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+				if identidad.Username != "jperez" || identidad.NombreCompleto != "Juan Perez" || identidad.Email != "jperez@uni.edu" || identidad.DN != jperez {
+					t.Errorf("identidad = %+v", identidad)
+				}
+				if identidad.Rol != c.esperaRol {
+					t.Errorf("rol = %q, se esperaba %q", identidad.Rol, c.esperaRol)
+				}
+			}
+
+			// El servidor anota cada operación antes de responderla
+			orden := directorio.operaciones()[antes:]
+			if strings.Join(orden, "|") != strings.Join(c.esperaOrden, "|") {
+				t.Errorf("operaciones = %q, se esperaban %q", orden, c.esperaOrden)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package autenticacion
+
+import (
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyHash es un hash bcrypt pre-calculado usado para prevenir timing attacks
+// cuando el usuario no existe, aún ejecutamos bcrypt.CompareHashAndPassword
+// para que el tiempo de respuesta sea consistente
+var dummyHash = []byte("$2a$10$dummyhashfortimingatttackprevention1234567890")
+
+// Local valida la contraseña contra el hash bcrypt de usuarios.password
+type Local struct {
+	usuarioRepo repositories.UsuarioRepository
+}
+
+func NewLocal(usuarioRepo repositories.UsuarioRepository) *Local {
+	return &Local{usuarioRepo: usuarioRepo}
+}
+
+func (a *Local) Autenticar(username, password string) (*Identidad, error) {
+	usuario, err := a.usuarioRepo.FindByUsername(username)
+
+	// Siempre ejecutar bcrypt.CompareHashAndPassword para prevenir timing attacks
+	// Si el usuario no existe, usamos un hash dummy para que el tiempo sea consistente
+	var hashToCompare []byte
+	if err != nil || usuario == nil {
+		hashToCompare = dummyHash
+	} else {
+		hashToCompare = []byte(usuario.Password)
+	}
+
+	// Ejecutar comparación siempre (incluso si usuario no existe)
+	bcryptErr := bcrypt.CompareHashAndPassword(hashToCompare, []byte(password))
+
+	// Ahora verificamos los errores después de la comparación
+	if err != nil || usuario == nil || bcryptErr != nil {
+		return nil, ErrCredencialesInvalidas
+	}
+
+	return &Identidad{
+		Username:       usuario.Username,
+		NombreCompleto: usuario.NombreCompleto,
+		Email:          usuario.Email,
+		Rol:            usuario.Rol,
+	}, nil
+}
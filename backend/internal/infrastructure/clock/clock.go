@@ -0,0 +1,84 @@
+package clock
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultTimezone es la zona horaria de la institución si no se configura INSTITUTION_TIMEZONE
+const DefaultTimezone = "America/La_Paz"
+
+// Clock entrega la hora actual en la zona horaria de la institución.
+// Todo cálculo de "hoy", retrasos y reportes debe pasar por aquí en lugar de usar time.Now()
+// directamente, para no depender de la zona horaria del servidor ni de la base de datos.
+type Clock interface {
+	Now() time.Time
+	Location() *time.Location
+}
+
+type systemClock struct {
+	location *time.Location
+}
+
+// New crea un reloj del sistema en la zona horaria indicada
+func New(location *time.Location) Clock {
+	return &systemClock{location: location}
+}
+
+func (c *systemClock) Now() time.Time {
+	return time.Now().In(c.location)
+}
+
+func (c *systemClock) Location() *time.Location {
+	return c.location
+}
+
+type fixedClock struct {
+	instante time.Time
+}
+
+// Fixed crea un reloj detenido en un instante dado, expresado en la zona horaria indicada.
+// Pensado para herramientas y pruebas que necesitan reproducir un momento concreto.
+func Fixed(instante time.Time, location *time.Location) Clock {
+	return &fixedClock{instante: instante.In(location)}
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.instante
+}
+
+func (c *fixedClock) Location() *time.Location {
+	return c.instante.Location()
+}
+
+// TimezoneFromEnv retorna el nombre de la zona horaria configurada en INSTITUTION_TIMEZONE
+func TimezoneFromEnv() string {
+	if tz := os.Getenv("INSTITUTION_TIMEZONE"); tz != "" {
+		return tz
+	}
+	return DefaultTimezone
+}
+
+// NewFromEnv crea el reloj del sistema con la zona horaria de INSTITUTION_TIMEZONE
+func NewFromEnv() (Clock, error) {
+	nombre := TimezoneFromEnv()
+	location, err := time.LoadLocation(nombre)
+	if err != nil {
+		return nil, fmt.Errorf("zona horaria inválida %q: %w", nombre, err)
+	}
+	return New(location), nil
+}
+
+// Dia retorna el inicio y el fin (exclusivo) del día de calendario de fecha en la zona
+// horaria del reloj. Se toman año, mes y día tal como vienen, sin convertir fecha de zona:
+// "2024-01-15" parseado en UTC representa el 15 de enero de la institución.
+func Dia(c Clock, fecha time.Time) (time.Time, time.Time) {
+	inicio := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, c.Location())
+	return inicio, inicio.AddDate(0, 0, 1)
+}
+
+// Hoy retorna el inicio y el fin (exclusivo) del día actual de la institución
+func Hoy(c Clock) (time.Time, time.Time) {
+	return Dia(c, c.Now())
+}
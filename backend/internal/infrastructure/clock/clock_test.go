@@ -0,0 +1,118 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func cargarZona(t *testing.T, nombre string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(nombre)
+	if err != nil {
+		t.Fatalf("zona horaria %s: %v", nombre, err)
+	}
+	return loc
+}
+
+func TestHoy(t *testing.T) {
+	laPaz := cargarZona(t, DefaultTimezone)
+
+	casos := []struct {
+		nombre      string
+		instante    time.Time
+		esperaDesde time.Time
+	}{
+		{
+			nombre:      "23:59 local sigue siendo el mismo día",
+			instante:    time.Date(2024, 1, 15, 23, 59, 59, 0, laPaz),
+			esperaDesde: time.Date(2024, 1, 15, 0, 0, 0, 0, laPaz),
+		},
+		{
+			nombre:      "00:00 local empieza el día siguiente",
+			instante:    time.Date(2024, 1, 16, 0, 0, 0, 0, laPaz),
+			esperaDesde: time.Date(2024, 1, 16, 0, 0, 0, 0, laPaz),
+		},
+		{
+			// 20:00 en La Paz ya es el día siguiente en UTC
+			nombre:      "20:00 local (00:00 UTC) sigue en el día local",
+			instante:    time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+			esperaDesde: time.Date(2024, 1, 15, 0, 0, 0, 0, laPaz),
+		},
+		{
+			nombre:      "03:59 UTC todavía es el día anterior en La Paz",
+			instante:    time.Date(2024, 1, 16, 3, 59, 0, 0, time.UTC),
+			esperaDesde: time.Date(2024, 1, 15, 0, 0, 0, 0, laPaz),
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			inicio, fin := Hoy(Fixed(c.instante, laPaz))
+			if !inicio.Equal(c.esperaDesde) {
+				t.Errorf("inicio = %v, se esperaba %v", inicio, c.esperaDesde)
+			}
+			if esperaHasta := c.esperaDesde.AddDate(0, 0, 1); !fin.Equal(esperaHasta) {
+				t.Errorf("fin = %v, se esperaba %v", fin, esperaHasta)
+			}
+			if c.instante.Before(inicio) || !c.instante.Before(fin) {
+				t.Errorf("el instante %v queda fuera de [%v, %v)", c.instante, inicio, fin)
+			}
+		})
+	}
+}
+
+func TestDia(t *testing.T) {
+	laPaz := cargarZona(t, DefaultTimezone)
+	reloj := Fixed(time.Date(2024, 3, 1, 12, 0, 0, 0, laPaz), laPaz)
+
+	casos := []struct {
+		nombre      string
+		fecha       time.Time
+		esperaDesde time.Time
+	}{
+		{
+			// Las fechas de los filtros llegan parseadas en UTC ("2024-01-15")
+			nombre:      "fecha en UTC se toma como día de la institución",
+			fecha:       time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+			esperaDesde: time.Date(2024, 1, 15, 0, 0, 0, 0, laPaz),
+		},
+		{
+			nombre:      "23:59 local",
+			fecha:       time.Date(2024, 1, 15, 23, 59, 0, 0, laPaz),
+			esperaDesde: time.Date(2024, 1, 15, 0, 0, 0, 0, laPaz),
+		},
+		{
+			nombre:      "00:00 local",
+			fecha:       time.Date(2024, 1, 16, 0, 0, 0, 0, laPaz),
+			esperaDesde: time.Date(2024, 1, 16, 0, 0, 0, 0, laPaz),
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			inicio, fin := Dia(reloj, c.fecha)
+			if !inicio.Equal(c.esperaDesde) {
+				t.Errorf("inicio = %v, se esperaba %v", inicio, c.esperaDesde)
+			}
+			if fin.Sub(inicio) != 24*time.Hour {
+				t.Errorf("el día dura %v, se esperaban 24h", fin.Sub(inicio))
+			}
+		})
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("INSTITUTION_TIMEZONE", "America/Bogota")
+	reloj, err := NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloj.Location().String() != "America/Bogota" {
+		t.Errorf("zona = %s, se esperaba America/Bogota", reloj.Location())
+	}
+
+	t.Setenv("INSTITUTION_TIMEZONE", "Marte/Olympus")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("se esperaba error con una zona horaria inexistente")
+	}
+}
@@ -0,0 +1,233 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+type CalendarioRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCalendarioRepository(db *sql.DB) *CalendarioRepositoryImpl {
+	return &CalendarioRepositoryImpl{db: db}
+}
+
+const periodoAcademicoColumns = `id, gestion, nombre, fecha_inicio, fecha_fin, descripcion, created_at, updated_at`
+
+const eventoCalendarioColumns = `id, tipo, nombre, fecha_inicio, fecha_fin, turno_id, hora_inicio, hora_fin,
+	          descripcion, created_at, updated_at`
+
+func scanPeriodo(row interface{ Scan(...interface{}) error }) (*entities.PeriodoAcademico, error) {
+	periodo := &entities.PeriodoAcademico{}
+	err := row.Scan(
+		&periodo.ID,
+		&periodo.Gestion,
+		&periodo.Nombre,
+		&periodo.FechaInicio,
+		&periodo.FechaFin,
+		&periodo.Descripcion,
+		&periodo.CreatedAt,
+		&periodo.UpdatedAt,
+	)
+	return periodo, err
+}
+
+func scanEvento(row interface{ Scan(...interface{}) error }) (*entities.EventoCalendario, error) {
+	evento := &entities.EventoCalendario{}
+	err := row.Scan(
+		&evento.ID,
+		&evento.Tipo,
+		&evento.Nombre,
+		&evento.FechaInicio,
+		&evento.FechaFin,
+		&evento.TurnoID,
+		&evento.HoraInicio,
+		&evento.HoraFin,
+		&evento.Descripcion,
+		&evento.CreatedAt,
+		&evento.UpdatedAt,
+	)
+	return evento, err
+}
+
+func (r *CalendarioRepositoryImpl) FindPeriodos() ([]*entities.PeriodoAcademico, error) {
+	query := `SELECT ` + periodoAcademicoColumns + ` FROM periodos_academicos ORDER BY fecha_inicio`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periodos := []*entities.PeriodoAcademico{}
+	for rows.Next() {
+		periodo, err := scanPeriodo(rows)
+		if err != nil {
+			return nil, err
+		}
+		periodos = append(periodos, periodo)
+	}
+	return periodos, rows.Err()
+}
+
+func (r *CalendarioRepositoryImpl) FindPeriodoByID(id int) (*entities.PeriodoAcademico, error) {
+	query := `SELECT ` + periodoAcademicoColumns + ` FROM periodos_academicos WHERE id = $1`
+
+	periodo, err := scanPeriodo(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("periodo no encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return periodo, nil
+}
+
+func (r *CalendarioRepositoryImpl) CreatePeriodo(periodo *entities.PeriodoAcademico) error {
+	query := `INSERT INTO periodos_academicos (gestion, nombre, fecha_inicio, fecha_fin, descripcion)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRow(
+		query,
+		periodo.Gestion,
+		periodo.Nombre,
+		periodo.FechaInicio,
+		periodo.FechaFin,
+		periodo.Descripcion,
+	).Scan(&periodo.ID, &periodo.CreatedAt, &periodo.UpdatedAt)
+}
+
+func (r *CalendarioRepositoryImpl) UpdatePeriodo(periodo *entities.PeriodoAcademico) error {
+	query := `UPDATE periodos_academicos SET gestion = $1, nombre = $2, fecha_inicio = $3, fecha_fin = $4,
+	          descripcion = $5 WHERE id = $6 RETURNING updated_at`
+
+	err := r.db.QueryRow(
+		query,
+		periodo.Gestion,
+		periodo.Nombre,
+		periodo.FechaInicio,
+		periodo.FechaFin,
+		periodo.Descripcion,
+		periodo.ID,
+	).Scan(&periodo.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("periodo no encontrado")
+	}
+	return err
+}
+
+func (r *CalendarioRepositoryImpl) DeletePeriodo(id int) error {
+	result, err := r.db.Exec(`DELETE FROM periodos_academicos WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return fmt.Errorf("periodo no encontrado")
+	}
+	return nil
+}
+
+func (r *CalendarioRepositoryImpl) FindEventos(desde, hasta *entities.Fecha) ([]*entities.EventoCalendario, error) {
+	condiciones := []string{"TRUE"}
+	args := []interface{}{}
+
+	agregar := func(condicion string, valor interface{}) {
+		args = append(args, valor)
+		condiciones = append(condiciones, fmt.Sprintf(condicion, len(args)))
+	}
+
+	// Un evento se cruza con el rango si termina después del inicio y empieza antes del fin
+	if desde != nil {
+		agregar("fecha_fin >= $%d", *desde)
+	}
+	if hasta != nil {
+		agregar("fecha_inicio <= $%d", *hasta)
+	}
+
+	query := `SELECT ` + eventoCalendarioColumns + ` FROM eventos_calendario
+	          WHERE ` + strings.Join(condiciones, " AND ") + `
+	          ORDER BY fecha_inicio, id`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	eventos := []*entities.EventoCalendario{}
+	for rows.Next() {
+		evento, err := scanEvento(rows)
+		if err != nil {
+			return nil, err
+		}
+		eventos = append(eventos, evento)
+	}
+	return eventos, rows.Err()
+}
+
+func (r *CalendarioRepositoryImpl) FindEventoByID(id int) (*entities.EventoCalendario, error) {
+	query := `SELECT ` + eventoCalendarioColumns + ` FROM eventos_calendario WHERE id = $1`
+
+	evento, err := scanEvento(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("evento no encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return evento, nil
+}
+
+func (r *CalendarioRepositoryImpl) CreateEvento(evento *entities.EventoCalendario) error {
+	query := `INSERT INTO eventos_calendario (tipo, nombre, fecha_inicio, fecha_fin, turno_id, hora_inicio, hora_fin, descripcion)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
+
+	return r.db.QueryRow(
+		query,
+		evento.Tipo,
+		evento.Nombre,
+		evento.FechaInicio,
+		evento.FechaFin,
+		evento.TurnoID,
+		evento.HoraInicio,
+		evento.HoraFin,
+		evento.Descripcion,
+	).Scan(&evento.ID, &evento.CreatedAt, &evento.UpdatedAt)
+}
+
+func (r *CalendarioRepositoryImpl) UpdateEvento(evento *entities.EventoCalendario) error {
+	query := `UPDATE eventos_calendario SET tipo = $1, nombre = $2, fecha_inicio = $3, fecha_fin = $4, turno_id = $5,
+	          hora_inicio = $6, hora_fin = $7, descripcion = $8 WHERE id = $9 RETURNING updated_at`
+
+	err := r.db.QueryRow(
+		query,
+		evento.Tipo,
+		evento.Nombre,
+		evento.FechaInicio,
+		evento.FechaFin,
+		evento.TurnoID,
+		evento.HoraInicio,
+		evento.HoraFin,
+		evento.Descripcion,
+		evento.ID,
+	).Scan(&evento.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("evento no encontrado")
+	}
+	return err
+}
+
+func (r *CalendarioRepositoryImpl) DeleteEvento(id int) error {
+	result, err := r.db.Exec(`DELETE FROM eventos_calendario WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return fmt.Errorf("evento no encontrado")
+	}
+	return nil
+}
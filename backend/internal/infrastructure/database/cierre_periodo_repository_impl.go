@@ -0,0 +1,105 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+type CierrePeriodoRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewCierrePeriodoRepository(db *sql.DB) *CierrePeriodoRepositoryImpl {
+	return &CierrePeriodoRepositoryImpl{db: db}
+}
+
+const movimientoCierreColumns = `c.id, c.anio, c.mes, c.accion, c.usuario_id, COALESCE(u.username, ''), c.motivo, c.created_at`
+
+func scanMovimientoCierre(row interface{ Scan(...interface{}) error }) (*entities.MovimientoCierre, error) {
+	movimiento := &entities.MovimientoCierre{}
+	err := row.Scan(
+		&movimiento.ID,
+		&movimiento.Anio,
+		&movimiento.Mes,
+		&movimiento.Accion,
+		&movimiento.UsuarioID,
+		&movimiento.Usuario,
+		&movimiento.Motivo,
+		&movimiento.CreatedAt,
+	)
+	return movimiento, err
+}
+
+func (r *CierrePeriodoRepositoryImpl) CreateMovimiento(movimiento *entities.MovimientoCierre) error {
+	query := `
+		INSERT INTO cierres_periodo (anio, mes, accion, usuario_id, motivo)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(
+		query,
+		movimiento.Anio,
+		movimiento.Mes,
+		movimiento.Accion,
+		movimiento.UsuarioID,
+		movimiento.Motivo,
+	).Scan(&movimiento.ID, &movimiento.CreatedAt)
+}
+
+func (r *CierrePeriodoRepositoryImpl) EstaCerrado(mes entities.MesContable) (bool, error) {
+	query := `
+		SELECT accion FROM cierres_periodo
+		WHERE anio = $1 AND mes = $2
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	var accion entities.AccionCierre
+	err := r.db.QueryRow(query, mes.Anio, mes.Mes).Scan(&accion)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return accion == entities.AccionCerrar, nil
+}
+
+func (r *CierrePeriodoRepositoryImpl) FindUltimosMovimientos() ([]*entities.MovimientoCierre, error) {
+	query := `
+		SELECT DISTINCT ON (c.anio, c.mes) ` + movimientoCierreColumns + `
+		FROM cierres_periodo c
+		LEFT JOIN usuarios u ON u.id = c.usuario_id
+		ORDER BY c.anio DESC, c.mes DESC, c.id DESC
+	`
+	return r.query(query)
+}
+
+func (r *CierrePeriodoRepositoryImpl) FindHistorial(mes entities.MesContable) ([]*entities.MovimientoCierre, error) {
+	query := `
+		SELECT ` + movimientoCierreColumns + `
+		FROM cierres_periodo c
+		LEFT JOIN usuarios u ON u.id = c.usuario_id
+		WHERE c.anio = $1 AND c.mes = $2
+		ORDER BY c.id
+	`
+	return r.query(query, mes.Anio, mes.Mes)
+}
+
+func (r *CierrePeriodoRepositoryImpl) query(query string, args ...interface{}) ([]*entities.MovimientoCierre, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movimientos := []*entities.MovimientoCierre{}
+	for rows.Next() {
+		movimiento, err := scanMovimientoCierre(rows)
+		if err != nil {
+			return nil, err
+		}
+		movimientos = append(movimientos, movimiento)
+	}
+	return movimientos, rows.Err()
+}
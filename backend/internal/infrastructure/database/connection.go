@@ -7,6 +7,7 @@ import (
 	"os"
 
 	_ "github.com/lib/pq"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
 )
 
 type Config struct {
@@ -16,6 +17,7 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	Timezone string
 }
 
 func NewConnection() (*sql.DB, error) {
@@ -24,9 +26,11 @@ func NewConnection() (*sql.DB, error) {
 		return nil, err
 	}
 
+	// La sesión usa la zona horaria de la institución para que los timestamps leídos y
+	// cualquier CURRENT_DATE de vistas o consultas manuales coincidan con el backend
 	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode,
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s timezone=%s",
+		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode, config.Timezone,
 	)
 
 	db, err := sql.Open("postgres", dsn)
@@ -101,5 +105,6 @@ func loadConfig() (*Config, error) {
 		Password: password,
 		DBName:   dbName,
 		SSLMode:  sslMode,
+		Timezone: clock.TimezoneFromEnv(),
 	}, nil
 }
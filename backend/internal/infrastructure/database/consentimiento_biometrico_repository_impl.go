@@ -0,0 +1,108 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+type ConsentimientoBiometricoRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewConsentimientoBiometricoRepository(db *sql.DB) *ConsentimientoBiometricoRepositoryImpl {
+	return &ConsentimientoBiometricoRepositoryImpl{db: db}
+}
+
+const consentimientoColumns = `c.id, c.docente_id, c.version_documento, c.fecha_consentimiento, c.recolectado_por,
+	          COALESCE(NULLIF(u.nombre_completo, ''), u.username, ''), c.revocado_at, c.revocado_por, c.motivo_revocacion, c.created_at`
+
+func (r *ConsentimientoBiometricoRepositoryImpl) Create(consentimiento *entities.ConsentimientoBiometrico) error {
+	query := `INSERT INTO consentimientos_biometricos (docente_id, version_documento, fecha_consentimiento, recolectado_por)
+	          VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+
+	return r.db.QueryRow(
+		query,
+		consentimiento.DocenteID,
+		consentimiento.VersionDocumento,
+		consentimiento.FechaConsentimiento,
+		consentimiento.RecolectadoPor,
+	).Scan(&consentimiento.ID, &consentimiento.CreatedAt)
+}
+
+func (r *ConsentimientoBiometricoRepositoryImpl) FindVigente(docenteID int) (*entities.ConsentimientoBiometrico, error) {
+	query := `SELECT ` + consentimientoColumns + `
+	          FROM consentimientos_biometricos c
+	          LEFT JOIN usuarios u ON c.recolectado_por = u.id
+	          WHERE c.docente_id = $1 AND c.revocado_at IS NULL`
+
+	consentimiento, err := r.scanConsentimiento(r.db.QueryRow(query, docenteID))
+	if err == sql.ErrNoRows {
+		return nil, repositories.ErrConsentimientoNoEncontrado
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return consentimiento, nil
+}
+
+func (r *ConsentimientoBiometricoRepositoryImpl) FindByDocente(docenteID int) ([]*entities.ConsentimientoBiometrico, error) {
+	query := `SELECT ` + consentimientoColumns + `
+	          FROM consentimientos_biometricos c
+	          LEFT JOIN usuarios u ON c.recolectado_por = u.id
+	          WHERE c.docente_id = $1
+	          ORDER BY c.fecha_consentimiento DESC, c.id DESC`
+
+	rows, err := r.db.Query(query, docenteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consentimientos := []*entities.ConsentimientoBiometrico{}
+	for rows.Next() {
+		consentimiento, err := r.scanConsentimiento(rows)
+		if err != nil {
+			return nil, err
+		}
+		consentimientos = append(consentimientos, consentimiento)
+	}
+
+	return consentimientos, nil
+}
+
+func (r *ConsentimientoBiometricoRepositoryImpl) Revocar(id int, revocadoPor int, motivo *string) error {
+	query := `UPDATE consentimientos_biometricos
+	          SET revocado_at = CURRENT_TIMESTAMP, revocado_por = $1, motivo_revocacion = $2
+	          WHERE id = $3 AND revocado_at IS NULL`
+	result, err := r.db.Exec(query, revocadoPor, motivo, id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return repositories.ErrConsentimientoNoEncontrado
+	}
+	return nil
+}
+
+func (r *ConsentimientoBiometricoRepositoryImpl) scanConsentimiento(row rowScanner) (*entities.ConsentimientoBiometrico, error) {
+	consentimiento := &entities.ConsentimientoBiometrico{}
+	err := row.Scan(
+		&consentimiento.ID,
+		&consentimiento.DocenteID,
+		&consentimiento.VersionDocumento,
+		&consentimiento.FechaConsentimiento,
+		&consentimiento.RecolectadoPor,
+		&consentimiento.RecolectadoPorNombre,
+		&consentimiento.RevocadoAt,
+		&consentimiento.RevocadoPor,
+		&consentimiento.MotivoRevocacion,
+		&consentimiento.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return consentimiento, nil
+}
@@ -2,10 +2,11 @@ package database
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 )
 
@@ -27,7 +28,7 @@ func NewDocenteRepository(db *sql.DB) *DocenteRepositoryImpl {
 }
 
 func (r *DocenteRepositoryImpl) FindByID(id int) (*entities.Docente, error) {
-	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, face_descriptors, created_at, updated_at
+	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, created_at, updated_at
 	          FROM docentes WHERE id = $1`
 
 	docente := &entities.Docente{}
@@ -39,7 +40,33 @@ func (r *DocenteRepositoryImpl) FindByID(id int) (*entities.Docente, error) {
 		&docente.Correo,
 		&docente.Telefono,
 		&docente.Activo,
-		&docente.FaceDescriptors,
+		&docente.CreatedAt,
+		&docente.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("docente no encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return docente, nil
+}
+
+func (r *DocenteRepositoryImpl) FindByUsuarioID(usuarioID int) (*entities.Docente, error) {
+	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, created_at, updated_at
+	          FROM docentes WHERE usuario_id = $1`
+
+	docente := &entities.Docente{}
+	err := r.db.QueryRow(query, usuarioID).Scan(
+		&docente.ID,
+		&docente.UsuarioID,
+		&docente.DocumentoIdentidad,
+		&docente.NombreCompleto,
+		&docente.Correo,
+		&docente.Telefono,
+		&docente.Activo,
 		&docente.CreatedAt,
 		&docente.UpdatedAt,
 	)
@@ -55,7 +82,7 @@ func (r *DocenteRepositoryImpl) FindByID(id int) (*entities.Docente, error) {
 }
 
 func (r *DocenteRepositoryImpl) FindByCI(ci int64) (*entities.Docente, error) {
-	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, face_descriptors, created_at, updated_at
+	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, created_at, updated_at
 	          FROM docentes WHERE documento_identidad = $1`
 
 	docente := &entities.Docente{}
@@ -67,7 +94,6 @@ func (r *DocenteRepositoryImpl) FindByCI(ci int64) (*entities.Docente, error) {
 		&docente.Correo,
 		&docente.Telefono,
 		&docente.Activo,
-		&docente.FaceDescriptors,
 		&docente.CreatedAt,
 		&docente.UpdatedAt,
 	)
@@ -83,7 +109,7 @@ func (r *DocenteRepositoryImpl) FindByCI(ci int64) (*entities.Docente, error) {
 }
 
 func (r *DocenteRepositoryImpl) SearchByCI(ciPartial string) ([]*entities.Docente, error) {
-	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, face_descriptors, created_at, updated_at
+	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, created_at, updated_at
 	          FROM docentes
 	          WHERE CAST(documento_identidad AS TEXT) LIKE $1 AND activo = TRUE
 	          ORDER BY documento_identidad
@@ -108,7 +134,6 @@ func (r *DocenteRepositoryImpl) SearchByCI(ciPartial string) ([]*entities.Docent
 			&docente.Correo,
 			&docente.Telefono,
 			&docente.Activo,
-			&docente.FaceDescriptors,
 			&docente.CreatedAt,
 			&docente.UpdatedAt,
 		)
@@ -122,7 +147,7 @@ func (r *DocenteRepositoryImpl) SearchByCI(ciPartial string) ([]*entities.Docent
 }
 
 func (r *DocenteRepositoryImpl) FindAll() ([]*entities.Docente, error) {
-	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, face_descriptors, created_at, updated_at
+	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, created_at, updated_at
 	          FROM docentes WHERE activo = TRUE ORDER BY nombre_completo`
 
 	rows, err := r.db.Query(query)
@@ -142,7 +167,6 @@ func (r *DocenteRepositoryImpl) FindAll() ([]*entities.Docente, error) {
 			&docente.Correo,
 			&docente.Telefono,
 			&docente.Activo,
-			&docente.FaceDescriptors,
 			&docente.CreatedAt,
 			&docente.UpdatedAt,
 		)
@@ -192,68 +216,197 @@ func (r *DocenteRepositoryImpl) Delete(id int) error {
 	return err
 }
 
-func (r *DocenteRepositoryImpl) AddFaceDescriptor(id int, descriptorJSON string) error {
-	query := `UPDATE docentes
-	          SET face_descriptors = COALESCE(face_descriptors, '[]'::jsonb) || $1::jsonb,
-	              updated_at = CURRENT_TIMESTAMP
-	          WHERE id = $2`
-	_, err := r.db.Exec(query, descriptorJSON, id)
-	return err
+func (r *DocenteRepositoryImpl) AddFaceDescriptor(rostro *entities.RostroDocente) error {
+	query := `INSERT INTO rostros_docente (docente_id, descriptor_cifrado, clave_id, modelo_version, calidad, origen)
+	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, requiere_reenrolamiento, created_at`
+
+	return r.db.QueryRow(
+		query,
+		rostro.DocenteID,
+		rostro.DescriptorCifrado,
+		rostro.ClaveID,
+		rostro.ModeloVersion,
+		rostro.Calidad,
+		rostro.Origen,
+	).Scan(&rostro.ID, &rostro.RequiereReenrolamiento, &rostro.CreatedAt)
 }
 
-func (r *DocenteRepositoryImpl) GetFaceDescriptors(id int) ([]string, error) {
-	query := `SELECT COALESCE(face_descriptors::text, '[]') FROM docentes WHERE id = $1`
+func (r *DocenteRepositoryImpl) GetFaceDescriptors(docenteID int) ([]*entities.RostroDocente, error) {
+	query := `SELECT id, docente_id, descriptor_cifrado, clave_id, descriptor, modelo_version, calidad, origen,
+	                 requiere_reenrolamiento, created_at
+	          FROM rostros_docente WHERE docente_id = $1 ORDER BY id`
 
-	var descriptorsJSON string
-	err := r.db.QueryRow(query, id).Scan(&descriptorsJSON)
-	if err == sql.ErrNoRows {
-		return []string{}, nil
+	return r.queryRostros(query, docenteID)
+}
+
+func (r *DocenteRepositoryImpl) GetAllFaceDescriptors() ([]*entities.RostroDocente, error) {
+	query := `SELECT rd.id, rd.docente_id, rd.descriptor_cifrado, rd.clave_id, rd.descriptor, rd.modelo_version,
+	                 rd.calidad, rd.origen, rd.requiere_reenrolamiento, rd.created_at
+	          FROM rostros_docente rd
+	          INNER JOIN docentes d ON rd.docente_id = d.id
+	          WHERE d.activo = TRUE AND rd.requiere_reenrolamiento = FALSE
+	          ORDER BY rd.docente_id, rd.id`
+
+	return r.queryRostros(query)
+}
+
+func (r *DocenteRepositoryImpl) FindFaceDescriptorsToEncrypt(activeKeyID string, includeOtherKeys bool) ([]*entities.RostroDocente, error) {
+	query := `SELECT id, docente_id, descriptor_cifrado, clave_id, descriptor, modelo_version, calidad, origen,
+	                 requiere_reenrolamiento, created_at
+	          FROM rostros_docente
+	          WHERE descriptor_cifrado IS NULL OR ($2 AND clave_id <> $1)
+	          ORDER BY id`
+
+	return r.queryRostros(query, activeKeyID, includeOtherKeys)
+}
+
+func (r *DocenteRepositoryImpl) UpdateFaceDescriptorCipher(rostroID int, descriptorCifrado []byte, claveID string) error {
+	query := `UPDATE rostros_docente SET descriptor_cifrado = $1, clave_id = $2, descriptor = NULL
+	          WHERE id = $3`
+	result, err := r.db.Exec(query, descriptorCifrado, claveID, rostroID)
+	if err != nil {
+		return err
 	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("rostro no encontrado")
+	}
+	return nil
+}
+
+func (r *DocenteRepositoryImpl) queryRostros(query string, args ...interface{}) ([]*entities.RostroDocente, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	// Si es un array vacío o nulo, retornar array vacío
-	if descriptorsJSON == "" || descriptorsJSON == "[]" || descriptorsJSON == "null" {
-		return []string{}, nil
+	rostros := []*entities.RostroDocente{}
+	for rows.Next() {
+		rostro := &entities.RostroDocente{}
+		var descriptor pq.Float32Array
+		err := rows.Scan(
+			&rostro.ID,
+			&rostro.DocenteID,
+			&rostro.DescriptorCifrado,
+			&rostro.ClaveID,
+			&descriptor,
+			&rostro.ModeloVersion,
+			&rostro.Calidad,
+			&rostro.Origen,
+			&rostro.RequiereReenrolamiento,
+			&rostro.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(descriptor) > 0 {
+			rostro.Descriptor = descriptor
+		}
+		rostros = append(rostros, rostro)
 	}
 
-	// Los descriptores están guardados como un array de objetos JSON
-	// Necesitamos retornar cada objeto completo como string
-	var rawDescriptors []json.RawMessage
-	err = json.Unmarshal([]byte(descriptorsJSON), &rawDescriptors)
+	return rostros, nil
+}
+
+func (r *DocenteRepositoryImpl) RemoveFaceDescriptor(docenteID int, rostroID int) error {
+	query := `DELETE FROM rostros_docente WHERE id = $1 AND docente_id = $2`
+	result, err := r.db.Exec(query, rostroID, docenteID)
 	if err != nil {
-		return []string{}, nil
+		return err
 	}
-
-	// Convertir cada RawMessage a string
-	descriptors := make([]string, len(rawDescriptors))
-	for i, raw := range rawDescriptors {
-		descriptors[i] = string(raw)
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("rostro no encontrado")
 	}
-
-	return descriptors, nil
+	return nil
 }
 
-func (r *DocenteRepositoryImpl) RemoveFaceDescriptor(id int, index int) error {
-	// Usar jsonb_set con NULL para eliminar un elemento específico del array
-	query := `UPDATE docentes
-	          SET face_descriptors = (
-	              SELECT jsonb_agg(value)
-	              FROM jsonb_array_elements(COALESCE(face_descriptors, '[]'::jsonb)) WITH ORDINALITY arr(value, idx)
-	              WHERE idx != $2 + 1
-	          ),
-	          updated_at = CURRENT_TIMESTAMP
-	          WHERE id = $1`
-	_, err := r.db.Exec(query, id, index)
+func (r *DocenteRepositoryImpl) ClearFaceDescriptors(docenteID int) error {
+	query := `DELETE FROM rostros_docente WHERE docente_id = $1`
+	_, err := r.db.Exec(query, docenteID)
 	return err
 }
 
-func (r *DocenteRepositoryImpl) ClearFaceDescriptors(id int) error {
-	query := `UPDATE docentes
-	          SET face_descriptors = '[]'::jsonb,
-	              updated_at = CURRENT_TIMESTAMP
-	          WHERE id = $1`
-	_, err := r.db.Exec(query, id)
+func (r *DocenteRepositoryImpl) ClearOutdatedFaceDescriptors(docenteID int) error {
+	query := `DELETE FROM rostros_docente WHERE docente_id = $1 AND requiere_reenrolamiento = TRUE`
+	_, err := r.db.Exec(query, docenteID)
 	return err
 }
+
+func (r *DocenteRepositoryImpl) FlagOutdatedFaceDescriptors(modeloVersion string) (int64, error) {
+	// Sincroniza la marca con el modelo actual: también desmarca las muestras que
+	// vuelven a ser comparables (por ejemplo, al regresar al motor anterior)
+	query := `UPDATE rostros_docente SET requiere_reenrolamiento = (modelo_version <> $1)
+	          WHERE requiere_reenrolamiento <> (modelo_version <> $1)
+	          RETURNING requiere_reenrolamiento`
+	rows, err := r.db.Query(query, modeloVersion)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var marcados int64
+	for rows.Next() {
+		var marcado bool
+		if err := rows.Scan(&marcado); err != nil {
+			return 0, err
+		}
+		if marcado {
+			marcados++
+		}
+	}
+	return marcados, rows.Err()
+}
+
+func (r *DocenteRepositoryImpl) FindRequiringReenrollment() ([]*entities.Docente, error) {
+	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, created_at, updated_at
+	          FROM docentes d
+	          WHERE d.activo = TRUE AND EXISTS (
+	              SELECT 1 FROM rostros_docente rd
+	              WHERE rd.docente_id = d.id AND rd.requiere_reenrolamiento = TRUE
+	          )
+	          ORDER BY nombre_completo`
+
+	return r.queryDocentes(query)
+}
+
+func (r *DocenteRepositoryImpl) FindInactiveWithFaceDescriptors(inactivoDesde time.Time) ([]*entities.Docente, error) {
+	// updated_at registra la desactivación (trigger update_docentes_modtime)
+	query := `SELECT id, usuario_id, documento_identidad, nombre_completo, correo, telefono, activo, created_at, updated_at
+	          FROM docentes d
+	          WHERE d.activo = FALSE AND d.updated_at < $1 AND EXISTS (
+	              SELECT 1 FROM rostros_docente rd WHERE rd.docente_id = d.id
+	          )
+	          ORDER BY id`
+
+	return r.queryDocentes(query, inactivoDesde)
+}
+
+func (r *DocenteRepositoryImpl) queryDocentes(query string, args ...interface{}) ([]*entities.Docente, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	docentes := []*entities.Docente{}
+	for rows.Next() {
+		docente := &entities.Docente{}
+		err := rows.Scan(
+			&docente.ID,
+			&docente.UsuarioID,
+			&docente.DocumentoIdentidad,
+			&docente.NombreCompleto,
+			&docente.Correo,
+			&docente.Telefono,
+			&docente.Activo,
+			&docente.CreatedAt,
+			&docente.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		docentes = append(docentes, docente)
+	}
+
+	return docentes, nil
+}
@@ -0,0 +1,241 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+type DosFactoresRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewDosFactoresRepository(db *sql.DB) *DosFactoresRepositoryImpl {
+	return &DosFactoresRepositoryImpl{db: db}
+}
+
+func (r *DosFactoresRepositoryImpl) FindByUsuarioID(usuarioID int) (*entities.DosFactores, error) {
+	query := `SELECT usuario_id, secreto_cifrado, clave_id, activo, ultimo_paso, activado_at, created_at, updated_at
+	          FROM dos_factores
+	          WHERE usuario_id = $1`
+
+	dosFactores := &entities.DosFactores{}
+	err := r.db.QueryRow(query, usuarioID).Scan(
+		&dosFactores.UsuarioID,
+		&dosFactores.SecretoCifrado,
+		&dosFactores.ClaveID,
+		&dosFactores.Activo,
+		&dosFactores.UltimoPaso,
+		&dosFactores.ActivadoAt,
+		&dosFactores.CreatedAt,
+		&dosFactores.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, repositories.ErrDosFactoresNoConfigurado
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dosFactores, nil
+}
+
+func (r *DosFactoresRepositoryImpl) FindConOtraClave(claveActiva string) ([]*entities.DosFactores, error) {
+	query := `SELECT usuario_id, secreto_cifrado, clave_id, activo, ultimo_paso, activado_at, created_at, updated_at
+	          FROM dos_factores
+	          WHERE clave_id <> $1
+	          ORDER BY usuario_id`
+
+	rows, err := r.db.Query(query, claveActiva)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	configuraciones := []*entities.DosFactores{}
+	for rows.Next() {
+		dosFactores := &entities.DosFactores{}
+		err := rows.Scan(
+			&dosFactores.UsuarioID,
+			&dosFactores.SecretoCifrado,
+			&dosFactores.ClaveID,
+			&dosFactores.Activo,
+			&dosFactores.UltimoPaso,
+			&dosFactores.ActivadoAt,
+			&dosFactores.CreatedAt,
+			&dosFactores.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		configuraciones = append(configuraciones, dosFactores)
+	}
+	return configuraciones, rows.Err()
+}
+
+func (r *DosFactoresRepositoryImpl) ActualizarCifrado(usuarioID int, claveAnterior string, secretoCifrado []byte, claveID string) error {
+	query := `UPDATE dos_factores SET secreto_cifrado = $1, clave_id = $2
+	          WHERE usuario_id = $3 AND clave_id = $4`
+	_, err := r.db.Exec(query, secretoCifrado, claveID, usuarioID, claveAnterior)
+	return err
+}
+
+func (r *DosFactoresRepositoryImpl) Guardar(dosFactores *entities.DosFactores) error {
+	query := `INSERT INTO dos_factores (usuario_id, secreto_cifrado, clave_id)
+	          VALUES ($1, $2, $3)
+	          ON CONFLICT (usuario_id) DO UPDATE
+	          SET secreto_cifrado = EXCLUDED.secreto_cifrado, clave_id = EXCLUDED.clave_id,
+	              activo = FALSE, ultimo_paso = 0, activado_at = NULL
+	          RETURNING activo, ultimo_paso, activado_at, created_at, updated_at`
+
+	return r.db.QueryRow(
+		query,
+		dosFactores.UsuarioID,
+		dosFactores.SecretoCifrado,
+		dosFactores.ClaveID,
+	).Scan(&dosFactores.Activo, &dosFactores.UltimoPaso, &dosFactores.ActivadoAt, &dosFactores.CreatedAt, &dosFactores.UpdatedAt)
+}
+
+func (r *DosFactoresRepositoryImpl) Activar(usuarioID int, paso int64, hashesRecuperacion []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE dos_factores SET activo = TRUE, ultimo_paso = $2, activado_at = CURRENT_TIMESTAMP
+	          WHERE usuario_id = $1 AND activo = FALSE`
+	result, err := tx.Exec(query, usuarioID, paso)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return fmt.Errorf("no hay un enrolamiento pendiente de confirmar")
+	}
+	if err := reemplazarCodigos(tx, usuarioID, hashesRecuperacion); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *DosFactoresRepositoryImpl) Delete(usuarioID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM dos_factores WHERE usuario_id = $1`, usuarioID)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return repositories.ErrDosFactoresNoConfigurado
+	}
+	if _, err := tx.Exec(`DELETE FROM codigos_recuperacion WHERE usuario_id = $1`, usuarioID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *DosFactoresRepositoryImpl) RegistrarPaso(usuarioID int, paso int64) (bool, error) {
+	query := `UPDATE dos_factores SET ultimo_paso = $2
+	          WHERE usuario_id = $1 AND activo = TRUE AND ultimo_paso < $2`
+
+	result, err := r.db.Exec(query, usuarioID, paso)
+	if err != nil {
+		return false, err
+	}
+	filas, err := result.RowsAffected()
+	return filas > 0, err
+}
+
+func (r *DosFactoresRepositoryImpl) UsarCodigoRecuperacion(usuarioID int, hash string) (bool, error) {
+	query := `UPDATE codigos_recuperacion SET usado_at = CURRENT_TIMESTAMP
+	          WHERE usuario_id = $1 AND codigo_hash = $2 AND usado_at IS NULL`
+
+	result, err := r.db.Exec(query, usuarioID, hash)
+	if err != nil {
+		return false, err
+	}
+	filas, err := result.RowsAffected()
+	return filas > 0, err
+}
+
+func (r *DosFactoresRepositoryImpl) ReemplazarCodigosRecuperacion(usuarioID int, hashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := reemplazarCodigos(tx, usuarioID, hashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// reemplazarCodigos elimina los códigos de recuperación del usuario e inserta los nuevos
+func reemplazarCodigos(tx *sql.Tx, usuarioID int, hashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM codigos_recuperacion WHERE usuario_id = $1`, usuarioID); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if _, err := tx.Exec(`INSERT INTO codigos_recuperacion (usuario_id, codigo_hash) VALUES ($1, $2)`, usuarioID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DosFactoresRepositoryImpl) CountCodigosRecuperacion(usuarioID int) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM codigos_recuperacion WHERE usuario_id = $1 AND usado_at IS NULL`, usuarioID).Scan(&total)
+	return total, err
+}
+
+func (r *DosFactoresRepositoryImpl) FindPoliticas() ([]*entities.PoliticaDosFactores, error) {
+	rows, err := r.db.Query(`SELECT rol, requerido, actualizado_por, updated_at FROM politicas_dos_factores ORDER BY rol`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	politicas := []*entities.PoliticaDosFactores{}
+	for rows.Next() {
+		politica := &entities.PoliticaDosFactores{}
+		if err := rows.Scan(&politica.Rol, &politica.Requerido, &politica.ActualizadoPor, &politica.UpdatedAt); err != nil {
+			return nil, err
+		}
+		politicas = append(politicas, politica)
+	}
+	return politicas, rows.Err()
+}
+
+func (r *DosFactoresRepositoryImpl) FindPolitica(rol entities.Rol) (*entities.PoliticaDosFactores, error) {
+	query := `SELECT rol, requerido, actualizado_por, updated_at FROM politicas_dos_factores WHERE rol = $1`
+
+	politica := &entities.PoliticaDosFactores{}
+	err := r.db.QueryRow(query, rol).Scan(&politica.Rol, &politica.Requerido, &politica.ActualizadoPor, &politica.UpdatedAt)
+	if err == sql.ErrNoRows {
+		// Sin fila el rol no exige segundo factor
+		return &entities.PoliticaDosFactores{Rol: rol}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return politica, nil
+}
+
+func (r *DosFactoresRepositoryImpl) SavePolitica(politica *entities.PoliticaDosFactores) error {
+	query := `INSERT INTO politicas_dos_factores (rol, requerido, actualizado_por)
+	          VALUES ($1, $2, $3)
+	          ON CONFLICT (rol) DO UPDATE
+	          SET requerido = EXCLUDED.requerido, actualizado_por = EXCLUDED.actualizado_por
+	          RETURNING updated_at`
+
+	return r.db.QueryRow(query, politica.Rol, politica.Requerido, politica.ActualizadoPor).Scan(&politica.UpdatedAt)
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+type EvidenciaReconocimientoRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewEvidenciaReconocimientoRepository(db *sql.DB) *EvidenciaReconocimientoRepositoryImpl {
+	return &EvidenciaReconocimientoRepositoryImpl{db: db}
+}
+
+func (r *EvidenciaReconocimientoRepositoryImpl) Create(evidencia *entities.EvidenciaReconocimiento) error {
+	query := `INSERT INTO evidencias_reconocimiento (intento_id, docente_id, almacen, archivo, imagen, clave_id, tamano)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`
+
+	return r.db.QueryRow(
+		query,
+		evidencia.IntentoID,
+		evidencia.DocenteID,
+		evidencia.Almacen,
+		evidencia.Archivo,
+		evidencia.Imagen,
+		evidencia.ClaveID,
+		evidencia.Tamano,
+	).Scan(&evidencia.ID, &evidencia.CreatedAt)
+}
+
+func (r *EvidenciaReconocimientoRepositoryImpl) FindByRegistro(registroID int) (*entities.EvidenciaReconocimiento, error) {
+	query := `SELECT e.id, e.intento_id, e.docente_id, i.registro_id, e.almacen, e.archivo, e.imagen, e.clave_id,
+	                 e.tamano, e.created_at
+	          FROM evidencias_reconocimiento e
+	          INNER JOIN intentos_reconocimiento i ON e.intento_id = i.id
+	          WHERE i.registro_id = $1`
+
+	evidencia := &entities.EvidenciaReconocimiento{}
+	err := r.db.QueryRow(query, registroID).Scan(
+		&evidencia.ID,
+		&evidencia.IntentoID,
+		&evidencia.DocenteID,
+		&evidencia.RegistroID,
+		&evidencia.Almacen,
+		&evidencia.Archivo,
+		&evidencia.Imagen,
+		&evidencia.ClaveID,
+		&evidencia.Tamano,
+		&evidencia.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("evidencia no encontrada")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return evidencia, nil
+}
+
+func (r *EvidenciaReconocimientoRepositoryImpl) DeleteBefore(antes time.Time) ([]*entities.EvidenciaReconocimiento, error) {
+	query := `DELETE FROM evidencias_reconocimiento WHERE created_at < $1
+	          RETURNING id, intento_id, docente_id, almacen, archivo, tamano, created_at`
+
+	return r.eliminar(query, antes)
+}
+
+func (r *EvidenciaReconocimientoRepositoryImpl) DeleteByDocente(docenteID int) ([]*entities.EvidenciaReconocimiento, error) {
+	query := `DELETE FROM evidencias_reconocimiento WHERE docente_id = $1
+	          RETURNING id, intento_id, docente_id, almacen, archivo, tamano, created_at`
+
+	return r.eliminar(query, docenteID)
+}
+
+// eliminar ejecuta un DELETE ... RETURNING y retorna las evidencias eliminadas
+func (r *EvidenciaReconocimientoRepositoryImpl) eliminar(query string, args ...interface{}) ([]*entities.EvidenciaReconocimiento, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	eliminadas := []*entities.EvidenciaReconocimiento{}
+	for rows.Next() {
+		evidencia := &entities.EvidenciaReconocimiento{}
+		err := rows.Scan(
+			&evidencia.ID,
+			&evidencia.IntentoID,
+			&evidencia.DocenteID,
+			&evidencia.Almacen,
+			&evidencia.Archivo,
+			&evidencia.Tamano,
+			&evidencia.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		eliminadas = append(eliminadas, evidencia)
+	}
+
+	return eliminadas, rows.Err()
+}
@@ -0,0 +1,224 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+const (
+	// limiteIntentosPorDefecto es la cantidad de intentos retornados si no se especifica un límite
+	limiteIntentosPorDefecto = 100
+	// limiteIntentosMaximo evita consultas demasiado grandes
+	limiteIntentosMaximo = 1000
+)
+
+type IntentoReconocimientoRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewIntentoReconocimientoRepository(db *sql.DB) *IntentoReconocimientoRepositoryImpl {
+	return &IntentoReconocimientoRepositoryImpl{db: db}
+}
+
+const intentoReconocimientoColumns = `id, fecha_hora, modo, operador_id, terminal, rostros_detectados, candidatos, decision,
+	          docente_id, registro_id, detalle, marcado_incorrecto, docente_real_id, motivo_marca, marcado_por, marcado_at,
+	          verificacion_usada_at, created_at`
+
+func (r *IntentoReconocimientoRepositoryImpl) FindByID(id int) (*entities.IntentoReconocimiento, error) {
+	query := `SELECT ` + intentoReconocimientoColumns + `
+	          FROM intentos_reconocimiento WHERE id = $1`
+
+	intento, err := r.scanIntento(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("intento de reconocimiento no encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return intento, nil
+}
+
+func (r *IntentoReconocimientoRepositoryImpl) FindAll(filtro repositories.FiltroIntentosReconocimiento) ([]*entities.IntentoReconocimiento, error) {
+	condiciones := []string{}
+	args := []interface{}{}
+
+	agregar := func(condicion string, valor interface{}) {
+		args = append(args, valor)
+		condiciones = append(condiciones, fmt.Sprintf(condicion, len(args)))
+	}
+
+	if filtro.Desde != nil {
+		agregar("fecha_hora >= $%d", *filtro.Desde)
+	}
+	if filtro.Hasta != nil {
+		agregar("fecha_hora < $%d", *filtro.Hasta)
+	}
+	if filtro.Modo != nil {
+		agregar("modo = $%d", string(*filtro.Modo))
+	}
+	if filtro.Decision != nil {
+		agregar("decision = $%d", string(*filtro.Decision))
+	}
+	if filtro.DocenteID != nil {
+		agregar("(docente_id = $%[1]d OR docente_real_id = $%[1]d)", *filtro.DocenteID)
+	}
+	if filtro.SoloIncorrectos {
+		condiciones = append(condiciones, "marcado_incorrecto = TRUE")
+	}
+
+	limite := filtro.Limite
+	if limite <= 0 {
+		limite = limiteIntentosPorDefecto
+	}
+	if limite > limiteIntentosMaximo {
+		limite = limiteIntentosMaximo
+	}
+
+	query := `SELECT ` + intentoReconocimientoColumns + ` FROM intentos_reconocimiento`
+	if len(condiciones) > 0 {
+		query += " WHERE " + strings.Join(condiciones, " AND ")
+	}
+	args = append(args, limite)
+	query += fmt.Sprintf(" ORDER BY fecha_hora DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	intentos := []*entities.IntentoReconocimiento{}
+	for rows.Next() {
+		intento, err := r.scanIntento(rows)
+		if err != nil {
+			return nil, err
+		}
+		intentos = append(intentos, intento)
+	}
+
+	return intentos, nil
+}
+
+func (r *IntentoReconocimientoRepositoryImpl) Create(intento *entities.IntentoReconocimiento) error {
+	candidatos := intento.Candidatos
+	if candidatos == nil {
+		candidatos = []entities.CandidatoReconocimiento{}
+	}
+	candidatosJSON, err := json.Marshal(candidatos)
+	if err != nil {
+		return fmt.Errorf("error serializando candidatos: %w", err)
+	}
+
+	query := `INSERT INTO intentos_reconocimiento (fecha_hora, modo, operador_id, terminal, rostros_detectados, candidatos,
+	          decision, docente_id, detalle)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, created_at`
+
+	return r.db.QueryRow(
+		query,
+		intento.FechaHora,
+		intento.Modo,
+		intento.OperadorID,
+		intento.Terminal,
+		intento.RostrosDetectados,
+		string(candidatosJSON),
+		intento.Decision,
+		intento.DocenteID,
+		intento.Detalle,
+	).Scan(&intento.ID, &intento.CreatedAt)
+}
+
+func (r *IntentoReconocimientoRepositoryImpl) VincularRegistro(id int, registroID int) error {
+	query := `UPDATE intentos_reconocimiento SET registro_id = $1 WHERE id = $2 AND registro_id IS NULL`
+	result, err := r.db.Exec(query, registroID, id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("el intento no existe o ya está vinculado a un registro")
+	}
+	return nil
+}
+
+// ConsumirVerificacion usa una sola sentencia para que dos solicitudes con el mismo token no
+// puedan consumirlo a la vez
+func (r *IntentoReconocimientoRepositoryImpl) ConsumirVerificacion(id int, docenteID int) error {
+	query := `UPDATE intentos_reconocimiento SET verificacion_usada_at = CURRENT_TIMESTAMP
+	          WHERE id = $1 AND docente_id = $2 AND modo = $3 AND decision = $4 AND verificacion_usada_at IS NULL`
+	result, err := r.db.Exec(query, id, docenteID, entities.ModoVerificacion, entities.DecisionIdentificado)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return repositories.ErrVerificacionUtilizada
+	}
+	return nil
+}
+
+func (r *IntentoReconocimientoRepositoryImpl) LiberarVerificacion(id int) error {
+	query := `UPDATE intentos_reconocimiento SET verificacion_usada_at = NULL WHERE id = $1 AND registro_id IS NULL`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *IntentoReconocimientoRepositoryImpl) MarcarIncorrecto(id int, marcadoPor int, docenteRealID *int, motivo string) error {
+	query := `UPDATE intentos_reconocimiento
+	          SET marcado_incorrecto = TRUE, docente_real_id = $1, motivo_marca = $2,
+	              marcado_por = $3, marcado_at = CURRENT_TIMESTAMP
+	          WHERE id = $4`
+	result, err := r.db.Exec(query, docenteRealID, motivo, marcadoPor, id)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("intento de reconocimiento no encontrado")
+	}
+	return nil
+}
+
+// rowScanner permite reutilizar el escaneo para sql.Row y sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *IntentoReconocimientoRepositoryImpl) scanIntento(row rowScanner) (*entities.IntentoReconocimiento, error) {
+	intento := &entities.IntentoReconocimiento{}
+	var candidatosJSON []byte
+	err := row.Scan(
+		&intento.ID,
+		&intento.FechaHora,
+		&intento.Modo,
+		&intento.OperadorID,
+		&intento.Terminal,
+		&intento.RostrosDetectados,
+		&candidatosJSON,
+		&intento.Decision,
+		&intento.DocenteID,
+		&intento.RegistroID,
+		&intento.Detalle,
+		&intento.MarcadoIncorrecto,
+		&intento.DocenteRealID,
+		&intento.MotivoMarca,
+		&intento.MarcadoPor,
+		&intento.MarcadoAt,
+		&intento.VerificacionUsadaAt,
+		&intento.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	intento.Candidatos = []entities.CandidatoReconocimiento{}
+	if len(candidatosJSON) > 0 {
+		if err := json.Unmarshal(candidatosJSON, &intento.Candidatos); err != nil {
+			return nil, fmt.Errorf("error deserializando candidatos: %w", err)
+		}
+	}
+
+	return intento, nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+// driverVerificaciones simula las sentencias UPDATE de ConsumirVerificacion y LiberarVerificacion
+// sobre un intento de verificación exitoso del docente 7, respetando su condición
+// verificacion_usada_at IS NULL
+type driverVerificaciones struct {
+	usadas map[int64]bool
+}
+
+func (d *driverVerificaciones) Open(string) (driver.Conn, error) {
+	return &conexionVerificaciones{driver: d}, nil
+}
+
+type conexionVerificaciones struct {
+	driver *driverVerificaciones
+}
+
+func (c *conexionVerificaciones) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *conexionVerificaciones) Close() error {
+	return nil
+}
+
+func (c *conexionVerificaciones) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *conexionVerificaciones) Exec(query string, args []driver.Value) (driver.Result, error) {
+	id, _ := args[0].(int64)
+	switch {
+	case strings.Contains(query, "verificacion_usada_at = NULL"):
+		c.driver.usadas[id] = false
+		return driver.RowsAffected(1), nil
+	case strings.Contains(query, "verificacion_usada_at IS NULL"):
+		docenteID, _ := args[1].(int64)
+		if docenteID != 7 || c.driver.usadas[id] {
+			return driver.RowsAffected(0), nil
+		}
+		c.driver.usadas[id] = true
+		return driver.RowsAffected(1), nil
+	}
+	return nil, errors.New("sentencia no esperada: " + query)
+}
+
+func TestConsumirVerificacion(t *testing.T) {
+	sql.Register("verificaciones", &driverVerificaciones{usadas: map[int64]bool{}})
+	db, err := sql.Open("verificaciones", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	repo := NewIntentoReconocimientoRepository(db)
+
+	if err := repo.ConsumirVerificacion(43, 8); !errors.Is(err, repositories.ErrVerificacionUtilizada) {
+		t.Errorf("verificación de otro docente: error = %v", err)
+	}
+	if err := repo.ConsumirVerificacion(43, 7); err != nil {
+		t.Fatal(err)
+	}
+	// El mismo token presentado de nuevo
+	if err := repo.ConsumirVerificacion(43, 7); !errors.Is(err, repositories.ErrVerificacionUtilizada) {
+		t.Errorf("segundo uso: error = %v, se esperaba ErrVerificacionUtilizada", err)
+	}
+
+	// Si el ingreso no se pudo crear, el token vuelve a servir
+	if err := repo.LiberarVerificacion(43); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.ConsumirVerificacion(43, 7); err != nil {
+		t.Errorf("uso tras liberar: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+type JustificacionRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewJustificacionRepository(db *sql.DB) *JustificacionRepositoryImpl {
+	return &JustificacionRepositoryImpl{db: db}
+}
+
+const justificacionColumns = `j.id, j.docente_id, d.nombre_completo, d.documento_identidad, j.registro_id, j.fecha,
+	          j.turno_id, j.categoria, j.descripcion, j.documento_nombre, j.documento_tipo, j.documento_tamano,
+	          j.documento_archivo, j.estado, j.creado_por, j.revisado_por, j.revisado_at, j.observacion_revision,
+	          j.created_at, j.updated_at`
+
+const justificacionFrom = ` FROM justificaciones j INNER JOIN docentes d ON d.id = j.docente_id`
+
+func scanJustificacion(row interface{ Scan(...interface{}) error }) (*entities.Justificacion, error) {
+	j := &entities.Justificacion{}
+	err := row.Scan(
+		&j.ID,
+		&j.DocenteID,
+		&j.DocenteNombre,
+		&j.DocenteCI,
+		&j.RegistroID,
+		&j.Fecha,
+		&j.TurnoID,
+		&j.Categoria,
+		&j.Descripcion,
+		&j.DocumentoNombre,
+		&j.DocumentoTipo,
+		&j.DocumentoTamano,
+		&j.DocumentoArchivo,
+		&j.Estado,
+		&j.CreadoPor,
+		&j.RevisadoPor,
+		&j.RevisadoAt,
+		&j.ObservacionRevision,
+		&j.CreatedAt,
+		&j.UpdatedAt,
+	)
+	return j, err
+}
+
+func (r *JustificacionRepositoryImpl) FindByID(id int) (*entities.Justificacion, error) {
+	query := `SELECT ` + justificacionColumns + justificacionFrom + ` WHERE j.id = $1`
+
+	j, err := scanJustificacion(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("justificación no encontrada")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (r *JustificacionRepositoryImpl) Find(filtro repositories.FiltroJustificaciones) ([]*entities.Justificacion, error) {
+	condiciones := []string{}
+	args := []interface{}{}
+
+	agregar := func(condicion string, valor interface{}) {
+		args = append(args, valor)
+		condiciones = append(condiciones, fmt.Sprintf(condicion, len(args)))
+	}
+
+	if filtro.DocenteID != nil {
+		agregar("j.docente_id = $%d", *filtro.DocenteID)
+	}
+	if filtro.Estado != nil {
+		agregar("j.estado = $%d", string(*filtro.Estado))
+	}
+	if filtro.Desde != nil {
+		agregar("j.fecha >= $%d", *filtro.Desde)
+	}
+	if filtro.Hasta != nil {
+		agregar("j.fecha <= $%d", *filtro.Hasta)
+	}
+
+	query := `SELECT ` + justificacionColumns + justificacionFrom
+	if len(condiciones) > 0 {
+		query += " WHERE " + strings.Join(condiciones, " AND ")
+	}
+	query += " ORDER BY j.fecha DESC, j.id DESC"
+
+	return r.query(query, args...)
+}
+
+func (r *JustificacionRepositoryImpl) FindAprobadas(docenteID int, fecha entities.Fecha) ([]*entities.Justificacion, error) {
+	query := `SELECT ` + justificacionColumns + justificacionFrom + `
+	          WHERE j.docente_id = $1 AND j.fecha = $2 AND j.estado = 'aprobada'`
+	return r.query(query, docenteID, fecha)
+}
+
+func (r *JustificacionRepositoryImpl) Create(j *entities.Justificacion) error {
+	query := `
+		INSERT INTO justificaciones (docente_id, registro_id, fecha, turno_id, categoria, descripcion, estado, creado_por)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRow(
+		query,
+		j.DocenteID,
+		j.RegistroID,
+		j.Fecha,
+		j.TurnoID,
+		j.Categoria,
+		j.Descripcion,
+		j.Estado,
+		j.CreadoPor,
+	).Scan(&j.ID, &j.CreatedAt, &j.UpdatedAt)
+}
+
+func (r *JustificacionRepositoryImpl) UpdateRevision(j *entities.Justificacion) error {
+	query := `UPDATE justificaciones SET estado = $1, revisado_por = $2, revisado_at = $3, observacion_revision = $4
+	          WHERE id = $5 RETURNING updated_at`
+	return r.db.QueryRow(query, j.Estado, j.RevisadoPor, j.RevisadoAt, j.ObservacionRevision, j.ID).Scan(&j.UpdatedAt)
+}
+
+func (r *JustificacionRepositoryImpl) UpdateDocumento(j *entities.Justificacion) error {
+	query := `UPDATE justificaciones SET documento_nombre = $1, documento_tipo = $2, documento_tamano = $3,
+	          documento_archivo = $4 WHERE id = $5 RETURNING updated_at`
+	return r.db.QueryRow(query, j.DocumentoNombre, j.DocumentoTipo, j.DocumentoTamano, j.DocumentoArchivo, j.ID).Scan(&j.UpdatedAt)
+}
+
+func (r *JustificacionRepositoryImpl) Delete(id int) error {
+	_, err := r.db.Exec(`DELETE FROM justificaciones WHERE id = $1`, id)
+	return err
+}
+
+func (r *JustificacionRepositoryImpl) query(query string, args ...interface{}) ([]*entities.Justificacion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	justificaciones := []*entities.Justificacion{}
+	for rows.Next() {
+		j, err := scanJustificacion(rows)
+		if err != nil {
+			return nil, err
+		}
+		justificaciones = append(justificaciones, j)
+	}
+	return justificaciones, rows.Err()
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+type LicenciaRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewLicenciaRepository(db *sql.DB) *LicenciaRepositoryImpl {
+	return &LicenciaRepositoryImpl{db: db}
+}
+
+const licenciaColumns = `l.id, l.docente_id, d.nombre_completo, d.documento_identidad, l.tipo, l.fecha_inicio,
+	          l.fecha_fin, l.descripcion, l.creado_por, l.created_at, l.updated_at`
+
+const licenciaFrom = ` FROM licencias l INNER JOIN docentes d ON d.id = l.docente_id`
+
+func scanLicencia(row interface{ Scan(...interface{}) error }) (*entities.Licencia, error) {
+	licencia := &entities.Licencia{}
+	err := row.Scan(
+		&licencia.ID,
+		&licencia.DocenteID,
+		&licencia.DocenteNombre,
+		&licencia.DocenteCI,
+		&licencia.Tipo,
+		&licencia.FechaInicio,
+		&licencia.FechaFin,
+		&licencia.Descripcion,
+		&licencia.CreadoPor,
+		&licencia.CreatedAt,
+		&licencia.UpdatedAt,
+	)
+	return licencia, err
+}
+
+func (r *LicenciaRepositoryImpl) FindByID(id int) (*entities.Licencia, error) {
+	query := `SELECT ` + licenciaColumns + licenciaFrom + ` WHERE l.id = $1`
+
+	licencia, err := scanLicencia(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("licencia no encontrada")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return licencia, nil
+}
+
+func (r *LicenciaRepositoryImpl) Find(filtro repositories.FiltroLicencias) ([]*entities.Licencia, error) {
+	condiciones := []string{}
+	args := []interface{}{}
+
+	agregar := func(condicion string, valor interface{}) {
+		args = append(args, valor)
+		condiciones = append(condiciones, fmt.Sprintf(condicion, len(args)))
+	}
+
+	if filtro.DocenteID != nil {
+		agregar("l.docente_id = $%d", *filtro.DocenteID)
+	}
+	if filtro.Desde != nil {
+		agregar("l.fecha_fin >= $%d", *filtro.Desde)
+	}
+	if filtro.Hasta != nil {
+		agregar("l.fecha_inicio <= $%d", *filtro.Hasta)
+	}
+
+	query := `SELECT ` + licenciaColumns + licenciaFrom
+	if len(condiciones) > 0 {
+		query += " WHERE " + strings.Join(condiciones, " AND ")
+	}
+	query += " ORDER BY l.fecha_inicio DESC, l.id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	licencias := []*entities.Licencia{}
+	for rows.Next() {
+		licencia, err := scanLicencia(rows)
+		if err != nil {
+			return nil, err
+		}
+		licencias = append(licencias, licencia)
+	}
+	return licencias, rows.Err()
+}
+
+func (r *LicenciaRepositoryImpl) Create(licencia *entities.Licencia) error {
+	query := `
+		INSERT INTO licencias (docente_id, tipo, fecha_inicio, fecha_fin, descripcion, creado_por)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRow(
+		query,
+		licencia.DocenteID,
+		licencia.Tipo,
+		licencia.FechaInicio,
+		licencia.FechaFin,
+		licencia.Descripcion,
+		licencia.CreadoPor,
+	).Scan(&licencia.ID, &licencia.CreatedAt, &licencia.UpdatedAt)
+}
+
+func (r *LicenciaRepositoryImpl) Update(licencia *entities.Licencia) error {
+	query := `UPDATE licencias SET tipo = $1, fecha_inicio = $2, fecha_fin = $3, descripcion = $4
+	          WHERE id = $5 RETURNING updated_at`
+	err := r.db.QueryRow(
+		query,
+		licencia.Tipo,
+		licencia.FechaInicio,
+		licencia.FechaFin,
+		licencia.Descripcion,
+		licencia.ID,
+	).Scan(&licencia.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("licencia no encontrada")
+	}
+	return err
+}
+
+func (r *LicenciaRepositoryImpl) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM licencias WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return fmt.Errorf("licencia no encontrada")
+	}
+	return nil
+}
@@ -3,22 +3,28 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
 )
 
+// RegistroRepositoryImpl filtra por día con rangos [inicio, fin) calculados en la zona horaria
+// de la institución, nunca con DATE(...) = CURRENT_DATE, que depende de la sesión de la base
 type RegistroRepositoryImpl struct {
-	db *sql.DB
+	db    *sql.DB
+	clock clock.Clock
 }
 
-func NewRegistroRepository(db *sql.DB) *RegistroRepositoryImpl {
-	return &RegistroRepositoryImpl{db: db}
+func NewRegistroRepository(db *sql.DB, reloj clock.Clock) *RegistroRepositoryImpl {
+	return &RegistroRepositoryImpl{db: db, clock: reloj}
 }
 
 func (r *RegistroRepositoryImpl) FindByID(id int) (*entities.Registro, error) {
 	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
-	          minutos_retraso, minutos_extra, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
 	          FROM registros WHERE id = $1`
 
 	registro := &entities.Registro{}
@@ -31,6 +37,9 @@ func (r *RegistroRepositoryImpl) FindByID(id int) (*entities.Registro, error) {
 		&registro.FechaHora,
 		&registro.MinutosRetraso,
 		&registro.MinutosExtra,
+		&registro.Clasificacion,
+		&registro.IngresoID,
+		&registro.SuplenciaID,
 		&registro.EsExcepcional,
 		&registro.Observaciones,
 		&registro.EditadoPor,
@@ -50,7 +59,7 @@ func (r *RegistroRepositoryImpl) FindByID(id int) (*entities.Registro, error) {
 
 func (r *RegistroRepositoryImpl) FindAll() ([]*entities.Registro, error) {
 	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
-	          minutos_retraso, minutos_extra, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
 	          FROM registros ORDER BY fecha_hora DESC LIMIT 100`
 
 	rows, err := r.db.Query(query)
@@ -64,7 +73,7 @@ func (r *RegistroRepositoryImpl) FindAll() ([]*entities.Registro, error) {
 
 func (r *RegistroRepositoryImpl) FindByDocente(docenteID int) ([]*entities.Registro, error) {
 	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
-	          minutos_retraso, minutos_extra, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
 	          FROM registros WHERE docente_id = $1 ORDER BY fecha_hora DESC`
 
 	rows, err := r.db.Query(query, docenteID)
@@ -77,11 +86,10 @@ func (r *RegistroRepositoryImpl) FindByDocente(docenteID int) ([]*entities.Regis
 }
 
 func (r *RegistroRepositoryImpl) FindByFecha(fecha time.Time) ([]*entities.Registro, error) {
-	inicio := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, fecha.Location())
-	fin := inicio.Add(24 * time.Hour)
+	inicio, fin := clock.Dia(r.clock, fecha)
 
 	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
-	          minutos_retraso, minutos_extra, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
 	          FROM registros WHERE fecha_hora >= $1 AND fecha_hora < $2 ORDER BY fecha_hora DESC`
 
 	rows, err := r.db.Query(query, inicio, fin)
@@ -94,11 +102,10 @@ func (r *RegistroRepositoryImpl) FindByFecha(fecha time.Time) ([]*entities.Regis
 }
 
 func (r *RegistroRepositoryImpl) FindByDocenteYFecha(docenteID int, fecha time.Time) ([]*entities.Registro, error) {
-	inicio := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, fecha.Location())
-	fin := inicio.Add(24 * time.Hour)
+	inicio, fin := clock.Dia(r.clock, fecha)
 
 	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
-	          minutos_retraso, minutos_extra, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
 	          FROM registros WHERE docente_id = $1 AND fecha_hora >= $2 AND fecha_hora < $3 ORDER BY fecha_hora DESC`
 
 	rows, err := r.db.Query(query, docenteID, inicio, fin)
@@ -110,13 +117,25 @@ func (r *RegistroRepositoryImpl) FindByDocenteYFecha(docenteID int, fecha time.T
 	return r.scanRegistros(rows)
 }
 
+func (r *RegistroRepositoryImpl) FindByDocenteYRango(docenteID int, desde, hasta time.Time) ([]*entities.Registro, error) {
+	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          FROM registros WHERE docente_id = $1 AND fecha_hora >= $2 AND fecha_hora < $3 ORDER BY fecha_hora DESC`
+
+	rows, err := r.db.Query(query, docenteID, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanRegistros(rows)
+}
+
 func (r *RegistroRepositoryImpl) FindRegistrosHoy() ([]*entities.Registro, error) {
-	ahora := time.Now()
-	inicio := time.Date(ahora.Year(), ahora.Month(), ahora.Day(), 0, 0, 0, 0, ahora.Location())
-	fin := inicio.Add(24 * time.Hour)
+	inicio, fin := clock.Hoy(r.clock)
 
 	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
-	          minutos_retraso, minutos_extra, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
 	          FROM registros WHERE fecha_hora >= $1 AND fecha_hora < $2 ORDER BY fecha_hora DESC`
 
 	rows, err := r.db.Query(query, inicio, fin)
@@ -128,59 +147,156 @@ func (r *RegistroRepositoryImpl) FindRegistrosHoy() ([]*entities.Registro, error
 	return r.scanRegistros(rows)
 }
 
-func (r *RegistroRepositoryImpl) FindUltimoIngresoConLlave(docenteID int) (*entities.Registro, error) {
-	// Buscar el último ingreso con llave que NO tenga una salida posterior
-	query := `
-		SELECT ing.id, ing.docente_id, ing.turno_id, ing.llave_id,
-		       ing.tipo, ing.fecha_hora, ing.minutos_retraso, ing.minutos_extra,
-		       ing.es_excepcional, ing.observaciones, ing.editado_por, ing.created_at, ing.updated_at
+// Un ingreso está abierto mientras ninguna salida lo referencia en ingreso_id
+const ingresoAbiertoQuery = `
+		SELECT ing.id, ing.docente_id, ing.turno_id, ing.llave_id, ing.tipo, ing.fecha_hora,
+		       ing.minutos_retraso, ing.minutos_extra, ing.clasificacion, ing.ingreso_id,
+		       ing.suplencia_id, ing.es_excepcional, ing.observaciones, ing.editado_por, ing.created_at, ing.updated_at
 		FROM registros ing
-		WHERE ing.docente_id = $1
-		  AND ing.tipo = 'ingreso'
-		  AND ing.llave_id IS NOT NULL
-		  AND DATE(ing.fecha_hora) = CURRENT_DATE
-		  AND NOT EXISTS (
-		      SELECT 1 FROM registros sal
-		      WHERE sal.docente_id = ing.docente_id
-		        AND sal.llave_id = ing.llave_id
-		        AND sal.tipo = 'salida'
-		        AND sal.fecha_hora > ing.fecha_hora
-		        AND DATE(sal.fecha_hora) = CURRENT_DATE
-		  )
-		ORDER BY ing.fecha_hora DESC
-		LIMIT 1`
+		LEFT JOIN registros sal ON sal.ingreso_id = ing.id
+		WHERE ing.tipo = 'ingreso' AND sal.id IS NULL AND ing.docente_id = $1`
 
-	registro := &entities.Registro{}
-	err := r.db.QueryRow(query, docenteID).Scan(
-		&registro.ID,
-		&registro.DocenteID,
-		&registro.TurnoID,
-		&registro.LlaveID,
-		&registro.Tipo,
-		&registro.FechaHora,
-		&registro.MinutosRetraso,
-		&registro.MinutosExtra,
-		&registro.EsExcepcional,
-		&registro.Observaciones,
-		&registro.EditadoPor,
-		&registro.CreatedAt,
-		&registro.UpdatedAt,
-	)
+func (r *RegistroRepositoryImpl) FindUltimoIngresoConLlave(docenteID int) (*entities.Registro, error) {
+	query := ingresoAbiertoQuery + ` AND ing.llave_id IS NOT NULL ORDER BY ing.fecha_hora DESC LIMIT 1`
+	registro, err := r.findUno(query, docenteID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no se encontró ingreso con llave sin salida")
+	}
+	return registro, err
+}
+
+func (r *RegistroRepositoryImpl) FindIngresoAbierto(docenteID int) (*entities.Registro, error) {
+	query := ingresoAbiertoQuery + ` ORDER BY ing.fecha_hora DESC LIMIT 1`
+	registro, err := r.findUno(query, docenteID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no se encontró ingreso sin salida")
+	}
+	return registro, err
+}
 
+func (r *RegistroRepositoryImpl) FindIngresoAbiertoConLlave(docenteID, llaveID int) (*entities.Registro, error) {
+	query := ingresoAbiertoQuery + ` AND ing.llave_id = $2 ORDER BY ing.fecha_hora DESC LIMIT 1`
+	registro, err := r.findUno(query, docenteID, llaveID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no se encontró ingreso con esa llave sin salida")
+	}
+	return registro, err
+}
+
+func (r *RegistroRepositoryImpl) FindSalidaDeIngreso(ingresoID int) (*entities.Registro, error) {
+	query := `SELECT id, docente_id, turno_id, llave_id, tipo, fecha_hora,
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por, created_at, updated_at
+	          FROM registros WHERE ingreso_id = $1`
+	registro, err := r.findUno(query, ingresoID)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("no se encontró ingreso con llave sin salida para hoy")
+		return nil, fmt.Errorf("el ingreso no tiene salida")
 	}
+	return registro, err
+}
+
+// findUno ejecuta una consulta de un único registro; retorna sql.ErrNoRows si no hay resultado
+func (r *RegistroRepositoryImpl) findUno(query string, args ...interface{}) (*entities.Registro, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return registro, nil
+	registros, err := r.scanRegistros(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(registros) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return registros[0], nil
+}
+
+// FindSesiones empareja cada ingreso con la salida que lo referencia
+func (r *RegistroRepositoryImpl) FindSesiones(filtro repositories.FiltroSesiones) ([]*entities.Sesion, error) {
+	condiciones := []string{"ing.tipo = 'ingreso'"}
+	args := []interface{}{}
+
+	agregar := func(condicion string, valor interface{}) {
+		args = append(args, valor)
+		condiciones = append(condiciones, fmt.Sprintf(condicion, len(args)))
+	}
+
+	if filtro.Desde != nil {
+		agregar("ing.fecha_hora >= $%d", *filtro.Desde)
+	}
+	if filtro.Hasta != nil {
+		agregar("ing.fecha_hora < $%d", *filtro.Hasta)
+	}
+	if filtro.DocenteID != nil {
+		agregar("ing.docente_id = $%d", *filtro.DocenteID)
+	}
+	if filtro.IngresoID != nil {
+		agregar("ing.id = $%d", *filtro.IngresoID)
+	}
+	if filtro.SoloAbiertas {
+		condiciones = append(condiciones, "sal.id IS NULL")
+	}
+	if filtro.SoloConLlave {
+		condiciones = append(condiciones, "ing.llave_id IS NOT NULL")
+	}
+
+	query := `
+		SELECT ing.id, sal.id, ing.docente_id, d.nombre_completo, CAST(d.documento_identidad AS TEXT),
+		       ing.turno_id, t.nombre, ing.llave_id, l.codigo, l.aula_codigo, l.aula_nombre,
+		       ing.fecha_hora, sal.fecha_hora, ing.minutos_retraso, COALESCE(sal.minutos_extra, 0),
+		       ing.clasificacion, sal.clasificacion, ing.suplencia_id
+		FROM registros ing
+		LEFT JOIN registros sal ON sal.ingreso_id = ing.id
+		INNER JOIN docentes d ON ing.docente_id = d.id
+		INNER JOIN turnos t ON ing.turno_id = t.id
+		LEFT JOIN llaves l ON ing.llave_id = l.id
+		WHERE ` + strings.Join(condiciones, " AND ") + `
+		ORDER BY ing.fecha_hora DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sesiones := []*entities.Sesion{}
+	for rows.Next() {
+		sesion := &entities.Sesion{}
+		err := rows.Scan(
+			&sesion.IngresoID,
+			&sesion.SalidaID,
+			&sesion.DocenteID,
+			&sesion.DocenteNombre,
+			&sesion.DocenteCI,
+			&sesion.TurnoID,
+			&sesion.TurnoNombre,
+			&sesion.LlaveID,
+			&sesion.LlaveCodigo,
+			&sesion.AulaCodigo,
+			&sesion.AulaNombre,
+			&sesion.HoraIngreso,
+			&sesion.HoraSalida,
+			&sesion.MinutosRetraso,
+			&sesion.MinutosExtra,
+			&sesion.ClasificacionIngreso,
+			&sesion.ClasificacionSalida,
+			&sesion.SuplenciaID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sesion.CalcularDuracion()
+		sesiones = append(sesiones, sesion)
+	}
+
+	return sesiones, rows.Err()
 }
 
 func (r *RegistroRepositoryImpl) Create(registro *entities.Registro) error {
 	query := `INSERT INTO registros (docente_id, turno_id, llave_id, tipo, fecha_hora,
-	          minutos_retraso, minutos_extra, es_excepcional, observaciones, editado_por)
-	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at`
+	          minutos_retraso, minutos_extra, clasificacion, ingreso_id, suplencia_id, es_excepcional, observaciones, editado_por)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at, updated_at`
 
 	return r.db.QueryRow(
 		query,
@@ -191,6 +307,9 @@ func (r *RegistroRepositoryImpl) Create(registro *entities.Registro) error {
 		registro.FechaHora,
 		registro.MinutosRetraso,
 		registro.MinutosExtra,
+		registro.Clasificacion,
+		registro.IngresoID,
+		registro.SuplenciaID,
 		registro.EsExcepcional,
 		registro.Observaciones,
 		registro.EditadoPor,
@@ -200,7 +319,8 @@ func (r *RegistroRepositoryImpl) Create(registro *entities.Registro) error {
 func (r *RegistroRepositoryImpl) Update(registro *entities.Registro) error {
 	query := `UPDATE registros SET docente_id = $1, turno_id = $2,
 	          llave_id = $3, tipo = $4, fecha_hora = $5, minutos_retraso = $6, minutos_extra = $7,
-	          es_excepcional = $8, observaciones = $9, editado_por = $10 WHERE id = $11 RETURNING updated_at`
+	          clasificacion = $8, ingreso_id = $9, suplencia_id = $10, es_excepcional = $11, observaciones = $12,
+	          editado_por = $13 WHERE id = $14 RETURNING updated_at`
 
 	return r.db.QueryRow(
 		query,
@@ -211,6 +331,9 @@ func (r *RegistroRepositoryImpl) Update(registro *entities.Registro) error {
 		registro.FechaHora,
 		registro.MinutosRetraso,
 		registro.MinutosExtra,
+		registro.Clasificacion,
+		registro.IngresoID,
+		registro.SuplenciaID,
 		registro.EsExcepcional,
 		registro.Observaciones,
 		registro.EditadoPor,
@@ -224,33 +347,6 @@ func (r *RegistroRepositoryImpl) Delete(id int) error {
 	return err
 }
 
-// DocenteTieneLlave verifica si un docente tiene una llave específica (ingreso sin salida correspondiente)
-func (r *RegistroRepositoryImpl) DocenteTieneLlave(docenteID int, llaveID int) (bool, error) {
-	query := `
-		SELECT EXISTS (
-			SELECT 1 FROM registros ing
-			WHERE ing.docente_id = $1
-			  AND ing.llave_id = $2
-			  AND ing.tipo = 'ingreso'
-			  AND DATE(ing.fecha_hora) = CURRENT_DATE
-			  AND NOT EXISTS (
-				  SELECT 1 FROM registros sal
-				  WHERE sal.docente_id = ing.docente_id
-					AND sal.llave_id = ing.llave_id
-					AND sal.tipo = 'salida'
-					AND sal.fecha_hora > ing.fecha_hora
-					AND DATE(sal.fecha_hora) = CURRENT_DATE
-			  )
-		)`
-
-	var existe bool
-	err := r.db.QueryRow(query, docenteID, llaveID).Scan(&existe)
-	if err != nil {
-		return false, err
-	}
-	return existe, nil
-}
-
 func (r *RegistroRepositoryImpl) scanRegistros(rows *sql.Rows) ([]*entities.Registro, error) {
 	registros := []*entities.Registro{}
 	for rows.Next() {
@@ -264,6 +360,9 @@ func (r *RegistroRepositoryImpl) scanRegistros(rows *sql.Rows) ([]*entities.Regi
 			&registro.FechaHora,
 			&registro.MinutosRetraso,
 			&registro.MinutosExtra,
+			&registro.Clasificacion,
+			&registro.IngresoID,
+			&registro.SuplenciaID,
 			&registro.EsExcepcional,
 			&registro.Observaciones,
 			&registro.EditadoPor,
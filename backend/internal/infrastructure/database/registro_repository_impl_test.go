@@ -0,0 +1,97 @@
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+)
+
+// driverRegistrador es un driver de database/sql que no devuelve filas y guarda los argumentos
+// de la última consulta, para verificar los límites que calculan los filtros de fecha
+type driverRegistrador struct {
+	args []driver.NamedValue
+}
+
+func (d *driverRegistrador) Open(string) (driver.Conn, error) {
+	return &conexionRegistradora{driver: d}, nil
+}
+
+type conexionRegistradora struct {
+	driver *driverRegistrador
+}
+
+func (c *conexionRegistradora) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *conexionRegistradora) Close() error {
+	return nil
+}
+
+func (c *conexionRegistradora) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *conexionRegistradora) Query(query string, args []driver.Value) (driver.Rows, error) {
+	c.driver.args = c.driver.args[:0]
+	for i, v := range args {
+		c.driver.args = append(c.driver.args, driver.NamedValue{Ordinal: i + 1, Value: v})
+	}
+	return filasVacias{}, nil
+}
+
+type filasVacias struct{}
+
+func (filasVacias) Columns() []string              { return nil }
+func (filasVacias) Close() error                   { return nil }
+func (filasVacias) Next(dest []driver.Value) error { return io.EOF }
+
+func TestFindRegistrosHoyLimitesDelDia(t *testing.T) {
+	laPaz, err := time.LoadLocation(clock.DefaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registrador := &driverRegistrador{}
+	sql.Register("registrador_hoy", registrador)
+	db, err := sql.Open("registrador_hoy", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	casos := []struct {
+		nombre   string
+		ahora    time.Time
+		registro time.Time
+	}{
+		// 20:30 en La Paz son las 00:30 UTC del día siguiente: con CURRENT_DATE en UTC quedaba fuera
+		{"registro de las 20:30 consultado a las 21:00", time.Date(2024, 1, 15, 21, 0, 0, 0, laPaz), time.Date(2024, 1, 15, 20, 30, 0, 0, laPaz)},
+		{"registro de las 20:30 consultado a las 23:59", time.Date(2024, 1, 15, 23, 59, 0, 0, laPaz), time.Date(2024, 1, 16, 0, 30, 0, 0, time.UTC)},
+		{"registro de las 00:00 consultado a las 08:00", time.Date(2024, 1, 15, 8, 0, 0, 0, laPaz), time.Date(2024, 1, 15, 0, 0, 0, 0, laPaz)},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			repo := NewRegistroRepository(db, clock.Fixed(c.ahora, laPaz))
+			if _, err := repo.FindRegistrosHoy(); err != nil {
+				t.Fatal(err)
+			}
+			if len(registrador.args) != 2 {
+				t.Fatalf("se esperaban 2 argumentos, hubo %d", len(registrador.args))
+			}
+			desde, _ := registrador.args[0].Value.(time.Time)
+			hasta, _ := registrador.args[1].Value.(time.Time)
+			if c.registro.Before(desde) || !c.registro.Before(hasta) {
+				t.Errorf("el registro %v queda fuera de [%v, %v)", c.registro, desde, hasta)
+			}
+			if ayer := c.registro.AddDate(0, 0, -1); !ayer.Before(desde) {
+				t.Errorf("el registro del día anterior %v queda dentro de [%v, %v)", ayer, desde, hasta)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+type ReporteDuplicadosRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewReporteDuplicadosRepository(db *sql.DB) *ReporteDuplicadosRepositoryImpl {
+	return &ReporteDuplicadosRepositoryImpl{db: db}
+}
+
+const reporteDuplicadosColumns = `id, estado, iniciado_por, modelo_version, umbral, docentes_analizados, muestras_analizadas,
+	          pares_encontrados, error, iniciado_at, finalizado_at`
+
+func (r *ReporteDuplicadosRepositoryImpl) Create(reporte *entities.ReporteDuplicados) error {
+	query := `INSERT INTO reportes_duplicados (estado, iniciado_por, modelo_version, umbral)
+	          VALUES ($1, $2, $3, $4) RETURNING id, iniciado_at`
+
+	return r.db.QueryRow(
+		query,
+		reporte.Estado,
+		reporte.IniciadoPor,
+		reporte.ModeloVersion,
+		reporte.Umbral,
+	).Scan(&reporte.ID, &reporte.IniciadoAt)
+}
+
+func (r *ReporteDuplicadosRepositoryImpl) Completar(reporte *entities.ReporteDuplicados, pares []*entities.ParDuplicado) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertPar := `INSERT INTO pares_duplicados (reporte_id, grupo, docente_a_id, docente_b_id, coincidencias, comparaciones,
+	              mejor_distancia, distancia_promedio, concluyente)
+	              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, estado`
+	for _, par := range pares {
+		err := tx.QueryRow(
+			insertPar,
+			reporte.ID,
+			par.Grupo,
+			par.DocenteAID,
+			par.DocenteBID,
+			par.Coincidencias,
+			par.Comparaciones,
+			par.MejorDistancia,
+			par.DistanciaPromedio,
+			par.Concluyente,
+		).Scan(&par.ID, &par.Estado)
+		if err != nil {
+			return err
+		}
+		par.ReporteID = reporte.ID
+	}
+
+	query := `UPDATE reportes_duplicados
+	          SET estado = $1, docentes_analizados = $2, muestras_analizadas = $3, pares_encontrados = $4,
+	              finalizado_at = CURRENT_TIMESTAMP
+	          WHERE id = $5 RETURNING finalizado_at`
+	err = tx.QueryRow(
+		query,
+		entities.EstadoReporteCompletado,
+		reporte.DocentesAnalizados,
+		reporte.MuestrasAnalizadas,
+		len(pares),
+		reporte.ID,
+	).Scan(&reporte.FinalizadoAt)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	reporte.Estado = entities.EstadoReporteCompletado
+	reporte.ParesEncontrados = len(pares)
+	reporte.Pares = pares
+	return nil
+}
+
+func (r *ReporteDuplicadosRepositoryImpl) MarcarError(id int, mensaje string) error {
+	query := `UPDATE reportes_duplicados SET estado = $1, error = $2, finalizado_at = CURRENT_TIMESTAMP WHERE id = $3`
+	_, err := r.db.Exec(query, entities.EstadoReporteError, mensaje, id)
+	return err
+}
+
+func (r *ReporteDuplicadosRepositoryImpl) MarcarInterrumpidos() (int64, error) {
+	query := `UPDATE reportes_duplicados
+	          SET estado = $1, error = 'Escaneo interrumpido por un reinicio del servidor', finalizado_at = CURRENT_TIMESTAMP
+	          WHERE estado = $2`
+	result, err := r.db.Exec(query, entities.EstadoReporteError, entities.EstadoReporteEnProceso)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *ReporteDuplicadosRepositoryImpl) FindAll(limite int) ([]*entities.ReporteDuplicados, error) {
+	query := `SELECT ` + reporteDuplicadosColumns + `
+	          FROM reportes_duplicados ORDER BY iniciado_at DESC LIMIT $1`
+
+	rows, err := r.db.Query(query, limite)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reportes := []*entities.ReporteDuplicados{}
+	for rows.Next() {
+		reporte, err := r.scanReporte(rows)
+		if err != nil {
+			return nil, err
+		}
+		reportes = append(reportes, reporte)
+	}
+
+	return reportes, nil
+}
+
+func (r *ReporteDuplicadosRepositoryImpl) FindByID(id int) (*entities.ReporteDuplicados, error) {
+	query := `SELECT ` + reporteDuplicadosColumns + `
+	          FROM reportes_duplicados WHERE id = $1`
+
+	reporte, err := r.scanReporte(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reporte no encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query = `SELECT p.id, p.reporte_id, p.grupo, p.docente_a_id, da.nombre_completo, p.docente_b_id, db.nombre_completo,
+	                p.coincidencias, p.comparaciones, p.mejor_distancia, p.distancia_promedio, p.concluyente,
+	                p.estado, p.revisado_por, p.revisado_at, p.nota
+	         FROM pares_duplicados p
+	         INNER JOIN docentes da ON p.docente_a_id = da.id
+	         INNER JOIN docentes db ON p.docente_b_id = db.id
+	         WHERE p.reporte_id = $1
+	         ORDER BY p.concluyente DESC, p.coincidencias DESC, p.mejor_distancia`
+
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reporte.Pares = []*entities.ParDuplicado{}
+	for rows.Next() {
+		par := &entities.ParDuplicado{}
+		err := rows.Scan(
+			&par.ID,
+			&par.ReporteID,
+			&par.Grupo,
+			&par.DocenteAID,
+			&par.DocenteANombre,
+			&par.DocenteBID,
+			&par.DocenteBNombre,
+			&par.Coincidencias,
+			&par.Comparaciones,
+			&par.MejorDistancia,
+			&par.DistanciaPromedio,
+			&par.Concluyente,
+			&par.Estado,
+			&par.RevisadoPor,
+			&par.RevisadoAt,
+			&par.Nota,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reporte.Pares = append(reporte.Pares, par)
+	}
+
+	return reporte, nil
+}
+
+func (r *ReporteDuplicadosRepositoryImpl) RevisarPar(parID int, estado entities.EstadoParDuplicado, revisadoPor int, nota *string) error {
+	query := `UPDATE pares_duplicados
+	          SET estado = $1, revisado_por = $2, nota = $3, revisado_at = CURRENT_TIMESTAMP
+	          WHERE id = $4`
+	result, err := r.db.Exec(query, estado, revisadoPor, nota, parID)
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return fmt.Errorf("par no encontrado")
+	}
+	return nil
+}
+
+func (r *ReporteDuplicadosRepositoryImpl) scanReporte(row rowScanner) (*entities.ReporteDuplicados, error) {
+	reporte := &entities.ReporteDuplicados{}
+	err := row.Scan(
+		&reporte.ID,
+		&reporte.Estado,
+		&reporte.IniciadoPor,
+		&reporte.ModeloVersion,
+		&reporte.Umbral,
+		&reporte.DocentesAnalizados,
+		&reporte.MuestrasAnalizadas,
+		&reporte.ParesEncontrados,
+		&reporte.Error,
+		&reporte.IniciadoAt,
+		&reporte.FinalizadoAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reporte, nil
+}
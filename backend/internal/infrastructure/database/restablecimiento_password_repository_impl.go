@@ -0,0 +1,79 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+type RestablecimientoPasswordRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewRestablecimientoPasswordRepository(db *sql.DB) *RestablecimientoPasswordRepositoryImpl {
+	return &RestablecimientoPasswordRepositoryImpl{db: db}
+}
+
+func (r *RestablecimientoPasswordRepositoryImpl) Create(restablecimiento *entities.RestablecimientoPassword) error {
+	query := `INSERT INTO restablecimientos_password (usuario_id, token_hash, expira_at)
+	          VALUES ($1, $2, $3) RETURNING id, created_at`
+
+	return r.db.QueryRow(
+		query,
+		restablecimiento.UsuarioID,
+		restablecimiento.TokenHash,
+		restablecimiento.ExpiraAt,
+	).Scan(&restablecimiento.ID, &restablecimiento.CreatedAt)
+}
+
+func (r *RestablecimientoPasswordRepositoryImpl) FindByTokenHash(tokenHash string) (*entities.RestablecimientoPassword, error) {
+	query := `SELECT id, usuario_id, token_hash, expira_at, usado_at, created_at
+	          FROM restablecimientos_password
+	          WHERE token_hash = $1`
+
+	restablecimiento := &entities.RestablecimientoPassword{}
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&restablecimiento.ID,
+		&restablecimiento.UsuarioID,
+		&restablecimiento.TokenHash,
+		&restablecimiento.ExpiraAt,
+		&restablecimiento.UsadoAt,
+		&restablecimiento.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("token de restablecimiento no encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return restablecimiento, nil
+}
+
+func (r *RestablecimientoPasswordRepositoryImpl) Consumir(id int, ahora time.Time) error {
+	query := `UPDATE restablecimientos_password SET usado_at = $2
+	          WHERE id = $1 AND usado_at IS NULL AND expira_at > $2`
+
+	result, err := r.db.Exec(query, id, ahora)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return fmt.Errorf("token de restablecimiento ya utilizado o expirado")
+	}
+	return nil
+}
+
+func (r *RestablecimientoPasswordRepositoryImpl) DeletePendientes(usuarioID int) error {
+	_, err := r.db.Exec(`DELETE FROM restablecimientos_password WHERE usuario_id = $1 AND usado_at IS NULL`, usuarioID)
+	return err
+}
+
+func (r *RestablecimientoPasswordRepositoryImpl) DeleteVencidos(antes time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM restablecimientos_password WHERE expira_at < $1`, antes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+)
+
+type SuplenciaRepositoryImpl struct {
+	db *sql.DB
+}
+
+func NewSuplenciaRepository(db *sql.DB) *SuplenciaRepositoryImpl {
+	return &SuplenciaRepositoryImpl{db: db}
+}
+
+const suplenciaColumns = `s.id, s.fecha, s.turno_id, t.nombre, s.aula_codigo,
+	          s.titular_id, dt.nombre_completo, dt.documento_identidad,
+	          s.suplente_id, ds.nombre_completo, ds.documento_identidad,
+	          s.motivo, s.asignado_por, s.created_at, s.updated_at`
+
+const suplenciaFrom = ` FROM suplencias s
+	          INNER JOIN turnos t ON t.id = s.turno_id
+	          INNER JOIN docentes dt ON dt.id = s.titular_id
+	          INNER JOIN docentes ds ON ds.id = s.suplente_id`
+
+func scanSuplencia(row interface{ Scan(...interface{}) error }) (*entities.Suplencia, error) {
+	suplencia := &entities.Suplencia{}
+	err := row.Scan(
+		&suplencia.ID,
+		&suplencia.Fecha,
+		&suplencia.TurnoID,
+		&suplencia.TurnoNombre,
+		&suplencia.AulaCodigo,
+		&suplencia.TitularID,
+		&suplencia.TitularNombre,
+		&suplencia.TitularCI,
+		&suplencia.SuplenteID,
+		&suplencia.SuplenteNombre,
+		&suplencia.SuplenteCI,
+		&suplencia.Motivo,
+		&suplencia.AsignadoPor,
+		&suplencia.CreatedAt,
+		&suplencia.UpdatedAt,
+	)
+	return suplencia, err
+}
+
+func (r *SuplenciaRepositoryImpl) FindByID(id int) (*entities.Suplencia, error) {
+	query := `SELECT ` + suplenciaColumns + suplenciaFrom + ` WHERE s.id = $1`
+
+	suplencia, err := scanSuplencia(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("suplencia no encontrada")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return suplencia, nil
+}
+
+func (r *SuplenciaRepositoryImpl) Find(filtro repositories.FiltroSuplencias) ([]*entities.Suplencia, error) {
+	condiciones := []string{}
+	args := []interface{}{}
+
+	agregar := func(condicion string, valor interface{}) {
+		args = append(args, valor)
+		condiciones = append(condiciones, fmt.Sprintf(condicion, len(args)))
+	}
+
+	if filtro.DocenteID != nil {
+		agregar("(s.titular_id = $%[1]d OR s.suplente_id = $%[1]d)", *filtro.DocenteID)
+	}
+	if filtro.TitularID != nil {
+		agregar("s.titular_id = $%d", *filtro.TitularID)
+	}
+	if filtro.SuplenteID != nil {
+		agregar("s.suplente_id = $%d", *filtro.SuplenteID)
+	}
+	if filtro.TurnoID != nil {
+		agregar("s.turno_id = $%d", *filtro.TurnoID)
+	}
+	if filtro.Desde != nil {
+		agregar("s.fecha >= $%d", *filtro.Desde)
+	}
+	if filtro.Hasta != nil {
+		agregar("s.fecha <= $%d", *filtro.Hasta)
+	}
+
+	query := `SELECT ` + suplenciaColumns + suplenciaFrom
+	if len(condiciones) > 0 {
+		query += " WHERE " + strings.Join(condiciones, " AND ")
+	}
+	query += " ORDER BY s.fecha DESC, t.hora_inicio, s.id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suplencias := []*entities.Suplencia{}
+	for rows.Next() {
+		suplencia, err := scanSuplencia(rows)
+		if err != nil {
+			return nil, err
+		}
+		suplencias = append(suplencias, suplencia)
+	}
+	return suplencias, rows.Err()
+}
+
+func (r *SuplenciaRepositoryImpl) Create(suplencia *entities.Suplencia) error {
+	query := `
+		INSERT INTO suplencias (fecha, turno_id, aula_codigo, titular_id, suplente_id, motivo, asignado_por)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRow(
+		query,
+		suplencia.Fecha,
+		suplencia.TurnoID,
+		suplencia.AulaCodigo,
+		suplencia.TitularID,
+		suplencia.SuplenteID,
+		suplencia.Motivo,
+		suplencia.AsignadoPor,
+	).Scan(&suplencia.ID, &suplencia.CreatedAt, &suplencia.UpdatedAt)
+}
+
+func (r *SuplenciaRepositoryImpl) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM suplencias WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return fmt.Errorf("suplencia no encontrada")
+	}
+	return nil
+}
+
+func (r *SuplenciaRepositoryImpl) CountRegistros(id int) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM registros WHERE suplencia_id = $1`, id).Scan(&total)
+	return total, err
+}
@@ -16,7 +16,8 @@ func NewTurnoRepository(db *sql.DB) *TurnoRepositoryImpl {
 }
 
 func (r *TurnoRepositoryImpl) FindByID(id int) (*entities.Turno, error) {
-	query := `SELECT id, nombre, hora_inicio::TEXT, hora_fin::TEXT, descripcion, activo, created_at, updated_at
+	query := `SELECT id, nombre, hora_inicio, hora_fin, dias_semana, vigente_desde, vigente_hasta, descripcion,
+	          tolerancia_ingreso_min, retraso_maximo_min, salida_anticipada_min, activo, created_at, updated_at
 	          FROM turnos WHERE id = $1`
 
 	turno := &entities.Turno{}
@@ -25,7 +26,13 @@ func (r *TurnoRepositoryImpl) FindByID(id int) (*entities.Turno, error) {
 		&turno.Nombre,
 		&turno.HoraInicio,
 		&turno.HoraFin,
+		&turno.DiasSemana,
+		&turno.VigenteDesde,
+		&turno.VigenteHasta,
 		&turno.Descripcion,
+		&turno.ToleranciaIngresoMin,
+		&turno.RetrasoMaximoMin,
+		&turno.SalidaAnticipadaMin,
 		&turno.Activo,
 		&turno.CreatedAt,
 		&turno.UpdatedAt,
@@ -42,7 +49,8 @@ func (r *TurnoRepositoryImpl) FindByID(id int) (*entities.Turno, error) {
 }
 
 func (r *TurnoRepositoryImpl) FindAll() ([]*entities.Turno, error) {
-	query := `SELECT id, nombre, hora_inicio::TEXT, hora_fin::TEXT, descripcion, activo, created_at, updated_at
+	query := `SELECT id, nombre, hora_inicio, hora_fin, dias_semana, vigente_desde, vigente_hasta, descripcion,
+	          tolerancia_ingreso_min, retraso_maximo_min, salida_anticipada_min, activo, created_at, updated_at
 	          FROM turnos ORDER BY hora_inicio`
 
 	rows, err := r.db.Query(query)
@@ -59,7 +67,13 @@ func (r *TurnoRepositoryImpl) FindAll() ([]*entities.Turno, error) {
 			&turno.Nombre,
 			&turno.HoraInicio,
 			&turno.HoraFin,
+			&turno.DiasSemana,
+			&turno.VigenteDesde,
+			&turno.VigenteHasta,
 			&turno.Descripcion,
+			&turno.ToleranciaIngresoMin,
+			&turno.RetrasoMaximoMin,
+			&turno.SalidaAnticipadaMin,
 			&turno.Activo,
 			&turno.CreatedAt,
 			&turno.UpdatedAt,
@@ -74,29 +88,44 @@ func (r *TurnoRepositoryImpl) FindAll() ([]*entities.Turno, error) {
 }
 
 func (r *TurnoRepositoryImpl) Create(turno *entities.Turno) error {
-	query := `INSERT INTO turnos (nombre, hora_inicio, hora_fin, descripcion, activo)
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+	query := `INSERT INTO turnos (nombre, hora_inicio, hora_fin, dias_semana, vigente_desde, vigente_hasta, descripcion,
+	          tolerancia_ingreso_min, retraso_maximo_min, salida_anticipada_min, activo)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at`
 
 	return r.db.QueryRow(
 		query,
 		turno.Nombre,
 		turno.HoraInicio,
 		turno.HoraFin,
+		turno.DiasSemana,
+		turno.VigenteDesde,
+		turno.VigenteHasta,
 		turno.Descripcion,
+		turno.ToleranciaIngresoMin,
+		turno.RetrasoMaximoMin,
+		turno.SalidaAnticipadaMin,
 		turno.Activo,
 	).Scan(&turno.ID, &turno.CreatedAt, &turno.UpdatedAt)
 }
 
 func (r *TurnoRepositoryImpl) Update(turno *entities.Turno) error {
-	query := `UPDATE turnos SET nombre = $1, hora_inicio = $2, hora_fin = $3,
-	          descripcion = $4, activo = $5 WHERE id = $6 RETURNING updated_at`
+	query := `UPDATE turnos SET nombre = $1, hora_inicio = $2, hora_fin = $3, dias_semana = $4,
+	          vigente_desde = $5, vigente_hasta = $6, descripcion = $7,
+	          tolerancia_ingreso_min = $8, retraso_maximo_min = $9, salida_anticipada_min = $10,
+	          activo = $11 WHERE id = $12 RETURNING updated_at`
 
 	return r.db.QueryRow(
 		query,
 		turno.Nombre,
 		turno.HoraInicio,
 		turno.HoraFin,
+		turno.DiasSemana,
+		turno.VigenteDesde,
+		turno.VigenteHasta,
 		turno.Descripcion,
+		turno.ToleranciaIngresoMin,
+		turno.RetrasoMaximoMin,
+		turno.SalidaAnticipadaMin,
 		turno.Activo,
 		turno.ID,
 	).Scan(&turno.UpdatedAt)
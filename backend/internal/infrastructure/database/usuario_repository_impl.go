@@ -20,7 +20,7 @@ func (r *UsuarioRepositoryImpl) FindByUsername(username string) (*entities.Usuar
 	query := `SELECT u.id, u.username, u.password, u.rol,
 	          COALESCE(d.nombre_completo, u.nombre_completo) as nombre_completo,
 	          COALESCE(d.correo, u.email) as email,
-	          u.activo, u.created_at, u.updated_at
+	          u.activo, u.must_change_password, u.origen, u.created_at, u.updated_at
 	          FROM usuarios u
 	          LEFT JOIN docentes d ON d.usuario_id = u.id AND u.rol = 'docente'
 	          WHERE u.username = $1 AND u.activo = TRUE`
@@ -34,6 +34,8 @@ func (r *UsuarioRepositoryImpl) FindByUsername(username string) (*entities.Usuar
 		&usuario.NombreCompleto,
 		&usuario.Email,
 		&usuario.Activo,
+		&usuario.MustChangePassword,
+		&usuario.Origen,
 		&usuario.CreatedAt,
 		&usuario.UpdatedAt,
 	)
@@ -53,7 +55,7 @@ func (r *UsuarioRepositoryImpl) FindByID(id int) (*entities.Usuario, error) {
 	query := `SELECT u.id, u.username, u.password, u.rol,
 	          COALESCE(d.nombre_completo, u.nombre_completo) as nombre_completo,
 	          COALESCE(d.correo, u.email) as email,
-	          u.activo, u.created_at, u.updated_at
+	          u.activo, u.must_change_password, u.origen, u.created_at, u.updated_at
 	          FROM usuarios u
 	          LEFT JOIN docentes d ON d.usuario_id = u.id AND u.rol = 'docente'
 	          WHERE u.id = $1`
@@ -67,6 +69,8 @@ func (r *UsuarioRepositoryImpl) FindByID(id int) (*entities.Usuario, error) {
 		&usuario.NombreCompleto,
 		&usuario.Email,
 		&usuario.Activo,
+		&usuario.MustChangePassword,
+		&usuario.Origen,
 		&usuario.CreatedAt,
 		&usuario.UpdatedAt,
 	)
@@ -82,8 +86,8 @@ func (r *UsuarioRepositoryImpl) FindByID(id int) (*entities.Usuario, error) {
 }
 
 func (r *UsuarioRepositoryImpl) Create(usuario *entities.Usuario) error {
-	query := `INSERT INTO usuarios (username, password, rol, nombre_completo, email, activo)
-	          VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
+	query := `INSERT INTO usuarios (username, password, rol, nombre_completo, email, activo, must_change_password, origen)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
 
 	return r.db.QueryRow(
 		query,
@@ -93,12 +97,14 @@ func (r *UsuarioRepositoryImpl) Create(usuario *entities.Usuario) error {
 		usuario.NombreCompleto,
 		usuario.Email,
 		usuario.Activo,
+		usuario.MustChangePassword,
+		usuario.Origen,
 	).Scan(&usuario.ID, &usuario.CreatedAt, &usuario.UpdatedAt)
 }
 
 func (r *UsuarioRepositoryImpl) Update(usuario *entities.Usuario) error {
-	query := `UPDATE usuarios SET username = $1, password = $2, rol = $3, nombre_completo = $4, email = $5, activo = $6
-	          WHERE id = $7 RETURNING updated_at`
+	query := `UPDATE usuarios SET username = $1, password = $2, rol = $3, nombre_completo = $4, email = $5, activo = $6, origen = $7
+	          WHERE id = $8 RETURNING updated_at`
 
 	return r.db.QueryRow(
 		query,
@@ -108,10 +114,64 @@ func (r *UsuarioRepositoryImpl) Update(usuario *entities.Usuario) error {
 		usuario.NombreCompleto,
 		usuario.Email,
 		usuario.Activo,
+		usuario.Origen,
 		usuario.ID,
 	).Scan(&usuario.UpdatedAt)
 }
 
+func (r *UsuarioRepositoryImpl) UpdatePassword(usuarioID int, password string, mustChange bool, historial int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE usuarios SET password = $1, must_change_password = $2 WHERE id = $3`,
+		password, mustChange, usuarioID)
+	if err != nil {
+		return err
+	}
+	if filas, _ := result.RowsAffected(); filas == 0 {
+		return fmt.Errorf("usuario no encontrado")
+	}
+
+	if _, err := tx.Exec(`INSERT INTO password_historial (usuario_id, password) VALUES ($1, $2)`, usuarioID, password); err != nil {
+		return err
+	}
+	query := `DELETE FROM password_historial
+	          WHERE usuario_id = $1 AND id NOT IN (
+	              SELECT id FROM password_historial WHERE usuario_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2
+	          )`
+	if _, err := tx.Exec(query, usuarioID, historial); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *UsuarioRepositoryImpl) FindPasswordHistorial(usuarioID int, limite int) ([]string, error) {
+	query := `SELECT password FROM password_historial
+	          WHERE usuario_id = $1
+	          ORDER BY created_at DESC, id DESC
+	          LIMIT $2`
+
+	rows, err := r.db.Query(query, usuarioID, limite)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
 func (r *UsuarioRepositoryImpl) Delete(id int) error {
 	query := `DELETE FROM usuarios WHERE id = $1`
 	_, err := r.db.Exec(query, id)
@@ -123,7 +183,7 @@ func (r *UsuarioRepositoryImpl) FindAll() ([]*entities.Usuario, error) {
 	query := `SELECT u.id, u.username, u.password, u.rol,
 	          COALESCE(d.nombre_completo, u.nombre_completo) as nombre_completo,
 	          COALESCE(d.correo, u.email) as email,
-	          u.activo, u.created_at, u.updated_at
+	          u.activo, u.must_change_password, u.origen, u.created_at, u.updated_at
 	          FROM usuarios u
 	          LEFT JOIN docentes d ON d.usuario_id = u.id AND u.rol = 'docente'
 	          ORDER BY u.id`
@@ -145,6 +205,8 @@ func (r *UsuarioRepositoryImpl) FindAll() ([]*entities.Usuario, error) {
 			&usuario.NombreCompleto,
 			&usuario.Email,
 			&usuario.Activo,
+			&usuario.MustChangePassword,
+			&usuario.Origen,
 			&usuario.CreatedAt,
 			&usuario.UpdatedAt,
 		)
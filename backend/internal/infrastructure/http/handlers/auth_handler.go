@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/sistema-ingreso-docente/backend/internal/application/dto"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
 )
 
 type AuthHandler struct {
@@ -23,21 +28,106 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, usuario, err := h.authUseCase.Login(req.Username, req.Password)
+	resultado, err := h.authUseCase.Login(req.Username, req.Password)
 	if err != nil {
 		http.Error(w, `{"error":"Credenciales inválidas"}`, http.StatusUnauthorized)
 		return
 	}
 
-	response := dto.LoginResponse{
-		Token: token,
+	w.Header().Set("Content-Type", "application/json")
+	if resultado.DesafioToken != "" {
+		json.NewEncoder(w).Encode(dto.DesafioDosFactoresResponse{
+			RequiereDosFactores: true,
+			DesafioToken:        resultado.DesafioToken,
+			ExpiraEn:            int(jwt.DesafioTokenExpiration.Seconds()),
+		})
+		return
+	}
+	json.NewEncoder(w).Encode(loginResponse(resultado))
+}
+
+// VerificarSegundoFactor completa el login de un usuario con segundo factor: canjea el token de
+// desafío y un código TOTP o de recuperación por el token de sesión
+func (h *AuthHandler) VerificarSegundoFactor(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerificarDosFactoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Datos inválidos"}`, http.StatusBadRequest)
+		return
+	}
+	if req.DesafioToken == "" || strings.TrimSpace(req.Codigo) == "" {
+		http.Error(w, `{"error":"desafio_token y codigo son requeridos"}`, http.StatusBadRequest)
+		return
+	}
+
+	resultado, err := h.authUseCase.VerificarSegundoFactor(req.DesafioToken, req.Codigo)
+	if err != nil {
+		log.Printf("[SECURITY] Segundo factor rechazado: %v", err)
+		h.sendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) inició sesión con segundo factor", resultado.Usuario.ID, resultado.Usuario.Username)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loginResponse(resultado))
+}
+
+// CambiarPassword reemplaza la contraseña del usuario autenticado. Es la única ruta disponible
+// mientras la cuenta tenga must_change_password; responde con un token nuevo sin esa restricción
+func (h *AuthHandler) CambiarPassword(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		http.Error(w, `{"error":"No autorizado"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CambiarPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Datos inválidos"}`, http.StatusBadRequest)
+		return
+	}
+	if req.CurrentPassword == "" {
+		http.Error(w, `{"error":"La contraseña actual es requerida"}`, http.StatusBadRequest)
+		return
+	}
+
+	// Validar fortaleza de la nueva contraseña
+	if err := security.ValidatePasswordStrength(req.NewPassword); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resultado, err := h.authUseCase.CambiarPassword(claims.UserID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, usecases.ErrPasswordActualIncorrecta) {
+			log.Printf("[SECURITY] Usuario %d (%s) envió una contraseña actual incorrecta al cambiarla", claims.UserID, claims.Username)
+		} else {
+			log.Printf("[WARN] Usuario %d (%s) no pudo cambiar su contraseña: %v", claims.UserID, claims.Username, err)
+		}
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) cambió su contraseña", claims.UserID, claims.Username)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loginResponse(resultado))
+}
+
+func loginResponse(resultado *usecases.ResultadoLogin) dto.LoginResponse {
+	return dto.LoginResponse{
+		Token: resultado.Token,
 		User: dto.UserProfile{
-			ID:       usuario.ID,
-			Username: usuario.Username,
-			Rol:      string(usuario.Rol),
+			ID:                 resultado.Usuario.ID,
+			Username:           resultado.Usuario.Username,
+			Rol:                string(resultado.Usuario.Rol),
+			MustChangePassword: resultado.Usuario.MustChangePassword,
+			EnrolarDosFactores: resultado.EnrolarDosFactores,
 		},
 	}
+}
 
+func (h *AuthHandler) sendError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ApiResponse{Error: message})
 }
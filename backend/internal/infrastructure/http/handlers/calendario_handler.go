@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type CalendarioHandler struct {
+	calendarioUseCase *usecases.CalendarioUseCase
+}
+
+func NewCalendarioHandler(calendarioUseCase *usecases.CalendarioUseCase) *CalendarioHandler {
+	return &CalendarioHandler{calendarioUseCase: calendarioUseCase}
+}
+
+// ==================== PERIODOS ====================
+
+func (h *CalendarioHandler) ListarPeriodos(w http.ResponseWriter, r *http.Request) {
+	periodos, err := h.calendarioUseCase.GetPeriodos()
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo periodos académicos: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener periodos")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: periodos})
+}
+
+func (h *CalendarioHandler) CrearPeriodo(w http.ResponseWriter, r *http.Request) {
+	var periodo entities.PeriodoAcademico
+	if err := json.NewDecoder(r.Body).Decode(&periodo); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	if err := h.calendarioUseCase.CreatePeriodo(&periodo); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.auditar(r, "creó el periodo académico %d (%s)", periodo.ID, periodo.Nombre)
+	h.sendJSON(w, http.StatusCreated, ApiResponse{Data: periodo, Message: "Periodo creado exitosamente"})
+}
+
+// ActualizarPeriodo reemplaza los datos del periodo con los del request
+func (h *CalendarioHandler) ActualizarPeriodo(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	var periodo entities.PeriodoAcademico
+	if err := json.NewDecoder(r.Body).Decode(&periodo); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	periodo.ID = id
+
+	if err := h.calendarioUseCase.UpdatePeriodo(&periodo); err != nil {
+		h.sendErrorCalendario(w, err)
+		return
+	}
+
+	h.auditar(r, "actualizó el periodo académico %d (%s)", periodo.ID, periodo.Nombre)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: periodo, Message: "Periodo actualizado exitosamente"})
+}
+
+func (h *CalendarioHandler) EliminarPeriodo(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	if err := h.calendarioUseCase.DeletePeriodo(id); err != nil {
+		h.sendErrorCalendario(w, err)
+		return
+	}
+
+	h.auditar(r, "eliminó el periodo académico %d", id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Periodo eliminado exitosamente"})
+}
+
+// ==================== EVENTOS ====================
+
+// ListarEventos lista feriados, recesos, exámenes y horarios especiales; desde y hasta
+// (YYYY-MM-DD) son opcionales
+func (h *CalendarioHandler) ListarEventos(w http.ResponseWriter, r *http.Request) {
+	desde, err := fechaOpcional(r, "desde")
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	hasta, err := fechaOpcional(r, "hasta")
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	eventos, err := h.calendarioUseCase.GetEventos(desde, hasta)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo eventos del calendario: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener eventos")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: eventos})
+}
+
+func (h *CalendarioHandler) CrearEvento(w http.ResponseWriter, r *http.Request) {
+	var evento entities.EventoCalendario
+	if err := json.NewDecoder(r.Body).Decode(&evento); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	if err := h.calendarioUseCase.CreateEvento(&evento); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.auditar(r, "creó el evento de calendario %d (%s %s)", evento.ID, evento.Tipo, evento.Nombre)
+	h.sendJSON(w, http.StatusCreated, ApiResponse{Data: evento, Message: "Evento creado exitosamente"})
+}
+
+// ActualizarEvento reemplaza los datos del evento con los del request
+func (h *CalendarioHandler) ActualizarEvento(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	var evento entities.EventoCalendario
+	if err := json.NewDecoder(r.Body).Decode(&evento); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	evento.ID = id
+
+	if err := h.calendarioUseCase.UpdateEvento(&evento); err != nil {
+		h.sendErrorCalendario(w, err)
+		return
+	}
+
+	h.auditar(r, "actualizó el evento de calendario %d (%s %s)", evento.ID, evento.Tipo, evento.Nombre)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: evento, Message: "Evento actualizado exitosamente"})
+}
+
+func (h *CalendarioHandler) EliminarEvento(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	if err := h.calendarioUseCase.DeleteEvento(id); err != nil {
+		h.sendErrorCalendario(w, err)
+		return
+	}
+
+	h.auditar(r, "eliminó el evento de calendario %d", id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Evento eliminado exitosamente"})
+}
+
+// ==================== CONSULTAS ====================
+
+// ConsultarLaborable responde si la fecha es laborable para el turno: ?fecha=YYYY-MM-DD&turno_id=N
+func (h *CalendarioHandler) ConsultarLaborable(w http.ResponseWriter, r *http.Request) {
+	fecha, err := fechaOpcional(r, "fecha")
+	if err != nil || fecha == nil {
+		h.sendError(w, http.StatusBadRequest, "fecha requerida (YYYY-MM-DD)")
+		return
+	}
+	turnoID, err := security.ValidateID(r.URL.Query().Get("turno_id"))
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "turno_id requerido")
+		return
+	}
+
+	dia, err := h.calendarioUseCase.ConsultarDia(*fecha, turnoID)
+	if err != nil {
+		h.sendErrorCalendario(w, err)
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: dia})
+}
+
+// ConsultarDias evalúa cada día de un rango: ?desde=YYYY-MM-DD&hasta=YYYY-MM-DD[&turno_id=N]
+func (h *CalendarioHandler) ConsultarDias(w http.ResponseWriter, r *http.Request) {
+	desde, err := fechaOpcional(r, "desde")
+	if err != nil || desde == nil {
+		h.sendError(w, http.StatusBadRequest, "desde requerido (YYYY-MM-DD)")
+		return
+	}
+	hasta, err := fechaOpcional(r, "hasta")
+	if err != nil || hasta == nil {
+		h.sendError(w, http.StatusBadRequest, "hasta requerido (YYYY-MM-DD)")
+		return
+	}
+
+	var turnoID *int
+	if valor := r.URL.Query().Get("turno_id"); valor != "" {
+		id, err := security.ValidateID(valor)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "turno_id inválido")
+			return
+		}
+		turnoID = &id
+	}
+
+	dias, err := h.calendarioUseCase.ConsultarRango(*desde, *hasta, turnoID)
+	if err != nil {
+		h.sendErrorCalendario(w, err)
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: dias})
+}
+
+// fechaOpcional lee un parámetro YYYY-MM-DD de la query; nil si no viene
+func fechaOpcional(r *http.Request, nombre string) (*entities.Fecha, error) {
+	valor := r.URL.Query().Get(nombre)
+	if valor == "" {
+		return nil, nil
+	}
+	fecha, err := entities.ParseFecha(valor)
+	if err != nil {
+		return nil, err
+	}
+	return &fecha, nil
+}
+
+func (h *CalendarioHandler) auditar(r *http.Request, formato string, args ...interface{}) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		return
+	}
+	args = append([]interface{}{claims.UserID, claims.Username}, args...)
+	log.Printf("[AUDIT] Usuario %d (%s) "+formato, args...)
+}
+
+// sendErrorCalendario responde 404 a los recursos inexistentes y 400 al resto de errores
+func (h *CalendarioHandler) sendErrorCalendario(w http.ResponseWriter, err error) {
+	if strings.HasSuffix(err.Error(), "no encontrado") {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.sendError(w, http.StatusBadRequest, err.Error())
+}
+
+func (h *CalendarioHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *CalendarioHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type CierreHandler struct {
+	cierreUseCase *usecases.CierrePeriodoUseCase
+}
+
+func NewCierreHandler(cierreUseCase *usecases.CierrePeriodoUseCase) *CierreHandler {
+	return &CierreHandler{cierreUseCase: cierreUseCase}
+}
+
+// Listar retorna los meses que alguna vez se cerraron con su estado actual
+func (h *CierreHandler) Listar(w http.ResponseWriter, r *http.Request) {
+	estados, err := h.cierreUseCase.GetEstados()
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo cierres de periodo: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener cierres")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: estados})
+}
+
+// Obtener retorna el estado de un mes con su historial de cierres y reaperturas
+func (h *CierreHandler) Obtener(w http.ResponseWriter, r *http.Request) {
+	mes, err := mesDeRuta(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	estado, err := h.cierreUseCase.GetEstado(mes)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo cierre de %s: %v", mes, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener el cierre")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: estado})
+}
+
+// Cerrar congela los registros de un mes concluido
+func (h *CierreHandler) Cerrar(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var req CerrarPeriodoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if req.Observacion != nil {
+		if err := security.ValidateDescripcion(*req.Observacion); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	mes := entities.MesContable{Anio: req.Anio, Mes: req.Mes}
+	movimiento, err := h.cierreUseCase.Cerrar(mes, claims.UserID, req.Observacion)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) cerró el periodo %s", claims.UserID, claims.Username, mes)
+	h.sendJSON(w, http.StatusCreated, ApiResponse{Data: movimiento, Message: "Periodo cerrado exitosamente"})
+}
+
+// Reabrir vuelve a permitir correcciones en un mes cerrado; requiere motivo
+func (h *CierreHandler) Reabrir(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	mes, err := mesDeRuta(r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req ReabrirPeriodoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if err := security.ValidateDescripcion(req.Motivo); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	movimiento, err := h.cierreUseCase.Reabrir(mes, claims.UserID, req.Motivo)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) reabrió el periodo %s: %s", claims.UserID, claims.Username, mes, *movimiento.Motivo)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: movimiento, Message: "Periodo reabierto exitosamente"})
+}
+
+// mesDeRuta lee {anio} y {mes} de la ruta
+func mesDeRuta(r *http.Request) (entities.MesContable, error) {
+	vars := mux.Vars(r)
+	anio, errAnio := strconv.Atoi(vars["anio"])
+	mes, errMes := strconv.Atoi(vars["mes"])
+	periodo := entities.MesContable{Anio: anio, Mes: mes}
+	if errAnio != nil || errMes != nil || !periodo.Valido() {
+		return periodo, fmt.Errorf("mes inválido")
+	}
+	return periodo, nil
+}
+
+func (h *CierreHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *CierreHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/application/dto"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type ConsentimientoHandler struct {
+	consentimientoUseCase *usecases.ConsentimientoBiometricoUseCase
+	docenteUseCase        *usecases.DocenteUseCase
+}
+
+func NewConsentimientoHandler(consentimientoUseCase *usecases.ConsentimientoBiometricoUseCase, docenteUseCase *usecases.DocenteUseCase) *ConsentimientoHandler {
+	return &ConsentimientoHandler{
+		consentimientoUseCase: consentimientoUseCase,
+		docenteUseCase:        docenteUseCase,
+	}
+}
+
+// Registrar guarda el consentimiento biométrico de un docente, recolectado por el usuario actual
+func (h *ConsentimientoHandler) Registrar(w http.ResponseWriter, r *http.Request) {
+	docenteID, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID de docente inválido")
+		return
+	}
+
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var req dto.RegistrarConsentimientoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	consentimiento, err := h.consentimientoUseCase.Registrar(docenteID, req.VersionDocumento, req.FechaConsentimiento, claims.UserID)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) registró el consentimiento biométrico del docente %d (documento %s)",
+		claims.UserID, claims.Username, docenteID, consentimiento.VersionDocumento)
+
+	h.sendJSON(w, http.StatusCreated, ApiResponse{
+		Message: "Consentimiento registrado exitosamente",
+		Data:    consentimiento,
+	})
+}
+
+// Obtener retorna el consentimiento vigente y el historial de un docente
+func (h *ConsentimientoHandler) Obtener(w http.ResponseWriter, r *http.Request) {
+	docenteID, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID de docente inválido")
+		return
+	}
+
+	if !h.autorizarDocente(w, r, docenteID) {
+		return
+	}
+
+	historial, err := h.consentimientoUseCase.GetHistorial(docenteID)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo consentimientos del docente %d: %v", docenteID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener el consentimiento")
+		return
+	}
+
+	var vigente *entities.ConsentimientoBiometrico
+	for _, consentimiento := range historial {
+		if consentimiento.Vigente() {
+			vigente = consentimiento
+			break
+		}
+	}
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{
+		Data: map[string]interface{}{
+			"docente_id": docenteID,
+			"vigente":    vigente,
+			"historial":  historial,
+		},
+	})
+}
+
+// Revocar revoca el consentimiento vigente y elimina todas las muestras faciales del docente
+// Puede hacerlo un administrador o el propio docente
+func (h *ConsentimientoHandler) Revocar(w http.ResponseWriter, r *http.Request) {
+	docenteID, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID de docente inválido")
+		return
+	}
+
+	if !h.autorizarDocente(w, r, docenteID) {
+		return
+	}
+	claims := getUserClaims(r)
+
+	var req dto.RevocarConsentimientoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if err := security.ValidateDescripcion(req.Motivo); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Motivo demasiado largo")
+		return
+	}
+
+	consentimiento, err := h.consentimientoUseCase.Revocar(docenteID, claims.UserID, req.Motivo)
+	if err != nil {
+		if errors.Is(err, repositories.ErrConsentimientoNoEncontrado) {
+			h.sendError(w, http.StatusNotFound, "El docente no tiene un consentimiento vigente")
+			return
+		}
+		log.Printf("[ERROR] Error revocando el consentimiento del docente %d: %v", docenteID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al revocar el consentimiento")
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) revocó el consentimiento biométrico %d del docente %d; muestras faciales y evidencias eliminadas",
+		claims.UserID, claims.Username, consentimiento.ID, docenteID)
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{
+		Message: "Consentimiento revocado. Se eliminaron todas las muestras faciales y evidencias fotográficas del docente",
+	})
+}
+
+// autorizarDocente permite el acceso a administradores y al propio docente
+func (h *ConsentimientoHandler) autorizarDocente(w http.ResponseWriter, r *http.Request, docenteID int) bool {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return false
+	}
+
+	docente, err := h.docenteUseCase.GetByID(docenteID)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, "Docente no encontrado")
+		return false
+	}
+
+	if !esDocentePropio(claims, docente) && claims.Rol != entities.RolAdministrador {
+		log.Printf("[SECURITY] Usuario %d (%s) intentó acceder al consentimiento del docente %d", claims.UserID, claims.Username, docenteID)
+		h.sendError(w, http.StatusForbidden, "Acceso denegado")
+		return false
+	}
+	return true
+}
+
+// esDocentePropio indica si el docente pertenece al usuario autenticado
+func esDocentePropio(claims *jwt.Claims, docente *entities.Docente) bool {
+	return claims.Rol == entities.RolDocente && docente.UsuarioID != nil && *docente.UsuarioID == claims.UserID
+}
+
+func (h *ConsentimientoHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *ConsentimientoHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/application/dto"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type DeduplicacionHandler struct {
+	deduplicacionUseCase *usecases.DeduplicacionUseCase
+}
+
+func NewDeduplicacionHandler(deduplicacionUseCase *usecases.DeduplicacionUseCase) *DeduplicacionHandler {
+	return &DeduplicacionHandler{deduplicacionUseCase: deduplicacionUseCase}
+}
+
+// IniciarEscaneo lanza en segundo plano la comparación de rostros entre todos los docentes
+func (h *DeduplicacionHandler) IniciarEscaneo(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	reporte, err := h.deduplicacionUseCase.IniciarEscaneo(claims.UserID)
+	if err != nil {
+		h.sendError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) inició el escaneo de rostros duplicados %d", claims.UserID, claims.Username, reporte.ID)
+
+	h.sendJSON(w, http.StatusAccepted, ApiResponse{
+		Message: "Escaneo de duplicados iniciado. Consulte el reporte para ver el resultado",
+		Data:    reporte,
+	})
+}
+
+// ListarReportes lista los escaneos de duplicados más recientes
+func (h *DeduplicacionHandler) ListarReportes(w http.ResponseWriter, r *http.Request) {
+	reportes, err := h.deduplicacionUseCase.GetReportes()
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo reportes de duplicados: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener reportes")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: reportes})
+}
+
+// ObtenerReporte retorna un reporte con los pares de docentes coincidentes
+func (h *DeduplicacionHandler) ObtenerReporte(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	reporte, err := h.deduplicacionUseCase.GetReporte(id)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, "Reporte no encontrado")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: reporte})
+}
+
+// RevisarPar registra si un par de docentes es la misma persona o un falso positivo
+func (h *DeduplicacionHandler) RevisarPar(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var req dto.RevisarParDuplicadoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	if err := h.deduplicacionUseCase.RevisarPar(id, entities.EstadoParDuplicado(req.Estado), claims.UserID, req.Nota); err != nil {
+		if err.Error() == "par no encontrado" {
+			h.sendError(w, http.StatusNotFound, "Par no encontrado")
+			return
+		}
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) revisó el par de duplicados %d: %s", claims.UserID, claims.Username, id, req.Estado)
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Revisión registrada"})
+}
+
+func (h *DeduplicacionHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *DeduplicacionHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/application/dto"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+	"github.com/skip2/go-qrcode"
+)
+
+// DosFactoresHandler atiende el enrolamiento TOTP del propio usuario (/me/2fa) y la
+// administración de la política por rol
+type DosFactoresHandler struct {
+	authUseCase        *usecases.AuthUseCase
+	dosFactoresUseCase *usecases.DosFactoresUseCase
+	usuarioUseCase     *usecases.UsuarioUseCase
+}
+
+func NewDosFactoresHandler(
+	authUseCase *usecases.AuthUseCase,
+	dosFactoresUseCase *usecases.DosFactoresUseCase,
+	usuarioUseCase *usecases.UsuarioUseCase,
+) *DosFactoresHandler {
+	return &DosFactoresHandler{
+		authUseCase:        authUseCase,
+		dosFactoresUseCase: dosFactoresUseCase,
+		usuarioUseCase:     usuarioUseCase,
+	}
+}
+
+// Estado indica si el usuario tiene el segundo factor activo, si su rol lo exige y cuántos
+// códigos de recuperación le quedan
+func (h *DosFactoresHandler) Estado(w http.ResponseWriter, r *http.Request) {
+	_, usuario, ok := h.usuarioDeSesion(w, r)
+	if !ok {
+		return
+	}
+
+	estado, err := h.dosFactoresUseCase.Estado(usuario)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo el segundo factor del usuario %d: %v", usuario.ID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener la autenticación en dos pasos")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: estado})
+}
+
+// Enrolar genera un secreto nuevo y lo entrega como texto, URL otpauth:// y código QR. Queda
+// inactivo hasta confirmarlo en /me/2fa/activar
+func (h *DosFactoresHandler) Enrolar(w http.ResponseWriter, r *http.Request) {
+	claims, usuario, ok := h.usuarioDeSesion(w, r)
+	if !ok {
+		return
+	}
+
+	enrolamiento, err := h.dosFactoresUseCase.IniciarEnrolamiento(usuario)
+	if err != nil {
+		h.sendErrorDosFactores(w, err)
+		return
+	}
+	png, err := qrcode.Encode(enrolamiento.URL, qrcode.Medium, 256)
+	if err != nil {
+		log.Printf("[ERROR] Error generando el código QR del usuario %d: %v", usuario.ID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al generar el código QR")
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) inició el enrolamiento de la autenticación en dos pasos", claims.UserID, claims.Username)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: dto.EnrolamientoDosFactoresResponse{
+		Secreto:    enrolamiento.Secreto,
+		OTPAuthURL: enrolamiento.URL,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}})
+}
+
+// Activar confirma el enrolamiento con un código de la aplicación. Retorna los códigos de
+// recuperación y un token nuevo, sin la restricción de enrolamiento si el rol la imponía
+func (h *DosFactoresHandler) Activar(w http.ResponseWriter, r *http.Request) {
+	claims, req, ok := h.leerCodigo(w, r)
+	if !ok {
+		return
+	}
+
+	codigos, err := h.dosFactoresUseCase.Activar(claims.UserID, req.Codigo)
+	if err != nil {
+		h.sendErrorDosFactores(w, err)
+		return
+	}
+	resultado, err := h.authUseCase.RenovarSesion(claims.UserID)
+	if err != nil {
+		log.Printf("[ERROR] Error renovando la sesión del usuario %d tras activar el segundo factor: %v", claims.UserID, err)
+		h.sendError(w, http.StatusInternalServerError, "Autenticación en dos pasos activada; inicie sesión nuevamente")
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) activó la autenticación en dos pasos", claims.UserID, claims.Username)
+	h.sendJSON(w, http.StatusOK, ApiResponse{
+		Data: dto.ActivacionDosFactoresResponse{
+			CodigosRecuperacion: codigos,
+			LoginResponse:       loginResponse(resultado),
+		},
+		Message: "Autenticación en dos pasos activada. Guarde los códigos de recuperación: no se volverán a mostrar",
+	})
+}
+
+// RegenerarCodigos invalida los códigos de recuperación y entrega otros; exige un código válido
+func (h *DosFactoresHandler) RegenerarCodigos(w http.ResponseWriter, r *http.Request) {
+	claims, req, ok := h.leerCodigo(w, r)
+	if !ok {
+		return
+	}
+
+	codigos, err := h.dosFactoresUseCase.RegenerarCodigos(claims.UserID, req.Codigo)
+	if err != nil {
+		h.sendErrorDosFactores(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) regeneró sus códigos de recuperación", claims.UserID, claims.Username)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: codigos, Message: "Códigos de recuperación regenerados"})
+}
+
+// Desactivar quita el segundo factor propio con un código válido, si el rol no lo exige
+func (h *DosFactoresHandler) Desactivar(w http.ResponseWriter, r *http.Request) {
+	claims, req, ok := h.leerCodigo(w, r)
+	if !ok {
+		return
+	}
+	usuario, err := h.usuarioUseCase.GetByID(claims.UserID)
+	if err != nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	if err := h.dosFactoresUseCase.Desactivar(usuario, req.Codigo); err != nil {
+		h.sendErrorDosFactores(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) desactivó la autenticación en dos pasos", claims.UserID, claims.Username)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Autenticación en dos pasos desactivada"})
+}
+
+// Restablecer quita el segundo factor de otro usuario que perdió la aplicación y sus códigos
+func (h *DosFactoresHandler) Restablecer(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+	if _, err := h.usuarioUseCase.GetByID(id); err != nil {
+		h.sendError(w, http.StatusNotFound, "Usuario no encontrado")
+		return
+	}
+
+	if err := h.dosFactoresUseCase.Restablecer(id); err != nil {
+		h.sendErrorDosFactores(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) restableció la autenticación en dos pasos del usuario %d", claims.UserID, claims.Username, id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Autenticación en dos pasos restablecida"})
+}
+
+func (h *DosFactoresHandler) ListarPoliticas(w http.ResponseWriter, r *http.Request) {
+	politicas, err := h.dosFactoresUseCase.Politicas()
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo políticas de autenticación en dos pasos: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener las políticas")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: politicas})
+}
+
+// DefinirPolitica exige o no el segundo factor al rol de la ruta
+func (h *DosFactoresHandler) DefinirPolitica(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var req dto.PoliticaDosFactoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	rol := entities.Rol(mux.Vars(r)["rol"])
+	politica, err := h.dosFactoresUseCase.DefinirPolitica(rol, req.Requerido, claims.UserID)
+	if err != nil {
+		h.sendErrorDosFactores(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) definió la autenticación en dos pasos del rol %s como requerida=%t",
+		claims.UserID, claims.Username, rol, req.Requerido)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: politica, Message: "Política actualizada"})
+}
+
+// usuarioDeSesion obtiene el usuario del token
+func (h *DosFactoresHandler) usuarioDeSesion(w http.ResponseWriter, r *http.Request) (*jwt.Claims, *entities.Usuario, bool) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return nil, nil, false
+	}
+	usuario, err := h.usuarioUseCase.GetByID(claims.UserID)
+	if err != nil || !usuario.Activo {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return nil, nil, false
+	}
+	return claims, usuario, true
+}
+
+// leerCodigo obtiene los claims y el cuerpo {"codigo": "..."} de la petición
+func (h *DosFactoresHandler) leerCodigo(w http.ResponseWriter, r *http.Request) (*jwt.Claims, dto.CodigoDosFactoresRequest, bool) {
+	var req dto.CodigoDosFactoresRequest
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return nil, req, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return nil, req, false
+	}
+	if strings.TrimSpace(req.Codigo) == "" {
+		h.sendError(w, http.StatusBadRequest, "codigo es requerido")
+		return nil, req, false
+	}
+	return claims, req, true
+}
+
+// sendErrorDosFactores responde 404 si el usuario no tiene segundo factor y 400 al resto de errores,
+// incluido un código inválido: un 401 cerraría la sesión en el frontend
+func (h *DosFactoresHandler) sendErrorDosFactores(w http.ResponseWriter, err error) {
+	if errors.Is(err, repositories.ErrDosFactoresNoConfigurado) {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.sendError(w, http.StatusBadRequest, err.Error())
+}
+
+func (h *DosFactoresHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *DosFactoresHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
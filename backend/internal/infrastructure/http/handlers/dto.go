@@ -8,6 +8,7 @@ type CreateUsuarioRequest struct {
 	Rol            string `json:"rol"`
 	NombreCompleto string `json:"nombre_completo,omitempty"`
 	Email          string `json:"email,omitempty"`
+	Origen         string `json:"origen,omitempty"` // local (por defecto) o ldap, sin contraseña
 }
 
 type UpdateUsuarioRequest struct {
@@ -15,8 +16,34 @@ type UpdateUsuarioRequest struct {
 	NombreCompleto string `json:"nombre_completo,omitempty"`
 	Email          string `json:"email,omitempty"`
 	Activo         *bool  `json:"activo,omitempty"`
+	Origen         string `json:"origen,omitempty"`
 }
 
 type ChangePasswordRequest struct {
 	NewPassword string `json:"new_password"`
 }
+
+type CerrarPeriodoRequest struct {
+	Anio        int     `json:"anio"`
+	Mes         int     `json:"mes"`
+	Observacion *string `json:"observacion,omitempty"`
+}
+
+type ReabrirPeriodoRequest struct {
+	Motivo string `json:"motivo"`
+}
+
+// CrearJustificacionRequest adjunta la justificación a registro_id o a docente_id + fecha (+ turno_id)
+type CrearJustificacionRequest struct {
+	RegistroID  *int   `json:"registro_id,omitempty"`
+	DocenteID   int    `json:"docente_id,omitempty"`
+	Fecha       string `json:"fecha,omitempty"`
+	TurnoID     *int   `json:"turno_id,omitempty"`
+	Categoria   string `json:"categoria"`
+	Descripcion string `json:"descripcion"`
+}
+
+type RevisarJustificacionRequest struct {
+	Estado      string  `json:"estado"`
+	Observacion *string `json:"observacion,omitempty"`
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type EvidenciaHandler struct {
+	evidenciaUseCase *usecases.EvidenciaReconocimientoUseCase
+}
+
+func NewEvidenciaHandler(evidenciaUseCase *usecases.EvidenciaReconocimientoUseCase) *EvidenciaHandler {
+	return &EvidenciaHandler{evidenciaUseCase: evidenciaUseCase}
+}
+
+// ObtenerPorRegistro retorna como JPEG el recorte del rostro capturado al identificar al
+// docente del registro. Cada consulta queda en el log de auditoría
+func (h *EvidenciaHandler) ObtenerPorRegistro(w http.ResponseWriter, r *http.Request) {
+	registroID, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	evidencia, imagen, err := h.evidenciaUseCase.GetImagenPorRegistro(registroID)
+	if err != nil {
+		if err.Error() == "evidencia no encontrada" {
+			h.sendError(w, http.StatusNotFound, "El registro no tiene evidencia fotográfica")
+			return
+		}
+		log.Printf("[ERROR] Error obteniendo la evidencia del registro %d: %v", registroID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener la evidencia")
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) consultó la evidencia %d del registro %d (docente %d)",
+		claims.UserID, claims.Username, evidencia.ID, registroID, evidencia.DocenteID)
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Length", strconv.Itoa(len(imagen)))
+	w.Header().Set("Cache-Control", "no-store, private")
+	w.Write(imagen)
+}
+
+func (h *EvidenciaHandler) sendError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ApiResponse{Error: message})
+}
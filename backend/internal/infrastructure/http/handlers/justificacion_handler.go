@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+// maxDocumentoRequestSize deja margen sobre el documento para el resto del formulario
+const maxDocumentoRequestSize = usecases.MaxDocumentoJustificacion + 64<<10
+
+type JustificacionHandler struct {
+	justificacionUseCase *usecases.JustificacionUseCase
+}
+
+func NewJustificacionHandler(justificacionUseCase *usecases.JustificacionUseCase) *JustificacionHandler {
+	return &JustificacionHandler{justificacionUseCase: justificacionUseCase}
+}
+
+// Listar filtra por ?estado=pendiente|aprobada|rechazada&docente_id=N&desde=YYYY-MM-DD&hasta=YYYY-MM-DD
+func (h *JustificacionHandler) Listar(w http.ResponseWriter, r *http.Request) {
+	filtro := repositories.FiltroJustificaciones{}
+	query := r.URL.Query()
+
+	if valor := query.Get("estado"); valor != "" {
+		estado := entities.EstadoJustificacion(valor)
+		if !estado.IsValid() {
+			h.sendError(w, http.StatusBadRequest, "estado inválido")
+			return
+		}
+		filtro.Estado = &estado
+	}
+	if valor := query.Get("docente_id"); valor != "" {
+		id, err := security.ValidateID(valor)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "docente_id inválido")
+			return
+		}
+		filtro.DocenteID = &id
+	}
+	var err error
+	if filtro.Desde, err = fechaOpcional(r, "desde"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filtro.Hasta, err = fechaOpcional(r, "hasta"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	justificaciones, err := h.justificacionUseCase.Listar(filtro)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo justificaciones: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener justificaciones")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: justificaciones})
+}
+
+func (h *JustificacionHandler) Obtener(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	justificacion, err := h.justificacionUseCase.GetByID(id)
+	if err != nil {
+		h.sendErrorJustificacion(w, err)
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: justificacion})
+}
+
+// Crear registra una justificación en nombre del docente; queda pendiente de revisión
+func (h *JustificacionHandler) Crear(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var req CrearJustificacionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	justificacion, err := nuevaJustificacion(req)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	justificacion.CreadoPor = claims.UserID
+
+	if err := h.justificacionUseCase.Crear(justificacion); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) registró la justificación %d del docente %d (%s)",
+		claims.UserID, claims.Username, justificacion.ID, justificacion.DocenteID, justificacion.Fecha)
+	h.sendJSON(w, http.StatusCreated, ApiResponse{Data: justificacion, Message: "Justificación registrada, pendiente de revisión"})
+}
+
+// Revisar aprueba o rechaza una justificación pendiente
+func (h *JustificacionHandler) Revisar(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	var req RevisarJustificacionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if req.Observacion != nil {
+		if err := security.ValidateDescripcion(*req.Observacion); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	justificacion, justificados, err := h.justificacionUseCase.Revisar(id, entities.EstadoJustificacion(req.Estado), claims.UserID, req.Observacion)
+	if err != nil {
+		h.sendErrorJustificacion(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) marcó la justificación %d como %s (%d registros justificados)",
+		claims.UserID, claims.Username, id, justificacion.Estado, justificados)
+	h.sendJSON(w, http.StatusOK, ApiResponse{
+		Data: map[string]interface{}{
+			"justificacion":          justificacion,
+			"registros_justificados": justificados,
+		},
+		Message: "Justificación revisada",
+	})
+}
+
+func (h *JustificacionHandler) Eliminar(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	if err := h.justificacionUseCase.Eliminar(id); err != nil {
+		h.sendErrorJustificacion(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) eliminó la justificación %d", claims.UserID, claims.Username, id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Justificación eliminada"})
+}
+
+// SubirDocumento adjunta un PDF o una imagen (campo multipart "documento") a una justificación pendiente
+func (h *JustificacionHandler) SubirDocumento(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	nombre, datos, err := leerDocumento(w, r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	justificacion, err := h.justificacionUseCase.AdjuntarDocumento(id, nombre, datos)
+	if err != nil {
+		h.sendErrorJustificacion(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) adjuntó un documento a la justificación %d", claims.UserID, claims.Username, id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: justificacion, Message: "Documento adjuntado"})
+}
+
+// Documento descarga el documento de respaldo de la justificación
+func (h *JustificacionHandler) Documento(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	justificacion, datos, err := h.justificacionUseCase.GetDocumento(id)
+	if err != nil {
+		h.sendErrorJustificacion(w, err)
+		return
+	}
+	enviarDocumento(w, justificacion, datos)
+}
+
+// nuevaJustificacion convierte el request en la entidad; el caso de uso valida el resto
+func nuevaJustificacion(req CrearJustificacionRequest) (*entities.Justificacion, error) {
+	if err := security.ValidateDescripcion(req.Descripcion); err != nil {
+		return nil, err
+	}
+	justificacion := &entities.Justificacion{
+		RegistroID:  req.RegistroID,
+		DocenteID:   req.DocenteID,
+		TurnoID:     req.TurnoID,
+		Categoria:   entities.CategoriaJustificacion(req.Categoria),
+		Descripcion: req.Descripcion,
+	}
+	if req.Fecha != "" && req.RegistroID == nil {
+		fecha, err := entities.ParseFecha(req.Fecha)
+		if err != nil {
+			return nil, err
+		}
+		justificacion.Fecha = fecha
+	}
+	return justificacion, nil
+}
+
+// leerDocumento lee el archivo "documento" de un formulario multipart
+func leerDocumento(w http.ResponseWriter, r *http.Request) (string, []byte, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxDocumentoRequestSize)
+	if err := r.ParseMultipartForm(maxDocumentoRequestSize); err != nil {
+		return "", nil, errors.New("error al parsear form o documento demasiado grande")
+	}
+	archivo, cabecera, err := r.FormFile("documento")
+	if err != nil {
+		return "", nil, errors.New("no se encontró el documento en la petición")
+	}
+	defer archivo.Close()
+
+	datos, err := io.ReadAll(archivo)
+	if err != nil {
+		return "", nil, errors.New("error leyendo el documento")
+	}
+	return cabecera.Filename, datos, nil
+}
+
+// enviarDocumento responde con el documento como descarga, con el tipo detectado al subirlo
+func enviarDocumento(w http.ResponseWriter, justificacion *entities.Justificacion, datos []byte) {
+	tipo := "application/octet-stream"
+	if justificacion.DocumentoTipo != nil {
+		tipo = *justificacion.DocumentoTipo
+	}
+	nombre := "documento"
+	if justificacion.DocumentoNombre != nil {
+		nombre = *justificacion.DocumentoNombre
+	}
+	w.Header().Set("Content-Type", tipo)
+	w.Header().Set("Content-Length", strconv.Itoa(len(datos)))
+	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(nombre))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store, private")
+	w.Write(datos)
+}
+
+// sendErrorJustificacion responde 409 si el mes está cerrado, 404 a los recursos inexistentes
+// y 400 al resto de errores
+func (h *JustificacionHandler) sendErrorJustificacion(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrPeriodoCerrado):
+		h.sendError(w, http.StatusConflict, err.Error())
+	case strings.HasSuffix(err.Error(), "no encontrada"), strings.HasSuffix(err.Error(), "no encontrado"):
+		h.sendError(w, http.StatusNotFound, err.Error())
+	default:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+func (h *JustificacionHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *JustificacionHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type LicenciaHandler struct {
+	licenciaUseCase *usecases.LicenciaUseCase
+	clock           clock.Clock
+}
+
+func NewLicenciaHandler(licenciaUseCase *usecases.LicenciaUseCase, reloj clock.Clock) *LicenciaHandler {
+	return &LicenciaHandler{licenciaUseCase: licenciaUseCase, clock: reloj}
+}
+
+// Listar filtra por ?docente_id=N&desde=YYYY-MM-DD&hasta=YYYY-MM-DD; con vigentes=true solo las
+// licencias que cubren el día de hoy
+func (h *LicenciaHandler) Listar(w http.ResponseWriter, r *http.Request) {
+	filtro := repositories.FiltroLicencias{}
+	query := r.URL.Query()
+
+	if valor := query.Get("docente_id"); valor != "" {
+		id, err := security.ValidateID(valor)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "docente_id inválido")
+			return
+		}
+		filtro.DocenteID = &id
+	}
+	var err error
+	if filtro.Desde, err = fechaOpcional(r, "desde"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filtro.Hasta, err = fechaOpcional(r, "hasta"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if query.Get("vigentes") == "true" {
+		hoy := entities.FechaDe(h.clock.Now().In(h.clock.Location()))
+		filtro.Desde, filtro.Hasta = &hoy, &hoy
+	}
+
+	licencias, err := h.licenciaUseCase.Listar(filtro)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo licencias: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener licencias")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: licencias})
+}
+
+func (h *LicenciaHandler) Obtener(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	licencia, err := h.licenciaUseCase.GetByID(id)
+	if err != nil {
+		h.sendErrorLicencia(w, err)
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: licencia})
+}
+
+func (h *LicenciaHandler) Crear(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var licencia entities.Licencia
+	if err := json.NewDecoder(r.Body).Decode(&licencia); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if licencia.Descripcion != nil {
+		if err := security.ValidateDescripcion(*licencia.Descripcion); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	licencia.CreadoPor = claims.UserID
+
+	if err := h.licenciaUseCase.Crear(&licencia); err != nil {
+		h.sendErrorLicencia(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) registró la licencia %d (%s) del docente %d del %s al %s",
+		claims.UserID, claims.Username, licencia.ID, licencia.Tipo, licencia.DocenteID, licencia.FechaInicio, licencia.FechaFin)
+	h.sendJSON(w, http.StatusCreated, ApiResponse{Data: licencia, Message: "Licencia registrada exitosamente"})
+}
+
+// Actualizar reemplaza tipo, fechas y descripción de la licencia
+func (h *LicenciaHandler) Actualizar(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	var licencia entities.Licencia
+	if err := json.NewDecoder(r.Body).Decode(&licencia); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if licencia.Descripcion != nil {
+		if err := security.ValidateDescripcion(*licencia.Descripcion); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	licencia.ID = id
+
+	if err := h.licenciaUseCase.Actualizar(&licencia); err != nil {
+		h.sendErrorLicencia(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) actualizó la licencia %d del docente %d: %s del %s al %s",
+		claims.UserID, claims.Username, id, licencia.DocenteID, licencia.Tipo, licencia.FechaInicio, licencia.FechaFin)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: licencia, Message: "Licencia actualizada exitosamente"})
+}
+
+func (h *LicenciaHandler) Eliminar(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	if err := h.licenciaUseCase.Eliminar(id); err != nil {
+		h.sendErrorLicencia(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) eliminó la licencia %d", claims.UserID, claims.Username, id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Licencia eliminada exitosamente"})
+}
+
+// sendErrorLicencia responde 409 si toca un mes cerrado, 404 a los recursos inexistentes y 400
+// al resto de errores
+func (h *LicenciaHandler) sendErrorLicencia(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrPeriodoCerrado):
+		h.sendError(w, http.StatusConflict, err.Error())
+	case strings.HasSuffix(err.Error(), "no encontrada"), strings.HasSuffix(err.Error(), "no encontrado"):
+		h.sendError(w, http.StatusNotFound, err.Error())
+	default:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+func (h *LicenciaHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *LicenciaHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
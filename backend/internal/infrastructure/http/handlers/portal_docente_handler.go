@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+// PortalDocenteHandler atiende las rutas /me del rol docente. Cada operación se limita al docente
+// vinculado por usuario_id al usuario del token; ningún parámetro de la petición lo reemplaza
+type PortalDocenteHandler struct {
+	docenteUseCase       *usecases.DocenteUseCase
+	registroUseCase      *usecases.RegistroUseCase
+	reporteUseCase       *usecases.ReporteUseCase
+	justificacionUseCase *usecases.JustificacionUseCase
+	clock                clock.Clock
+}
+
+func NewPortalDocenteHandler(
+	docenteUseCase *usecases.DocenteUseCase,
+	registroUseCase *usecases.RegistroUseCase,
+	reporteUseCase *usecases.ReporteUseCase,
+	justificacionUseCase *usecases.JustificacionUseCase,
+	reloj clock.Clock,
+) *PortalDocenteHandler {
+	return &PortalDocenteHandler{
+		docenteUseCase:       docenteUseCase,
+		registroUseCase:      registroUseCase,
+		reporteUseCase:       reporteUseCase,
+		justificacionUseCase: justificacionUseCase,
+		clock:                reloj,
+	}
+}
+
+// Perfil retorna los datos del docente autenticado
+func (h *PortalDocenteHandler) Perfil(w http.ResponseWriter, r *http.Request) {
+	_, docente, ok := h.docenteDeSesion(w, r)
+	if !ok {
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: docente})
+}
+
+// Registros lista los ingresos y salidas del docente en ?desde=YYYY-MM-DD&hasta=YYYY-MM-DD; por
+// defecto, el mes en curso hasta hoy
+func (h *PortalDocenteHandler) Registros(w http.ResponseWriter, r *http.Request) {
+	_, docente, ok := h.docenteDeSesion(w, r)
+	if !ok {
+		return
+	}
+
+	hoy := entities.FechaDe(h.clock.Now().In(h.clock.Location()))
+	desde := entities.Fecha{Time: hoy.AddDate(0, 0, 1-hoy.Day())}
+	hasta := hoy
+	if fecha, err := fechaOpcional(r, "desde"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if fecha != nil {
+		desde = *fecha
+	}
+	if fecha, err := fechaOpcional(r, "hasta"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if fecha != nil {
+		hasta = *fecha
+	}
+
+	registros, err := h.registroUseCase.GetByDocenteEnRango(docente.ID, desde, hasta)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: registros})
+}
+
+// LlaveActual retorna la sesión abierta en la que el docente retiró una llave, o null si no tiene
+func (h *PortalDocenteHandler) LlaveActual(w http.ResponseWriter, r *http.Request) {
+	_, docente, ok := h.docenteDeSesion(w, r)
+	if !ok {
+		return
+	}
+
+	sesiones, err := h.registroUseCase.GetSesiones(repositories.FiltroSesiones{
+		DocenteID:    &docente.ID,
+		SoloAbiertas: true,
+		SoloConLlave: true,
+	})
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo la llave actual del docente %d: %v", docente.ID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener la llave actual")
+		return
+	}
+	var sesion *entities.Sesion
+	if len(sesiones) > 0 {
+		sesion = sesiones[0]
+	}
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{"data": sesion})
+}
+
+// ResumenMensual resume la asistencia del docente en ?mes=YYYY-MM; por defecto, el mes en curso
+func (h *PortalDocenteHandler) ResumenMensual(w http.ResponseWriter, r *http.Request) {
+	_, docente, ok := h.docenteDeSesion(w, r)
+	if !ok {
+		return
+	}
+
+	mes := entities.MesDe(h.clock.Now().In(h.clock.Location()))
+	if valor := r.URL.Query().Get("mes"); valor != "" {
+		t, err := time.Parse("2006-01", valor)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "mes inválido, se espera AAAA-MM")
+			return
+		}
+		mes = entities.MesDe(t)
+	}
+
+	resumen, err := h.reporteUseCase.ResumenMensual(docente, mes)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: resumen})
+}
+
+// Justificaciones lista las justificaciones del docente, opcionalmente por ?estado=
+func (h *PortalDocenteHandler) Justificaciones(w http.ResponseWriter, r *http.Request) {
+	_, docente, ok := h.docenteDeSesion(w, r)
+	if !ok {
+		return
+	}
+
+	filtro := repositories.FiltroJustificaciones{DocenteID: &docente.ID}
+	if valor := r.URL.Query().Get("estado"); valor != "" {
+		estado := entities.EstadoJustificacion(valor)
+		if !estado.IsValid() {
+			h.sendError(w, http.StatusBadRequest, "estado inválido")
+			return
+		}
+		filtro.Estado = &estado
+	}
+
+	justificaciones, err := h.justificacionUseCase.Listar(filtro)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo justificaciones del docente %d: %v", docente.ID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener justificaciones")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: justificaciones})
+}
+
+// Justificar registra una justificación del propio docente; docente_id del cuerpo se ignora y un
+// registro_id de otro docente se rechaza
+func (h *PortalDocenteHandler) Justificar(w http.ResponseWriter, r *http.Request) {
+	claims, docente, ok := h.docenteDeSesion(w, r)
+	if !ok {
+		return
+	}
+
+	var req CrearJustificacionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	justificacion, err := nuevaJustificacion(req)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	justificacion.DocenteID = docente.ID
+	justificacion.CreadoPor = claims.UserID
+
+	if err := h.justificacionUseCase.Crear(justificacion); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Docente %d (usuario %s) registró su justificación %d (%s)",
+		docente.ID, claims.Username, justificacion.ID, justificacion.Fecha)
+	h.sendJSON(w, http.StatusCreated, ApiResponse{Data: justificacion, Message: "Justificación registrada, pendiente de revisión"})
+}
+
+// SubirDocumento adjunta el respaldo (campo multipart "documento") a una justificación pendiente
+// del propio docente
+func (h *PortalDocenteHandler) SubirDocumento(w http.ResponseWriter, r *http.Request) {
+	claims, docente, ok := h.docenteDeSesion(w, r)
+	if !ok {
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	// Una justificación ajena responde igual que una inexistente
+	existente, err := h.justificacionUseCase.GetByID(id)
+	if err != nil || existente.DocenteID != docente.ID {
+		if err == nil {
+			log.Printf("[SECURITY] Docente %d (usuario %s) intentó adjuntar un documento a la justificación %d de otro docente",
+				docente.ID, claims.Username, id)
+		}
+		h.sendError(w, http.StatusNotFound, "justificación no encontrada")
+		return
+	}
+
+	nombre, datos, err := leerDocumento(w, r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	justificacion, err := h.justificacionUseCase.AdjuntarDocumento(id, nombre, datos)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Docente %d (usuario %s) adjuntó un documento a su justificación %d", docente.ID, claims.Username, id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: justificacion, Message: "Documento adjuntado"})
+}
+
+// docenteDeSesion obtiene el docente vinculado al usuario del token. Responde 404 si la cuenta
+// no tiene docente
+func (h *PortalDocenteHandler) docenteDeSesion(w http.ResponseWriter, r *http.Request) (*jwt.Claims, *entities.Docente, bool) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return nil, nil, false
+	}
+
+	docente, err := h.docenteUseCase.GetByUsuarioID(claims.UserID)
+	if err != nil {
+		log.Printf("[WARN] Usuario %d (%s) con rol %s sin docente vinculado: %v", claims.UserID, claims.Username, claims.Rol, err)
+		h.sendError(w, http.StatusNotFound, "No hay un docente vinculado a este usuario")
+		return nil, nil, false
+	}
+	return claims, docente, true
+}
+
+func (h *PortalDocenteHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *PortalDocenteHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
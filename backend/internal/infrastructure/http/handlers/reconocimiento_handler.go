@@ -1,84 +1,73 @@
 package handlers
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"mime"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/application/dto"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
 	"github.com/sistema-ingreso-docente/backend/internal/recognition"
 )
 
-// Extensiones de imagen permitidas
-var allowedImageExtensions = map[string]bool{
-	".jpg":  true,
-	".jpeg": true,
-	".png":  true,
-	".gif":  true,
-	".webp": true,
-}
-
-// generateSafeFilename genera un nombre de archivo seguro y único
-func generateSafeFilename(prefix string, originalExt string) string {
-	// Generar ID único
-	bytes := make([]byte, 8)
-	rand.Read(bytes)
-	uniqueID := hex.EncodeToString(bytes)
-
-	// Sanitizar extensión
-	ext := strings.ToLower(originalExt)
-	if !allowedImageExtensions[ext] {
-		ext = ".jpg" // Default seguro
-	}
-
-	return fmt.Sprintf("%s_%d_%s%s", prefix, time.Now().UnixNano(), uniqueID, ext)
-}
-
-// validateImageExtension verifica que la extensión sea permitida
-func validateImageExtension(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	return allowedImageExtensions[ext]
-}
+const (
+	// maxImageSize es el tamaño máximo de cada imagen ya decodificada (4MB)
+	maxImageSize = 4 << 20
+	// maxImageRequestSize limita el cuerpo de una petición con una imagen
+	// (una data URL base64 ocupa ~4/3 del tamaño de la imagen)
+	maxImageRequestSize = 6 << 20
+	// maxEnrollmentRequestSize limita el cuerpo de una petición de enrolamiento
+	maxEnrollmentRequestSize = 28 << 20
+	// maxEnrollmentImages es la cantidad máxima de fotos por enrolamiento
+	maxEnrollmentImages = 10
+)
 
 type ReconocimientoHandler struct {
-	docenteRepo repositories.DocenteRepository
+	engine                recognition.FaceEngine
+	index                 *recognition.Index
+	docenteRepo           repositories.DocenteRepository
+	intentoUseCase        *usecases.IntentoReconocimientoUseCase
+	consentimientoUseCase *usecases.ConsentimientoBiometricoUseCase
+	evidenciaUseCase      *usecases.EvidenciaReconocimientoUseCase
+	clock                 clock.Clock
 }
 
-func NewReconocimientoHandler(docenteRepo repositories.DocenteRepository) *ReconocimientoHandler {
+func NewReconocimientoHandler(engine recognition.FaceEngine, index *recognition.Index, docenteRepo repositories.DocenteRepository, intentoUseCase *usecases.IntentoReconocimientoUseCase, consentimientoUseCase *usecases.ConsentimientoBiometricoUseCase, evidenciaUseCase *usecases.EvidenciaReconocimientoUseCase, reloj clock.Clock) *ReconocimientoHandler {
 	return &ReconocimientoHandler{
-		docenteRepo: docenteRepo,
+		engine:                engine,
+		index:                 index,
+		docenteRepo:           docenteRepo,
+		intentoUseCase:        intentoUseCase,
+		consentimientoUseCase: consentimientoUseCase,
+		evidenciaUseCase:      evidenciaUseCase,
+		clock:                 reloj,
 	}
 }
 
 // DetectarRostro detecta rostros en una imagen y retorna el descriptor del rostro más grande
 func (h *ReconocimientoHandler) DetectarRostro(w http.ResponseWriter, r *http.Request) {
-	file, _, tempFile, err := h.processUploadedImage(r)
+	imagen, err := h.leerImagen(w, r)
 	if err != nil {
 		h.sendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	defer file.Close()
-	defer os.Remove(tempFile)
-
-	rec, err := recognition.NewRecognizer()
-	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, "Error al inicializar reconocedor facial")
-		return
-	}
-	defer rec.Close()
 
-	faces, err := rec.RecognizeFile(tempFile)
+	faces, err := h.engine.Detect(imagen.Datos)
 	if err != nil {
 		h.sendError(w, http.StatusInternalServerError, fmt.Sprintf("Error al procesar imagen: %v", err))
 		return
@@ -101,45 +90,51 @@ func (h *ReconocimientoHandler) DetectarRostro(w http.ResponseWriter, r *http.Re
 	})
 }
 
+// maxCandidatosRegistrados es la cantidad de candidatos guardados por intento de identificación
+const maxCandidatosRegistrados = 5
+
+// docenteIdentificado es la respuesta de una identificación exitosa
+type docenteIdentificado struct {
+	ID                 int     `json:"id"`
+	DocumentoIdentidad int64   `json:"documento_identidad"`
+	NombreCompleto     string  `json:"nombre_completo"`
+	MatchCount         int     `json:"match_count"`
+	TotalDescriptors   int     `json:"total_descriptors"`
+	Distance           float32 `json:"distance"` // Mejor distancia encontrada
+	IntentoID          int     `json:"intento_id"`
+}
+
 // IdentificarDocente identifica un docente por su rostro
+// Cada intento queda registrado con sus candidatos para auditoría del sistema biométrico
 func (h *ReconocimientoHandler) IdentificarDocente(w http.ResponseWriter, r *http.Request) {
-	fmt.Println("═══════════════════════════════════════════════════════════════")
-	fmt.Println("[IdentificarDocente] Nueva petición de identificación recibida")
-	fmt.Printf("[IdentificarDocente] Timestamp: %v\n", time.Now().Format("2006-01-02 15:04:05.000"))
-
-	file, _, tempFile, err := h.processUploadedImage(r)
+	imagen, err := h.leerImagen(w, r)
 	if err != nil {
-		fmt.Printf("[IdentificarDocente] ERROR procesando imagen: %v\n", err)
 		h.sendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	defer file.Close()
-	defer os.Remove(tempFile)
 
-	fmt.Printf("[IdentificarDocente] ✓ Imagen guardada en: %s\n", tempFile)
-
-	rec, err := recognition.NewRecognizer()
-	if err != nil {
-		fmt.Printf("[IdentificarDocente] ERROR inicializando reconocedor: %v\n", err)
-		h.sendError(w, http.StatusInternalServerError, "Error al inicializar reconocedor facial")
-		return
+	intento := &entities.IntentoReconocimiento{
+		FechaHora: h.clock.Now(),
+		Modo:      entities.ModoIdentificacion,
+		Terminal:  terminalDePeticion(r, imagen.Terminal),
+	}
+	if claims := getUserClaims(r); claims != nil {
+		intento.OperadorID = &claims.UserID
 	}
-	defer rec.Close()
-
-	fmt.Println("[IdentificarDocente] ✓ Reconocedor inicializado")
-	fmt.Println("[IdentificarDocente] → Detectando rostros en la imagen...")
 
-	faces, err := rec.RecognizeFile(tempFile)
+	faces, err := h.engine.Detect(imagen.Datos)
 	if err != nil {
-		fmt.Printf("[IdentificarDocente] ERROR al detectar rostros: %v\n", err)
+		log.Printf("[ERROR] Error detectando rostros para identificación: %v", err)
+		h.registrarIntentoFallido(intento, fmt.Sprintf("Error al procesar imagen: %v", err))
 		h.sendError(w, http.StatusInternalServerError, fmt.Sprintf("Error al procesar imagen: %v", err))
 		return
 	}
 
-	fmt.Printf("[IdentificarDocente] ✓ Rostros detectados: %d\n", len(faces))
+	intento.RostrosDetectados = len(faces)
 
 	if len(faces) == 0 {
-		fmt.Println("[IdentificarDocente] ✗ No se detectaron rostros, retornando respuesta vacía")
+		intento.Decision = entities.DecisionSinRostro
+		h.registrarIntento(intento)
 		h.sendJSON(w, http.StatusOK, ApiResponse{
 			Message: "No se detectaron rostros en la imagen",
 			Data:    nil,
@@ -148,139 +143,250 @@ func (h *ReconocimientoHandler) IdentificarDocente(w http.ResponseWriter, r *htt
 	}
 
 	capturedFace := recognition.GetBiggerFace(faces)
-	fmt.Println("[IdentificarDocente] ✓ Rostro más grande seleccionado para comparación")
 
-	fmt.Println("[IdentificarDocente] → Obteniendo lista de docentes...")
 	docentes, err := h.docenteRepo.FindAll()
 	if err != nil {
-		fmt.Printf("[IdentificarDocente] ERROR obteniendo docentes: %v\n", err)
+		log.Printf("[ERROR] Error obteniendo docentes para identificación: %v", err)
+		h.registrarIntentoFallido(intento, "Error al obtener docentes")
 		h.sendError(w, http.StatusInternalServerError, "Error al obtener docentes")
 		return
 	}
 
-	fmt.Printf("[IdentificarDocente] ✓ Total de docentes en BD: %d\n", len(docentes))
-
-	// Umbral mínimo de coincidencias requeridas (al menos 3 de los descriptores deben coincidir)
-	const minMatchesRequired = 3
-
-	var matchedDocente *struct {
-		ID                 int     `json:"id"`
-		DocumentoIdentidad int64   `json:"documento_identidad"`
-		NombreCompleto     string  `json:"nombre_completo"`
-		MatchCount         int     `json:"match_count"`
-		TotalDescriptors   int     `json:"total_descriptors"`
-		Distance           float32 `json:"distance"` // Mejor distancia encontrada
+	muestras, err := h.index.AllDescriptors()
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo descriptores faciales para identificación: %v", err)
+		h.registrarIntentoFallido(intento, "Error al obtener descriptores faciales")
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener descriptores faciales")
+		return
 	}
-	bestMatchCount := 0
-	totalComparisons := 0
-	docentesConDescriptores := 0
 
-	fmt.Println("[IdentificarDocente] → Iniciando comparación con descriptores almacenados...")
-	fmt.Printf("[IdentificarDocente]   Umbral de distancia: < 0.25 (tolerance)\n")
-	fmt.Printf("[IdentificarDocente]   Mínimo de coincidencias requerido: %d matches\n", minMatchesRequired)
+	var matchedDocente *docenteIdentificado
+	candidatos := []entities.CandidatoReconocimiento{}
 
 	for _, docente := range docentes {
-		if docente.FaceDescriptors == nil || *docente.FaceDescriptors == "" || *docente.FaceDescriptors == "[]" {
+		descriptores := muestras[docente.ID]
+		if len(descriptores) == 0 {
 			continue
 		}
 
-		docentesConDescriptores++
-		fmt.Printf("[IdentificarDocente]   Comparando con: %s (CI: %d)\n", docente.NombreCompleto, docente.DocumentoIdentidad)
+		matchCount, minDistance := recognition.CountMatches(capturedFace, descriptores)
 
-		// Obtener descriptores usando el método del repositorio que maneja correctamente el JSONB
-		descriptorsJSON, err := h.docenteRepo.GetFaceDescriptors(docente.ID)
-		if err != nil {
-			fmt.Printf("[IdentificarDocente]   ERROR obteniendo descriptores: %v\n", err)
-			continue
+		if matchCount > 0 {
+			candidatos = append(candidatos, entities.CandidatoReconocimiento{
+				DocenteID:         docente.ID,
+				NombreCompleto:    docente.NombreCompleto,
+				Coincidencias:     matchCount,
+				TotalDescriptores: len(descriptores),
+				MejorDistancia:    minDistance,
+			})
 		}
 
-		fmt.Printf("[IdentificarDocente]   Total descriptores del docente: %d\n", len(descriptorsJSON))
-
-		// Contar coincidencias para este docente
-		matchCount := 0
-		minDistance := float32(999999.0)
-
-		// Comparar con cada descriptor del docente
-		for i, descJSON := range descriptorsJSON {
-			totalComparisons++
-			storedDesc, err := recognition.JSONToDescriptor(descJSON)
-			if err != nil {
-				fmt.Printf("[IdentificarDocente]   ERROR parseando descriptor %d: %v\n", i+1, err)
-				continue
-			}
-
-			distance := recognition.CompareFaces(capturedFace, storedDesc)
-			isSame := recognition.IsSamePerson(capturedFace, storedDesc)
-
-			fmt.Printf("[IdentificarDocente]   Descriptor %d/%d: distancia=%.4f, coincide=%v\n",
-				i+1, len(descriptorsJSON), distance, isSame)
-
-			if isSame {
-				matchCount++
-				if distance < minDistance {
-					minDistance = distance
-				}
-				fmt.Printf("[IdentificarDocente]   ✓ Coincidencia %d registrada (distancia: %.4f)\n", matchCount, distance)
+		// Solo considerar como match válido si tiene al menos MinMatchesRequired coincidencias
+		if matchCount >= recognition.MinMatchesRequired && (matchedDocente == nil || matchCount > matchedDocente.MatchCount) {
+			matchedDocente = &docenteIdentificado{
+				ID:                 docente.ID,
+				DocumentoIdentidad: docente.DocumentoIdentidad,
+				NombreCompleto:     docente.NombreCompleto,
+				MatchCount:         matchCount,
+				TotalDescriptors:   len(descriptores),
+				Distance:           minDistance,
 			}
 		}
+	}
 
-		fmt.Printf("[IdentificarDocente]   Resultado: %d/%d coincidencias (%.1f%%)\n",
-			matchCount, len(descriptorsJSON), float32(matchCount)/float32(len(descriptorsJSON))*100)
-
-		// Solo considerar como match válido si tiene al menos minMatchesRequired coincidencias
-		if matchCount >= minMatchesRequired {
-			if matchCount > bestMatchCount {
-				bestMatchCount = matchCount
-				matchedDocente = &struct {
-					ID                 int     `json:"id"`
-					DocumentoIdentidad int64   `json:"documento_identidad"`
-					NombreCompleto     string  `json:"nombre_completo"`
-					MatchCount         int     `json:"match_count"`
-					TotalDescriptors   int     `json:"total_descriptors"`
-					Distance           float32 `json:"distance"` // Mejor distancia encontrada
-				}{
-					ID:                 docente.ID,
-					DocumentoIdentidad: docente.DocumentoIdentidad,
-					NombreCompleto:     docente.NombreCompleto,
-					MatchCount:         matchCount,
-					TotalDescriptors:   len(descriptorsJSON),
-					Distance:           minDistance,
-				}
-				fmt.Printf("[IdentificarDocente]   ★★★ NUEVA MEJOR COINCIDENCIA: %s (%d/%d matches, %.1f%%, mejor distancia: %.4f)\n",
-					docente.NombreCompleto, matchCount, len(descriptorsJSON),
-					float32(matchCount)/float32(len(descriptorsJSON))*100, minDistance)
-			}
-		} else {
-			fmt.Printf("[IdentificarDocente]   ✗ Descartado: solo %d/%d coincidencias (mínimo requerido: %d)\n",
-				matchCount, len(descriptorsJSON), minMatchesRequired)
+	// Guardar solo los mejores candidatos: más coincidencias primero y, a igualdad, menor distancia
+	sort.Slice(candidatos, func(i, j int) bool {
+		if candidatos[i].Coincidencias != candidatos[j].Coincidencias {
+			return candidatos[i].Coincidencias > candidatos[j].Coincidencias
 		}
+		return candidatos[i].MejorDistancia < candidatos[j].MejorDistancia
+	})
+	if len(candidatos) > maxCandidatosRegistrados {
+		candidatos = candidatos[:maxCandidatosRegistrados]
 	}
-
-	fmt.Printf("[IdentificarDocente] ✓ Comparación completada\n")
-	fmt.Printf("[IdentificarDocente]   Docentes con descriptores: %d/%d\n", docentesConDescriptores, len(docentes))
-	fmt.Printf("[IdentificarDocente]   Total comparaciones realizadas: %d\n", totalComparisons)
+	intento.Candidatos = candidatos
 
 	if matchedDocente == nil {
-		fmt.Println("[IdentificarDocente] ✗ NO SE ENCONTRÓ COINCIDENCIA (ningún docente alcanzó el mínimo de coincidencias)")
+		intento.Decision = entities.DecisionNoIdentificado
+		h.registrarIntento(intento)
 		h.sendJSON(w, http.StatusOK, ApiResponse{
 			Message: "No se encontró ningún docente con ese rostro",
 			Data:    nil,
 		})
-		fmt.Println("═══════════════════════════════════════════════════════════════")
 		return
 	}
 
-	fmt.Println("[IdentificarDocente] ✓✓✓ DOCENTE IDENTIFICADO EXITOSAMENTE ✓✓✓")
-	fmt.Printf("[IdentificarDocente]   Nombre: %s\n", matchedDocente.NombreCompleto)
-	fmt.Printf("[IdentificarDocente]   CI: %d\n", matchedDocente.DocumentoIdentidad)
-	fmt.Printf("[IdentificarDocente]   Coincidencias: %d/%d (%.1f%%)\n", matchedDocente.MatchCount, matchedDocente.TotalDescriptors, float32(matchedDocente.MatchCount)/float32(matchedDocente.TotalDescriptors)*100)
-	fmt.Printf("[IdentificarDocente]   Mejor distancia (< 0.25): %.4f\n", matchedDocente.Distance)
+	intento.Decision = entities.DecisionIdentificado
+	intento.DocenteID = &matchedDocente.ID
+	h.registrarIntento(intento)
+	matchedDocente.IntentoID = intento.ID
+	h.guardarEvidencia(intento, imagen.Datos, capturedFace)
 
 	h.sendJSON(w, http.StatusOK, ApiResponse{Data: matchedDocente})
-	fmt.Println("═══════════════════════════════════════════════════════════════")
+}
+
+// guardarEvidencia guarda el recorte del rostro de una identificación exitosa, para revisar
+// después el registro que resulte de ella. Un fallo no interrumpe la respuesta
+func (h *ReconocimientoHandler) guardarEvidencia(intento *entities.IntentoReconocimiento, imagen []byte, rostro recognition.FaceDescriptor) {
+	if !h.evidenciaUseCase.CapturaHabilitada() || intento.ID == 0 || intento.DocenteID == nil {
+		return
+	}
+
+	recorte, err := recognition.CropFace(imagen, rostro.Rectangle)
+	if err == nil {
+		err = h.evidenciaUseCase.Guardar(intento.ID, *intento.DocenteID, recorte)
+	}
+	if err != nil {
+		log.Printf("[ERROR] No se pudo guardar la evidencia del intento %d: %v", intento.ID, err)
+	}
+}
+
+// registrarIntento persiste un intento de identificación sin interrumpir la respuesta si falla
+func (h *ReconocimientoHandler) registrarIntento(intento *entities.IntentoReconocimiento) {
+	if err := h.intentoUseCase.Registrar(intento); err != nil {
+		log.Printf("[ERROR] No se pudo registrar el intento de reconocimiento: %v", err)
+	}
+}
+
+// registrarIntentoFallido persiste un intento que terminó en error
+func (h *ReconocimientoHandler) registrarIntentoFallido(intento *entities.IntentoReconocimiento, detalle string) {
+	intento.Decision = entities.DecisionError
+	intento.Detalle = &detalle
+	h.registrarIntento(intento)
+}
+
+// terminalDePeticion identifica la terminal que originó la petición
+// Se usa el campo "terminal" enviado con la imagen, el header X-Terminal-ID o la dirección remota
+func terminalDePeticion(r *http.Request, declarada string) string {
+	terminal := strings.TrimSpace(declarada)
+	if terminal == "" {
+		terminal = strings.TrimSpace(r.Header.Get("X-Terminal-ID"))
+	}
+	if terminal == "" {
+		terminal = r.RemoteAddr
+		if idx := strings.LastIndex(terminal, ":"); idx != -1 {
+			terminal = terminal[:idx]
+		}
+	}
+	if len(terminal) > security.MaxCodigoLength {
+		terminal = terminal[:security.MaxCodigoLength]
+	}
+	return terminal
+}
+
+// VerificarDocente compara el rostro capturado únicamente con las muestras del docente declarado (1:1)
+// Si la verificación es exitosa se emite un token de corta duración que puede exigirse al registrar el ingreso
+func (h *ReconocimientoHandler) VerificarDocente(w http.ResponseWriter, r *http.Request) {
+	imagen, err := h.leerImagen(w, r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ci, err := security.ValidateCIString(imagen.CI)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "CI inválido")
+		return
+	}
+
+	docente, err := h.docenteRepo.FindByCI(ci)
+	if err != nil || !docente.Activo {
+		h.sendError(w, http.StatusNotFound, "Docente no encontrado")
+		return
+	}
+
+	descriptores, err := h.index.Descriptors(docente.ID)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo descriptores del docente %d: %v", docente.ID, err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener descriptores faciales")
+		return
+	}
+	if len(descriptores) == 0 {
+		h.sendError(w, http.StatusBadRequest, "El docente no tiene rostro registrado")
+		return
+	}
+
+	intento := &entities.IntentoReconocimiento{
+		FechaHora: h.clock.Now(),
+		Modo:      entities.ModoVerificacion,
+		Terminal:  terminalDePeticion(r, imagen.Terminal),
+	}
+	if claims := getUserClaims(r); claims != nil {
+		intento.OperadorID = &claims.UserID
+	}
+
+	faces, err := h.engine.Detect(imagen.Datos)
+	if err != nil {
+		h.registrarIntentoFallido(intento, fmt.Sprintf("Error al procesar imagen: %v", err))
+		h.sendError(w, http.StatusInternalServerError, fmt.Sprintf("Error al procesar imagen: %v", err))
+		return
+	}
+
+	intento.RostrosDetectados = len(faces)
+	if len(faces) == 0 {
+		intento.Decision = entities.DecisionSinRostro
+		h.registrarIntento(intento)
+		h.sendError(w, http.StatusBadRequest, "No se detectaron rostros en la imagen")
+		return
+	}
+
+	capturedFace := recognition.GetBiggerFace(faces)
+	matchCount, minDistance := recognition.CountMatches(capturedFace, descriptores)
+	verificado := recognition.IsIdentityMatch(matchCount, len(descriptores))
+
+	intento.Candidatos = []entities.CandidatoReconocimiento{{
+		DocenteID:         docente.ID,
+		NombreCompleto:    docente.NombreCompleto,
+		Coincidencias:     matchCount,
+		TotalDescriptores: len(descriptores),
+		MejorDistancia:    minDistance,
+	}}
+	if verificado {
+		intento.Decision = entities.DecisionIdentificado
+		intento.DocenteID = &docente.ID
+	} else {
+		intento.Decision = entities.DecisionNoIdentificado
+	}
+	h.registrarIntento(intento)
+	if verificado {
+		h.guardarEvidencia(intento, imagen.Datos, capturedFace)
+	}
+
+	resultado := dto.VerificacionFacialResponse{
+		DocenteID:        docente.ID,
+		NombreCompleto:   docente.NombreCompleto,
+		Verificado:       verificado,
+		Score:            float32(matchCount) / float32(len(descriptores)),
+		MatchCount:       matchCount,
+		TotalDescriptors: len(descriptores),
+		Distance:         minDistance,
+		IntentoID:        intento.ID,
+	}
+
+	if !verificado {
+		log.Printf("[SECURITY] Verificación facial fallida para docente %d (%d/%d coincidencias)",
+			docente.ID, matchCount, len(descriptores))
+		h.sendJSON(w, http.StatusOK, ApiResponse{
+			Message: "El rostro no coincide con el docente indicado",
+			Data:    resultado,
+		})
+		return
+	}
+
+	token, err := jwt.GenerateVerificationToken(docente.ID, intento.ID)
+	if err != nil {
+		log.Printf("[ERROR] Error generando token de verificación: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al generar token de verificación")
+		return
+	}
+	resultado.VerificacionToken = token
+	resultado.ExpiraEn = int(jwt.VerificationTokenExpiration.Seconds())
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: resultado})
 }
 
 // RegistrarRostroDocente registra el descriptor facial de un docente (soporta múltiples fotos)
+// Cada imagen pasa por un control de calidad y la respuesta incluye el veredicto de cada una
 func (h *ReconocimientoHandler) RegistrarRostroDocente(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	docenteID, err := strconv.Atoi(vars["id"])
@@ -295,184 +401,368 @@ func (h *ReconocimientoHandler) RegistrarRostroDocente(w http.ResponseWriter, r
 		return
 	}
 
-	// SEGURIDAD: Límite de 20MB total (máx 5 imágenes de 4MB cada una)
-	err = r.ParseMultipartForm(20 << 20)
-	if err != nil {
-		h.sendError(w, http.StatusBadRequest, "Error al parsear form o archivo demasiado grande")
+	// Sin consentimiento biométrico vigente no se guarda ningún rostro
+	if !h.consentimientoUseCase.TieneConsentimiento(docenteID) {
+		h.sendError(w, http.StatusForbidden, "El docente no tiene un consentimiento biométrico vigente. Regístrelo antes de enrolar su rostro")
 		return
 	}
 
-	// Obtener múltiples archivos
-	files := r.MultipartForm.File["images"]
+	imagenes, err := h.leerImagenesEnrolamiento(w, r)
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if len(files) == 0 {
+	if len(imagenes) == 0 {
 		h.sendError(w, http.StatusBadRequest, "No se encontraron imágenes en la petición")
 		return
 	}
 
-	// SEGURIDAD: Limitar número de archivos
-	const maxImages = 10
-	if len(files) > maxImages {
-		h.sendError(w, http.StatusBadRequest, fmt.Sprintf("Máximo %d imágenes permitidas", maxImages))
+	// SEGURIDAD: Limitar número de imágenes
+	if len(imagenes) > maxEnrollmentImages {
+		h.sendError(w, http.StatusBadRequest, fmt.Sprintf("Máximo %d imágenes permitidas", maxEnrollmentImages))
 		return
 	}
 
-	if len(files) < 3 {
+	if len(imagenes) < 3 {
 		h.sendError(w, http.StatusBadRequest, "Se requieren al menos 3 fotos del docente")
 		return
 	}
 
-	// SEGURIDAD: Validar tamaño individual de cada archivo (máx 4MB por imagen)
-	const maxImageSize = 4 << 20 // 4MB
-	for _, fileHeader := range files {
-		if fileHeader.Size > maxImageSize {
-			h.sendError(w, http.StatusBadRequest, "Cada imagen debe ser menor a 4MB")
-			return
-		}
+	// Muestras ya registradas del docente (referencia para detectar fotos atípicas)
+	muestrasDocente, err := h.index.Descriptors(docenteID)
+	if err != nil {
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener descriptores del docente")
+		return
 	}
 
-	rec, err := recognition.NewRecognizer()
+	// Muestras de los demás docentes (para evitar enrolar a la misma persona con dos identidades)
+	otrosDocentes, err := h.cargarDescriptoresOtrosDocentes(docenteID)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, "Error al inicializar reconocedor facial")
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener docentes")
 		return
 	}
-	defer rec.Close()
 
-	tempDir := "./temp"
-	os.MkdirAll(tempDir, 0755)
+	veredictos := make([]dto.VeredictoImagen, len(imagenes))
+	candidatos := make(map[int]recognition.FaceDescriptor)
+	hayDuplicado := false
 
-	facesProcessed := 0
-	for i, fileHeader := range files {
-		// Validar extensión
-		if !validateImageExtension(fileHeader.Filename) {
-			log.Printf("[SECURITY] Archivo con extensión no permitida ignorado: %s", fileHeader.Filename)
-			continue
+	for i, imagen := range imagenes {
+		veredicto := dto.VeredictoImagen{
+			Indice:  i,
+			Archivo: imagen.Nombre,
 		}
 
-		file, err := fileHeader.Open()
+		faces, err := h.detectarRostrosEnImagen(imagen)
 		if err != nil {
+			veredicto.Veredicto = string(recognition.VerdictInvalid)
+			veredicto.Motivo = err.Error()
+			veredictos[i] = veredicto
 			continue
 		}
-		defer file.Close()
-
-		// Generar nombre seguro
-		safeFilename := generateSafeFilename(fmt.Sprintf("docente_%d_photo_%d", docenteID, i), filepath.Ext(fileHeader.Filename))
-		tempFile := filepath.Join(tempDir, safeFilename)
-		dst, err := os.Create(tempFile)
-		if err != nil {
-			continue
+		veredicto.RostrosDetectados = len(faces)
+
+		switch {
+		case len(faces) == 0:
+			veredicto.Veredicto = string(recognition.VerdictNoFace)
+			veredicto.Motivo = "No se detectó ningún rostro"
+		case len(faces) > 1:
+			veredicto.Veredicto = string(recognition.VerdictMultipleFaces)
+			veredicto.Motivo = "La imagen debe contener un solo rostro"
+		case recognition.IsFaceTooSmall(faces[0]):
+			veredicto.Veredicto = string(recognition.VerdictFaceTooSmall)
+			veredicto.Motivo = fmt.Sprintf("El rostro debe medir al menos %dx%d píxeles", recognition.MinFaceSize, recognition.MinFaceSize)
+		default:
+			if otroID, distancia, ok := buscarDocenteCoincidente(faces[0], otrosDocentes); ok {
+				veredicto.Veredicto = string(recognition.VerdictDuplicate)
+				veredicto.Motivo = "El rostro coincide con otro docente registrado"
+				veredicto.DocenteDuplicadoID = &otroID
+				veredicto.Distancia = &distancia
+				hayDuplicado = true
+				log.Printf("[SECURITY] Enrolamiento de docente %d rechazado: rostro coincide con docente %d", docenteID, otroID)
+			} else {
+				veredicto.Veredicto = string(recognition.VerdictAccepted)
+				candidatos[i] = faces[0]
+			}
 		}
 
-		_, err = io.Copy(dst, file)
-		dst.Close()
-		if err != nil {
-			os.Remove(tempFile)
-			continue
+		veredictos[i] = veredicto
+	}
+
+	// Detectar fotos atípicas comparando cada candidata con las muestras existentes
+	// y con las demás fotos aceptadas del mismo lote
+	atipicas := []int{}
+	for i, candidato := range candidatos {
+		referencias := append([]recognition.FaceDescriptor{}, muestrasDocente...)
+		for j, otro := range candidatos {
+			if j != i {
+				referencias = append(referencias, otro)
+			}
 		}
+		if recognition.IsOutlier(candidato, referencias) {
+			atipicas = append(atipicas, i)
+		}
+	}
+	for _, i := range atipicas {
+		delete(candidatos, i)
+		veredictos[i].Veredicto = string(recognition.VerdictOutlier)
+		veredictos[i].Motivo = "El rostro difiere demasiado de las demás fotos del docente"
+	}
 
-		faces, err := rec.RecognizeFile(tempFile)
-		os.Remove(tempFile)
+	if hayDuplicado {
+		h.sendJSON(w, http.StatusConflict, ApiResponse{
+			Error: "Una o más fotos coinciden con otro docente registrado. No se registró ningún rostro",
+			Data:  veredictos,
+		})
+		return
+	}
 
-		if err != nil {
-			continue
-		}
+	if len(candidatos) < 3 {
+		h.sendJSON(w, http.StatusBadRequest, ApiResponse{
+			Error: fmt.Sprintf("Solo %d fotos superaron el control de calidad. Se requieren al menos 3 fotos válidas con un solo rostro visible", len(candidatos)),
+			Data:  veredictos,
+		})
+		return
+	}
+
+	// Las muestras de un modelo anterior ya no sirven una vez que el docente se re-enrola
+	if err := h.docenteRepo.ClearOutdatedFaceDescriptors(docenteID); err != nil {
+		log.Printf("[WARN] No se pudieron eliminar las muestras obsoletas del docente %d: %v", docenteID, err)
+	}
 
-		if len(faces) == 0 {
+	facesProcessed := 0
+	for i := range veredictos {
+		candidato, ok := candidatos[i]
+		if !ok {
 			continue
 		}
 
-		biggestFace := recognition.GetBiggerFace(faces)
-		descriptorJSON, err := recognition.DescriptorToJSON(biggestFace)
-		if err != nil {
-			continue
+		// La calidad se mide contra las muestras existentes y el resto del lote
+		referencias := append([]recognition.FaceDescriptor{}, muestrasDocente...)
+		for j, otro := range candidatos {
+			if j != i {
+				referencias = append(referencias, otro)
+			}
 		}
+		calidad := recognition.QualityScore(candidato, referencias)
 
-		// Agregar descriptor al array en la BD
-		err = h.docenteRepo.AddFaceDescriptor(docenteID, descriptorJSON)
+		_, err := h.index.Add(docenteID, candidato, h.engine.ModelVersion(), &calidad, entities.OrigenEnrolamiento)
 		if err != nil {
+			veredictos[i].Veredicto = string(recognition.VerdictInvalid)
+			veredictos[i].Motivo = "Error al guardar el descriptor"
 			continue
 		}
 
 		facesProcessed++
 	}
 
-	if facesProcessed == 0 {
-		h.sendError(w, http.StatusBadRequest, "No se pudo procesar ninguna foto válida")
-		return
-	}
-
-	if facesProcessed < 3 {
-		h.sendError(w, http.StatusBadRequest, fmt.Sprintf("Solo se procesaron %d fotos. Se requieren al menos 3 fotos válidas con rostros visibles", facesProcessed))
-		return
-	}
-
 	h.sendJSON(w, http.StatusOK, ApiResponse{
 		Message: fmt.Sprintf("Rostros registrados exitosamente (%d fotos procesadas)", facesProcessed),
 		Data: map[string]interface{}{
 			"docente_id":      docenteID,
 			"faces_processed": facesProcessed,
+			"imagenes":        veredictos,
 		},
 	})
 }
 
-// Helper methods
-func (h *ReconocimientoHandler) processUploadedImage(r *http.Request) (io.ReadCloser, *multipart.FileHeader, string, error) {
-	// SEGURIDAD: Límite de 5MB para una sola imagen
-	err := r.ParseMultipartForm(5 << 20)
+// detectarRostrosEnImagen normaliza una imagen del enrolamiento y detecta sus rostros
+func (h *ReconocimientoHandler) detectarRostrosEnImagen(imagen imagenRecibida) ([]recognition.FaceDescriptor, error) {
+	if imagen.Err != nil {
+		return nil, imagen.Err
+	}
+
+	datos, err := recognition.PrepareImage(imagen.Datos)
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("error al parsear form o archivo demasiado grande")
+		log.Printf("[SECURITY] Imagen de enrolamiento rechazada (%s): %v", imagen.Nombre, err)
+		return nil, err
 	}
 
-	file, header, err := r.FormFile("image")
+	faces, err := h.engine.Detect(datos)
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("no se encontró imagen en la petición")
+		return nil, fmt.Errorf("no se pudo procesar la imagen")
 	}
+	return faces, nil
+}
 
-	// SEGURIDAD: Validar tamaño del archivo (máx 4MB)
-	const maxImageSize = 4 << 20 // 4MB
-	if header.Size > maxImageSize {
-		file.Close()
-		return nil, nil, "", fmt.Errorf("imagen demasiado grande (máx 4MB)")
+// cargarDescriptoresOtrosDocentes obtiene los descriptores de todos los docentes con rostro registrado,
+// excepto el indicado
+func (h *ReconocimientoHandler) cargarDescriptoresOtrosDocentes(excluirID int) (map[int][]recognition.FaceDescriptor, error) {
+	resultado, err := h.index.AllDescriptors()
+	if err != nil {
+		return nil, err
 	}
+	delete(resultado, excluirID)
+	return resultado, nil
+}
 
-	// Validar extensión de archivo
-	if !validateImageExtension(header.Filename) {
-		file.Close()
-		log.Printf("[SECURITY] Intento de subir archivo con extensión no permitida: %s", header.Filename)
-		return nil, nil, "", fmt.Errorf("tipo de archivo no permitido")
+// buscarDocenteCoincidente retorna el docente cuyas muestras coinciden con el rostro, si existe
+func buscarDocenteCoincidente(face recognition.FaceDescriptor, docentes map[int][]recognition.FaceDescriptor) (int, float32, bool) {
+	for docenteID, muestras := range docentes {
+		matches, distancia := recognition.CountMatches(face, muestras)
+		if recognition.IsIdentityMatch(matches, len(muestras)) {
+			return docenteID, distancia, true
+		}
 	}
+	return 0, 0, false
+}
 
-	tempDir := "./temp"
-	os.MkdirAll(tempDir, 0755)
+// imagenRecibida es una imagen leída de la petición junto con los campos que la acompañan
+type imagenRecibida struct {
+	Nombre   string
+	Datos    []byte
+	Err      error // Error al leer esta imagen en particular (enrolamiento)
+	CI       string
+	Terminal string
+}
 
-	// Generar nombre de archivo seguro (evita path traversal)
-	safeFilename := generateSafeFilename("upload", filepath.Ext(header.Filename))
-	tempFile := filepath.Join(tempDir, safeFilename)
+// leerImagen obtiene y normaliza la imagen de la petición sin escribir nada a disco.
+// Acepta multipart/form-data (archivo "image" o campo "imagen" con una data URL) y
+// JSON {"imagen": "data:image/jpeg;base64,..."} como el que envía la webcam del navegador
+func (h *ReconocimientoHandler) leerImagen(w http.ResponseWriter, r *http.Request) (*imagenRecibida, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageRequestSize)
+	imagen := &imagenRecibida{}
+
+	if esPeticionJSON(r) {
+		var req dto.ImagenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, fmt.Errorf("datos inválidos o imagen demasiado grande")
+		}
+		datos, err := decodificarDataURL(req.Imagen)
+		if err != nil {
+			return nil, err
+		}
+		imagen.Datos = datos
+		imagen.CI = req.CI.String()
+		imagen.Terminal = req.Terminal
+	} else {
+		// maxMemory igual al límite del cuerpo: el formulario nunca se vuelca a archivos temporales
+		if err := r.ParseMultipartForm(maxImageRequestSize); err != nil {
+			return nil, fmt.Errorf("error al parsear form o archivo demasiado grande")
+		}
+		imagen.CI = r.FormValue("ci")
+		imagen.Terminal = r.FormValue("terminal")
 
-	// Verificar que el path está dentro del directorio temporal
-	absPath, _ := filepath.Abs(tempFile)
-	absTempDir, _ := filepath.Abs(tempDir)
-	if !strings.HasPrefix(absPath, absTempDir) {
-		file.Close()
-		log.Printf("[SECURITY] Intento de path traversal detectado: %s", header.Filename)
-		return nil, nil, "", fmt.Errorf("nombre de archivo inválido")
+		if dataURL := r.FormValue("imagen"); dataURL != "" {
+			datos, err := decodificarDataURL(dataURL)
+			if err != nil {
+				return nil, err
+			}
+			imagen.Datos = datos
+		} else {
+			files := r.MultipartForm.File["image"]
+			if len(files) == 0 {
+				return nil, fmt.Errorf("no se encontró imagen en la petición")
+			}
+			datos, err := leerArchivoImagen(files[0])
+			if err != nil {
+				return nil, err
+			}
+			imagen.Nombre = files[0].Filename
+			imagen.Datos = datos
+		}
 	}
 
-	dst, err := os.Create(tempFile)
+	datos, err := recognition.PrepareImage(imagen.Datos)
 	if err != nil {
-		file.Close()
-		return nil, nil, "", fmt.Errorf("error al guardar imagen temporal")
+		log.Printf("[SECURITY] Imagen rechazada: %v", err)
+		return nil, err
 	}
+	imagen.Datos = datos
+	return imagen, nil
+}
+
+// leerImagenesEnrolamiento obtiene las fotos de enrolamiento sin escribir nada a disco.
+// Acepta multipart/form-data con archivos "images" o JSON {"imagenes": ["data:image/...", ...]}.
+// Un error en una imagen individual se guarda en la imagen para reportarlo en su veredicto
+func (h *ReconocimientoHandler) leerImagenesEnrolamiento(w http.ResponseWriter, r *http.Request) ([]imagenRecibida, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxEnrollmentRequestSize)
 
-	_, err = io.Copy(dst, file)
-	dst.Close()
+	if esPeticionJSON(r) {
+		var req dto.EnrolamientoRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, fmt.Errorf("datos inválidos o imágenes demasiado grandes")
+		}
+		imagenes := make([]imagenRecibida, len(req.Imagenes))
+		for i, dataURL := range req.Imagenes {
+			imagenes[i].Nombre = fmt.Sprintf("imagen_%d", i+1)
+			imagenes[i].Datos, imagenes[i].Err = decodificarDataURL(dataURL)
+		}
+		return imagenes, nil
+	}
+
+	// maxMemory igual al límite del cuerpo: el formulario nunca se vuelca a archivos temporales
+	if err := r.ParseMultipartForm(maxEnrollmentRequestSize); err != nil {
+		return nil, fmt.Errorf("error al parsear form o archivo demasiado grande")
+	}
+
+	files := r.MultipartForm.File["images"]
+	imagenes := make([]imagenRecibida, len(files))
+	for i, fileHeader := range files {
+		imagenes[i].Nombre = fileHeader.Filename
+		imagenes[i].Datos, imagenes[i].Err = leerArchivoImagen(fileHeader)
+	}
+	return imagenes, nil
+}
+
+// esPeticionJSON indica si el cuerpo de la petición es JSON
+func esPeticionJSON(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+// leerArchivoImagen lee en memoria un archivo del formulario validando su tamaño
+func leerArchivoImagen(fileHeader *multipart.FileHeader) ([]byte, error) {
+	// SEGURIDAD: Validar tamaño del archivo (máx 4MB)
+	if fileHeader.Size > maxImageSize {
+		return nil, fmt.Errorf("imagen demasiado grande (máx 4MB)")
+	}
+
+	file, err := fileHeader.Open()
 	if err != nil {
-		file.Close()
-		os.Remove(tempFile)
-		return nil, nil, "", fmt.Errorf("error al copiar imagen")
+		return nil, fmt.Errorf("no se pudo leer la imagen")
 	}
+	defer file.Close()
 
-	return file, header, tempFile, nil
+	datos, err := io.ReadAll(io.LimitReader(file, maxImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer la imagen")
+	}
+	if len(datos) > maxImageSize {
+		return nil, fmt.Errorf("imagen demasiado grande (máx 4MB)")
+	}
+	return datos, nil
+}
+
+// decodificarDataURL decodifica una imagen en base64, con o sin el prefijo "data:image/...;base64,"
+// El tipo declarado se ignora: el formato real se verifica luego por sus magic bytes
+func decodificarDataURL(dataURL string) ([]byte, error) {
+	contenido := strings.TrimSpace(dataURL)
+	if contenido == "" {
+		return nil, fmt.Errorf("no se encontró imagen en la petición")
+	}
+
+	if strings.HasPrefix(contenido, "data:") {
+		coma := strings.Index(contenido, ",")
+		if coma == -1 || !strings.HasSuffix(contenido[:coma], ";base64") {
+			return nil, fmt.Errorf("data URL inválida: se espera codificación base64")
+		}
+		contenido = contenido[coma+1:]
+	}
+
+	// SEGURIDAD: Validar tamaño antes de decodificar (máx 4MB)
+	if base64.StdEncoding.DecodedLen(len(contenido)) > maxImageSize+3 {
+		return nil, fmt.Errorf("imagen demasiado grande (máx 4MB)")
+	}
+
+	datos, err := base64.StdEncoding.DecodeString(contenido)
+	if err != nil {
+		return nil, fmt.Errorf("imagen base64 inválida")
+	}
+	if len(datos) > maxImageSize {
+		return nil, fmt.Errorf("imagen demasiado grande (máx 4MB)")
+	}
+	return datos, nil
 }
 
 func (h *ReconocimientoHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -485,7 +775,8 @@ func (h *ReconocimientoHandler) sendError(w http.ResponseWriter, status int, mes
 	h.sendJSON(w, status, ApiResponse{Error: message})
 }
 
-// ObtenerDescriptoresDocente obtiene los descriptores faciales de un docente
+// ObtenerDescriptoresDocente obtiene los metadatos de las muestras faciales de un docente
+// Los descriptores nunca salen del índice de reconocimiento
 func (h *ReconocimientoHandler) ObtenerDescriptoresDocente(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	docenteID, err := strconv.Atoi(vars["id"])
@@ -494,22 +785,31 @@ func (h *ReconocimientoHandler) ObtenerDescriptoresDocente(w http.ResponseWriter
 		return
 	}
 
-	descriptors, err := h.docenteRepo.GetFaceDescriptors(docenteID)
+	rostros, err := h.docenteRepo.GetFaceDescriptors(docenteID)
 	if err != nil {
 		h.sendError(w, http.StatusInternalServerError, "Error al obtener descriptores")
 		return
 	}
 
+	obsoletos := 0
+	for _, rostro := range rostros {
+		if rostro.RequiereReenrolamiento {
+			obsoletos++
+		}
+	}
+
 	h.sendJSON(w, http.StatusOK, ApiResponse{
 		Data: map[string]interface{}{
-			"docente_id":  docenteID,
-			"count":       len(descriptors),
-			"descriptors": descriptors,
+			"docente_id":              docenteID,
+			"count":                   len(rostros),
+			"requiere_reenrolamiento": obsoletos,
+			"modelo_version":          h.engine.ModelVersion(),
+			"descriptors":             rostros,
 		},
 	})
 }
 
-// EliminarDescriptorDocente elimina un descriptor facial específico
+// EliminarDescriptorDocente elimina un descriptor facial específico por su ID
 func (h *ReconocimientoHandler) EliminarDescriptorDocente(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	docenteID, err := strconv.Atoi(vars["id"])
@@ -518,16 +818,15 @@ func (h *ReconocimientoHandler) EliminarDescriptorDocente(w http.ResponseWriter,
 		return
 	}
 
-	indexStr := vars["index"]
-	index, err := strconv.Atoi(indexStr)
+	rostroID, err := security.ValidateID(vars["rostroId"])
 	if err != nil {
-		h.sendError(w, http.StatusBadRequest, "Índice inválido")
+		h.sendError(w, http.StatusBadRequest, "ID de rostro inválido")
 		return
 	}
 
-	err = h.docenteRepo.RemoveFaceDescriptor(docenteID, index)
+	err = h.docenteRepo.RemoveFaceDescriptor(docenteID, rostroID)
 	if err != nil {
-		h.sendError(w, http.StatusInternalServerError, "Error al eliminar descriptor")
+		h.sendError(w, http.StatusNotFound, "Rostro no encontrado")
 		return
 	}
 
@@ -555,3 +854,150 @@ func (h *ReconocimientoHandler) LimpiarDescriptoresDocente(w http.ResponseWriter
 		Message: "Todos los descriptores han sido eliminados",
 	})
 }
+
+// ListarReenrolamientos lista los docentes con muestras generadas por otro modelo facial
+func (h *ReconocimientoHandler) ListarReenrolamientos(w http.ResponseWriter, r *http.Request) {
+	docentes, err := h.docenteRepo.FindRequiringReenrollment()
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo docentes para re-enrolamiento: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener docentes")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{
+		Data: map[string]interface{}{
+			"modelo_version": h.engine.ModelVersion(),
+			"docentes":       docentes,
+		},
+	})
+}
+
+// ListarIntentos lista los intentos de identificación facial con filtros opcionales
+// Parámetros: desde, hasta (YYYY-MM-DD), modo, decision, docente_id, incorrectos=true, limite
+func (h *ReconocimientoHandler) ListarIntentos(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filtro := repositories.FiltroIntentosReconocimiento{}
+
+	if desdeStr := query.Get("desde"); desdeStr != "" {
+		desde, err := time.Parse("2006-01-02", desdeStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "Fecha 'desde' inválida. Use YYYY-MM-DD")
+			return
+		}
+		// Los días se delimitan en la zona horaria de la institución
+		desde, _ = clock.Dia(h.clock, desde)
+		filtro.Desde = &desde
+	}
+
+	if hastaStr := query.Get("hasta"); hastaStr != "" {
+		hasta, err := time.Parse("2006-01-02", hastaStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "Fecha 'hasta' inválida. Use YYYY-MM-DD")
+			return
+		}
+		// Incluir el día completo
+		_, hasta = clock.Dia(h.clock, hasta)
+		filtro.Hasta = &hasta
+	}
+
+	if modoStr := query.Get("modo"); modoStr != "" {
+		modo := entities.ModoReconocimiento(modoStr)
+		if !modo.IsValid() {
+			h.sendError(w, http.StatusBadRequest, "Modo inválido. Valores permitidos: identificacion, verificacion")
+			return
+		}
+		filtro.Modo = &modo
+	}
+
+	if decisionStr := query.Get("decision"); decisionStr != "" {
+		decision := entities.DecisionReconocimiento(decisionStr)
+		if !decision.IsValid() {
+			h.sendError(w, http.StatusBadRequest, "Decisión inválida. Valores permitidos: identificado, no_identificado, sin_rostro, error")
+			return
+		}
+		filtro.Decision = &decision
+	}
+
+	if docenteIDStr := query.Get("docente_id"); docenteIDStr != "" {
+		docenteID, err := security.ValidateID(docenteIDStr)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "docente_id inválido")
+			return
+		}
+		filtro.DocenteID = &docenteID
+	}
+
+	filtro.SoloIncorrectos = query.Get("incorrectos") == "true"
+
+	if limiteStr := query.Get("limite"); limiteStr != "" {
+		limite, err := strconv.Atoi(limiteStr)
+		if err != nil || limite <= 0 {
+			h.sendError(w, http.StatusBadRequest, "limite inválido")
+			return
+		}
+		filtro.Limite = limite
+	}
+
+	intentos, err := h.intentoUseCase.GetAll(filtro)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo intentos de reconocimiento: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener intentos de reconocimiento")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: intentos})
+}
+
+// ObtenerIntento obtiene el detalle de un intento de identificación facial
+func (h *ReconocimientoHandler) ObtenerIntento(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := security.ValidateID(vars["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	intento, err := h.intentoUseCase.GetByID(id)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, "Intento de reconocimiento no encontrado")
+		return
+	}
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: intento})
+}
+
+// MarcarIntentoIncorrecto señala un intento como identificación errónea
+func (h *ReconocimientoHandler) MarcarIntentoIncorrecto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := security.ValidateID(vars["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var req dto.MarcarIntentoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+
+	if err := security.ValidateDescripcion(req.Motivo); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Motivo demasiado largo")
+		return
+	}
+
+	if err := h.intentoUseCase.MarcarIncorrecto(id, claims.UserID, req.DocenteRealID, req.Motivo); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) marcó el intento de reconocimiento %d como incorrecto", claims.UserID, claims.Username, id)
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Intento marcado como identificación incorrecta"})
+}
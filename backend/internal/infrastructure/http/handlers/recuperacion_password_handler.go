@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/sistema-ingreso-docente/backend/internal/application/dto"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+// RecuperacionPasswordHandler atiende las rutas públicas de contraseña olvidada
+type RecuperacionPasswordHandler struct {
+	recuperacionUseCase *usecases.RecuperacionPasswordUseCase
+}
+
+func NewRecuperacionPasswordHandler(recuperacionUseCase *usecases.RecuperacionPasswordUseCase) *RecuperacionPasswordHandler {
+	return &RecuperacionPasswordHandler{recuperacionUseCase: recuperacionUseCase}
+}
+
+// Olvido envía el correo de restablecimiento en segundo plano y responde siempre lo mismo, exista
+// o no la cuenta: ni el mensaje ni el tiempo de respuesta revelan qué usuarios existen
+func (h *RecuperacionPasswordHandler) Olvido(w http.ResponseWriter, r *http.Request) {
+	var req dto.OlvidoPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if strings.TrimSpace(req.Username) == "" {
+		h.sendError(w, http.StatusBadRequest, "El username es requerido")
+		return
+	}
+
+	go func(username string) {
+		if err := h.recuperacionUseCase.Solicitar(username); err != nil {
+			log.Printf("[ERROR] Error en la solicitud de restablecimiento de contraseña: %v", err)
+		}
+	}(req.Username)
+
+	h.sendJSON(w, http.StatusOK, ApiResponse{
+		Message: "Si la cuenta existe y tiene un correo registrado, recibirá las instrucciones para restablecer la contraseña",
+	})
+}
+
+// Restablecer consume el token recibido por correo y asigna la nueva contraseña
+func (h *RecuperacionPasswordHandler) Restablecer(w http.ResponseWriter, r *http.Request) {
+	var req dto.RestablecerPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if strings.TrimSpace(req.Token) == "" {
+		h.sendError(w, http.StatusBadRequest, "El token es requerido")
+		return
+	}
+
+	// Validar fortaleza de la nueva contraseña antes de consumir el token
+	if err := security.ValidatePasswordStrength(req.NewPassword); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	usuario, err := h.recuperacionUseCase.Restablecer(req.Token, req.NewPassword)
+	if err != nil {
+		log.Printf("[SECURITY] Restablecimiento de contraseña rechazado: %v", err)
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) restableció su contraseña con un token enviado por correo", usuario.ID, usuario.Username)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Contraseña restablecida. Ya puede iniciar sesión"})
+}
+
+func (h *RecuperacionPasswordHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *RecuperacionPasswordHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
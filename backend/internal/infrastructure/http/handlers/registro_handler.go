@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,7 +12,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/sistema-ingreso-docente/backend/internal/application/dto"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/http/middleware"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
 	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
@@ -28,20 +31,43 @@ type RegistroHandler struct {
 	registroUseCase *usecases.RegistroUseCase
 	docenteUseCase  *usecases.DocenteUseCase
 	turnoUseCase    *usecases.TurnoUseCase
+	intentoUseCase  *usecases.IntentoReconocimientoUseCase
 	db              *sql.DB
+	clock           clock.Clock
+
+	// requiereVerificacion exige un token de verificación facial 1:1 para registrar ingresos
+	requiereVerificacion bool
 }
 
 func NewRegistroHandler(
 	registroUseCase *usecases.RegistroUseCase,
 	docenteUseCase *usecases.DocenteUseCase,
 	turnoUseCase *usecases.TurnoUseCase,
+	intentoUseCase *usecases.IntentoReconocimientoUseCase,
 	db *sql.DB,
+	reloj clock.Clock,
+	requiereVerificacion bool,
 ) *RegistroHandler {
 	return &RegistroHandler{
 		registroUseCase: registroUseCase,
 		docenteUseCase:  docenteUseCase,
 		turnoUseCase:    turnoUseCase,
+		intentoUseCase:  intentoUseCase,
 		db:              db,
+		clock:           reloj,
+
+		requiereVerificacion: requiereVerificacion,
+	}
+}
+
+// vincularIntentoReconocimiento asocia el registro creado con la identificación facial que lo originó
+// Un fallo aquí no invalida el registro, solo se deja constancia en el log
+func (h *RegistroHandler) vincularIntentoReconocimiento(intentoID *int, registro *entities.Registro) {
+	if intentoID == nil {
+		return
+	}
+	if err := h.intentoUseCase.VincularRegistro(*intentoID, registro); err != nil {
+		log.Printf("[WARN] No se pudo vincular el intento de reconocimiento %d al registro %d: %v", *intentoID, registro.ID, err)
 	}
 }
 
@@ -59,21 +85,75 @@ func (h *RegistroHandler) RegistrarIngreso(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Validar que se proporcione turno_id
-	if req.TurnoID == nil {
-		http.Error(w, `{"error":"Debe especificar turno_id"}`, http.StatusBadRequest)
-		return
+	// Verificación facial 1:1: obligatoria si está configurada, y validada siempre que se envíe
+	var intentoVerificacion *int
+	if h.requiereVerificacion || req.VerificacionToken != nil {
+		if req.VerificacionToken == nil || *req.VerificacionToken == "" {
+			http.Error(w, `{"error":"Se requiere verificación facial del docente"}`, http.StatusForbidden)
+			return
+		}
+		intentoID, err := jwt.ValidateVerificationToken(*req.VerificacionToken, docente.ID)
+		if err != nil {
+			log.Printf("[SECURITY] Token de verificación facial rechazado para docente %d: %v", docente.ID, err)
+			http.Error(w, `{"error":"Verificación facial inválida o expirada"}`, http.StatusForbidden)
+			return
+		}
+		// Cada verificación acredita un solo ingreso
+		if err := h.intentoUseCase.ConsumirVerificacion(intentoID, docente.ID); err != nil {
+			if errors.Is(err, repositories.ErrVerificacionUtilizada) {
+				log.Printf("[SECURITY] Token de verificación facial reutilizado para docente %d (intento %d)", docente.ID, intentoID)
+				http.Error(w, `{"error":"La verificación facial ya fue utilizada"}`, http.StatusForbidden)
+				return
+			}
+			log.Printf("[ERROR] Error consumiendo la verificación facial %d: %v", intentoID, err)
+			http.Error(w, `{"error":"Error al validar la verificación facial"}`, http.StatusInternalServerError)
+			return
+		}
+		intentoVerificacion = &intentoID
+		if req.IntentoReconocimientoID == nil {
+			req.IntentoReconocimientoID = intentoVerificacion
+		}
 	}
 
-	registro, err := h.registroUseCase.RegistrarIngreso(docente.ID, *req.TurnoID, req.LlaveID, req.Observaciones)
+	// Sin turno_id, el caso de uso detecta el turno
+	registro, seleccion, err := h.registroUseCase.RegistrarIngreso(docente.ID, req.TurnoID, req.LlaveID, req.Observaciones)
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err.Error()), http.StatusBadRequest)
+		if intentoVerificacion != nil {
+			if err := h.intentoUseCase.LiberarVerificacion(*intentoVerificacion); err != nil {
+				log.Printf("[WARN] No se pudo liberar la verificación facial %d: %v", *intentoVerificacion, err)
+			}
+		}
+		status := http.StatusBadRequest
+		if errors.Is(err, usecases.ErrIngresoAbiertoEnTurno) {
+			status = http.StatusConflict
+		}
+		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err.Error()), status)
 		return
 	}
 
+	h.vincularIntentoReconocimiento(req.IntentoReconocimientoID, registro)
+
+	respuesta := dto.RegistroCreadoResponse{Registro: registro, TurnoSeleccion: seleccion}
+	h.advertirLicencia(&respuesta)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(registro)
+	json.NewEncoder(w).Encode(respuesta)
+}
+
+// advertirLicencia agrega a la respuesta la licencia vigente del docente, si tiene
+func (h *RegistroHandler) advertirLicencia(respuesta *dto.RegistroCreadoResponse) {
+	licencia, err := h.registroUseCase.LicenciaVigente(respuesta.DocenteID)
+	if err != nil {
+		log.Printf("[WARN] No se pudo consultar la licencia del docente %d: %v", respuesta.DocenteID, err)
+		return
+	}
+	if licencia == nil {
+		return
+	}
+	log.Printf("[WARN] Registro %d del docente %d durante su licencia %d (%s)", respuesta.ID, respuesta.DocenteID, licencia.ID, licencia.Tipo)
+	respuesta.Licencia = licencia
+	respuesta.Advertencias = append(respuesta.Advertencias, licencia.Advertencia())
 }
 
 func (h *RegistroHandler) RegistrarSalida(w http.ResponseWriter, r *http.Request) {
@@ -89,51 +169,60 @@ func (h *RegistroHandler) RegistrarSalida(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Validar que se proporcione turno_id
-	if req.TurnoID == nil {
-		http.Error(w, `{"error":"Debe especificar turno_id"}`, http.StatusBadRequest)
-		return
-	}
-
-	registro, err := h.registroUseCase.RegistrarSalida(docente.ID, *req.TurnoID, req.LlaveID, req.Observaciones)
+	// Sin turno_id, el caso de uso detecta el turno
+	registro, seleccion, err := h.registroUseCase.RegistrarSalida(docente.ID, req.TurnoID, req.LlaveID, req.Observaciones)
 	if err != nil {
 		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err.Error()), http.StatusBadRequest)
 		return
 	}
 
+	h.vincularIntentoReconocimiento(req.IntentoReconocimientoID, registro)
+
+	// La sesión cerrada acompaña a la respuesta con la duración en el aula
+	var sesion *entities.Sesion
+	if registro.IngresoID != nil {
+		sesion, err = h.registroUseCase.GetSesion(*registro.IngresoID)
+		if err != nil {
+			log.Printf("[WARN] No se pudo obtener la sesión del ingreso %d: %v", *registro.IngresoID, err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(registro)
+	json.NewEncoder(w).Encode(dto.RegistroCreadoResponse{Registro: registro, TurnoSeleccion: seleccion, Sesion: sesion})
 }
 
 func (h *RegistroHandler) GetByFecha(w http.ResponseWriter, r *http.Request) {
 	fechaStr := r.URL.Query().Get("fecha")
 	if fechaStr == "" {
-		fechaStr = time.Now().Format("2006-01-02")
+		fechaStr = h.clock.Now().Format("2006-01-02")
 	}
 
-	_, err := time.Parse("2006-01-02", fechaStr)
+	fecha, err := time.Parse("2006-01-02", fechaStr)
 	if err != nil {
 		http.Error(w, `{"error":"Fecha inválida"}`, http.StatusBadRequest)
 		return
 	}
 
+	// El día se delimita en la zona horaria de la institución
+	inicio, fin := clock.Dia(h.clock, fecha)
+
 	// Consulta directa con JOINS para obtener toda la información (similar a GetRegistrosHoy)
 	query := `
 		SELECT
 			r.id, r.docente_id, d.nombre_completo as docente_nombre, d.documento_identidad as docente_ci,
 			r.turno_id, t.nombre as turno_nombre,
 			r.llave_id, l.codigo as llave_codigo, l.aula_codigo, l.aula_nombre,
-			r.tipo, r.fecha_hora, r.minutos_retraso, r.minutos_extra, r.es_excepcional
+			r.tipo, r.fecha_hora, r.minutos_retraso, r.minutos_extra, r.clasificacion, r.es_excepcional, r.suplencia_id
 		FROM registros r
 		INNER JOIN docentes d ON r.docente_id = d.id
 		INNER JOIN turnos t ON r.turno_id = t.id
 		LEFT JOIN llaves l ON r.llave_id = l.id
-		WHERE DATE(r.fecha_hora) = $1
+		WHERE r.fecha_hora >= $1 AND r.fecha_hora < $2
 		ORDER BY r.fecha_hora DESC
 	`
 
-	rows, err := h.db.Query(query, fechaStr)
+	rows, err := h.db.Query(query, inicio, fin)
 	if err != nil {
 		http.Error(w, `{"error":"Error obteniendo registros"}`, http.StatusInternalServerError)
 		return
@@ -147,7 +236,7 @@ func (h *RegistroHandler) GetByFecha(w http.ResponseWriter, r *http.Request) {
 			&reg.ID, &reg.DocenteID, &reg.DocenteNombre, &reg.DocenteCI,
 			&reg.TurnoID, &reg.TurnoNombre,
 			&reg.LlaveID, &reg.LlaveCodigo, &reg.AulaCodigo, &reg.AulaNombre,
-			&reg.Tipo, &reg.FechaHora, &reg.MinutosRetraso, &reg.MinutosExtra, &reg.EsExcepcional,
+			&reg.Tipo, &reg.FechaHora, &reg.MinutosRetraso, &reg.MinutosExtra, &reg.Clasificacion, &reg.EsExcepcional, &reg.SuplenciaID,
 		)
 		if err != nil {
 			http.Error(w, `{"error":"Error procesando registros"}`, http.StatusInternalServerError)
@@ -171,16 +260,17 @@ func (h *RegistroHandler) GetRegistrosHoy(w http.ResponseWriter, r *http.Request
 			r.id, r.docente_id, d.nombre_completo as docente_nombre, d.documento_identidad as docente_ci,
 			r.turno_id, t.nombre as turno_nombre,
 			r.llave_id, l.codigo as llave_codigo, l.aula_codigo, l.aula_nombre,
-			r.tipo, r.fecha_hora, r.minutos_retraso, r.minutos_extra, r.es_excepcional
+			r.tipo, r.fecha_hora, r.minutos_retraso, r.minutos_extra, r.clasificacion, r.es_excepcional, r.suplencia_id
 		FROM registros r
 		INNER JOIN docentes d ON r.docente_id = d.id
 		INNER JOIN turnos t ON r.turno_id = t.id
 		LEFT JOIN llaves l ON r.llave_id = l.id
-		WHERE DATE(r.fecha_hora) = CURRENT_DATE
+		WHERE r.fecha_hora >= $1 AND r.fecha_hora < $2
 		ORDER BY r.fecha_hora DESC
 	`
 
-	rows, err := h.db.Query(query)
+	inicio, fin := clock.Hoy(h.clock)
+	rows, err := h.db.Query(query, inicio, fin)
 	if err != nil {
 		http.Error(w, `{"error":"Error obteniendo registros de hoy"}`, http.StatusInternalServerError)
 		return
@@ -194,7 +284,7 @@ func (h *RegistroHandler) GetRegistrosHoy(w http.ResponseWriter, r *http.Request
 			&reg.ID, &reg.DocenteID, &reg.DocenteNombre, &reg.DocenteCI,
 			&reg.TurnoID, &reg.TurnoNombre,
 			&reg.LlaveID, &reg.LlaveCodigo, &reg.AulaCodigo, &reg.AulaNombre,
-			&reg.Tipo, &reg.FechaHora, &reg.MinutosRetraso, &reg.MinutosExtra, &reg.EsExcepcional,
+			&reg.Tipo, &reg.FechaHora, &reg.MinutosRetraso, &reg.MinutosExtra, &reg.Clasificacion, &reg.EsExcepcional, &reg.SuplenciaID,
 		)
 		if err != nil {
 			http.Error(w, `{"error":"Error procesando registros"}`, http.StatusInternalServerError)
@@ -214,39 +304,14 @@ func (h *RegistroHandler) GetRegistrosHoy(w http.ResponseWriter, r *http.Request
 func (h *RegistroHandler) GetLlaveActual(w http.ResponseWriter, r *http.Request) {
 	docenteIDStr := r.URL.Query().Get("docente_id")
 
-	// Si no se proporciona docente_id, devolver todas las llaves actualmente en uso
+	// Si no se proporciona docente_id, devolver todas las llaves actualmente en uso:
+	// las de sesiones abiertas, es decir, ingresos con llave sin salida emparejada
 	if docenteIDStr == "" {
-		query := `
-			SELECT DISTINCT ON (r_ingreso.llave_id)
-				r_ingreso.id,
-				r_ingreso.llave_id,
-				l.codigo as llave_codigo,
-				l.aula_codigo,
-				r_ingreso.docente_id,
-				CAST(d.documento_identidad AS TEXT) as docente_ci,
-				d.nombre_completo as docente_nombre_completo,
-				r_ingreso.fecha_hora as hora_ingreso
-			FROM registros r_ingreso
-			INNER JOIN llaves l ON r_ingreso.llave_id = l.id
-			INNER JOIN docentes d ON r_ingreso.docente_id = d.id
-			WHERE r_ingreso.tipo = 'ingreso'
-			  AND r_ingreso.llave_id IS NOT NULL
-			  AND NOT EXISTS (
-				  SELECT 1 FROM registros r_salida
-				  WHERE r_salida.llave_id = r_ingreso.llave_id
-					AND r_salida.docente_id = r_ingreso.docente_id
-					AND r_salida.tipo = 'salida'
-					AND r_salida.fecha_hora > r_ingreso.fecha_hora
-			  )
-			ORDER BY r_ingreso.llave_id, r_ingreso.fecha_hora DESC
-		`
-
-		rows, err := h.db.Query(query)
+		sesiones, err := h.registroUseCase.GetLlavesEnUso()
 		if err != nil {
 			http.Error(w, `{"error":"Error al consultar registros activos"}`, http.StatusInternalServerError)
 			return
 		}
-		defer rows.Close()
 
 		type LlaveActual struct {
 			LlaveID             int    `json:"llave_id"`
@@ -258,22 +323,20 @@ func (h *RegistroHandler) GetLlaveActual(w http.ResponseWriter, r *http.Request)
 			HoraIngreso         string `json:"hora_ingreso"`
 		}
 
-		var llavesActuales []LlaveActual
-		for rows.Next() {
-			var la LlaveActual
-			var id int
-			err := rows.Scan(
-				&id,
-				&la.LlaveID,
-				&la.LlaveCodigo,
-				&la.AulaCodigo,
-				&la.DocenteID,
-				&la.DocenteCI,
-				&la.DocenteNombreCompleto,
-				&la.HoraIngreso,
-			)
-			if err != nil {
-				continue
+		llavesActuales := []LlaveActual{}
+		for _, sesion := range sesiones {
+			la := LlaveActual{
+				LlaveID:               *sesion.LlaveID,
+				DocenteID:             sesion.DocenteID,
+				DocenteCI:             sesion.DocenteCI,
+				DocenteNombreCompleto: sesion.DocenteNombre,
+				HoraIngreso:           sesion.HoraIngreso.Format(time.RFC3339),
+			}
+			if sesion.LlaveCodigo != nil {
+				la.LlaveCodigo = *sesion.LlaveCodigo
+			}
+			if sesion.AulaCodigo != nil {
+				la.AulaCodigo = *sesion.AulaCodigo
 			}
 			llavesActuales = append(llavesActuales, la)
 		}
@@ -305,6 +368,66 @@ func (h *RegistroHandler) GetLlaveActual(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// ListarSesiones devuelve los ingresos emparejados con su salida y la duración de cada sesión
+// Filtros opcionales: fecha o desde/hasta (YYYY-MM-DD, en la zona de la institución),
+// docente_id y abiertas=true
+func (h *RegistroHandler) ListarSesiones(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var filtro repositories.FiltroSesiones
+
+	parsearDia := func(valor string) (time.Time, time.Time, error) {
+		fecha, err := time.Parse("2006-01-02", valor)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		inicio, fin := clock.Dia(h.clock, fecha)
+		return inicio, fin, nil
+	}
+
+	if fecha := q.Get("fecha"); fecha != "" {
+		inicio, fin, err := parsearDia(fecha)
+		if err != nil {
+			http.Error(w, `{"error":"Fecha inválida"}`, http.StatusBadRequest)
+			return
+		}
+		filtro.Desde, filtro.Hasta = &inicio, &fin
+	}
+	if desde := q.Get("desde"); desde != "" {
+		inicio, _, err := parsearDia(desde)
+		if err != nil {
+			http.Error(w, `{"error":"Fecha desde inválida"}`, http.StatusBadRequest)
+			return
+		}
+		filtro.Desde = &inicio
+	}
+	if hasta := q.Get("hasta"); hasta != "" {
+		_, fin, err := parsearDia(hasta)
+		if err != nil {
+			http.Error(w, `{"error":"Fecha hasta inválida"}`, http.StatusBadRequest)
+			return
+		}
+		filtro.Hasta = &fin
+	}
+	if docenteIDStr := q.Get("docente_id"); docenteIDStr != "" {
+		var docenteID int
+		if _, err := fmt.Sscanf(docenteIDStr, "%d", &docenteID); err != nil {
+			http.Error(w, `{"error":"docente_id inválido"}`, http.StatusBadRequest)
+			return
+		}
+		filtro.DocenteID = &docenteID
+	}
+	filtro.SoloAbiertas = q.Get("abiertas") == "true"
+
+	sesiones, err := h.registroUseCase.GetSesiones(filtro)
+	if err != nil {
+		http.Error(w, `{"error":"Error obteniendo sesiones"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sesiones)
+}
+
 // Update permite al Bibliotecario o Jefe de Carrera editar/corregir registros
 func (h *RegistroHandler) Update(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -421,6 +544,10 @@ func (h *RegistroHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	// Guardar cambios con sincronización de estados de llaves
 	if err := h.registroUseCase.UpdateConSincronizacionLlaves(registroAnterior, registroActual); err != nil {
+		if errors.Is(err, usecases.ErrPeriodoCerrado) {
+			http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err.Error()), http.StatusConflict)
+			return
+		}
 		log.Printf("[ERROR] Error actualizando registro %d por usuario %d: %v", id, claims.UserID, err)
 		http.Error(w, `{"error":"Error al actualizar registro"}`, http.StatusInternalServerError)
 		return
@@ -468,6 +595,10 @@ func (h *RegistroHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	// Eliminar con sincronización de estado de llave
 	if err := h.registroUseCase.DeleteConSincronizacionLlave(registro); err != nil {
+		if errors.Is(err, usecases.ErrPeriodoCerrado) {
+			http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err.Error()), http.StatusConflict)
+			return
+		}
 		log.Printf("[ERROR] Error eliminando registro %d por usuario %d: %v", id, claims.UserID, err)
 		http.Error(w, `{"error":"Error al eliminar registro"}`, http.StatusInternalServerError)
 		return
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/clock"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type ReporteHandler struct {
+	reporteUseCase *usecases.ReporteUseCase
+	clock          clock.Clock
+}
+
+func NewReporteHandler(reporteUseCase *usecases.ReporteUseCase, reloj clock.Clock) *ReporteHandler {
+	return &ReporteHandler{reporteUseCase: reporteUseCase, clock: reloj}
+}
+
+// ReporteAsistencia resume la asistencia por docente: ?desde=YYYY-MM-DD&hasta=YYYY-MM-DD[&docente_id=N]
+// Sin fechas, cubre el mes en curso hasta hoy
+func (h *ReporteHandler) ReporteAsistencia(w http.ResponseWriter, r *http.Request) {
+	hoy := entities.FechaDe(h.clock.Now().In(h.clock.Location()))
+	desde := entities.Fecha{Time: hoy.AddDate(0, 0, 1-hoy.Day())}
+	hasta := hoy
+
+	if valor, err := fechaOpcional(r, "desde"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if valor != nil {
+		desde = *valor
+	}
+	if valor, err := fechaOpcional(r, "hasta"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if valor != nil {
+		hasta = *valor
+	}
+
+	var docenteID *int
+	if valor := r.URL.Query().Get("docente_id"); valor != "" {
+		id, err := security.ValidateID(valor)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, "docente_id inválido")
+			return
+		}
+		docenteID = &id
+	}
+
+	reporte, err := h.reporteUseCase.ReporteAsistencia(desde, hasta, docenteID)
+	if err != nil {
+		log.Printf("[ERROR] Error generando reporte de asistencia: %v", err)
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: reporte})
+}
+
+func (h *ReporteHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *ReporteHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/usecases"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+type SuplenciaHandler struct {
+	suplenciaUseCase *usecases.SuplenciaUseCase
+}
+
+func NewSuplenciaHandler(suplenciaUseCase *usecases.SuplenciaUseCase) *SuplenciaHandler {
+	return &SuplenciaHandler{suplenciaUseCase: suplenciaUseCase}
+}
+
+// Listar filtra por ?docente_id=N (titular o suplente), titular_id, suplente_id, turno_id y
+// desde/hasta (YYYY-MM-DD)
+func (h *SuplenciaHandler) Listar(w http.ResponseWriter, r *http.Request) {
+	filtro := repositories.FiltroSuplencias{}
+	query := r.URL.Query()
+
+	ids := map[string]**int{
+		"docente_id":  &filtro.DocenteID,
+		"titular_id":  &filtro.TitularID,
+		"suplente_id": &filtro.SuplenteID,
+		"turno_id":    &filtro.TurnoID,
+	}
+	for nombre, destino := range ids {
+		valor := query.Get(nombre)
+		if valor == "" {
+			continue
+		}
+		id, err := security.ValidateID(valor)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, nombre+" inválido")
+			return
+		}
+		*destino = &id
+	}
+	var err error
+	if filtro.Desde, err = fechaOpcional(r, "desde"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filtro.Hasta, err = fechaOpcional(r, "hasta"); err != nil {
+		h.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	suplencias, err := h.suplenciaUseCase.Listar(filtro)
+	if err != nil {
+		log.Printf("[ERROR] Error obteniendo suplencias: %v", err)
+		h.sendError(w, http.StatusInternalServerError, "Error al obtener suplencias")
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: suplencias})
+}
+
+func (h *SuplenciaHandler) Obtener(w http.ResponseWriter, r *http.Request) {
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	suplencia, err := h.suplenciaUseCase.GetByID(id)
+	if err != nil {
+		h.sendErrorSuplencia(w, err)
+		return
+	}
+	h.sendJSON(w, http.StatusOK, ApiResponse{Data: suplencia})
+}
+
+// Crear asigna un suplente a la clase del titular; el jefe de carrera queda como responsable
+func (h *SuplenciaHandler) Crear(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+
+	var suplencia entities.Suplencia
+	if err := json.NewDecoder(r.Body).Decode(&suplencia); err != nil {
+		h.sendError(w, http.StatusBadRequest, "Datos inválidos")
+		return
+	}
+	if suplencia.Motivo != nil {
+		if err := security.ValidateDescripcion(*suplencia.Motivo); err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	suplencia.AsignadoPor = claims.UserID
+
+	vinculados, err := h.suplenciaUseCase.Crear(&suplencia)
+	if err != nil {
+		h.sendErrorSuplencia(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) asignó la suplencia %d: docente %d reemplaza a %d el %s (turno %d, aula %s; %d registros vinculados)",
+		claims.UserID, claims.Username, suplencia.ID, suplencia.SuplenteID, suplencia.TitularID, suplencia.Fecha, suplencia.TurnoID, suplencia.AulaCodigo, vinculados)
+	mensaje := "Suplencia asignada exitosamente"
+	if vinculados > 0 {
+		mensaje = fmt.Sprintf("Suplencia asignada exitosamente; se vincularon %d registros del suplente", vinculados)
+	}
+	h.sendJSON(w, http.StatusCreated, ApiResponse{Data: suplencia, Message: mensaje})
+}
+
+func (h *SuplenciaHandler) Eliminar(w http.ResponseWriter, r *http.Request) {
+	claims := getUserClaims(r)
+	if claims == nil {
+		h.sendError(w, http.StatusUnauthorized, "No autorizado")
+		return
+	}
+	id, err := security.ValidateID(mux.Vars(r)["id"])
+	if err != nil {
+		h.sendError(w, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	if err := h.suplenciaUseCase.Eliminar(id); err != nil {
+		h.sendErrorSuplencia(w, err)
+		return
+	}
+
+	log.Printf("[AUDIT] Usuario %d (%s) eliminó la suplencia %d", claims.UserID, claims.Username, id)
+	h.sendJSON(w, http.StatusOK, ApiResponse{Message: "Suplencia eliminada exitosamente"})
+}
+
+// sendErrorSuplencia responde 409 si toca un mes cerrado, 404 a los recursos inexistentes y 400
+// al resto de errores
+func (h *SuplenciaHandler) sendErrorSuplencia(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrPeriodoCerrado):
+		h.sendError(w, http.StatusConflict, err.Error())
+	case strings.HasSuffix(err.Error(), "no encontrada"), strings.HasSuffix(err.Error(), "no encontrado"):
+		h.sendError(w, http.StatusNotFound, err.Error())
+	default:
+		h.sendError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+func (h *SuplenciaHandler) sendJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *SuplenciaHandler) sendError(w http.ResponseWriter, status int, message string) {
+	h.sendJSON(w, status, ApiResponse{Error: message})
+}
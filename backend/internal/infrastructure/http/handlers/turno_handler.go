@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
@@ -54,7 +56,8 @@ func (h *TurnoHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *TurnoHandler) Create(w http.ResponseWriter, r *http.Request) {
-	var turno entities.Turno
+	// Los márgenes omitidos en el request conservan sus valores por defecto
+	turno := entities.NuevoTurno()
 	if err := json.NewDecoder(r.Body).Decode(&turno); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -106,11 +109,20 @@ func (h *TurnoHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if nombre, ok := updateData["nombre"].(string); ok {
 		existingTurno.Nombre = nombre
 	}
-	if horaInicio, ok := updateData["hora_inicio"].(string); ok {
-		existingTurno.HoraInicio = horaInicio
+	if err := aplicarHorarioTurno(existingTurno, updateData); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ApiResponse{Error: err.Error()})
+		return
 	}
-	if horaFin, ok := updateData["hora_fin"].(string); ok {
-		existingTurno.HoraFin = horaFin
+	if tolerancia, ok := updateData["tolerancia_ingreso_min"].(float64); ok {
+		existingTurno.ToleranciaIngresoMin = int(tolerancia)
+	}
+	if retrasoMaximo, ok := updateData["retraso_maximo_min"].(float64); ok {
+		existingTurno.RetrasoMaximoMin = int(retrasoMaximo)
+	}
+	if salidaAnticipada, ok := updateData["salida_anticipada_min"].(float64); ok {
+		existingTurno.SalidaAnticipadaMin = int(salidaAnticipada)
 	}
 	if descripcion, ok := updateData["descripcion"].(string); ok {
 		existingTurno.Descripcion = &descripcion
@@ -130,6 +142,63 @@ func (h *TurnoHandler) Update(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(ApiResponse{Data: existingTurno, Message: "Turno actualizado exitosamente"})
 }
 
+// aplicarHorarioTurno actualiza horas, días y vigencia del turno con los campos presentes en
+// el request. vigente_desde/vigente_hasta en null quitan el límite
+func aplicarHorarioTurno(turno *entities.Turno, updateData map[string]interface{}) error {
+	if valor, ok := updateData["hora_inicio"].(string); ok {
+		hora, err := entities.ParseHoraDelDia(valor)
+		if err != nil {
+			return err
+		}
+		turno.HoraInicio = hora
+	}
+	if valor, ok := updateData["hora_fin"].(string); ok {
+		hora, err := entities.ParseHoraDelDia(valor)
+		if err != nil {
+			return err
+		}
+		turno.HoraFin = hora
+	}
+	if valores, ok := updateData["dias_semana"].([]interface{}); ok {
+		dias := make([]time.Weekday, 0, len(valores))
+		for _, valor := range valores {
+			dia, ok := valor.(float64)
+			if !ok {
+				return fmt.Errorf("dias_semana debe ser una lista de días (0 = domingo ... 6 = sábado)")
+			}
+			dias = append(dias, time.Weekday(dia))
+		}
+		mascara, err := entities.NuevosDiasSemana(dias...)
+		if err != nil {
+			return err
+		}
+		turno.DiasSemana = mascara
+	}
+	for campo, destino := range map[string]**entities.Fecha{
+		"vigente_desde": &turno.VigenteDesde,
+		"vigente_hasta": &turno.VigenteHasta,
+	} {
+		valor, presente := updateData[campo]
+		if !presente {
+			continue
+		}
+		if valor == nil {
+			*destino = nil
+			continue
+		}
+		texto, ok := valor.(string)
+		if !ok {
+			return fmt.Errorf("%s debe ser una fecha AAAA-MM-DD o null", campo)
+		}
+		fecha, err := entities.ParseFecha(texto)
+		if err != nil {
+			return err
+		}
+		*destino = &fecha
+	}
+	return nil
+}
+
 func (h *TurnoHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 
@@ -117,12 +118,25 @@ func (h *UsuarioHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validar fortaleza de la contraseña
-	if err := security.ValidatePasswordStrength(req.Password); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ApiResponse{Error: err.Error()})
-		return
+	origen := entities.OrigenLocal
+	if req.Origen != "" {
+		origen = entities.OrigenUsuario(req.Origen)
+		if !origen.IsValid() {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ApiResponse{Error: "Origen inválido. Valores permitidos: local, ldap"})
+			return
+		}
+	}
+
+	// Validar fortaleza de la contraseña; las cuentas del directorio no tienen contraseña local
+	if origen == entities.OrigenLocal {
+		if err := security.ValidatePasswordStrength(req.Password); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ApiResponse{Error: err.Error()})
+			return
+		}
 	}
 
 	usuario := &entities.Usuario{
@@ -131,6 +145,7 @@ func (h *UsuarioHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Rol:            rol,
 		NombreCompleto: req.NombreCompleto,
 		Email:          req.Email,
+		Origen:         origen,
 	}
 
 	if err := h.useCase.Create(usuario); err != nil {
@@ -199,6 +214,14 @@ func (h *UsuarioHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// SEGURIDAD: Prevenir que un administrador se deje sin acceso moviendo su cuenta al directorio
+	if claims.UserID == id && req.Origen != "" && entities.OrigenUsuario(req.Origen) != usuario.Origen {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(ApiResponse{Error: "No puede cambiar el origen de su propia cuenta"})
+		return
+	}
+
 	// Validar longitud de nombre completo
 	if req.NombreCompleto != "" {
 		if err := security.ValidateNombreCompleto(req.NombreCompleto); err != nil {
@@ -239,6 +262,16 @@ func (h *UsuarioHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.Activo != nil {
 		usuario.Activo = *req.Activo
 	}
+	if req.Origen != "" {
+		origen := entities.OrigenUsuario(req.Origen)
+		if !origen.IsValid() {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ApiResponse{Error: "Origen inválido. Valores permitidos: local, ldap"})
+			return
+		}
+		usuario.Origen = origen
+	}
 
 	if err := h.useCase.Update(usuario); err != nil {
 		log.Printf("[ERROR] Error actualizando usuario %d: %v", id, err)
@@ -331,9 +364,13 @@ func (h *UsuarioHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 
 	if err := h.useCase.ChangePassword(id, req.NewPassword); err != nil {
 		log.Printf("[ERROR] Error cambiando contraseña de usuario %d por usuario %d: %v", id, claims.UserID, err)
+		mensaje := "Error al cambiar contraseña"
+		if errors.Is(err, usecases.ErrPasswordEnDirectorio) {
+			mensaje = err.Error()
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ApiResponse{Error: "Error al cambiar contraseña"})
+		json.NewEncoder(w).Encode(ApiResponse{Error: mensaje})
 		return
 	}
 
@@ -13,6 +13,22 @@ type contextKey string
 
 const UserContextKey contextKey = "user"
 
+// RutaCambioPassword es la única ruta que acepta un token con MustChangePassword
+const RutaCambioPassword = "/me/password"
+
+// Rutas de la autenticación en dos pasos propia; las únicas que acepta un token con EnrolarDosFactores
+const (
+	RutaDosFactores        = "/me/2fa"
+	RutaEnrolarDosFactores = "/me/2fa/enrolar"
+	RutaActivarDosFactores = "/me/2fa/activar"
+)
+
+var rutasEnrolamientoDosFactores = map[string]bool{
+	RutaDosFactores:        true,
+	RutaEnrolarDosFactores: true,
+	RutaActivarDosFactores: true,
+}
+
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -28,6 +44,18 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Con una contraseña temporal solo se permite cambiarla
+		if claims.MustChangePassword && r.URL.Path != RutaCambioPassword {
+			http.Error(w, `{"error":"Debe cambiar su contraseña antes de continuar","code":"PASSWORD_CHANGE_REQUIRED"}`, http.StatusForbidden)
+			return
+		}
+		// Si el rol exige autenticación en dos pasos, primero hay que activarla. El cambio de
+		// contraseña pendiente va antes: sin él el token no llega a las rutas de enrolamiento
+		if claims.EnrolarDosFactores && !rutasEnrolamientoDosFactores[r.URL.Path] && r.URL.Path != RutaCambioPassword {
+			http.Error(w, `{"error":"Su rol requiere autenticación en dos pasos; actívela antes de continuar","code":"TWO_FACTOR_ENROLLMENT_REQUIRED"}`, http.StatusForbidden)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), UserContextKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/jwt"
+)
+
+func TestAuthMiddlewareRestricciones(t *testing.T) {
+	token := func(mustChange, enrolar bool) string {
+		usuario := &entities.Usuario{ID: 7, Username: "jefe", Rol: entities.RolJefeCarrera, MustChangePassword: mustChange}
+		firmado, err := jwt.GenerateToken(usuario, enrolar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return firmado
+	}
+
+	casos := []struct {
+		nombre     string
+		mustChange bool
+		enrolar    bool
+		ruta       string
+		espera     int
+	}{
+		{"sin restricciones", false, false, "/docentes", http.StatusOK},
+		{"contraseña temporal en otra ruta", true, false, "/docentes", http.StatusForbidden},
+		{"contraseña temporal al cambiarla", true, false, RutaCambioPassword, http.StatusOK},
+		{"enrolamiento pendiente en otra ruta", false, true, "/docentes", http.StatusForbidden},
+		{"enrolamiento pendiente al enrolar", false, true, RutaEnrolarDosFactores, http.StatusOK},
+		{"enrolamiento pendiente al activar", false, true, RutaActivarDosFactores, http.StatusOK},
+		{"ambas restricciones al cambiar la contraseña", true, true, RutaCambioPassword, http.StatusOK},
+		{"ambas restricciones al enrolar", true, true, RutaEnrolarDosFactores, http.StatusForbidden},
+		{"ambas restricciones en otra ruta", true, true, "/docentes", http.StatusForbidden},
+	}
+
+	siguiente := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, c.ruta, nil)
+			req.Header.Set("Authorization", "Bearer "+token(c.mustChange, c.enrolar))
+			rec := httptest.NewRecorder()
+			AuthMiddleware(siguiente).ServeHTTP(rec, req)
+			if rec.Code != c.espera {
+				t.Errorf("status = %d, se esperaba %d (%s)", rec.Code, c.espera, rec.Body.String())
+			}
+		})
+	}
+}
@@ -10,19 +10,34 @@ import (
 )
 
 type Handlers struct {
-	Auth           *handlers.AuthHandler
-	Usuario        *handlers.UsuarioHandler
-	Docente        *handlers.DocenteHandler
-	Registro       *handlers.RegistroHandler
-	Turno          *handlers.TurnoHandler
-	Llave          *handlers.LlaveHandler
-	Reconocimiento *handlers.ReconocimientoHandler
+	Auth                 *handlers.AuthHandler
+	Usuario              *handlers.UsuarioHandler
+	Docente              *handlers.DocenteHandler
+	Registro             *handlers.RegistroHandler
+	Turno                *handlers.TurnoHandler
+	Llave                *handlers.LlaveHandler
+	Reconocimiento       *handlers.ReconocimientoHandler
+	Consentimiento       *handlers.ConsentimientoHandler
+	Evidencia            *handlers.EvidenciaHandler
+	Deduplicacion        *handlers.DeduplicacionHandler
+	Calendario           *handlers.CalendarioHandler
+	Reporte              *handlers.ReporteHandler
+	Cierre               *handlers.CierreHandler
+	Justificacion        *handlers.JustificacionHandler
+	Licencia             *handlers.LicenciaHandler
+	Suplencia            *handlers.SuplenciaHandler
+	PortalDocente        *handlers.PortalDocenteHandler
+	RecuperacionPassword *handlers.RecuperacionPasswordHandler
+	DosFactores          *handlers.DosFactoresHandler
 }
 
 // SetupWithRateLimiter configura las rutas con rate limiting en endpoints sensibles
 func SetupWithRateLimiter(r *mux.Router, h *Handlers, loginLimiter *middleware.RateLimiter) {
 	// Public routes con rate limiting
 	r.HandleFunc("/login", loginLimiter.LimitHandler(h.Auth.Login)).Methods("POST")
+	r.HandleFunc("/login/2fa", loginLimiter.LimitHandler(h.Auth.VerificarSegundoFactor)).Methods("POST")
+	r.HandleFunc("/password/olvido", loginLimiter.LimitHandler(h.RecuperacionPassword.Olvido)).Methods("POST")
+	r.HandleFunc("/password/restablecer", loginLimiter.LimitHandler(h.RecuperacionPassword.Restablecer)).Methods("POST")
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"status":"ok"}`))
@@ -32,6 +47,18 @@ func SetupWithRateLimiter(r *mux.Router, h *Handlers, loginLimiter *middleware.R
 	api := r.PathPrefix("/").Subrouter()
 	api.Use(middleware.AuthMiddleware)
 
+	// ==================== CUENTA PROPIA (Todos los roles) ====================
+	// Única ruta permitida mientras la cuenta deba cambiar su contraseña
+	api.HandleFunc(middleware.RutaCambioPassword, loginLimiter.LimitHandler(h.Auth.CambiarPassword)).Methods("POST")
+
+	// Autenticación en dos pasos propia; estado, enrolar y activar son las únicas rutas permitidas
+	// mientras el rol la exija y la cuenta no la haya activado
+	api.HandleFunc(middleware.RutaDosFactores, h.DosFactores.Estado).Methods("GET")
+	api.HandleFunc(middleware.RutaEnrolarDosFactores, h.DosFactores.Enrolar).Methods("POST")
+	api.HandleFunc(middleware.RutaActivarDosFactores, loginLimiter.LimitHandler(h.DosFactores.Activar)).Methods("POST")
+	api.HandleFunc("/me/2fa/codigos-recuperacion", loginLimiter.LimitHandler(h.DosFactores.RegenerarCodigos)).Methods("POST")
+	api.HandleFunc("/me/2fa/desactivar", loginLimiter.LimitHandler(h.DosFactores.Desactivar)).Methods("POST")
+
 	// ==================== USUARIOS (Solo Administrador) ====================
 	api.Handle("/usuarios", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Usuario.GetAll))).Methods("GET")
 	api.Handle("/usuarios/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Usuario.GetByID))).Methods("GET")
@@ -40,6 +67,11 @@ func SetupWithRateLimiter(r *mux.Router, h *Handlers, loginLimiter *middleware.R
 	api.Handle("/usuarios/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Usuario.Delete))).Methods("DELETE")
 	api.Handle("/usuarios/{id}/password", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Usuario.ChangePassword))).Methods("PATCH")
 	api.Handle("/usuarios/{id}/toggle", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Usuario.ToggleActive))).Methods("PATCH")
+	api.Handle("/usuarios/{id}/2fa", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.DosFactores.Restablecer))).Methods("DELETE")
+
+	// Autenticación en dos pasos requerida por rol - Solo Administrador
+	api.Handle("/dos-factores/politicas", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.DosFactores.ListarPoliticas))).Methods("GET")
+	api.Handle("/dos-factores/politicas/{rol}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.DosFactores.DefinirPolitica))).Methods("PUT")
 
 	// ==================== DOCENTES ====================
 	// Lectura - Administrador, Bibliotecario, Becario y Jefe de Carrera
@@ -63,6 +95,12 @@ func SetupWithRateLimiter(r *mux.Router, h *Handlers, loginLimiter *middleware.R
 	api.Handle("/registros/llave-actual", middleware.RequireRole(entities.RolAdministrador, entities.RolBibliotecario, entities.RolBecario, entities.RolJefeCarrera)(http.HandlerFunc(h.Registro.GetLlaveActual))).Methods("GET")
 	api.Handle("/registros", middleware.RequireRole(entities.RolAdministrador, entities.RolBibliotecario, entities.RolBecario, entities.RolJefeCarrera)(http.HandlerFunc(h.Registro.GetByFecha))).Methods("GET")
 
+	// Sesiones (ingreso emparejado con su salida) - Administrador, Bibliotecario, Becario y Jefe de Carrera
+	api.Handle("/sesiones", middleware.RequireRole(entities.RolAdministrador, entities.RolBibliotecario, entities.RolBecario, entities.RolJefeCarrera)(http.HandlerFunc(h.Registro.ListarSesiones))).Methods("GET")
+
+	// Evidencia fotográfica del reconocimiento - Administrador y Jefe de Carrera
+	api.Handle("/registros/{id}/evidencia", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Evidencia.ObtenerPorRegistro))).Methods("GET")
+
 	// Editar registros - Bibliotecario y Jefe de Carrera (para corregir errores)
 	api.Handle("/registros/{id}", middleware.RequireRole(entities.RolBibliotecario, entities.RolJefeCarrera)(http.HandlerFunc(h.Registro.Update))).Methods("PUT")
 	api.Handle("/registros/{id}", middleware.RequireRole(entities.RolBibliotecario, entities.RolJefeCarrera)(http.HandlerFunc(h.Registro.Delete))).Methods("DELETE")
@@ -78,6 +116,65 @@ func SetupWithRateLimiter(r *mux.Router, h *Handlers, loginLimiter *middleware.R
 	api.Handle("/turnos/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Turno.Update))).Methods("PUT")
 	api.Handle("/turnos/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Turno.Delete))).Methods("DELETE")
 
+	// ==================== CALENDARIO ACADÉMICO ====================
+	// Lectura y consulta de días laborables - Administrador, Jefe de Carrera, Bibliotecario y Becario
+	api.Handle("/calendario/periodos", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Calendario.ListarPeriodos))).Methods("GET")
+	api.Handle("/calendario/eventos", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Calendario.ListarEventos))).Methods("GET")
+	api.Handle("/calendario/laborable", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Calendario.ConsultarLaborable))).Methods("GET")
+	api.Handle("/calendario/dias", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Calendario.ConsultarDias))).Methods("GET")
+
+	// Escritura - Solo Administrador
+	api.Handle("/calendario/periodos", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Calendario.CrearPeriodo))).Methods("POST")
+	api.Handle("/calendario/periodos/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Calendario.ActualizarPeriodo))).Methods("PUT")
+	api.Handle("/calendario/periodos/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Calendario.EliminarPeriodo))).Methods("DELETE")
+	api.Handle("/calendario/eventos", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Calendario.CrearEvento))).Methods("POST")
+	api.Handle("/calendario/eventos/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Calendario.ActualizarEvento))).Methods("PUT")
+	api.Handle("/calendario/eventos/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Calendario.EliminarEvento))).Methods("DELETE")
+
+	// ==================== REPORTES ====================
+	// Asistencia por docente según el calendario académico - Administrador y Jefe de Carrera
+	api.Handle("/reportes/asistencia", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Reporte.ReporteAsistencia))).Methods("GET")
+
+	// ==================== JUSTIFICACIONES ====================
+	// Registro y consulta - Administrador, Jefe de Carrera y Bibliotecario
+	api.Handle("/justificaciones", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario)(http.HandlerFunc(h.Justificacion.Listar))).Methods("GET")
+	api.Handle("/justificaciones/{id}", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario)(http.HandlerFunc(h.Justificacion.Obtener))).Methods("GET")
+	api.Handle("/justificaciones/{id}/documento", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario)(http.HandlerFunc(h.Justificacion.Documento))).Methods("GET")
+	api.Handle("/justificaciones", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario)(http.HandlerFunc(h.Justificacion.Crear))).Methods("POST")
+	api.Handle("/justificaciones/{id}/documento", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario)(http.HandlerFunc(h.Justificacion.SubirDocumento))).Methods("POST")
+	api.Handle("/justificaciones/{id}", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario)(http.HandlerFunc(h.Justificacion.Eliminar))).Methods("DELETE")
+
+	// Revisión (aprobar o rechazar) - Solo Jefe de Carrera
+	api.Handle("/justificaciones/{id}/revision", middleware.RequireRole(entities.RolJefeCarrera)(http.HandlerFunc(h.Justificacion.Revisar))).Methods("PATCH")
+
+	// ==================== LICENCIAS ====================
+	// Consulta - Administrador, Jefe de Carrera, Bibliotecario y Becario
+	api.Handle("/licencias", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Licencia.Listar))).Methods("GET")
+	api.Handle("/licencias/{id}", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Licencia.Obtener))).Methods("GET")
+
+	// Escritura - Administrador y Jefe de Carrera
+	api.Handle("/licencias", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Licencia.Crear))).Methods("POST")
+	api.Handle("/licencias/{id}", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Licencia.Actualizar))).Methods("PUT")
+	api.Handle("/licencias/{id}", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Licencia.Eliminar))).Methods("DELETE")
+
+	// ==================== SUPLENCIAS ====================
+	// Consulta - Administrador, Jefe de Carrera, Bibliotecario y Becario
+	api.Handle("/suplencias", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Suplencia.Listar))).Methods("GET")
+	api.Handle("/suplencias/{id}", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Suplencia.Obtener))).Methods("GET")
+
+	// Asignación - Solo Jefe de Carrera
+	api.Handle("/suplencias", middleware.RequireRole(entities.RolJefeCarrera)(http.HandlerFunc(h.Suplencia.Crear))).Methods("POST")
+	api.Handle("/suplencias/{id}", middleware.RequireRole(entities.RolJefeCarrera)(http.HandlerFunc(h.Suplencia.Eliminar))).Methods("DELETE")
+
+	// ==================== CIERRE DE PERIODOS ====================
+	// Consulta y cierre de meses - Administrador y Jefe de Carrera
+	api.Handle("/cierres", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Cierre.Listar))).Methods("GET")
+	api.Handle("/cierres/{anio:[0-9]+}/{mes:[0-9]+}", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Cierre.Obtener))).Methods("GET")
+	api.Handle("/cierres", middleware.RequireRole(entities.RolAdministrador, entities.RolJefeCarrera)(http.HandlerFunc(h.Cierre.Cerrar))).Methods("POST")
+
+	// Reapertura con motivo - Solo Administrador
+	api.Handle("/cierres/{anio:[0-9]+}/{mes:[0-9]+}/reabrir", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Cierre.Reabrir))).Methods("POST")
+
 	// ==================== LLAVES ====================
 	// Lectura - Administrador, Bibliotecario y Becario
 	api.Handle("/llaves", middleware.RequireRole(entities.RolAdministrador, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Llave.GetAll))).Methods("GET")
@@ -99,11 +196,43 @@ func SetupWithRateLimiter(r *mux.Router, h *Handlers, loginLimiter *middleware.R
 	// Identificar docente por rostro - Administrador, Bibliotecario y Becario
 	api.Handle("/reconocimiento/identificar", middleware.RequireRole(entities.RolAdministrador, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Reconocimiento.IdentificarDocente))).Methods("POST")
 
+	// Verificar docente (CI + rostro, 1:1) - Administrador, Bibliotecario y Becario
+	api.Handle("/reconocimiento/verificar", middleware.RequireRole(entities.RolAdministrador, entities.RolBibliotecario, entities.RolBecario)(http.HandlerFunc(h.Reconocimiento.VerificarDocente))).Methods("POST")
+
+	// Auditoría de intentos de identificación - Solo Administrador
+	api.Handle("/reconocimiento/intentos", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.ListarIntentos))).Methods("GET")
+	api.Handle("/reconocimiento/intentos/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.ObtenerIntento))).Methods("GET")
+	api.Handle("/reconocimiento/intentos/{id}/incorrecto", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.MarcarIntentoIncorrecto))).Methods("PATCH")
+
+	// Docentes con muestras de un modelo facial anterior - Solo Administrador
+	api.Handle("/reconocimiento/reenrolamiento", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.ListarReenrolamientos))).Methods("GET")
+
+	// Escaneo de rostros duplicados entre docentes - Solo Administrador
+	api.Handle("/reconocimiento/duplicados", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Deduplicacion.IniciarEscaneo))).Methods("POST")
+	api.Handle("/reconocimiento/duplicados", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Deduplicacion.ListarReportes))).Methods("GET")
+	api.Handle("/reconocimiento/duplicados/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Deduplicacion.ObtenerReporte))).Methods("GET")
+	api.Handle("/reconocimiento/duplicados/pares/{id}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Deduplicacion.RevisarPar))).Methods("PATCH")
+
 	// Gestión de rostros de docentes - Solo Administrador
 	api.Handle("/docentes/{id}/rostro", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.RegistrarRostroDocente))).Methods("POST")
 	api.Handle("/docentes/{id}/rostro", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.ObtenerDescriptoresDocente))).Methods("GET")
-	api.Handle("/docentes/{id}/rostro/{index}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.EliminarDescriptorDocente))).Methods("DELETE")
+	api.Handle("/docentes/{id}/rostro/{rostroId}", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.EliminarDescriptorDocente))).Methods("DELETE")
 	api.Handle("/docentes/{id}/rostro", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Reconocimiento.LimpiarDescriptoresDocente))).Methods("DELETE")
+
+	// Consentimiento biométrico - Registro solo Administrador; consulta y revocación también el propio docente
+	api.Handle("/docentes/{id}/consentimiento", middleware.RequireRole(entities.RolAdministrador)(http.HandlerFunc(h.Consentimiento.Registrar))).Methods("POST")
+	api.Handle("/docentes/{id}/consentimiento", middleware.RequireRole(entities.RolAdministrador, entities.RolDocente)(http.HandlerFunc(h.Consentimiento.Obtener))).Methods("GET")
+	api.Handle("/docentes/{id}/consentimiento", middleware.RequireRole(entities.RolAdministrador, entities.RolDocente)(http.HandlerFunc(h.Consentimiento.Revocar))).Methods("DELETE")
+
+	// ==================== PORTAL DEL DOCENTE ====================
+	// Datos propios del docente vinculado al usuario autenticado - Solo Docente
+	api.Handle("/me", middleware.RequireRole(entities.RolDocente)(http.HandlerFunc(h.PortalDocente.Perfil))).Methods("GET")
+	api.Handle("/me/registros", middleware.RequireRole(entities.RolDocente)(http.HandlerFunc(h.PortalDocente.Registros))).Methods("GET")
+	api.Handle("/me/llave-actual", middleware.RequireRole(entities.RolDocente)(http.HandlerFunc(h.PortalDocente.LlaveActual))).Methods("GET")
+	api.Handle("/me/resumen", middleware.RequireRole(entities.RolDocente)(http.HandlerFunc(h.PortalDocente.ResumenMensual))).Methods("GET")
+	api.Handle("/me/justificaciones", middleware.RequireRole(entities.RolDocente)(http.HandlerFunc(h.PortalDocente.Justificaciones))).Methods("GET")
+	api.Handle("/me/justificaciones", middleware.RequireRole(entities.RolDocente)(http.HandlerFunc(h.PortalDocente.Justificar))).Methods("POST")
+	api.Handle("/me/justificaciones/{id}/documento", middleware.RequireRole(entities.RolDocente)(http.HandlerFunc(h.PortalDocente.SubirDocumento))).Methods("POST")
 }
 
 // Setup mantiene compatibilidad con código existente (sin rate limiting)
@@ -1,11 +1,14 @@
 package jwt
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,6 +19,10 @@ type Claims struct {
 	UserID   int          `json:"user_id"`
 	Username string       `json:"username"`
 	Rol      entities.Rol `json:"rol"`
+	// MustChangePassword limita el token al cambio de contraseña (ver middleware.AuthMiddleware)
+	MustChangePassword bool `json:"must_change_password,omitempty"`
+	// EnrolarDosFactores limita el token a activar la autenticación en dos pasos que exige el rol
+	EnrolarDosFactores bool `json:"enrolar_2fa,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -65,11 +72,15 @@ func GetTokenExpiration() time.Duration {
 	return 24 * time.Hour // 24 horas en desarrollo
 }
 
-func GenerateToken(user *entities.Usuario) (string, error) {
+// GenerateToken emite el token de sesión. enrolarDosFactores indica que el rol exige autenticación
+// en dos pasos y el usuario aún no la activó
+func GenerateToken(user *entities.Usuario, enrolarDosFactores bool) (string, error) {
 	claims := &Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Rol:      user.Rol,
+		UserID:             user.ID,
+		Username:           user.Username,
+		Rol:                user.Rol,
+		MustChangePassword: user.MustChangePassword,
+		EnrolarDosFactores: enrolarDosFactores,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(GetTokenExpiration())),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -81,6 +92,69 @@ func GenerateToken(user *entities.Usuario) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
+// VerificationClaims identifica al docente cuyo rostro fue verificado 1:1. El jti es el ID del
+// intento de verificación, que se consume al crear el registro para que el token no se reutilice
+type VerificationClaims struct {
+	DocenteID int `json:"docente_id"`
+	jwt.RegisteredClaims
+}
+
+// VerificationTokenExpiration es la vigencia de un token de verificación facial
+const VerificationTokenExpiration = 2 * time.Minute
+
+// verificationSecret deriva un secreto distinto al de sesión para que un token de
+// verificación nunca sea aceptado como token de autenticación (y viceversa)
+func verificationSecret() []byte {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("verificacion-facial"))
+	return mac.Sum(nil)
+}
+
+// GenerateVerificationToken emite un token de corta duración que acredita
+// una verificación facial exitosa del docente en el intento intentoID
+func GenerateVerificationToken(docenteID int, intentoID int) (string, error) {
+	claims := &VerificationClaims{
+		DocenteID: docenteID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        strconv.Itoa(intentoID),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(VerificationTokenExpiration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "sistema-ingreso-docente",
+			Subject:   "verificacion_facial",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(verificationSecret())
+}
+
+// ValidateVerificationToken verifica que el token sea válido y corresponda al docente, y retorna
+// el ID del intento de verificación que lo originó
+func ValidateVerificationToken(tokenString string, docenteID int) (int, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &VerificationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de firma inválido: %v", token.Header["alg"])
+		}
+		return verificationSecret(), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	claims, ok := token.Claims.(*VerificationClaims)
+	if !ok || !token.Valid {
+		return 0, fmt.Errorf("token de verificación inválido")
+	}
+	if claims.DocenteID != docenteID {
+		return 0, fmt.Errorf("el token de verificación corresponde a otro docente")
+	}
+	intentoID, err := strconv.Atoi(claims.ID)
+	if err != nil || intentoID <= 0 {
+		return 0, fmt.Errorf("el token de verificación no indica el intento")
+	}
+	return intentoID, nil
+}
+
 func ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -99,3 +173,55 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, fmt.Errorf("token inválido")
 }
+
+// DesafioClaims identifica al usuario que superó la contraseña y debe presentar su segundo factor
+type DesafioClaims struct {
+	UserID int `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// DesafioTokenExpiration es el tiempo para ingresar el código del segundo factor
+const DesafioTokenExpiration = 5 * time.Minute
+
+// desafioSecret deriva un secreto propio para que el token de desafío no sirva como token de
+// sesión ni de verificación facial
+func desafioSecret() []byte {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("desafio-segundo-factor"))
+	return mac.Sum(nil)
+}
+
+// GenerateDesafioToken emite el token del primer paso del login con autenticación en dos pasos
+func GenerateDesafioToken(usuarioID int) (string, error) {
+	claims := &DesafioClaims{
+		UserID: usuarioID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(DesafioTokenExpiration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "sistema-ingreso-docente",
+			Subject:   "segundo_factor",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(desafioSecret())
+}
+
+// ValidateDesafioToken verifica el token de desafío y retorna el usuario al que corresponde
+func ValidateDesafioToken(tokenString string) (int, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &DesafioClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("método de firma inválido: %v", token.Header["alg"])
+		}
+		return desafioSecret(), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	claims, ok := token.Claims.(*DesafioClaims)
+	if !ok || !token.Valid || claims.Subject != "segundo_factor" {
+		return 0, fmt.Errorf("token de desafío inválido")
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+)
+
+func TestVerificationToken(t *testing.T) {
+	token, err := GenerateVerificationToken(7, 43)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	intentoID, err := ValidateVerificationToken(token, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if intentoID != 43 {
+		t.Errorf("intento = %d, se esperaba 43", intentoID)
+	}
+
+	if _, err := ValidateVerificationToken(token, 8); err == nil {
+		t.Error("se aceptó el token para otro docente")
+	}
+}
+
+func TestVerificationTokenRechazados(t *testing.T) {
+	firmar := func(claims *VerificationClaims, secreto []byte) string {
+		t.Helper()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secreto)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	vigente := func(jti string) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{ID: jti, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+	}
+	sesion, err := GenerateToken(&entities.Usuario{ID: 7, Username: "biblio", Rol: entities.RolBibliotecario}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	casos := map[string]string{
+		// Emitido antes de que el token llevara el intento: no se puede consumir
+		"sin intento": firmar(&VerificationClaims{DocenteID: 7, RegisteredClaims: vigente("")}, verificationSecret()),
+		"expirado": firmar(&VerificationClaims{DocenteID: 7, RegisteredClaims: jwt.RegisteredClaims{
+			ID: "43", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Second)),
+		}}, verificationSecret()),
+		"firmado con el secreto de sesión": firmar(&VerificationClaims{DocenteID: 7, RegisteredClaims: vigente("43")}, jwtSecret),
+		"token de sesión":                  sesion,
+	}
+	for nombre, token := range casos {
+		t.Run(nombre, func(t *testing.T) {
+			if _, err := ValidateVerificationToken(token, 7); err == nil {
+				t.Error("se aceptó el token")
+			}
+		})
+	}
+}
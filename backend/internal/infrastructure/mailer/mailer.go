@@ -0,0 +1,96 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverLog  = "log"
+)
+
+// Mensaje es un correo de texto plano a un único destinatario
+type Mensaje struct {
+	Para   string
+	Asunto string
+	Cuerpo string
+}
+
+// Mailer envía correos a los usuarios
+type Mailer interface {
+	Enviar(mensaje Mensaje) error
+}
+
+// NewFromEnv crea el mailer de MAIL_DRIVER: smtp usa SMTP_HOST, SMTP_PORT, SMTP_USERNAME,
+// SMTP_PASSWORD, SMTP_REQUIRE_TLS y MAIL_FROM; log (por defecto) solo escribe los correos en el log
+func NewFromEnv() (Mailer, string, error) {
+	driver := os.Getenv("MAIL_DRIVER")
+	if driver == "" {
+		driver = DriverLog
+	}
+
+	switch driver {
+	case DriverLog:
+		return NewLogMailer(), driver, nil
+	case DriverSMTP:
+		puerto := 587
+		if valor := os.Getenv("SMTP_PORT"); valor != "" {
+			var err error
+			if puerto, err = strconv.Atoi(valor); err != nil || puerto <= 0 || puerto > 65535 {
+				return nil, driver, fmt.Errorf("SMTP_PORT inválido: %q", valor)
+			}
+		}
+		exigirTLS, err := ExigirTLSFromEnv()
+		if err != nil {
+			return nil, driver, err
+		}
+		mailer, err := NewSMTPMailer(os.Getenv("SMTP_HOST"), puerto, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("MAIL_FROM"), exigirTLS)
+		return mailer, driver, err
+	default:
+		return nil, driver, fmt.Errorf("MAIL_DRIVER debe ser %s o %s", DriverSMTP, DriverLog)
+	}
+}
+
+// ExigirTLSFromEnv lee SMTP_REQUIRE_TLS. Por defecto se exige STARTTLS salvo con GO_ENV=development
+func ExigirTLSFromEnv() (bool, error) {
+	valor := strings.TrimSpace(os.Getenv("SMTP_REQUIRE_TLS"))
+	if valor == "" {
+		return os.Getenv("GO_ENV") != "development", nil
+	}
+	exigir, err := strconv.ParseBool(valor)
+	if err != nil {
+		return false, fmt.Errorf("SMTP_REQUIRE_TLS inválido: %q", valor)
+	}
+	return exigir, nil
+}
+
+// LogMailer escribe los correos en el log en lugar de enviarlos. Solo para desarrollo: el cuerpo
+// puede contener tokens de restablecimiento
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Enviar(mensaje Mensaje) error {
+	if err := validarCabeceras(mensaje); err != nil {
+		return err
+	}
+	log.Printf("[MAIL] Para: %s | Asunto: %s\n%s", mensaje.Para, mensaje.Asunto, mensaje.Cuerpo)
+	return nil
+}
+
+// validarCabeceras impide inyectar cabeceras con saltos de línea en el destinatario o el asunto
+func validarCabeceras(mensaje Mensaje) error {
+	if strings.TrimSpace(mensaje.Para) == "" {
+		return fmt.Errorf("destinatario requerido")
+	}
+	if strings.ContainsAny(mensaje.Para, "\r\n") || strings.ContainsAny(mensaje.Asunto, "\r\n") {
+		return fmt.Errorf("el destinatario y el asunto no pueden contener saltos de línea")
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpTimeout limita la conexión y la conversación con el servidor SMTP, para que un servidor
+// caído no deje colgada la petición que envía el correo
+const smtpTimeout = 15 * time.Second
+
+// SMTPMailer envía correos por SMTP. Usa STARTTLS si el servidor lo ofrece y se autentica solo si
+// hay usuario configurado. Con exigirTLS no envía nada a un servidor sin STARTTLS: los correos
+// llevan tokens de restablecimiento, y un intermediario podría quitar STARTTLS del EHLO. Sin
+// exigirTLS también funciona contra un servidor SMTP local de pruebas (MailHog, Mailpit)
+type SMTPMailer struct {
+	host      string
+	addr      string
+	usuario   string
+	password  string
+	remitente *mail.Address
+	exigirTLS bool
+	tls       *tls.Config
+}
+
+func NewSMTPMailer(host string, puerto int, usuario, password, remitente string, exigirTLS bool) (*SMTPMailer, error) {
+	if strings.TrimSpace(host) == "" {
+		return nil, fmt.Errorf("SMTP_HOST no configurado")
+	}
+	direccion, err := mail.ParseAddress(remitente)
+	if err != nil {
+		return nil, fmt.Errorf("MAIL_FROM inválido: %w", err)
+	}
+	return &SMTPMailer{
+		host:      host,
+		addr:      net.JoinHostPort(host, strconv.Itoa(puerto)),
+		usuario:   usuario,
+		password:  password,
+		remitente: direccion,
+		exigirTLS: exigirTLS,
+		tls:       &tls.Config{ServerName: host},
+	}, nil
+}
+
+func (m *SMTPMailer) Enviar(mensaje Mensaje) error {
+	if err := validarCabeceras(mensaje); err != nil {
+		return err
+	}
+	para, err := mail.ParseAddress(mensaje.Para)
+	if err != nil {
+		return fmt.Errorf("destinatario inválido: %w", err)
+	}
+
+	conn, err := net.DialTimeout("tcp", m.addr, smtpTimeout)
+	if err != nil {
+		return fmt.Errorf("error conectando al servidor SMTP %s: %w", m.addr, err)
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	cliente, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error iniciando la sesión SMTP: %w", err)
+	}
+	defer cliente.Close()
+
+	if ok, _ := cliente.Extension("STARTTLS"); ok {
+		if err := cliente.StartTLS(m.tls); err != nil {
+			return fmt.Errorf("error en STARTTLS: %w", err)
+		}
+	} else if m.exigirTLS {
+		return fmt.Errorf("el servidor SMTP %s no ofrece STARTTLS y SMTP_REQUIRE_TLS está activo", m.addr)
+	}
+	if m.usuario != "" {
+		// PlainAuth se niega a enviar la contraseña sin TLS salvo a localhost
+		if err := cliente.Auth(smtp.PlainAuth("", m.usuario, m.password, m.host)); err != nil {
+			return fmt.Errorf("error autenticando en el servidor SMTP: %w", err)
+		}
+	}
+
+	if err := cliente.Mail(m.remitente.Address); err != nil {
+		return fmt.Errorf("remitente rechazado: %w", err)
+	}
+	if err := cliente.Rcpt(para.Address); err != nil {
+		return fmt.Errorf("destinatario rechazado: %w", err)
+	}
+	escritor, err := cliente.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := escritor.Write(m.componer(para, mensaje)); err != nil {
+		escritor.Close()
+		return err
+	}
+	if err := escritor.Close(); err != nil {
+		return fmt.Errorf("el servidor SMTP rechazó el mensaje: %w", err)
+	}
+	return cliente.Quit()
+}
+
+// componer arma el mensaje RFC 5322 en texto plano UTF-8
+func (m *SMTPMailer) componer(para *mail.Address, mensaje Mensaje) []byte {
+	var buf bytes.Buffer
+	cabeceras := [][2]string{
+		{"From", m.remitente.String()},
+		{"To", para.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", mensaje.Asunto)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "8bit"},
+	}
+	for _, cabecera := range cabeceras {
+		fmt.Fprintf(&buf, "%s: %s\r\n", cabecera[0], cabecera[1])
+	}
+	buf.WriteString("\r\n")
+	cuerpo := strings.ReplaceAll(mensaje.Cuerpo, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(cuerpo, "\n", "\r\n"))
+	return buf.Bytes()
+}
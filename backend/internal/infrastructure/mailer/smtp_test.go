@@ -0,0 +1,298 @@
+package mailer
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// servidorSMTPFalso atiende una sola conversación SMTP en 127.0.0.1 y guarda los comandos
+// recibidos, las credenciales de AUTH PLAIN y el contenido de DATA
+type servidorSMTPFalso struct {
+	listener  net.Listener
+	starttls  bool
+	tlsConfig *tls.Config
+
+	mu          sync.Mutex
+	comandos    []string
+	credencial  string
+	datos       string
+	conTLS      bool
+	terminado   chan struct{}
+	errServidor error
+}
+
+func nuevoServidorSMTPFalso(t *testing.T, starttls bool, certificado tls.Certificate) *servidorSMTPFalso {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &servidorSMTPFalso{
+		listener:  listener,
+		starttls:  starttls,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{certificado}},
+		terminado: make(chan struct{}),
+	}
+	t.Cleanup(func() { listener.Close() })
+	go s.atender()
+	return s
+}
+
+func (s *servidorSMTPFalso) puerto() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *servidorSMTPFalso) atender() {
+	defer close(s.terminado)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		s.errServidor = err
+		return
+	}
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	lector := bufio.NewReader(conn)
+	responder := func(linea string) { conn.Write([]byte(linea + "\r\n")) }
+	responder("220 smtp.prueba ESMTP")
+
+	for {
+		linea, err := lector.ReadString('\n')
+		if err != nil {
+			return
+		}
+		linea = strings.TrimRight(linea, "\r\n")
+		verbo := strings.ToUpper(strings.SplitN(linea, " ", 2)[0])
+
+		s.mu.Lock()
+		if verbo == "AUTH" {
+			s.comandos = append(s.comandos, "AUTH")
+		} else {
+			s.comandos = append(s.comandos, linea)
+		}
+		s.mu.Unlock()
+
+		switch verbo {
+		case "EHLO":
+			if s.starttls && !s.conTLS {
+				responder("250-smtp.prueba")
+				responder("250 STARTTLS")
+			} else {
+				responder("250-smtp.prueba")
+				responder("250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			responder("220 listo para TLS")
+			servidorTLS := tls.Server(conn, s.tlsConfig)
+			if err := servidorTLS.Handshake(); err != nil {
+				s.errServidor = err
+				return
+			}
+			conn = servidorTLS
+			lector = bufio.NewReader(conn)
+			s.conTLS = true
+		case "AUTH":
+			partes := strings.Fields(linea)
+			if len(partes) == 3 {
+				decodificada, _ := base64.StdEncoding.DecodeString(partes[2])
+				s.mu.Lock()
+				s.credencial = string(decodificada)
+				s.mu.Unlock()
+			}
+			responder("235 autenticado")
+		case "MAIL", "RCPT":
+			responder("250 ok")
+		case "DATA":
+			responder("354 termine con <CRLF>.<CRLF>")
+			var cuerpo strings.Builder
+			for {
+				linea, err := lector.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if linea == ".\r\n" {
+					break
+				}
+				cuerpo.WriteString(linea)
+			}
+			s.mu.Lock()
+			s.datos = cuerpo.String()
+			s.mu.Unlock()
+			responder("250 encolado")
+		case "QUIT":
+			responder("221 adios")
+			return
+		default:
+			responder("250 ok")
+		}
+	}
+}
+
+// certificadoDePrueba genera un certificado autofirmado para 127.0.0.1
+func certificadoDePrueba(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	clave, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plantilla := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "smtp.prueba"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, plantilla, plantilla, &clave.PublicKey, clave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raices := x509.NewCertPool()
+	raices.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: clave}, raices
+}
+
+func TestSMTPMailer(t *testing.T) {
+	certificado, raices := certificadoDePrueba(t)
+	mensaje := Mensaje{
+		Para:   "Juan Perez <jperez@example.org>",
+		Asunto: "Restablecer contraseña",
+		Cuerpo: "Su token de restablecimiento es: tok-12345",
+	}
+
+	casos := []struct {
+		nombre        string
+		starttls      bool
+		exigirTLS     bool
+		usuario       string
+		esperaError   bool
+		esperaComando []string
+	}{
+		{
+			nombre:        "STARTTLS y autenticación",
+			starttls:      true,
+			exigirTLS:     true,
+			usuario:       "mailer",
+			esperaComando: []string{"EHLO", "STARTTLS", "EHLO", "AUTH", "MAIL FROM:<no-reply@example.org>", "RCPT TO:<jperez@example.org>", "DATA", "QUIT"},
+		},
+		{
+			nombre:        "servidor sin STARTTLS con TLS exigido",
+			starttls:      false,
+			exigirTLS:     true,
+			usuario:       "mailer",
+			esperaError:   true,
+			esperaComando: []string{"EHLO"},
+		},
+		{
+			// MailHog o Mailpit en desarrollo
+			nombre:        "servidor local sin TLS ni credenciales",
+			starttls:      false,
+			exigirTLS:     false,
+			esperaComando: []string{"EHLO", "MAIL FROM:<no-reply@example.org>", "RCPT TO:<jperez@example.org>", "DATA", "QUIT"},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			servidor := nuevoServidorSMTPFalso(t, c.starttls, certificado)
+			m, err := NewSMTPMailer("127.0.0.1", servidor.puerto(), c.usuario, "secreto", "Sistema <no-reply@example.org>", c.exigirTLS)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.tls.RootCAs = raices
+
+			err = m.Enviar(mensaje)
+			if c.esperaError && err == nil {
+				t.Fatal("se esperaba error")
+			}
+			if !c.esperaError && err != nil {
+				t.Fatal(err)
+			}
+			// Al rechazar el servidor el cliente cierra la conexión sin QUIT; se espera a que el servidor termine
+			servidor.listener.Close()
+			<-servidor.terminado
+			if servidor.errServidor != nil {
+				t.Fatal(servidor.errServidor)
+			}
+
+			servidor.mu.Lock()
+			defer servidor.mu.Unlock()
+			if got := normalizarComandos(servidor.comandos); strings.Join(got, "|") != strings.Join(c.esperaComando, "|") {
+				t.Errorf("comandos = %v, se esperaban %v", got, c.esperaComando)
+			}
+			if c.esperaError {
+				if servidor.datos != "" {
+					t.Error("se envió el mensaje a pesar del error")
+				}
+				return
+			}
+
+			if c.usuario != "" && servidor.credencial != "\x00mailer\x00secreto" {
+				t.Errorf("credencial AUTH PLAIN = %q", servidor.credencial)
+			}
+			if !strings.Contains(servidor.datos, "tok-12345") {
+				t.Errorf("el cuerpo no contiene el token: %q", servidor.datos)
+			}
+			if !strings.Contains(servidor.datos, "To: \"Juan Perez\" <jperez@example.org>\r\n") {
+				t.Errorf("cabecera To incorrecta: %q", servidor.datos)
+			}
+		})
+	}
+}
+
+// normalizarComandos quita el argumento de EHLO, que depende del nombre del equipo
+func normalizarComandos(comandos []string) []string {
+	normalizados := make([]string, 0, len(comandos))
+	for _, comando := range comandos {
+		if strings.HasPrefix(strings.ToUpper(comando), "EHLO") {
+			comando = "EHLO"
+		}
+		normalizados = append(normalizados, comando)
+	}
+	return normalizados
+}
+
+func TestExigirTLSFromEnv(t *testing.T) {
+	casos := []struct {
+		goEnv, valor string
+		espera       bool
+		esperaError  bool
+	}{
+		{"production", "", true, false},
+		{"", "", true, false},
+		{"development", "", false, false},
+		{"development", "true", true, false},
+		{"production", "false", false, false},
+		{"production", "quizas", false, true},
+	}
+	for _, c := range casos {
+		t.Run(c.goEnv+"/"+c.valor, func(t *testing.T) {
+			t.Setenv("GO_ENV", c.goEnv)
+			t.Setenv("SMTP_REQUIRE_TLS", c.valor)
+			exigir, err := ExigirTLSFromEnv()
+			if (err != nil) != c.esperaError {
+				t.Fatalf("error = %v", err)
+			}
+			if exigir != c.espera {
+				t.Errorf("exigir = %t, se esperaba %t", exigir, c.espera)
+			}
+		})
+	}
+}
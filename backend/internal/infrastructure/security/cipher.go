@@ -0,0 +1,176 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	// BiometricKeyEnv contiene la clave activa (32 bytes en base64) para cifrar datos biométricos
+	BiometricKeyEnv = "BIOMETRIC_KEY"
+	// BiometricPreviousKeysEnv contiene claves anteriores separadas por comas, solo para descifrar
+	// datos aún no re-cifrados tras una rotación
+	BiometricPreviousKeysEnv = "BIOMETRIC_PREVIOUS_KEYS"
+
+	// TOTPKeyEnv y TOTPPreviousKeysEnv son los equivalentes para los secretos TOTP
+	TOTPKeyEnv          = "TOTP_KEY"
+	TOTPPreviousKeysEnv = "TOTP_PREVIOUS_KEYS"
+
+	keySize = 32
+	// devBiometricSeed y devTOTPSeed derivan claves fijas para desarrollo, así los datos
+	// sobreviven reinicios
+	devBiometricSeed = "sistema-ingreso-docente/desarrollo/biometria"
+	devTOTPSeed      = "sistema-ingreso-docente/desarrollo/totp"
+)
+
+// ErrUnknownKey indica que el dato fue cifrado con una clave que ya no está configurada
+var ErrUnknownKey = errors.New("clave de cifrado desconocida")
+
+// Cipher cifra datos sensibles (descriptores y evidencias biométricas, secretos TOTP) con
+// AES-256-GCM. Cada dato cifrado se asocia al identificador de la clave usada, para poder
+// rotar claves sin perder información
+type Cipher struct {
+	activeID string
+	keys     map[string]cipher.AEAD
+}
+
+// NewCipher crea el cifrador con la clave activa y claves anteriores (solo lectura)
+func NewCipher(activeKey []byte, previousKeys ...[]byte) (*Cipher, error) {
+	c := &Cipher{keys: make(map[string]cipher.AEAD)}
+
+	activeID, err := c.addKey(activeKey)
+	if err != nil {
+		return nil, err
+	}
+	c.activeID = activeID
+
+	for _, key := range previousKeys {
+		if _, err := c.addKey(key); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// NewBiometricCipherFromEnv carga las claves desde BIOMETRIC_KEY y BIOMETRIC_PREVIOUS_KEYS
+// En producción la clave es obligatoria; en desarrollo se usa una clave fija con advertencia
+func NewBiometricCipherFromEnv() (*Cipher, error) {
+	return newCipherFromEnv(BiometricKeyEnv, BiometricPreviousKeysEnv, devBiometricSeed)
+}
+
+// NewTOTPCipherFromEnv carga las claves de TOTP_KEY y TOTP_PREVIOUS_KEYS, con las que se cifran
+// los secretos de autenticación en dos pasos. Es una clave distinta de la biométrica: rotar una
+// no afecta los datos de la otra
+func NewTOTPCipherFromEnv() (*Cipher, error) {
+	return newCipherFromEnv(TOTPKeyEnv, TOTPPreviousKeysEnv, devTOTPSeed)
+}
+
+func newCipherFromEnv(keyEnv, previousKeysEnv, devSeed string) (*Cipher, error) {
+	encoded := strings.TrimSpace(os.Getenv(keyEnv))
+	var activeKey []byte
+	if encoded == "" {
+		if os.Getenv("GO_ENV") == "production" {
+			return nil, fmt.Errorf("%s es obligatorio en producción", keyEnv)
+		}
+		sum := sha256.Sum256([]byte(devSeed))
+		activeKey = sum[:]
+		log.Printf("ADVERTENCIA: Usando %s de desarrollo. NO usar en producción.", keyEnv)
+	} else {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%s inválida: %w", keyEnv, err)
+		}
+		activeKey = key
+	}
+
+	var previousKeys [][]byte
+	for _, value := range strings.Split(os.Getenv(previousKeysEnv), ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		key, err := decodeKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s inválida: %w", previousKeysEnv, err)
+		}
+		previousKeys = append(previousKeys, key)
+	}
+
+	return NewCipher(activeKey, previousKeys...)
+}
+
+// ActiveKeyID retorna el identificador de la clave con la que se cifra
+func (c *Cipher) ActiveKeyID() string {
+	return c.activeID
+}
+
+// Encrypt cifra el dato con la clave activa. associatedData (por ejemplo el ID del dueño)
+// debe repetirse al descifrar, lo que impide mover un dato cifrado a otro registro
+func (c *Cipher) Encrypt(plaintext, associatedData []byte) ([]byte, string, error) {
+	aead := c.keys[c.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", fmt.Errorf("error generando nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, associatedData), c.activeID, nil
+}
+
+// Decrypt descifra un dato cifrado con la clave indicada
+func (c *Cipher) Decrypt(ciphertext []byte, keyID string, associatedData []byte) ([]byte, error) {
+	aead, ok := c.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("dato cifrado inválido")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo descifrar el dato: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (c *Cipher) addKey(key []byte) (string, error) {
+	if len(key) != keySize {
+		return "", fmt.Errorf("la clave de cifrado debe tener %d bytes", keySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	id := keyID(key)
+	c.keys[id] = aead
+	return id, nil
+}
+
+// keyID identifica una clave por su huella, sin revelarla
+func keyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("se espera base64 (openssl rand -base64 32)")
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("se esperan %d bytes y hay %d", keySize, len(key))
+	}
+	return key, nil
+}
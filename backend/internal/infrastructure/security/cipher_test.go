@@ -0,0 +1,203 @@
+package security
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func claveDePrueba(relleno byte) []byte {
+	return bytes.Repeat([]byte{relleno}, keySize)
+}
+
+func TestCipherIdaYVuelta(t *testing.T) {
+	c, err := NewCipher(claveDePrueba(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	descriptor := []byte("descriptor facial de 128 floats")
+	aad := []byte("rostros_docente:7")
+
+	cifrado, claveID, err := c.Encrypt(descriptor, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claveID != c.ActiveKeyID() {
+		t.Errorf("clave = %s, se esperaba la activa %s", claveID, c.ActiveKeyID())
+	}
+	if bytes.Contains(cifrado, descriptor) {
+		t.Error("el dato cifrado contiene el texto plano")
+	}
+	otro, _, err := c.Encrypt(descriptor, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(cifrado, otro) {
+		t.Error("dos cifrados del mismo dato son iguales: el nonce se repite")
+	}
+
+	descifrado, err := c.Decrypt(cifrado, claveID, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(descifrado, descriptor) {
+		t.Errorf("descifrado = %q", descifrado)
+	}
+
+	alterado := append([]byte{}, cifrado...)
+	alterado[len(alterado)-1] ^= 1
+	if _, err := c.Decrypt(alterado, claveID, aad); err == nil {
+		t.Error("se descifró un dato alterado")
+	}
+	if _, err := c.Decrypt(cifrado[:4], claveID, aad); err == nil {
+		t.Error("se descifró un dato más corto que el nonce")
+	}
+}
+
+// Una muestra copiada a otro docente no debe descifrar
+func TestCipherAADDeOtroDocente(t *testing.T) {
+	c, err := NewCipher(claveDePrueba(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cifrado, claveID, err := c.Encrypt([]byte("descriptor"), []byte("rostros_docente:7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, aad := range []string{"rostros_docente:8", "rostros_docente:70", ""} {
+		if _, err := c.Decrypt(cifrado, claveID, []byte(aad)); err == nil {
+			t.Errorf("se descifró con el AAD %q", aad)
+		}
+	}
+}
+
+// Reproduce la rotación de cmd/recifrar: la clave nueva activa y la anterior solo para descifrar
+func TestCipherRotacion(t *testing.T) {
+	anterior, nueva := claveDePrueba(1), claveDePrueba(2)
+	aad := []byte("rostros_docente:7")
+
+	viejo, err := NewCipher(anterior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cifrado, claveAnterior, err := viejo.Encrypt([]byte("descriptor"), aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotando, err := NewCipher(nueva, anterior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotando.ActiveKeyID() == claveAnterior {
+		t.Fatal("la clave activa sigue siendo la anterior")
+	}
+	descifrado, err := rotando.Decrypt(cifrado, claveAnterior, aad)
+	if err != nil {
+		t.Fatalf("no se descifró con la clave anterior: %v", err)
+	}
+	recifrado, claveNueva, err := rotando.Encrypt(descifrado, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claveNueva != rotando.ActiveKeyID() {
+		t.Errorf("re-cifrado con %s, se esperaba la clave activa", claveNueva)
+	}
+
+	// Retirada la clave anterior, solo queda legible lo re-cifrado
+	final, err := NewCipher(nueva)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := final.Decrypt(recifrado, claveNueva, aad); err != nil {
+		t.Errorf("no se descifró lo re-cifrado: %v", err)
+	}
+	if _, err := final.Decrypt(cifrado, claveAnterior, aad); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("error = %v, se esperaba ErrUnknownKey", err)
+	}
+}
+
+func TestNewCipherFromEnv(t *testing.T) {
+	valida := base64.StdEncoding.EncodeToString(claveDePrueba(1))
+	anterior := base64.StdEncoding.EncodeToString(claveDePrueba(2))
+	corta := base64.StdEncoding.EncodeToString(claveDePrueba(1)[:16])
+
+	casos := []struct {
+		nombre      string
+		goEnv       string
+		clave       string
+		anteriores  string
+		esperaError bool
+	}{
+		{nombre: "producción sin clave", goEnv: "production", esperaError: true},
+		{nombre: "producción con clave", goEnv: "production", clave: valida},
+		{nombre: "desarrollo sin clave", goEnv: "development"},
+		{nombre: "clave de 16 bytes", goEnv: "production", clave: corta, esperaError: true},
+		{nombre: "clave que no es base64", goEnv: "production", clave: "no-es-base64!", esperaError: true},
+		{nombre: "claves anteriores", goEnv: "production", clave: valida, anteriores: anterior + ", " + valida},
+		{nombre: "clave anterior inválida", goEnv: "production", clave: valida, anteriores: anterior + "," + corta, esperaError: true},
+	}
+
+	constructores := map[string]struct {
+		nuevo           func() (*Cipher, error)
+		clave, anterior string
+	}{
+		"biometría": {NewBiometricCipherFromEnv, BiometricKeyEnv, BiometricPreviousKeysEnv},
+		"TOTP":      {NewTOTPCipherFromEnv, TOTPKeyEnv, TOTPPreviousKeysEnv},
+	}
+
+	for nombreCifrador, cifrador := range constructores {
+		for _, c := range casos {
+			t.Run(nombreCifrador+"/"+c.nombre, func(t *testing.T) {
+				t.Setenv("GO_ENV", c.goEnv)
+				t.Setenv(BiometricKeyEnv, "")
+				t.Setenv(TOTPKeyEnv, "")
+				t.Setenv(cifrador.clave, c.clave)
+				t.Setenv(cifrador.anterior, c.anteriores)
+
+				cipher, err := cifrador.nuevo()
+				if c.esperaError {
+					if err == nil {
+						t.Fatal("se esperaba error")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c.clave != "" && cipher.ActiveKeyID() != keyID(claveDePrueba(1)) {
+					t.Errorf("clave activa = %s, se esperaba la configurada", cipher.ActiveKeyID())
+				}
+			})
+		}
+	}
+}
+
+// Las claves de desarrollo son fijas para que los datos sobrevivan reinicios, pero distintas
+// entre biometría y TOTP
+func TestClavesDeDesarrollo(t *testing.T) {
+	t.Setenv("GO_ENV", "development")
+	t.Setenv(BiometricKeyEnv, "")
+	t.Setenv(TOTPKeyEnv, "")
+
+	primera, err := NewBiometricCipherFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	segunda, err := NewBiometricCipherFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	totp, err := NewTOTPCipherFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primera.ActiveKeyID() != segunda.ActiveKeyID() {
+		t.Error("la clave biométrica de desarrollo cambia entre arranques")
+	}
+	if primera.ActiveKeyID() == totp.ActiveKeyID() {
+		t.Error("biometría y TOTP comparten la clave de desarrollo")
+	}
+}
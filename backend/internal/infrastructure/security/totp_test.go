@@ -0,0 +1,98 @@
+package security
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secretoRFC6238 es la clave SHA-1 de los vectores de prueba del RFC 6238, "12345678901234567890", en base32
+const secretoRFC6238 = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Los vectores del RFC 6238 (apéndice B) son de 8 dígitos; con 6 se conservan los últimos 6
+func TestTOTPCodeVectoresRFC6238(t *testing.T) {
+	vectores := []struct {
+		unix   int64
+		codigo string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, v := range vectores {
+		codigo, err := TOTPCode(secretoRFC6238, TOTPStep(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if codigo != v.codigo {
+			t.Errorf("T=%d: código %s, se esperaba %s", v.unix, codigo, v.codigo)
+		}
+	}
+
+	// Algunas aplicaciones muestran el secreto en minúsculas
+	codigo, err := TOTPCode(strings.ToLower(secretoRFC6238), TOTPStep(time.Unix(59, 0)))
+	if err != nil || codigo != "287082" {
+		t.Errorf("secreto en minúsculas: código %s, error %v", codigo, err)
+	}
+	if _, err := TOTPCode("no-es-base32!", 1); err == nil {
+		t.Error("se aceptó un secreto inválido")
+	}
+}
+
+func TestValidateTOTPVentana(t *testing.T) {
+	ahora := time.Unix(1111111111, 0)
+	actual := TOTPStep(ahora)
+	codigoDe := func(paso int64) string {
+		codigo, err := TOTPCode(secretoRFC6238, paso)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return codigo
+	}
+
+	casos := []struct {
+		nombre string
+		codigo string
+		paso   int64
+		acepta bool
+	}{
+		{"paso actual", codigoDe(actual), actual, true},
+		{"paso anterior", codigoDe(actual - 1), actual - 1, true},
+		{"paso siguiente", codigoDe(actual + 1), actual + 1, true},
+		{"dos pasos antes", codigoDe(actual - 2), 0, false},
+		{"dos pasos después", codigoDe(actual + 2), 0, false},
+		{"con espacios", " " + codigoDe(actual) + "\n", actual, true},
+		{"de 8 dígitos", "14050471", 0, false},
+		{"vacío", "", 0, false},
+	}
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			paso, ok := ValidateTOTP(secretoRFC6238, c.codigo, ahora)
+			if ok != c.acepta || paso != c.paso {
+				t.Errorf("ValidateTOTP = (%d, %t), se esperaba (%d, %t)", paso, ok, c.paso, c.acepta)
+			}
+		})
+	}
+}
+
+func TestTOTPURL(t *testing.T) {
+	uri, err := url.Parse(TOTPURL("Biblioteca UMSA", "jperez", secretoRFC6238))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Biblioteca UMSA:jperez" {
+		t.Errorf("URI = %s", uri)
+	}
+	params := uri.Query()
+	for clave, espera := range map[string]string{
+		"secret": secretoRFC6238, "issuer": "Biblioteca UMSA", "algorithm": "SHA1", "digits": "6", "period": "30",
+	} {
+		if params.Get(clave) != espera {
+			t.Errorf("%s = %q, se esperaba %q", clave, params.Get(clave), espera)
+		}
+	}
+}
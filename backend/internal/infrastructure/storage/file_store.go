@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileStore guarda archivos en un directorio local con permisos restringidos al proceso
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore crea el almacén y su directorio si no existe
+func NewFileStore(dir string) (*FileStore, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("directorio de almacenamiento no configurado")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("no se pudo crear el directorio %s: %w", dir, err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Save escribe el archivo de forma atómica: primero a un temporal y luego se renombra
+func (s *FileStore) Save(name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Read lee un archivo del almacén
+func (s *FileStore) Read(name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// Delete elimina un archivo; no es un error si ya no existe
+func (s *FileStore) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path valida que el nombre no salga del directorio del almacén
+func (s *FileStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("nombre de archivo inválido")
+	}
+	return filepath.Join(s.dir, name), nil
+}
@@ -0,0 +1,122 @@
+package recognition
+
+import "sort"
+
+// DuplicatePair resume la comparación entre las muestras de dos docentes distintos cuyos
+// rostros coinciden. Las distancias se calculan con CompareFaces y el mismo umbral de la identificación
+type DuplicatePair struct {
+	DocenteA     int
+	DocenteB     int
+	Matches      int     // Pares de muestras (A, B) por debajo del umbral
+	Comparisons  int     // Total de pares de muestras comparados
+	BestDistance float32 // Menor distancia entre una muestra de A y una de B
+	MeanDistance float32 // Distancia promedio entre todas las muestras de A y B
+	// Conclusive indica que alguna muestra de un docente sería identificada como el otro
+	// con la regla de IsIdentityMatch
+	Conclusive bool
+	// Group agrupa los docentes conectados por coincidencias (misma persona en 3+ registros)
+	Group int
+}
+
+// FindDuplicates compara todas las muestras de cada par de docentes y retorna los pares con
+// al menos una coincidencia, agrupados y ordenados de mayor a menor evidencia
+func FindDuplicates(samples map[int][]FaceDescriptor) []DuplicatePair {
+	ids := make([]int, 0, len(samples))
+	for id, descriptors := range samples {
+		if len(descriptors) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	pairs := []DuplicatePair{}
+	for i := 0; i < len(ids); i++ {
+		for j := i + 1; j < len(ids); j++ {
+			if pair, ok := comparePeople(ids[i], samples[ids[i]], ids[j], samples[ids[j]]); ok {
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+
+	groupPairs(pairs)
+	sort.SliceStable(pairs, func(a, b int) bool {
+		if pairs[a].Conclusive != pairs[b].Conclusive {
+			return pairs[a].Conclusive
+		}
+		if pairs[a].Matches != pairs[b].Matches {
+			return pairs[a].Matches > pairs[b].Matches
+		}
+		return pairs[a].BestDistance < pairs[b].BestDistance
+	})
+	return pairs
+}
+
+func comparePeople(idA int, samplesA []FaceDescriptor, idB int, samplesB []FaceDescriptor) (DuplicatePair, bool) {
+	pair := DuplicatePair{DocenteA: idA, DocenteB: idB, BestDistance: -1}
+	var total float32
+
+	// matchesB cuenta, por cada muestra de B, cuántas muestras de A coinciden con ella
+	matchesB := make([]int, len(samplesB))
+	for _, a := range samplesA {
+		matchesA := 0
+		for k, b := range samplesB {
+			distance := CompareFaces(a, b)
+			total += distance
+			pair.Comparisons++
+			if pair.BestDistance < 0 || distance < pair.BestDistance {
+				pair.BestDistance = distance
+			}
+			if distance < tolerance {
+				pair.Matches++
+				matchesA++
+				matchesB[k]++
+			}
+		}
+		if IsIdentityMatch(matchesA, len(samplesB)) {
+			pair.Conclusive = true
+		}
+	}
+	for _, matches := range matchesB {
+		if IsIdentityMatch(matches, len(samplesA)) {
+			pair.Conclusive = true
+		}
+	}
+
+	if pair.Matches == 0 {
+		return DuplicatePair{}, false
+	}
+	pair.MeanDistance = total / float32(pair.Comparisons)
+	return pair, true
+}
+
+// groupPairs asigna un número de grupo a los pares conectados (union-find sobre docentes)
+func groupPairs(pairs []DuplicatePair) {
+	parent := make(map[int]int)
+	var find func(int) int
+	find = func(id int) int {
+		if _, ok := parent[id]; !ok {
+			parent[id] = id
+		}
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+
+	for _, pair := range pairs {
+		rootA, rootB := find(pair.DocenteA), find(pair.DocenteB)
+		if rootA != rootB {
+			parent[max(rootA, rootB)] = min(rootA, rootB)
+		}
+	}
+
+	// Los grupos se numeran desde 1 en el orden del docente de menor ID
+	groups := make(map[int]int)
+	for i := range pairs {
+		root := find(pairs[i].DocenteA)
+		if _, ok := groups[root]; !ok {
+			groups[root] = len(groups) + 1
+		}
+		pairs[i].Group = groups[root]
+	}
+}
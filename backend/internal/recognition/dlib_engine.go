@@ -0,0 +1,73 @@
+//go:build dlib
+
+package recognition
+
+import (
+	"fmt"
+
+	"github.com/Kagami/go-face"
+)
+
+// dlibModelVersion identifica el modelo ResNet de dlib usado por go-face
+const dlibModelVersion = "dlib_face_recognition_resnet_model_v1"
+
+func init() {
+	engineFactories[EngineDlib] = func(modelDir string) (FaceEngine, error) {
+		return NewDlibEngine(modelDir)
+	}
+}
+
+// DlibEngine implementa FaceEngine con go-face (dlib). El reconocedor de go-face
+// sincroniza internamente sus llamadas, por lo que una instancia se comparte entre peticiones
+type DlibEngine struct {
+	rec *face.Recognizer
+}
+
+// NewDlibEngine carga los modelos pre-entrenados de dlib desde modelDir
+func NewDlibEngine(modelDir string) (*DlibEngine, error) {
+	rec, err := face.NewRecognizer(modelDir)
+	if err != nil {
+		return nil, fmt.Errorf("error al inicializar el reconocedor: %v", err)
+	}
+	return &DlibEngine{rec: rec}, nil
+}
+
+// Detect detecta rostros en una imagen JPEG desde bytes
+func (e *DlibEngine) Detect(imageData []byte) ([]FaceDescriptor, error) {
+	faces, err := e.rec.Recognize(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("error al procesar la imagen: %v", err)
+	}
+	return convertFaces(faces), nil
+}
+
+// ModelVersion retorna la versión del modelo de descriptores de dlib
+func (e *DlibEngine) ModelVersion() string {
+	return dlibModelVersion
+}
+
+// Close cierra el reconocedor y libera recursos
+func (e *DlibEngine) Close() {
+	if e.rec != nil {
+		e.rec.Close()
+	}
+}
+
+// convertFaces convierte []face.Face a []FaceDescriptor
+func convertFaces(faces []face.Face) []FaceDescriptor {
+	if len(faces) == 0 {
+		return nil
+	}
+
+	result := make([]FaceDescriptor, len(faces))
+	for i, f := range faces {
+		result[i] = FaceDescriptor{
+			Descriptor: f.Descriptor,
+			Rectangle: Rectangle{
+				Min: Point{X: f.Rectangle.Min.X, Y: f.Rectangle.Min.Y},
+				Max: Point{X: f.Rectangle.Max.X, Y: f.Rectangle.Max.Y},
+			},
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package recognition
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// EngineDlib es el motor de producción basado en dlib (requiere CGO y compilar con -tags dlib)
+	EngineDlib = "dlib"
+	// EngineFake es el motor determinista para desarrollo y pruebas, no requiere dlib
+	EngineFake = "fake"
+)
+
+// FaceEngine abstrae el motor de reconocimiento facial: detecta los rostros de una
+// imagen y calcula el descriptor de cada uno. Las implementaciones deben poder
+// usarse desde varias peticiones a la vez.
+type FaceEngine interface {
+	// Detect detecta y describe los rostros de una imagen JPEG en memoria
+	// (ver PrepareImage para normalizar otros formatos)
+	Detect(imageData []byte) ([]FaceDescriptor, error)
+	// ModelVersion identifica el modelo que genera los descriptores. Si cambia, las
+	// muestras generadas con otra versión dejan de ser comparables y deben re-enrolarse
+	ModelVersion() string
+	// Close libera los recursos del motor
+	Close()
+}
+
+// engineFactories contiene los motores compilados en el binario
+// El motor dlib se registra solo al compilar con -tags dlib
+var engineFactories = map[string]func(modelDir string) (FaceEngine, error){
+	EngineFake: func(string) (FaceEngine, error) { return NewFakeEngine(), nil },
+}
+
+// NewEngine crea el motor indicado. modelDir es el directorio de modelos pre-entrenados
+func NewEngine(name, modelDir string) (FaceEngine, error) {
+	factory, ok := engineFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("motor de reconocimiento %q no disponible (disponibles: %v)", name, AvailableEngines())
+	}
+	return factory(modelDir)
+}
+
+// DefaultEngine retorna dlib si fue compilado, o el motor fake en caso contrario
+func DefaultEngine() string {
+	if _, ok := engineFactories[EngineDlib]; ok {
+		return EngineDlib
+	}
+	return EngineFake
+}
+
+// AvailableEngines retorna los nombres de los motores compilados en el binario
+func AvailableEngines() []string {
+	nombres := make([]string, 0, len(engineFactories))
+	for nombre := range engineFactories {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	return nombres
+}
@@ -1,19 +1,17 @@
 package recognition
 
-import (
-	"encoding/json"
-	"fmt"
-
-	"github.com/Kagami/go-face"
-)
+import "fmt"
 
 const (
-	// Directorios de modelos pre-entrenados de dlib
-	modelDir = "./models"
 	// Umbral de similitud para considerar un rostro como coincidente
 	tolerance = 0.25
+	// DescriptorSize es la cantidad de valores de cada descriptor facial
+	DescriptorSize = 128
 )
 
+// MatchTolerance expone el umbral de distancia para reportes y auditoría
+const MatchTolerance = tolerance
+
 type FaceDescriptor struct {
 	Descriptor [128]float32 `json:"descriptor"`
 	Rectangle  Rectangle    `json:"rectangle"`
@@ -29,77 +27,6 @@ type Point struct {
 	Y int `json:"y"`
 }
 
-// Recognizer encapsula el reconocedor facial
-type Recognizer struct {
-	rec *face.Recognizer
-}
-
-// NewRecognizer inicializa un nuevo reconocedor facial
-func NewRecognizer() (*Recognizer, error) {
-	rec, err := face.NewRecognizer(modelDir)
-	if err != nil {
-		return nil, fmt.Errorf("error al inicializar el reconocedor: %v", err)
-	}
-	return &Recognizer{rec: rec}, nil
-}
-
-// Close cierra el reconocedor y libera recursos
-func (r *Recognizer) Close() {
-	if r.rec != nil {
-		r.rec.Close()
-	}
-}
-
-// RecognizeFile detecta rostros en una imagen desde un archivo
-func (r *Recognizer) RecognizeFile(imagePath string) ([]FaceDescriptor, error) {
-	faces, err := r.rec.RecognizeFile(imagePath)
-	if err != nil {
-		return nil, fmt.Errorf("error al procesar la imagen: %v", err)
-	}
-
-	if len(faces) == 0 {
-		return nil, nil
-	}
-
-	// Convertir []face.Face a []FaceDescriptor
-	result := make([]FaceDescriptor, len(faces))
-	for i, f := range faces {
-		result[i] = FaceDescriptor{
-			Descriptor: f.Descriptor,
-			Rectangle: Rectangle{
-				Min: Point{X: f.Rectangle.Min.X, Y: f.Rectangle.Min.Y},
-				Max: Point{X: f.Rectangle.Max.X, Y: f.Rectangle.Max.Y},
-			},
-		}
-	}
-	return result, nil
-}
-
-// Recognize detecta rostros en una imagen desde bytes
-func (r *Recognizer) Recognize(imageData []byte) ([]FaceDescriptor, error) {
-	faces, err := r.rec.Recognize(imageData)
-	if err != nil {
-		return nil, fmt.Errorf("error al procesar la imagen: %v", err)
-	}
-
-	if len(faces) == 0 {
-		return nil, nil
-	}
-
-	// Convertir []face.Face a []FaceDescriptor
-	result := make([]FaceDescriptor, len(faces))
-	for i, f := range faces {
-		result[i] = FaceDescriptor{
-			Descriptor: f.Descriptor,
-			Rectangle: Rectangle{
-				Min: Point{X: f.Rectangle.Min.X, Y: f.Rectangle.Min.Y},
-				Max: Point{X: f.Rectangle.Max.X, Y: f.Rectangle.Max.Y},
-			},
-		}
-	}
-	return result, nil
-}
-
 // CompareFaces compara dos descriptores faciales y retorna la distancia euclidiana
 func CompareFaces(desc1, desc2 FaceDescriptor) float32 {
 	var sum float32
@@ -138,21 +65,12 @@ func GetBiggerFace(faces []FaceDescriptor) FaceDescriptor {
 	return faces[maxIndex]
 }
 
-// DescriptorToJSON convierte un descriptor facial a JSON
-func DescriptorToJSON(desc FaceDescriptor) (string, error) {
-	data, err := json.Marshal(desc)
-	if err != nil {
-		return "", fmt.Errorf("error al serializar descriptor: %v", err)
+// DescriptorFromVector reconstruye un descriptor a partir de sus valores
+func DescriptorFromVector(vector []float32) (FaceDescriptor, error) {
+	if len(vector) != DescriptorSize {
+		return FaceDescriptor{}, fmt.Errorf("descriptor inválido: se esperaban %d valores y hay %d", DescriptorSize, len(vector))
 	}
-	return string(data), nil
-}
-
-// JSONToDescriptor convierte JSON a un descriptor facial
-func JSONToDescriptor(jsonData string) (FaceDescriptor, error) {
 	var desc FaceDescriptor
-	err := json.Unmarshal([]byte(jsonData), &desc)
-	if err != nil {
-		return FaceDescriptor{}, fmt.Errorf("error al deserializar descriptor: %v", err)
-	}
+	copy(desc.Descriptor[:], vector)
 	return desc, nil
 }
@@ -0,0 +1,146 @@
+package recognition
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// fakeModelVersion distingue las muestras del motor fake de las generadas por dlib
+	fakeModelVersion = "fake_engine_v1"
+	// fakeMarker precede a los rostros embebidos en el comentario (COM) de un JPEG
+	fakeMarker = "FACESTUB:"
+
+	jpegMarkerSOI = 0xD8
+	jpegMarkerSOS = 0xDA
+	jpegMarkerEOI = 0xD9
+	jpegMarkerCOM = 0xFE
+
+	// fakeSpread y fakeNoise controlan la dispersión de los descriptores generados:
+	// identidades distintas quedan lejos del umbral y las variantes de una misma, cerca
+	fakeSpread = 0.1
+	fakeNoise  = 0.01
+)
+
+// FakeEngine implementa FaceEngine sin dlib. No analiza píxeles: lee los rostros
+// embebidos en un segmento de comentario del JPEG (ver EmbedFakeFaces). Una imagen
+// sin ese segmento se procesa como una imagen sin rostros. Es determinista y solo
+// debe usarse en desarrollo y pruebas.
+type FakeEngine struct{}
+
+// NewFakeEngine crea el motor fake
+func NewFakeEngine() *FakeEngine {
+	return &FakeEngine{}
+}
+
+// Detect retorna los rostros embebidos en la imagen
+func (e *FakeEngine) Detect(imageData []byte) ([]FaceDescriptor, error) {
+	if len(imageData) < 2 || imageData[0] != 0xFF || imageData[1] != jpegMarkerSOI {
+		return nil, fmt.Errorf("error al procesar la imagen: formato no soportado, se espera JPEG")
+	}
+
+	payload, err := findFakePayload(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("error al procesar la imagen: %v", err)
+	}
+	if payload == nil {
+		return nil, nil
+	}
+
+	var faces []FaceDescriptor
+	if err := json.Unmarshal(payload, &faces); err != nil {
+		return nil, fmt.Errorf("error al procesar la imagen: rostros embebidos inválidos: %v", err)
+	}
+	if len(faces) == 0 {
+		return nil, nil
+	}
+	return faces, nil
+}
+
+// ModelVersion retorna la versión del motor fake
+func (e *FakeEngine) ModelVersion() string {
+	return fakeModelVersion
+}
+
+// Close no libera nada: el motor fake no tiene recursos
+func (e *FakeEngine) Close() {}
+
+// findFakePayload recorre los segmentos del JPEG hasta el inicio de los datos de imagen
+// y retorna el contenido del primer comentario con el marcador del motor fake
+func findFakePayload(data []byte) ([]byte, error) {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("estructura JPEG inválida")
+		}
+		marker := data[pos+1]
+		if marker == jpegMarkerSOS || marker == jpegMarkerEOI {
+			return nil, nil
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil, fmt.Errorf("segmento JPEG truncado")
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == jpegMarkerCOM && bytes.HasPrefix(segment, []byte(fakeMarker)) {
+			return segment[len(fakeMarker):], nil
+		}
+		pos += 2 + length
+	}
+	return nil, nil
+}
+
+// EmbedFakeFaces inserta los rostros en un comentario del JPEG para que el motor fake los detecte
+func EmbedFakeFaces(jpegData []byte, faces []FaceDescriptor) ([]byte, error) {
+	if len(jpegData) < 2 || jpegData[0] != 0xFF || jpegData[1] != jpegMarkerSOI {
+		return nil, fmt.Errorf("la imagen no es JPEG")
+	}
+
+	payload, err := json.Marshal(faces)
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar rostros: %v", err)
+	}
+	segment := append([]byte(fakeMarker), payload...)
+	if len(segment)+2 > 0xFFFF {
+		return nil, fmt.Errorf("demasiados rostros para un solo comentario JPEG")
+	}
+
+	result := make([]byte, 0, len(jpegData)+len(segment)+4)
+	result = append(result, jpegData[:2]...)
+	result = append(result, 0xFF, jpegMarkerCOM)
+	result = binary.BigEndian.AppendUint16(result, uint16(len(segment)+2))
+	result = append(result, segment...)
+	result = append(result, jpegData[2:]...)
+	return result, nil
+}
+
+// FakeDescriptor genera un descriptor determinista para una identidad. Las variantes de
+// una misma semilla coinciden entre sí y las semillas distintas no coinciden
+func FakeDescriptor(seed string, variant int) FaceDescriptor {
+	var desc FaceDescriptor
+	base := pseudoRandom(seed, DescriptorSize)
+	noise := pseudoRandom(fmt.Sprintf("%s#%d", seed, variant), DescriptorSize)
+	for i := range desc.Descriptor {
+		desc.Descriptor[i] = base[i] * fakeSpread
+		if variant != 0 {
+			desc.Descriptor[i] += noise[i] * fakeNoise
+		}
+	}
+	return desc
+}
+
+// pseudoRandom deriva n valores en [-1, 1) a partir de una semilla usando SHA-256
+func pseudoRandom(seed string, n int) []float32 {
+	values := make([]float32, 0, n)
+	for block := 0; len(values) < n; block++ {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", seed, block)))
+		for i := 0; i+2 <= len(sum) && len(values) < n; i += 2 {
+			v := binary.BigEndian.Uint16(sum[i : i+2])
+			values = append(values, float32(v)/32768-1)
+		}
+	}
+	return values
+}
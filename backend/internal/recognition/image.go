@@ -0,0 +1,251 @@
+package recognition
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	// Decodificadores registrados para image.Decode
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxImageDimension es el lado máximo en píxeles de la imagen entregada al motor.
+	// Las fotos más grandes se reducen antes de la detección
+	MaxImageDimension = 1280
+	// maxImagePixels evita decodificar imágenes enormes (bombas de descompresión)
+	maxImagePixels = 40000000
+	// jpegQuality es la calidad usada al re-codificar imágenes normalizadas
+	jpegQuality = 90
+
+	// snapshotMaxSide y snapshotQuality mantienen pequeños los recortes de evidencia
+	snapshotMaxSide = 240
+	snapshotQuality = 75
+	// snapshotMargin agrega contexto alrededor del rostro detectado (fracción del lado)
+	snapshotMargin = 0.25
+
+	// exifOrientationTag es la etiqueta EXIF que indica la rotación de la foto
+	exifOrientationTag = 0x0112
+	jpegMarkerAPP1     = 0xE1
+)
+
+// ImageFormat es el formato real de una imagen según sus primeros bytes
+type ImageFormat string
+
+const (
+	FormatJPEG ImageFormat = "jpeg"
+	FormatPNG  ImageFormat = "png"
+	FormatGIF  ImageFormat = "gif"
+	FormatWebP ImageFormat = "webp"
+)
+
+// DetectImageFormat identifica el formato por su firma (magic bytes), sin confiar
+// en la extensión ni en el tipo declarado por el cliente
+func DetectImageFormat(data []byte) (ImageFormat, error) {
+	switch {
+	case len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF:
+		return FormatJPEG, nil
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG, nil
+	case bytes.HasPrefix(data, []byte("GIF87a")) || bytes.HasPrefix(data, []byte("GIF89a")):
+		return FormatGIF, nil
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return FormatWebP, nil
+	}
+	return "", fmt.Errorf("formato de imagen no soportado (se aceptan JPEG, PNG, GIF y WebP)")
+}
+
+// PrepareImage normaliza una imagen para el motor de reconocimiento: valida el formato
+// real, aplica la orientación EXIF, reduce las fotos grandes a MaxImageDimension y
+// retorna un JPEG. Los JPEG que no necesitan cambios se retornan sin re-codificar.
+// Todo el proceso ocurre en memoria.
+func PrepareImage(data []byte) ([]byte, error) {
+	format, err := DetectImageFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imagen corrupta o ilegible")
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("dimensiones de imagen no permitidas (%dx%d)", config.Width, config.Height)
+	}
+
+	orientation := 1
+	if format == FormatJPEG {
+		orientation = exifOrientation(data)
+	}
+	if format == FormatJPEG && orientation == 1 && max(config.Width, config.Height) <= MaxImageDimension {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imagen corrupta o ilegible")
+	}
+	img = downscale(img, MaxImageDimension)
+	img = applyOrientation(img, orientation)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("error al codificar la imagen: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// CropFace recorta el rostro indicado (con un margen) de una imagen normalizada por
+// PrepareImage y lo retorna como un JPEG comprimido de a lo sumo snapshotMaxSide píxeles
+func CropFace(data []byte, rect Rectangle) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imagen corrupta o ilegible")
+	}
+
+	marginX := int(float64(rect.Max.X-rect.Min.X) * snapshotMargin)
+	marginY := int(float64(rect.Max.Y-rect.Min.Y) * snapshotMargin)
+	area := image.Rect(rect.Min.X-marginX, rect.Min.Y-marginY, rect.Max.X+marginX, rect.Max.Y+marginY).
+		Add(img.Bounds().Min).
+		Intersect(img.Bounds())
+	if area.Empty() {
+		return nil, fmt.Errorf("el rostro está fuera de la imagen")
+	}
+
+	crop := image.NewRGBA(image.Rect(0, 0, area.Dx(), area.Dy()))
+	draw.Copy(crop, image.Point{}, img, area, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, downscale(crop, snapshotMaxSide), &jpeg.Options{Quality: snapshotQuality}); err != nil {
+		return nil, fmt.Errorf("error al codificar el recorte: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// downscale reduce la imagen para que su lado mayor no supere maxSide
+func downscale(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if max(width, height) <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height = height * maxSide / width
+		width = maxSide
+	} else {
+		width = width * maxSide / height
+		height = maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	draw.BiLinear.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// applyOrientation rota o refleja la imagen según el valor EXIF de orientación (1-8)
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // espejo horizontal
+				sx, sy = width-1-x, y
+			case 3: // rotación 180°
+				sx, sy = width-1-x, height-1-y
+			case 4: // espejo vertical
+				sx, sy = x, height-1-y
+			case 5: // transpuesta
+				sx, sy = y, x
+			case 6: // rotación 90° horaria
+				sx, sy = y, height-1-x
+			case 7: // transversa
+				sx, sy = width-1-y, height-1-x
+			case 8: // rotación 90° antihoraria
+				sx, sy = width-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+// exifOrientation lee la orientación del bloque EXIF (APP1) de un JPEG. Retorna 1
+// (sin rotación) si no hay EXIF o no se puede interpretar
+func exifOrientation(data []byte) int {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == jpegMarkerSOS || marker == jpegMarkerEOI {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == jpegMarkerAPP1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation busca la etiqueta de orientación en el primer IFD de un bloque TIFF
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == exifOrientationTag {
+			value := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
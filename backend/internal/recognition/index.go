@@ -0,0 +1,154 @@
+package recognition
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/sistema-ingreso-docente/backend/internal/domain/entities"
+	"github.com/sistema-ingreso-docente/backend/internal/domain/repositories"
+	"github.com/sistema-ingreso-docente/backend/internal/infrastructure/security"
+)
+
+// Index es el único punto donde los descriptores faciales se cifran y descifran.
+// El repositorio solo maneja datos cifrados y los endpoints solo ven metadatos
+type Index struct {
+	repo   repositories.DocenteRepository
+	cipher *security.Cipher
+}
+
+// NewIndex crea el índice de reconocimiento
+func NewIndex(repo repositories.DocenteRepository, cipher *security.Cipher) *Index {
+	return &Index{repo: repo, cipher: cipher}
+}
+
+// Add cifra y guarda una muestra facial del docente
+func (i *Index) Add(docenteID int, desc FaceDescriptor, modeloVersion string, calidad *float32, origen entities.OrigenRostro) (*entities.RostroDocente, error) {
+	cifrado, claveID, err := i.cipher.Encrypt(encodeDescriptor(desc), descriptorAAD(docenteID))
+	if err != nil {
+		return nil, err
+	}
+
+	rostro := &entities.RostroDocente{
+		DocenteID:         docenteID,
+		DescriptorCifrado: cifrado,
+		ClaveID:           &claveID,
+		ModeloVersion:     modeloVersion,
+		Calidad:           calidad,
+		Origen:            origen,
+	}
+	if err := i.repo.AddFaceDescriptor(rostro); err != nil {
+		return nil, err
+	}
+	return rostro, nil
+}
+
+// Descriptors retorna los descriptores vigentes de un docente
+// Las muestras marcadas para re-enrolamiento se excluyen de toda comparación
+func (i *Index) Descriptors(docenteID int) ([]FaceDescriptor, error) {
+	rostros, err := i.repo.GetFaceDescriptors(docenteID)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptores := make([]FaceDescriptor, 0, len(rostros))
+	for _, rostro := range rostros {
+		if rostro.RequiereReenrolamiento {
+			continue
+		}
+		desc, err := i.descifrar(rostro)
+		if err != nil {
+			log.Printf("[SECURITY] Rostro %d del docente %d ignorado: %v", rostro.ID, docenteID, err)
+			continue
+		}
+		descriptores = append(descriptores, desc)
+	}
+	return descriptores, nil
+}
+
+// AllDescriptors retorna los descriptores vigentes de todos los docentes activos
+func (i *Index) AllDescriptors() (map[int][]FaceDescriptor, error) {
+	rostros, err := i.repo.GetAllFaceDescriptors()
+	if err != nil {
+		return nil, err
+	}
+
+	resultado := make(map[int][]FaceDescriptor)
+	for _, rostro := range rostros {
+		desc, err := i.descifrar(rostro)
+		if err != nil {
+			log.Printf("[SECURITY] Rostro %d del docente %d ignorado: %v", rostro.ID, rostro.DocenteID, err)
+			continue
+		}
+		resultado[rostro.DocenteID] = append(resultado[rostro.DocenteID], desc)
+	}
+	return resultado, nil
+}
+
+// Reencrypt cifra con la clave activa las muestras guardadas sin cifrar y, si rotate,
+// también las cifradas con claves anteriores. Retorna cuántas muestras se actualizaron
+func (i *Index) Reencrypt(rotate bool) (int, error) {
+	rostros, err := i.repo.FindFaceDescriptorsToEncrypt(i.cipher.ActiveKeyID(), rotate)
+	if err != nil {
+		return 0, err
+	}
+
+	actualizados := 0
+	for _, rostro := range rostros {
+		desc, err := i.descifrar(rostro)
+		if err != nil {
+			return actualizados, fmt.Errorf("rostro %d: %w", rostro.ID, err)
+		}
+		cifrado, claveID, err := i.cipher.Encrypt(encodeDescriptor(desc), descriptorAAD(rostro.DocenteID))
+		if err != nil {
+			return actualizados, err
+		}
+		if err := i.repo.UpdateFaceDescriptorCipher(rostro.ID, cifrado, claveID); err != nil {
+			return actualizados, fmt.Errorf("rostro %d: %w", rostro.ID, err)
+		}
+		actualizados++
+	}
+	return actualizados, nil
+}
+
+// descifrar obtiene el descriptor de una muestra; las muestras anteriores al cifrado
+// aún guardan los valores en claro
+func (i *Index) descifrar(rostro *entities.RostroDocente) (FaceDescriptor, error) {
+	if rostro.DescriptorCifrado == nil {
+		return DescriptorFromVector(rostro.Descriptor)
+	}
+	if rostro.ClaveID == nil {
+		return FaceDescriptor{}, fmt.Errorf("descriptor cifrado sin clave")
+	}
+
+	datos, err := i.cipher.Decrypt(rostro.DescriptorCifrado, *rostro.ClaveID, descriptorAAD(rostro.DocenteID))
+	if err != nil {
+		return FaceDescriptor{}, err
+	}
+	return decodeDescriptor(datos)
+}
+
+// descriptorAAD vincula el dato cifrado al docente: una muestra copiada a otro docente no descifra
+func descriptorAAD(docenteID int) []byte {
+	return []byte(fmt.Sprintf("rostros_docente:%d", docenteID))
+}
+
+func encodeDescriptor(desc FaceDescriptor) []byte {
+	datos := make([]byte, DescriptorSize*4)
+	for j, v := range desc.Descriptor {
+		binary.LittleEndian.PutUint32(datos[j*4:], math.Float32bits(v))
+	}
+	return datos
+}
+
+func decodeDescriptor(datos []byte) (FaceDescriptor, error) {
+	if len(datos) != DescriptorSize*4 {
+		return FaceDescriptor{}, fmt.Errorf("descriptor inválido: se esperaban %d bytes y hay %d", DescriptorSize*4, len(datos))
+	}
+	var desc FaceDescriptor
+	for j := range desc.Descriptor {
+		desc.Descriptor[j] = math.Float32frombits(binary.LittleEndian.Uint32(datos[j*4:]))
+	}
+	return desc, nil
+}
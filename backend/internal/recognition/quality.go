@@ -0,0 +1,88 @@
+package recognition
+
+const (
+	// MinFaceSize es el lado mínimo en píxeles que debe tener un rostro para ser enrolado
+	MinFaceSize = 80
+	// MinMatchesRequired es el mínimo de descriptores que deben coincidir para identificar a un docente
+	MinMatchesRequired = 3
+	// outlierMinAgreement es la fracción mínima de muestras de referencia con las que
+	// un descriptor nuevo debe coincidir para no considerarse atípico
+	outlierMinAgreement = 0.5
+	// outlierMinReferences es la cantidad mínima de muestras para poder evaluar atípicos
+	outlierMinReferences = 2
+)
+
+// EnrollmentVerdict es el resultado del control de calidad de una imagen de enrolamiento
+type EnrollmentVerdict string
+
+const (
+	VerdictAccepted      EnrollmentVerdict = "aceptada"
+	VerdictInvalid       EnrollmentVerdict = "invalida"
+	VerdictNoFace        EnrollmentVerdict = "sin_rostro"
+	VerdictMultipleFaces EnrollmentVerdict = "multiples_rostros"
+	VerdictFaceTooSmall  EnrollmentVerdict = "rostro_pequeno"
+	VerdictOutlier       EnrollmentVerdict = "atipica"
+	VerdictDuplicate     EnrollmentVerdict = "duplicada"
+)
+
+// Size retorna el ancho y alto del rectángulo del rostro
+func (f FaceDescriptor) Size() (int, int) {
+	return f.Rectangle.Max.X - f.Rectangle.Min.X, f.Rectangle.Max.Y - f.Rectangle.Min.Y
+}
+
+// IsFaceTooSmall indica si el rostro es demasiado pequeño para generar un descriptor confiable
+func IsFaceTooSmall(f FaceDescriptor) bool {
+	width, height := f.Size()
+	return width < MinFaceSize || height < MinFaceSize
+}
+
+// CountMatches cuenta cuántas muestras coinciden con el descriptor y retorna la mejor distancia
+func CountMatches(desc FaceDescriptor, samples []FaceDescriptor) (int, float32) {
+	matches := 0
+	best := float32(-1)
+	for _, sample := range samples {
+		distance := CompareFaces(desc, sample)
+		if best < 0 || distance < best {
+			best = distance
+		}
+		if distance < tolerance {
+			matches++
+		}
+	}
+	return matches, best
+}
+
+// IsIdentityMatch aplica la misma regla que la identificación para decidir si un conjunto
+// de muestras pertenece a la persona. Si el docente tiene menos muestras que el mínimo
+// requerido, basta con que coincidan todas.
+func IsIdentityMatch(matches, total int) bool {
+	required := MinMatchesRequired
+	if total < required {
+		required = total
+	}
+	return required > 0 && matches >= required
+}
+
+// IsOutlier determina si un descriptor se aleja demasiado de las demás muestras de la misma persona
+func IsOutlier(desc FaceDescriptor, references []FaceDescriptor) bool {
+	if len(references) < outlierMinReferences {
+		return false
+	}
+	matches, _ := CountMatches(desc, references)
+	return float32(matches) < outlierMinAgreement*float32(len(references))
+}
+
+// QualityScore estima la calidad de una muestra entre 0 y 1: promedia el tamaño del rostro
+// (completo a partir del doble del mínimo) y la fracción de referencias con las que coincide
+func QualityScore(f FaceDescriptor, references []FaceDescriptor) float32 {
+	width, height := f.Size()
+	sizeScore := float32(min(width, height)) / float32(2*MinFaceSize)
+	if sizeScore > 1 {
+		sizeScore = 1
+	}
+	if len(references) == 0 {
+		return sizeScore
+	}
+	matches, _ := CountMatches(f, references)
+	return (sizeScore + float32(matches)/float32(len(references))) / 2
+}
@@ -0,0 +1,30 @@
+-- ============================================
+-- INTENTOS DE RECONOCIMIENTO FACIAL
+-- Auditoría de cada identificación: candidatos, decisión y registro resultante
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS intentos_reconocimiento (
+    id SERIAL PRIMARY KEY,
+    fecha_hora TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    operador_id INTEGER REFERENCES usuarios(id) ON DELETE SET NULL,
+    terminal VARCHAR(100) NOT NULL DEFAULT '',
+    rostros_detectados INTEGER NOT NULL DEFAULT 0 CHECK (rostros_detectados >= 0),
+    candidatos JSONB NOT NULL DEFAULT '[]'::jsonb,
+    decision VARCHAR(20) NOT NULL CHECK (decision IN ('identificado', 'no_identificado', 'sin_rostro', 'error')),
+    docente_id INTEGER REFERENCES docentes(id) ON DELETE SET NULL,
+    registro_id INTEGER REFERENCES registros(id) ON DELETE SET NULL,
+    detalle TEXT,
+    marcado_incorrecto BOOLEAN NOT NULL DEFAULT FALSE,
+    docente_real_id INTEGER REFERENCES docentes(id) ON DELETE SET NULL,
+    motivo_marca TEXT,
+    marcado_por INTEGER REFERENCES usuarios(id) ON DELETE SET NULL,
+    marcado_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_intentos_fecha ON intentos_reconocimiento(fecha_hora DESC);
+CREATE INDEX idx_intentos_docente ON intentos_reconocimiento(docente_id);
+CREATE INDEX idx_intentos_decision ON intentos_reconocimiento(decision);
+CREATE INDEX idx_intentos_registro ON intentos_reconocimiento(registro_id) WHERE registro_id IS NOT NULL;
+CREATE INDEX idx_intentos_incorrectos ON intentos_reconocimiento(marcado_incorrecto) WHERE marcado_incorrecto = TRUE;
@@ -0,0 +1,11 @@
+-- ============================================
+-- VERIFICACION FACIAL 1:1
+-- Distingue los intentos de identificación (1:N) de las verificaciones (CI + rostro)
+-- ============================================
+SET client_encoding = 'UTF8';
+
+ALTER TABLE intentos_reconocimiento
+    ADD COLUMN IF NOT EXISTS modo VARCHAR(20) NOT NULL DEFAULT 'identificacion'
+    CHECK (modo IN ('identificacion', 'verificacion'));
+
+CREATE INDEX IF NOT EXISTS idx_intentos_modo ON intentos_reconocimiento(modo);
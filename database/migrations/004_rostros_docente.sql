@@ -0,0 +1,50 @@
+-- ============================================
+-- ROSTROS DE DOCENTES
+-- Cada descriptor facial pasa a ser una fila con ID estable, versión del modelo
+-- que lo generó, calidad y origen. Reemplaza la columna JSONB docentes.face_descriptors
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS rostros_docente (
+    id SERIAL PRIMARY KEY,
+    docente_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    descriptor REAL[] NOT NULL CHECK (array_length(descriptor, 1) = 128),
+    modelo_version VARCHAR(100) NOT NULL,
+    calidad REAL CHECK (calidad IS NULL OR (calidad >= 0 AND calidad <= 1)),
+    origen VARCHAR(20) NOT NULL DEFAULT 'enrolamiento' CHECK (origen IN ('enrolamiento', 'migracion')),
+    requiere_reenrolamiento BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rostros_docente ON rostros_docente(docente_id);
+CREATE INDEX IF NOT EXISTS idx_rostros_reenrolamiento ON rostros_docente(docente_id) WHERE requiere_reenrolamiento = TRUE;
+
+-- Migrar los descriptores existentes. Se aceptan elementos guardados como objeto
+-- {"descriptor": [...], "rectangle": {...}} o directamente como arreglo de 128 valores.
+-- Todos fueron generados por el modelo dlib ResNet v1 usado hasta ahora.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'docentes' AND column_name = 'face_descriptors'
+    ) THEN
+        INSERT INTO rostros_docente (docente_id, descriptor, modelo_version, origen, created_at)
+        SELECT d.id,
+               ARRAY(
+                   SELECT jsonb_array_elements_text(
+                       CASE jsonb_typeof(elem) WHEN 'array' THEN elem ELSE elem->'descriptor' END
+                   )::real
+               ),
+               'dlib_face_recognition_resnet_model_v1',
+               'migracion',
+               d.updated_at
+        FROM docentes d,
+             jsonb_array_elements(d.face_descriptors) AS elem
+        WHERE jsonb_typeof(d.face_descriptors) = 'array'
+          AND jsonb_array_length(
+                  CASE jsonb_typeof(elem) WHEN 'array' THEN elem ELSE COALESCE(elem->'descriptor', '[]'::jsonb) END
+              ) = 128;
+
+        ALTER TABLE docentes DROP COLUMN face_descriptors;
+    END IF;
+END $$;
@@ -0,0 +1,23 @@
+-- ============================================
+-- CIFRADO DE DESCRIPTORES FACIALES
+-- Los descriptores se guardan cifrados con AES-256-GCM (clave BIOMETRIC_KEY).
+-- clave_id identifica la clave usada para permitir la rotación con cmd/recifrar.
+-- Las filas existentes conservan el descriptor en claro hasta que el servidor
+-- las cifra al iniciar; después la columna descriptor queda en NULL
+-- ============================================
+SET client_encoding = 'UTF8';
+
+ALTER TABLE rostros_docente ADD COLUMN IF NOT EXISTS descriptor_cifrado BYTEA;
+ALTER TABLE rostros_docente ADD COLUMN IF NOT EXISTS clave_id VARCHAR(16);
+ALTER TABLE rostros_docente ALTER COLUMN descriptor DROP NOT NULL;
+
+ALTER TABLE rostros_docente DROP CONSTRAINT IF EXISTS rostros_docente_descriptor_presente;
+ALTER TABLE rostros_docente ADD CONSTRAINT rostros_docente_descriptor_presente
+    CHECK (descriptor_cifrado IS NOT NULL OR descriptor IS NOT NULL);
+
+ALTER TABLE rostros_docente DROP CONSTRAINT IF EXISTS rostros_docente_clave_presente;
+ALTER TABLE rostros_docente ADD CONSTRAINT rostros_docente_clave_presente
+    CHECK (descriptor_cifrado IS NULL OR clave_id IS NOT NULL);
+
+-- Facilita encontrar las filas pendientes de cifrar o de re-cifrar
+CREATE INDEX IF NOT EXISTS idx_rostros_clave ON rostros_docente(clave_id);
@@ -0,0 +1,25 @@
+-- ============================================
+-- CONSENTIMIENTO BIOMETRICO
+-- Registra la autorizacion de cada docente para el uso de su rostro:
+-- fecha, version del documento firmado y quien lo recolecto.
+-- Revocar el consentimiento elimina todas sus muestras faciales
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS consentimientos_biometricos (
+    id SERIAL PRIMARY KEY,
+    docente_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    version_documento VARCHAR(50) NOT NULL,
+    fecha_consentimiento TIMESTAMP WITH TIME ZONE NOT NULL,
+    recolectado_por INTEGER NOT NULL REFERENCES usuarios(id),
+    revocado_at TIMESTAMP WITH TIME ZONE,
+    revocado_por INTEGER REFERENCES usuarios(id),
+    motivo_revocacion TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT consentimiento_revocacion_completa CHECK (revocado_at IS NULL OR revocado_por IS NOT NULL)
+);
+
+-- A lo sumo un consentimiento vigente por docente
+CREATE UNIQUE INDEX IF NOT EXISTS idx_consentimiento_vigente
+    ON consentimientos_biometricos(docente_id) WHERE revocado_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_consentimiento_docente ON consentimientos_biometricos(docente_id);
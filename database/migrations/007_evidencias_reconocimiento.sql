@@ -0,0 +1,26 @@
+-- ============================================
+-- EVIDENCIAS DE RECONOCIMIENTO
+-- Recorte del rostro capturado en cada identificacion exitosa, cifrado con
+-- BIOMETRIC_KEY. Se guarda en un archivo local (archivo) o en la fila (imagen).
+-- El registro resultante se obtiene por intentos_reconocimiento.registro_id
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS evidencias_reconocimiento (
+    id SERIAL PRIMARY KEY,
+    intento_id INTEGER NOT NULL UNIQUE REFERENCES intentos_reconocimiento(id) ON DELETE CASCADE,
+    docente_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    almacen VARCHAR(20) NOT NULL CHECK (almacen IN ('archivo', 'base_datos')),
+    archivo VARCHAR(255),
+    imagen BYTEA,
+    clave_id VARCHAR(16) NOT NULL,
+    tamano INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT evidencia_contenido CHECK (
+        (almacen = 'archivo' AND archivo IS NOT NULL AND imagen IS NULL) OR
+        (almacen = 'base_datos' AND imagen IS NOT NULL AND archivo IS NULL)
+    )
+);
+
+-- La retencion elimina por antiguedad
+CREATE INDEX IF NOT EXISTS idx_evidencias_created ON evidencias_reconocimiento(created_at);
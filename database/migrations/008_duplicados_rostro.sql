@@ -0,0 +1,43 @@
+-- ============================================
+-- ESCANEO DE ROSTROS DUPLICADOS
+-- Cada escaneo compara las muestras faciales de todos los docentes entre si y
+-- guarda los pares cuya distancia cae dentro del umbral de reconocimiento.
+-- Un administrador revisa cada par (misma persona registrada dos veces o
+-- falso positivo)
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS reportes_duplicados (
+    id SERIAL PRIMARY KEY,
+    estado VARCHAR(20) NOT NULL DEFAULT 'en_proceso' CHECK (estado IN ('en_proceso', 'completado', 'error')),
+    iniciado_por INTEGER NOT NULL REFERENCES usuarios(id),
+    modelo_version VARCHAR(100) NOT NULL,
+    umbral REAL NOT NULL,
+    docentes_analizados INTEGER NOT NULL DEFAULT 0,
+    muestras_analizadas INTEGER NOT NULL DEFAULT 0,
+    pares_encontrados INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    iniciado_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finalizado_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS pares_duplicados (
+    id SERIAL PRIMARY KEY,
+    reporte_id INTEGER NOT NULL REFERENCES reportes_duplicados(id) ON DELETE CASCADE,
+    grupo INTEGER NOT NULL,
+    docente_a_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    docente_b_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    coincidencias INTEGER NOT NULL,
+    comparaciones INTEGER NOT NULL,
+    mejor_distancia REAL NOT NULL,
+    distancia_promedio REAL NOT NULL,
+    concluyente BOOLEAN NOT NULL DEFAULT false,
+    estado VARCHAR(20) NOT NULL DEFAULT 'pendiente' CHECK (estado IN ('pendiente', 'confirmado', 'descartado')),
+    revisado_por INTEGER REFERENCES usuarios(id),
+    revisado_at TIMESTAMP WITH TIME ZONE,
+    nota VARCHAR(500),
+    CONSTRAINT par_duplicado_orden CHECK (docente_a_id < docente_b_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reportes_duplicados_iniciado ON reportes_duplicados(iniciado_at DESC);
+CREATE INDEX IF NOT EXISTS idx_pares_duplicados_reporte ON pares_duplicados(reporte_id, grupo);
@@ -0,0 +1,88 @@
+-- ============================================
+-- MARGENES DE TURNO Y CLASIFICACION DE REGISTROS
+-- Cada turno define su tolerancia de ingreso, el retraso maximo antes de
+-- contar falta y el margen de salida anticipada (en minutos). Cada registro
+-- guarda su clasificacion para que los reportes no la recalculen
+--
+-- Requiere la zona horaria de la institucion (INSTITUTION_TIMEZONE) para
+-- clasificar las salidas existentes:
+--   psql -v institution_timezone=America/La_Paz -f 009_clasificacion_registros.sql
+-- ============================================
+\set ON_ERROR_STOP on
+\if :{?institution_timezone}
+\else
+\echo 'Falta -v institution_timezone=<INSTITUTION_TIMEZONE>; no se aplico la migracion'
+-- Termina con error (ON_ERROR_STOP) para que las migraciones siguientes no se apliquen sin esta
+DO $$ BEGIN RAISE EXCEPTION 'falta la variable institution_timezone'; END $$;
+\endif
+SET client_encoding = 'UTF8';
+-- Falla con una zona horaria inexistente antes de modificar nada
+SET TIME ZONE :'institution_timezone';
+
+ALTER TABLE turnos ADD COLUMN IF NOT EXISTS tolerancia_ingreso_min INTEGER NOT NULL DEFAULT 10
+    CHECK (tolerancia_ingreso_min >= 0);
+ALTER TABLE turnos ADD COLUMN IF NOT EXISTS retraso_maximo_min INTEGER NOT NULL DEFAULT 30
+    CHECK (retraso_maximo_min >= 0);
+ALTER TABLE turnos ADD COLUMN IF NOT EXISTS salida_anticipada_min INTEGER NOT NULL DEFAULT 10
+    CHECK (salida_anticipada_min >= 0);
+
+ALTER TABLE turnos DROP CONSTRAINT IF EXISTS turno_margenes_coherentes;
+ALTER TABLE turnos ADD CONSTRAINT turno_margenes_coherentes CHECK (retraso_maximo_min >= tolerancia_ingreso_min);
+
+ALTER TABLE registros ADD COLUMN IF NOT EXISTS clasificacion VARCHAR(20);
+
+-- Clasificar los registros existentes con los margenes por defecto.
+-- minutos_retraso se contaba desde hora_inicio sin tolerancia; dentro de la
+-- tolerancia pasa a ser 0, como lo calcula ahora el backend.
+-- Las salidas se comparan con hora_fin en la zona horaria de la institucion.
+UPDATE registros r
+SET clasificacion = CASE
+        WHEN r.minutos_retraso > t.retraso_maximo_min THEN 'falta'
+        WHEN r.minutos_retraso > t.tolerancia_ingreso_min THEN 'tarde'
+        ELSE 'puntual'
+    END,
+    minutos_retraso = CASE WHEN r.minutos_retraso > t.tolerancia_ingreso_min THEN r.minutos_retraso ELSE 0 END
+FROM turnos t
+WHERE r.turno_id = t.id AND r.tipo = 'ingreso' AND r.clasificacion IS NULL;
+
+UPDATE registros r
+SET clasificacion = CASE
+        WHEN t.hora_fin - (r.fecha_hora AT TIME ZONE :'institution_timezone')::TIME > make_interval(mins => t.salida_anticipada_min)
+            THEN 'salida_anticipada'
+        ELSE 'puntual'
+    END
+FROM turnos t
+WHERE r.turno_id = t.id AND r.tipo = 'salida' AND r.clasificacion IS NULL;
+
+ALTER TABLE registros ALTER COLUMN clasificacion SET DEFAULT 'puntual';
+UPDATE registros SET clasificacion = 'puntual' WHERE clasificacion IS NULL;
+ALTER TABLE registros ALTER COLUMN clasificacion SET NOT NULL;
+
+ALTER TABLE registros DROP CONSTRAINT IF EXISTS registro_clasificacion_valida;
+ALTER TABLE registros ADD CONSTRAINT registro_clasificacion_valida
+    CHECK (clasificacion IN ('puntual', 'tarde', 'falta', 'salida_anticipada'));
+
+CREATE INDEX IF NOT EXISTS idx_registros_clasificacion ON registros(clasificacion) WHERE clasificacion <> 'puntual';
+
+-- La vista de registros expone la clasificacion
+CREATE OR REPLACE VIEW v_registros_completos AS
+SELECT
+    r.id,
+    r.fecha_hora,
+    r.tipo,
+    d.documento_identidad,
+    d.nombre_completo AS docente,
+    d.correo,
+    l.aula_codigo,
+    l.aula_nombre,
+    t.nombre AS turno_nombre,
+    l.codigo AS llave_codigo,
+    r.minutos_retraso,
+    r.minutos_extra,
+    r.observaciones,
+    r.clasificacion
+FROM registros r
+INNER JOIN docentes d ON r.docente_id = d.id
+INNER JOIN turnos t ON r.turno_id = t.id
+LEFT JOIN llaves l ON r.llave_id = l.id
+ORDER BY r.fecha_hora DESC;
@@ -0,0 +1,47 @@
+-- ============================================
+-- SESIONES: EMPAREJAMIENTO INGRESO / SALIDA
+-- Cada salida referencia el ingreso que cierra. Un ingreso sin salida que
+-- lo referencie es una sesion abierta (el docente sigue en el aula)
+-- ============================================
+SET client_encoding = 'UTF8';
+
+ALTER TABLE registros ADD COLUMN IF NOT EXISTS ingreso_id INTEGER REFERENCES registros(id) ON DELETE SET NULL;
+
+ALTER TABLE registros DROP CONSTRAINT IF EXISTS registro_ingreso_solo_salidas;
+ALTER TABLE registros ADD CONSTRAINT registro_ingreso_solo_salidas CHECK (tipo = 'salida' OR ingreso_id IS NULL);
+
+-- Un ingreso se cierra con una sola salida
+CREATE UNIQUE INDEX IF NOT EXISTS idx_registros_ingreso_id ON registros(ingreso_id) WHERE ingreso_id IS NOT NULL;
+
+-- Emparejar las salidas existentes, en orden cronologico, con el ingreso
+-- libre mas reciente del mismo docente en las 24 horas previas (y con la
+-- misma llave cuando la salida tiene una)
+DO $$
+DECLARE
+    s RECORD;
+    ingreso INTEGER;
+BEGIN
+    FOR s IN
+        SELECT id, docente_id, llave_id, fecha_hora
+        FROM registros
+        WHERE tipo = 'salida' AND ingreso_id IS NULL
+        ORDER BY fecha_hora
+    LOOP
+        SELECT i.id INTO ingreso
+        FROM registros i
+        WHERE i.tipo = 'ingreso'
+          AND i.docente_id = s.docente_id
+          AND i.fecha_hora <= s.fecha_hora
+          AND i.fecha_hora > s.fecha_hora - INTERVAL '24 hours'
+          AND (s.llave_id IS NULL OR i.llave_id = s.llave_id)
+          AND NOT EXISTS (SELECT 1 FROM registros o WHERE o.ingreso_id = i.id)
+        ORDER BY i.fecha_hora DESC
+        LIMIT 1;
+
+        IF ingreso IS NOT NULL THEN
+            UPDATE registros SET ingreso_id = ingreso WHERE id = s.id;
+        END IF;
+    END LOOP;
+END $$;
+
+COMMENT ON COLUMN registros.ingreso_id IS 'Ingreso que cierra esta salida (solo salidas)';
@@ -0,0 +1,25 @@
+-- ============================================
+-- TURNOS POR DIA DE LA SEMANA Y VIGENCIA
+-- dias_semana es una mascara de bits: el bit i corresponde al dia i
+-- (0 = domingo ... 6 = sabado). 127 = todos los dias.
+-- Un turno cuya hora_fin no es posterior a hora_inicio cruza la medianoche.
+-- El solapamiento entre turnos activos lo valida el backend
+-- ============================================
+SET client_encoding = 'UTF8';
+
+ALTER TABLE turnos ADD COLUMN IF NOT EXISTS dias_semana SMALLINT NOT NULL DEFAULT 127
+    CHECK (dias_semana BETWEEN 1 AND 127);
+ALTER TABLE turnos ADD COLUMN IF NOT EXISTS vigente_desde DATE;
+ALTER TABLE turnos ADD COLUMN IF NOT EXISTS vigente_hasta DATE;
+
+ALTER TABLE turnos DROP CONSTRAINT IF EXISTS turno_vigencia_coherente;
+ALTER TABLE turnos ADD CONSTRAINT turno_vigencia_coherente
+    CHECK (vigente_desde IS NULL OR vigente_hasta IS NULL OR vigente_desde <= vigente_hasta);
+
+-- hora_fin <= hora_inicio ahora significa cruce de medianoche; solo se rechaza la duracion nula
+ALTER TABLE turnos DROP CONSTRAINT IF EXISTS turno_horas_distintas;
+ALTER TABLE turnos ADD CONSTRAINT turno_horas_distintas CHECK (hora_inicio <> hora_fin);
+
+COMMENT ON COLUMN turnos.dias_semana IS 'Mascara de dias (bit 0 = domingo ... bit 6 = sabado)';
+COMMENT ON COLUMN turnos.vigente_desde IS 'Primer dia en que aplica el turno (NULL = sin limite)';
+COMMENT ON COLUMN turnos.vigente_hasta IS 'Ultimo dia en que aplica el turno (NULL = sin limite)';
@@ -0,0 +1,59 @@
+-- ============================================
+-- CALENDARIO ACADEMICO
+-- Periodos (semestres) de cada gestion y eventos: feriados, recesos,
+-- semanas de examenes y dias con horario especial. Cuando hay periodos
+-- cargados, los dias fuera de todos ellos no son laborables
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS periodos_academicos (
+    id SERIAL PRIMARY KEY,
+    gestion INTEGER NOT NULL CHECK (gestion BETWEEN 2000 AND 2100),
+    nombre VARCHAR(100) NOT NULL,
+    fecha_inicio DATE NOT NULL,
+    fecha_fin DATE NOT NULL,
+    descripcion TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT periodo_fechas_coherentes CHECK (fecha_inicio <= fecha_fin)
+);
+
+DROP TRIGGER IF EXISTS update_periodos_academicos_modtime ON periodos_academicos;
+CREATE TRIGGER update_periodos_academicos_modtime
+    BEFORE UPDATE ON periodos_academicos
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_updated_at_column();
+
+CREATE INDEX IF NOT EXISTS idx_periodos_academicos_fechas ON periodos_academicos(fecha_inicio, fecha_fin);
+
+CREATE TABLE IF NOT EXISTS eventos_calendario (
+    id SERIAL PRIMARY KEY,
+    tipo VARCHAR(20) NOT NULL CHECK (tipo IN ('feriado', 'receso', 'examenes', 'horario_especial')),
+    nombre VARCHAR(150) NOT NULL,
+    fecha_inicio DATE NOT NULL,
+    fecha_fin DATE NOT NULL,
+    -- NULL: afecta a todos los turnos
+    turno_id INTEGER REFERENCES turnos(id) ON DELETE CASCADE,
+    -- Solo horario_especial: horario con que se dicta el turno esos dias
+    hora_inicio TIME,
+    hora_fin TIME,
+    descripcion TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT evento_fechas_coherentes CHECK (fecha_inicio <= fecha_fin),
+    CONSTRAINT evento_horario_especial CHECK (
+        (tipo = 'horario_especial' AND turno_id IS NOT NULL AND hora_inicio IS NOT NULL AND hora_fin IS NOT NULL)
+        OR (tipo <> 'horario_especial' AND hora_inicio IS NULL AND hora_fin IS NULL)
+    )
+);
+
+DROP TRIGGER IF EXISTS update_eventos_calendario_modtime ON eventos_calendario;
+CREATE TRIGGER update_eventos_calendario_modtime
+    BEFORE UPDATE ON eventos_calendario
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_updated_at_column();
+
+CREATE INDEX IF NOT EXISTS idx_eventos_calendario_fechas ON eventos_calendario(fecha_inicio, fecha_fin);
+
+COMMENT ON TABLE periodos_academicos IS 'Semestres y otros periodos lectivos por gestion';
+COMMENT ON TABLE eventos_calendario IS 'Feriados, recesos, examenes y horarios especiales';
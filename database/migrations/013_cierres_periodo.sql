@@ -0,0 +1,23 @@
+-- ============================================
+-- CIERRE DE PERIODOS
+-- Historial de cierres y reaperturas de meses. Un mes cerrado no admite
+-- correcciones ni eliminaciones de registros; su estado es el ultimo
+-- movimiento. Las filas no se modifican ni se eliminan
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS cierres_periodo (
+    id SERIAL PRIMARY KEY,
+    anio INTEGER NOT NULL CHECK (anio BETWEEN 2000 AND 2100),
+    mes INTEGER NOT NULL CHECK (mes BETWEEN 1 AND 12),
+    accion VARCHAR(20) NOT NULL CHECK (accion IN ('cierre', 'reapertura')),
+    usuario_id INTEGER NOT NULL REFERENCES usuarios(id),
+    -- Observacion del cierre u obligatorio motivo de la reapertura
+    motivo TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT reapertura_con_motivo CHECK (accion <> 'reapertura' OR motivo IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cierres_periodo_mes ON cierres_periodo(anio, mes, id DESC);
+
+COMMENT ON TABLE cierres_periodo IS 'Historial de cierres y reaperturas de meses; congela los registros';
@@ -0,0 +1,49 @@
+-- ============================================
+-- JUSTIFICACIONES
+-- Justificaciones de retrasos, salidas anticipadas y ausencias, adjuntas a
+-- un registro o a una fecha (y opcionalmente un turno). El jefe de carrera
+-- las aprueba o rechaza; al aprobarlas, los registros que cubren pasan a la
+-- clasificacion 'justificada' conservando sus minutos de retraso
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS justificaciones (
+    id SERIAL PRIMARY KEY,
+    docente_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    registro_id INTEGER REFERENCES registros(id) ON DELETE SET NULL,
+    -- Dia en que empieza la ocurrencia del turno, tambien si se adjunta a un registro
+    fecha DATE NOT NULL,
+    -- NULL sin registro: cubre todos los turnos del dia
+    turno_id INTEGER REFERENCES turnos(id) ON DELETE SET NULL,
+    categoria VARCHAR(20) NOT NULL
+        CHECK (categoria IN ('salud', 'familiar', 'tramite_oficial', 'transporte', 'fuerza_mayor', 'otro')),
+    descripcion TEXT NOT NULL,
+    -- Documento de respaldo en JUSTIFICACIONES_DIR
+    documento_nombre VARCHAR(255),
+    documento_tipo VARCHAR(50),
+    documento_tamano INTEGER,
+    documento_archivo VARCHAR(100),
+    estado VARCHAR(20) NOT NULL DEFAULT 'pendiente' CHECK (estado IN ('pendiente', 'aprobada', 'rechazada')),
+    creado_por INTEGER NOT NULL REFERENCES usuarios(id),
+    revisado_por INTEGER REFERENCES usuarios(id),
+    revisado_at TIMESTAMP WITH TIME ZONE,
+    observacion_revision TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_justificaciones_modtime ON justificaciones;
+CREATE TRIGGER update_justificaciones_modtime
+    BEFORE UPDATE ON justificaciones
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_updated_at_column();
+
+CREATE INDEX IF NOT EXISTS idx_justificaciones_docente_fecha ON justificaciones(docente_id, fecha);
+CREATE INDEX IF NOT EXISTS idx_justificaciones_pendientes ON justificaciones(fecha) WHERE estado = 'pendiente';
+
+-- Nueva clasificacion de registros
+ALTER TABLE registros DROP CONSTRAINT IF EXISTS registro_clasificacion_valida;
+ALTER TABLE registros ADD CONSTRAINT registro_clasificacion_valida
+    CHECK (clasificacion IN ('puntual', 'tarde', 'falta', 'salida_anticipada', 'justificada'));
+
+COMMENT ON TABLE justificaciones IS 'Justificaciones de retrasos y ausencias con revision del jefe de carrera';
@@ -0,0 +1,31 @@
+-- ============================================
+-- LICENCIAS
+-- Ausencias planificadas de los docentes (enfermedad, vacaciones o comision
+-- oficial) entre dos fechas inclusive. Durante la licencia el reporte de
+-- asistencia no califica las sesiones y el operador recibe una advertencia
+-- si registra el ingreso del docente
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS licencias (
+    id SERIAL PRIMARY KEY,
+    docente_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    tipo VARCHAR(20) NOT NULL CHECK (tipo IN ('enfermedad', 'vacaciones', 'comision')),
+    fecha_inicio DATE NOT NULL,
+    fecha_fin DATE NOT NULL,
+    descripcion TEXT,
+    creado_por INTEGER NOT NULL REFERENCES usuarios(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT licencia_fechas_coherentes CHECK (fecha_fin >= fecha_inicio)
+);
+
+DROP TRIGGER IF EXISTS update_licencias_modtime ON licencias;
+CREATE TRIGGER update_licencias_modtime
+    BEFORE UPDATE ON licencias
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_updated_at_column();
+
+CREATE INDEX IF NOT EXISTS idx_licencias_docente_fechas ON licencias(docente_id, fecha_inicio, fecha_fin);
+
+COMMENT ON TABLE licencias IS 'Licencias de docentes: enfermedad, vacaciones o comision oficial';
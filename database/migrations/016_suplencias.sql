@@ -0,0 +1,38 @@
+-- ============================================
+-- SUPLENCIAS
+-- El jefe de carrera asigna un docente suplente a la clase de otro (titular)
+-- en una fecha, un turno y un aula. Los registros del suplente en esa clase
+-- se vinculan a la suplencia: el reporte de asistencia le acredita las horas
+-- y cuenta al titular como reemplazado en lugar de ausente
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS suplencias (
+    id SERIAL PRIMARY KEY,
+    fecha DATE NOT NULL,
+    turno_id INTEGER NOT NULL REFERENCES turnos(id),
+    aula_codigo VARCHAR(50) NOT NULL,
+    titular_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    suplente_id INTEGER NOT NULL REFERENCES docentes(id) ON DELETE CASCADE,
+    motivo TEXT,
+    asignado_por INTEGER NOT NULL REFERENCES usuarios(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT suplencia_docentes_distintos CHECK (titular_id <> suplente_id)
+);
+
+DROP TRIGGER IF EXISTS update_suplencias_modtime ON suplencias;
+CREATE TRIGGER update_suplencias_modtime
+    BEFORE UPDATE ON suplencias
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_updated_at_column();
+
+-- Un suplente cubre una sola clase por turno, y cada clase tiene un solo suplente
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suplencias_suplente_turno ON suplencias(suplente_id, fecha, turno_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suplencias_clase ON suplencias(titular_id, fecha, turno_id, aula_codigo);
+
+-- Registros del suplente vinculados a la suplencia
+ALTER TABLE registros ADD COLUMN IF NOT EXISTS suplencia_id INTEGER REFERENCES suplencias(id);
+CREATE INDEX IF NOT EXISTS idx_registros_suplencia ON registros(suplencia_id) WHERE suplencia_id IS NOT NULL;
+
+COMMENT ON TABLE suplencias IS 'Suplencias: docente que dicta la clase de otro en una fecha, turno y aula';
@@ -0,0 +1,22 @@
+-- ============================================
+-- CAMBIO DE CONTRASEÑA
+-- must_change_password obliga al usuario a cambiar una contraseña que no
+-- eligió él (generada al crear su cuenta o asignada por un administrador)
+-- antes de usar el resto del sistema. password_historial guarda los hashes
+-- recientes de cada usuario para impedir que reutilice contraseñas
+-- ============================================
+SET client_encoding = 'UTF8';
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS password_historial (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    password VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_historial_usuario ON password_historial(usuario_id, created_at DESC);
+
+COMMENT ON COLUMN usuarios.must_change_password IS 'El usuario solo puede usar POST /me/password hasta cambiar su contraseña';
+COMMENT ON TABLE password_historial IS 'Hashes bcrypt de las últimas contraseñas de cada usuario, para impedir su reutilización';
@@ -0,0 +1,21 @@
+-- ============================================
+-- RESTABLECIMIENTO DE CONTRASEÑA
+-- POST /password/olvido envía al correo del usuario un token de un solo uso
+-- con vencimiento; POST /password/restablecer lo consume. Solo se guarda el
+-- hash SHA-256 del token: quien lea la tabla no puede usar los pendientes
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS restablecimientos_password (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expira_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    usado_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_restablecimientos_password_usuario ON restablecimientos_password(usuario_id) WHERE usado_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_restablecimientos_password_expira ON restablecimientos_password(expira_at);
+
+COMMENT ON TABLE restablecimientos_password IS 'Tokens de restablecimiento de contraseña (hash SHA-256), de un solo uso y con vencimiento';
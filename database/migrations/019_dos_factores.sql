@@ -0,0 +1,55 @@
+-- ============================================
+-- AUTENTICACION EN DOS PASOS (TOTP, RFC 6238)
+-- Cada usuario puede activar un segundo factor con una aplicacion
+-- autenticadora. El secreto se guarda cifrado con TOTP_KEY y los codigos de
+-- recuperacion solo como hash SHA-256. El administrador puede exigir el
+-- segundo factor por rol
+-- ============================================
+SET client_encoding = 'UTF8';
+
+CREATE TABLE IF NOT EXISTS dos_factores (
+    usuario_id INTEGER PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    secreto_cifrado BYTEA NOT NULL,
+    clave_id VARCHAR(16) NOT NULL,
+    activo BOOLEAN NOT NULL DEFAULT FALSE,
+    ultimo_paso BIGINT NOT NULL DEFAULT 0,
+    activado_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_dos_factores_modtime ON dos_factores;
+CREATE TRIGGER update_dos_factores_modtime
+    BEFORE UPDATE ON dos_factores
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS codigos_recuperacion (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    codigo_hash CHAR(64) NOT NULL,
+    usado_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT codigo_recuperacion_unico UNIQUE (usuario_id, codigo_hash)
+);
+
+CREATE TABLE IF NOT EXISTS politicas_dos_factores (
+    rol VARCHAR(50) PRIMARY KEY CHECK (rol IN ('administrador', 'jefe_carrera', 'bibliotecario', 'becario', 'docente')),
+    requerido BOOLEAN NOT NULL DEFAULT FALSE,
+    actualizado_por INTEGER REFERENCES usuarios(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_politicas_dos_factores_modtime ON politicas_dos_factores;
+CREATE TRIGGER update_politicas_dos_factores_modtime
+    BEFORE UPDATE ON politicas_dos_factores
+    FOR EACH ROW
+    EXECUTE PROCEDURE update_updated_at_column();
+
+INSERT INTO politicas_dos_factores (rol) VALUES
+    ('administrador'), ('jefe_carrera'), ('bibliotecario'), ('becario'), ('docente')
+ON CONFLICT (rol) DO NOTHING;
+
+COMMENT ON TABLE dos_factores IS 'Secreto TOTP cifrado de cada usuario; activo = enrolamiento confirmado';
+COMMENT ON TABLE codigos_recuperacion IS 'Codigos de un solo uso (hash SHA-256) para ingresar sin la aplicacion autenticadora';
+COMMENT ON TABLE politicas_dos_factores IS 'Roles que exigen autenticacion en dos pasos';
@@ -0,0 +1,15 @@
+-- ============================================
+-- ORIGEN DE LAS CUENTAS (LOCAL O DIRECTORIO LDAP)
+-- origen indica quién valida la contraseña del usuario: local = hash bcrypt
+-- de usuarios.password; ldap = bind contra el directorio institucional
+-- (AUTH_DRIVER=ldap). Las cuentas creadas al primer ingreso desde el
+-- directorio (LDAP_PROVISION_USERS) nacen con origen ldap
+-- ============================================
+SET client_encoding = 'UTF8';
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS origen VARCHAR(10) NOT NULL DEFAULT 'local';
+
+ALTER TABLE usuarios DROP CONSTRAINT IF EXISTS usuarios_origen_check;
+ALTER TABLE usuarios ADD CONSTRAINT usuarios_origen_check CHECK (origen IN ('local', 'ldap'));
+
+COMMENT ON COLUMN usuarios.origen IS 'local = contraseña propia (bcrypt); ldap = se autentica contra el directorio institucional';
@@ -0,0 +1,11 @@
+-- ============================================
+-- CONSUMO DEL TOKEN DE VERIFICACION FACIAL
+-- El token de POST /reconocimiento/verificar lleva el ID del intento (jti) y
+-- acredita un solo ingreso: verificacion_usada_at se marca al registrarlo y
+-- un segundo uso del mismo token se rechaza
+-- ============================================
+SET client_encoding = 'UTF8';
+
+ALTER TABLE intentos_reconocimiento ADD COLUMN IF NOT EXISTS verificacion_usada_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN intentos_reconocimiento.verificacion_usada_at IS 'Momento en que el token de esta verificación registró un ingreso; NULL si no se usó';
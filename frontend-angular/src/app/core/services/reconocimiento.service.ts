@@ -12,6 +12,30 @@ export interface FaceDescriptor {
   };
 }
 
+export interface RostroDocente {
+  id: number;
+  docente_id: number;
+  clave_id?: string;
+  modelo_version: string;
+  calidad?: number;
+  origen: 'enrolamiento' | 'migracion';
+  requiere_reenrolamiento: boolean;
+  created_at: string;
+}
+
+export interface ConsentimientoBiometrico {
+  id: number;
+  docente_id: number;
+  version_documento: string;
+  fecha_consentimiento: string;
+  recolectado_por: number;
+  recolectado_por_nombre?: string;
+  revocado_at?: string;
+  revocado_por?: number;
+  motivo_revocacion?: string;
+  created_at: string;
+}
+
 export interface DetectarRostroResponse {
   face_count: number;
   descriptor: FaceDescriptor;
@@ -119,8 +143,8 @@ export class ReconocimientoService {
    * Obtiene los descriptores faciales de un docente
    * @param docenteId ID del docente
    */
-  obtenerDescriptoresDocente(docenteId: number): Observable<ApiResponse<{ docente_id: number; count: number; descriptors: string[] }>> {
-    return this.http.get<ApiResponse<{ docente_id: number; count: number; descriptors: string[] }>>(
+  obtenerDescriptoresDocente(docenteId: number): Observable<ApiResponse<{ docente_id: number; count: number; requiere_reenrolamiento: number; modelo_version: string; descriptors: RostroDocente[] }>> {
+    return this.http.get<ApiResponse<{ docente_id: number; count: number; requiere_reenrolamiento: number; modelo_version: string; descriptors: RostroDocente[] }>>(
       `${environment.apiUrl}/docentes/${docenteId}/rostro`
     );
   }
@@ -128,11 +152,11 @@ export class ReconocimientoService {
   /**
    * Elimina un descriptor facial específico
    * @param docenteId ID del docente
-   * @param index Índice del descriptor a eliminar
+   * @param rostroId ID del rostro a eliminar
    */
-  eliminarDescriptorDocente(docenteId: number, index: number): Observable<ApiResponse<any>> {
+  eliminarDescriptorDocente(docenteId: number, rostroId: number): Observable<ApiResponse<any>> {
     return this.http.delete<ApiResponse<any>>(
-      `${environment.apiUrl}/docentes/${docenteId}/rostro/${index}`
+      `${environment.apiUrl}/docentes/${docenteId}/rostro/${rostroId}`
     );
   }
 
@@ -145,4 +169,38 @@ export class ReconocimientoService {
       `${environment.apiUrl}/docentes/${docenteId}/rostro`
     );
   }
+
+  /**
+   * Obtiene el consentimiento biométrico vigente y el historial de un docente
+   * @param docenteId ID del docente
+   */
+  obtenerConsentimiento(docenteId: number): Observable<ApiResponse<{ docente_id: number; vigente: ConsentimientoBiometrico | null; historial: ConsentimientoBiometrico[] }>> {
+    return this.http.get<ApiResponse<{ docente_id: number; vigente: ConsentimientoBiometrico | null; historial: ConsentimientoBiometrico[] }>>(
+      `${environment.apiUrl}/docentes/${docenteId}/consentimiento`
+    );
+  }
+
+  /**
+   * Registra el consentimiento biométrico de un docente (requerido antes de enrolar su rostro)
+   * @param docenteId ID del docente
+   * @param versionDocumento Versión del documento de consentimiento firmado
+   */
+  registrarConsentimiento(docenteId: number, versionDocumento: string): Observable<ApiResponse<ConsentimientoBiometrico>> {
+    return this.http.post<ApiResponse<ConsentimientoBiometrico>>(
+      `${environment.apiUrl}/docentes/${docenteId}/consentimiento`,
+      { version_documento: versionDocumento }
+    );
+  }
+
+  /**
+   * Revoca el consentimiento biométrico; el backend elimina todas las fotos registradas
+   * @param docenteId ID del docente
+   * @param motivo Motivo de la revocación (opcional)
+   */
+  revocarConsentimiento(docenteId: number, motivo = ''): Observable<ApiResponse<any>> {
+    return this.http.delete<ApiResponse<any>>(
+      `${environment.apiUrl}/docentes/${docenteId}/consentimiento`,
+      { body: { motivo } }
+    );
+  }
 }
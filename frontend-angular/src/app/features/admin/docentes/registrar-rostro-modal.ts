@@ -1,7 +1,7 @@
 import { Component, EventEmitter, input, OnDestroy, OnInit, Output, signal } from '@angular/core';
 import { CommonModule } from '@angular/common';
 import { WebcamCaptureComponent } from '../../../shared/components/webcam-capture/webcam-capture.component';
-import { ReconocimientoService } from '../../../core/services/reconocimiento.service';
+import { ConsentimientoBiometrico, ReconocimientoService, RostroDocente } from '../../../core/services/reconocimiento.service';
 import { Docente } from '../../../shared/models/docente.model';
 
 interface FotoCapturada {
@@ -54,6 +54,51 @@ interface FotoCapturada {
             </div>
           }
 
+          <!-- Consentimiento biométrico -->
+          @if (!guardandoRostro() && consentimientoCargado()) {
+            @if (consentimiento(); as vigente) {
+              <div class="mb-4 flex items-center justify-between bg-gray-50 border border-gray-200 rounded-lg px-4 py-2">
+                <p class="text-xs text-gray-600">
+                  Consentimiento vigente: documento {{ vigente.version_documento }},
+                  {{ vigente.fecha_consentimiento | date:'dd/MM/yyyy' }}
+                  @if (vigente.recolectado_por_nombre) { (recolectado por {{ vigente.recolectado_por_nombre }}) }
+                </p>
+                <button
+                  type="button"
+                  (click)="revocarConsentimiento()"
+                  [disabled]="procesandoConsentimiento()"
+                  class="text-xs text-red-600 hover:text-red-800 font-medium disabled:opacity-50"
+                >
+                  Revocar
+                </button>
+              </div>
+            } @else {
+              <div class="mb-6 bg-yellow-50 border border-yellow-200 rounded-lg p-4">
+                <h3 class="text-sm font-semibold text-yellow-800 mb-1">Consentimiento biométrico requerido</h3>
+                <p class="text-xs text-yellow-700 mb-3">
+                  Registre el consentimiento firmado por el docente antes de capturar sus fotos.
+                </p>
+                <div class="flex gap-2">
+                  <input
+                    #versionDocumento
+                    type="text"
+                    maxlength="50"
+                    placeholder="Versión del documento (ej: v1.0)"
+                    class="flex-1 px-3 py-2 border border-gray-300 rounded-lg text-sm focus:outline-none focus:ring-2 focus:ring-blue-500"
+                  />
+                  <button
+                    type="button"
+                    (click)="registrarConsentimiento(versionDocumento.value)"
+                    [disabled]="procesandoConsentimiento()"
+                    class="px-4 py-2 bg-blue-600 text-white text-sm rounded-lg hover:bg-blue-700 disabled:opacity-50"
+                  >
+                    Registrar consentimiento
+                  </button>
+                </div>
+              </div>
+            }
+          }
+
           <!-- Fotos Existentes -->
           @if (!guardandoRostro() && !success()) {
             @if (cargandoDescriptores()) {
@@ -77,7 +122,7 @@ interface FotoCapturada {
                   </button>
                 </div>
                 <div class="grid grid-cols-4 gap-3">
-                  @for (descriptor of descriptoresExistentes(); track $index) {
+                  @for (descriptor of descriptoresExistentes(); track descriptor.id) {
                     <div class="relative bg-white border-2 border-gray-300 rounded-lg p-2 group">
                       <div class="flex items-center justify-center h-16">
                         <svg class="w-10 h-10 text-gray-400" fill="none" stroke="currentColor" viewBox="0 0 24 24">
@@ -87,7 +132,7 @@ interface FotoCapturada {
                       <p class="text-xs text-gray-500 text-center">Foto {{ $index + 1 }}</p>
                       <button
                         type="button"
-                        (click)="eliminarDescriptor($index)"
+                        (click)="eliminarDescriptor(descriptor.id)"
                         [disabled]="eliminandoDescriptor()"
                         class="absolute -top-2 -right-2 bg-red-500 hover:bg-red-600 text-white rounded-full p-1 opacity-0 group-hover:opacity-100 transition-opacity disabled:opacity-50"
                       >
@@ -359,13 +404,19 @@ export class RegistrarRostroModal implements OnInit, OnDestroy {
   selectedFilesPreviews = signal<string[]>([]);
 
   // Descriptores existentes
-  descriptoresExistentes = signal<string[]>([]);
+  descriptoresExistentes = signal<RostroDocente[]>([]);
   cargandoDescriptores = signal(false);
   eliminandoDescriptor = signal(false);
 
+  // Consentimiento biométrico (requerido para enrolar)
+  consentimiento = signal<ConsentimientoBiometrico | null>(null);
+  consentimientoCargado = signal(false);
+  procesandoConsentimiento = signal(false);
+
   constructor(private reconocimientoService: ReconocimientoService) {}
 
   ngOnInit() {
+    this.cargarConsentimiento();
     this.cargarDescriptoresExistentes();
   }
 
@@ -391,6 +442,62 @@ export class RegistrarRostroModal implements OnInit, OnDestroy {
     });
   }
 
+  // === Consentimiento ===
+
+  cargarConsentimiento() {
+    this.reconocimientoService.obtenerConsentimiento(this.docente().id).subscribe({
+      next: (response) => {
+        this.consentimiento.set(response.data?.vigente ?? null);
+        this.consentimientoCargado.set(true);
+      },
+      error: (err) => {
+        console.error('Error al cargar consentimiento:', err);
+      }
+    });
+  }
+
+  registrarConsentimiento(versionDocumento: string) {
+    if (!versionDocumento.trim()) {
+      this.error.set('Indique la versión del documento de consentimiento');
+      return;
+    }
+
+    this.procesandoConsentimiento.set(true);
+    this.error.set('');
+
+    this.reconocimientoService.registrarConsentimiento(this.docente().id, versionDocumento.trim()).subscribe({
+      next: (response) => {
+        this.procesandoConsentimiento.set(false);
+        this.consentimiento.set(response.data ?? null);
+      },
+      error: (err) => {
+        this.procesandoConsentimiento.set(false);
+        this.error.set(err.error?.error || 'Error al registrar consentimiento');
+      }
+    });
+  }
+
+  revocarConsentimiento() {
+    if (!confirm('Al revocar el consentimiento se eliminarán TODAS las fotos registradas del docente. ¿Desea continuar?')) {
+      return;
+    }
+
+    this.procesandoConsentimiento.set(true);
+    this.error.set('');
+
+    this.reconocimientoService.revocarConsentimiento(this.docente().id).subscribe({
+      next: () => {
+        this.procesandoConsentimiento.set(false);
+        this.consentimiento.set(null);
+        this.cargarDescriptoresExistentes();
+      },
+      error: (err) => {
+        this.procesandoConsentimiento.set(false);
+        this.error.set(err.error?.error || 'Error al revocar consentimiento');
+      }
+    });
+  }
+
   // === Webcam Methods ===
 
   abrirWebcam() {
@@ -461,7 +568,7 @@ export class RegistrarRostroModal implements OnInit, OnDestroy {
 
   // === Descriptor Management ===
 
-  eliminarDescriptor(index: number) {
+  eliminarDescriptor(rostroId: number) {
     if (!confirm('¿Está seguro de eliminar esta foto registrada?')) {
       return;
     }
@@ -469,7 +576,7 @@ export class RegistrarRostroModal implements OnInit, OnDestroy {
     this.eliminandoDescriptor.set(true);
     this.error.set('');
 
-    this.reconocimientoService.eliminarDescriptorDocente(this.docente().id, index).subscribe({
+    this.reconocimientoService.eliminarDescriptorDocente(this.docente().id, rostroId).subscribe({
       next: () => {
         this.eliminandoDescriptor.set(false);
         this.success.set('Foto eliminada exitosamente');
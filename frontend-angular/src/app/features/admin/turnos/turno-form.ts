@@ -17,6 +17,17 @@ export class TurnoForm implements OnInit {
 
   turnoForm!: FormGroup;
 
+  readonly diasSemana = [
+    { valor: 1, etiqueta: 'Lu' },
+    { valor: 2, etiqueta: 'Ma' },
+    { valor: 3, etiqueta: 'Mi' },
+    { valor: 4, etiqueta: 'Ju' },
+    { valor: 5, etiqueta: 'Vi' },
+    { valor: 6, etiqueta: 'Sá' },
+    { valor: 0, etiqueta: 'Do' }
+  ];
+  private readonly todosLosDias = [0, 1, 2, 3, 4, 5, 6];
+
   get isEditMode(): boolean {
     return this.turno() !== null;
   }
@@ -50,9 +61,15 @@ export class TurnoForm implements OnInit {
     this.turnoForm = this.fb.group({
       nombre: ['', [Validators.required, Validators.minLength(3)]],
       hora_inicio: ['', [Validators.required]],
-      hora_fin: ['', [Validators.required]]
+      hora_fin: ['', [Validators.required]],
+      dias_semana: [this.todosLosDias, [Validators.required]],
+      vigente_desde: [''],
+      vigente_hasta: [''],
+      tolerancia_ingreso_min: [10, [Validators.required, Validators.min(0)]],
+      retraso_maximo_min: [30, [Validators.required, Validators.min(0)]],
+      salida_anticipada_min: [10, [Validators.required, Validators.min(0)]]
     }, {
-      validators: this.timeRangeValidator
+      validators: [this.timeRangeValidator, this.margenesValidator]
     });
   }
 
@@ -64,11 +81,32 @@ export class TurnoForm implements OnInit {
       return null;
     }
 
-    // Comparar las horas
-    if (horaInicio >= horaFin) {
+    // Si la hora de fin es anterior, el turno cruza la medianoche; solo se rechazan horas iguales
+    if (horaInicio === horaFin) {
       return { timeRangeInvalid: true };
     }
 
+    const desde = group.get('vigente_desde')?.value;
+    const hasta = group.get('vigente_hasta')?.value;
+    if (desde && hasta && hasta < desde) {
+      return { vigenciaInvalida: true };
+    }
+
+    return null;
+  }
+
+  private margenesValidator(group: FormGroup): { [key: string]: boolean } | null {
+    const tolerancia = group.get('tolerancia_ingreso_min')?.value;
+    const retrasoMaximo = group.get('retraso_maximo_min')?.value;
+
+    if (tolerancia === null || retrasoMaximo === null) {
+      return null;
+    }
+
+    if (retrasoMaximo < tolerancia) {
+      return { margenesInvalidos: true };
+    }
+
     return null;
   }
 
@@ -76,7 +114,13 @@ export class TurnoForm implements OnInit {
     this.turnoForm.reset({
       nombre: '',
       hora_inicio: '',
-      hora_fin: ''
+      hora_fin: '',
+      dias_semana: this.todosLosDias,
+      vigente_desde: '',
+      vigente_hasta: '',
+      tolerancia_ingreso_min: 10,
+      retraso_maximo_min: 30,
+      salida_anticipada_min: 10
     });
   }
 
@@ -88,16 +132,31 @@ export class TurnoForm implements OnInit {
     this.turnoForm.patchValue({
       nombre: turno.nombre,
       hora_inicio: horaInicio,
-      hora_fin: horaFin
+      hora_fin: horaFin,
+      dias_semana: turno.dias_semana ?? this.todosLosDias,
+      vigente_desde: turno.vigente_desde ?? '',
+      vigente_hasta: turno.vigente_hasta ?? '',
+      tolerancia_ingreso_min: turno.tolerancia_ingreso_min,
+      retraso_maximo_min: turno.retraso_maximo_min,
+      salida_anticipada_min: turno.salida_anticipada_min
     });
   }
 
-  private extractTime(dateTimeString: string): string {
-    if (!dateTimeString) return '';
-    const date = new Date(dateTimeString);
-    const hours = date.getUTCHours().toString().padStart(2, '0');
-    const minutes = date.getUTCMinutes().toString().padStart(2, '0');
-    return `${hours}:${minutes}`;
+  private extractTime(hora: string): string {
+    // El backend envía HH:MM:SS; los inputs tipo time usan HH:MM
+    return hora ? hora.substring(0, 5) : '';
+  }
+
+  isDiaSeleccionado(dia: number): boolean {
+    return (this.turnoForm.get('dias_semana')?.value ?? []).includes(dia);
+  }
+
+  toggleDia(dia: number): void {
+    const control = this.turnoForm.get('dias_semana');
+    const dias: number[] = control?.value ?? [];
+    const nuevos = dias.includes(dia) ? dias.filter(d => d !== dia) : [...dias, dia];
+    control?.setValue(nuevos.length > 0 ? nuevos : null);
+    control?.markAsTouched();
   }
 
   onSubmit(): void {
@@ -116,14 +175,26 @@ export class TurnoForm implements OnInit {
       const updateData: TurnoUpdate = {
         nombre: formValue.nombre,
         hora_inicio: horaInicio,
-        hora_fin: horaFin
+        hora_fin: horaFin,
+        dias_semana: formValue.dias_semana,
+        vigente_desde: formValue.vigente_desde || null,
+        vigente_hasta: formValue.vigente_hasta || null,
+        tolerancia_ingreso_min: formValue.tolerancia_ingreso_min,
+        retraso_maximo_min: formValue.retraso_maximo_min,
+        salida_anticipada_min: formValue.salida_anticipada_min
       };
       this.onSave.emit({ id: this.turno()!.id, data: updateData });
     } else {
       const createData: TurnoCreate = {
         nombre: formValue.nombre,
         hora_inicio: horaInicio,
-        hora_fin: horaFin
+        hora_fin: horaFin,
+        dias_semana: formValue.dias_semana,
+        vigente_desde: formValue.vigente_desde || null,
+        vigente_hasta: formValue.vigente_hasta || null,
+        tolerancia_ingreso_min: formValue.tolerancia_ingreso_min,
+        retraso_maximo_min: formValue.retraso_maximo_min,
+        salida_anticipada_min: formValue.salida_anticipada_min
       };
       this.onSave.emit(createData);
     }
@@ -155,6 +226,9 @@ export class TurnoForm implements OnInit {
     if (control?.hasError('required')) {
       return 'Este campo es requerido';
     }
+    if (control?.hasError('min')) {
+      return 'No puede ser negativo';
+    }
     if (control?.hasError('minlength')) {
       return 'Debe tener al menos 3 caracteres';
     }
@@ -166,6 +240,19 @@ export class TurnoForm implements OnInit {
              (this.turnoForm.get('hora_inicio')?.touched || this.turnoForm.get('hora_fin')?.touched));
   }
 
+  hasVigenciaError(): boolean {
+    return this.turnoForm.hasError('vigenciaInvalida');
+  }
+
+  hasDiasError(): boolean {
+    return this.hasError('dias_semana');
+  }
+
+  hasMargenesError(): boolean {
+    return !!(this.turnoForm.hasError('margenesInvalidos') &&
+             (this.turnoForm.get('tolerancia_ingreso_min')?.touched || this.turnoForm.get('retraso_maximo_min')?.touched));
+  }
+
   private markFormGroupTouched(formGroup: FormGroup): void {
     Object.keys(formGroup.controls).forEach(key => {
       const control = formGroup.get(key);
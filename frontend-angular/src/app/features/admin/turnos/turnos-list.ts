@@ -207,15 +207,8 @@ export class TurnosList implements OnInit {
     this.showAlert.set(false);
   }
 
-  formatTime(dateTimeString: string): string {
-    if (!dateTimeString) return '';
-    const date = new Date(dateTimeString);
-    // Usar UTC para evitar conversión de zona horaria
-    return date.toLocaleTimeString('es-ES', {
-      hour: '2-digit',
-      minute: '2-digit',
-      hour12: false,
-      timeZone: 'UTC'
-    });
+  formatTime(hora: string): string {
+    // El backend envía HH:MM:SS
+    return hora ? hora.substring(0, 5) : '';
   }
 }
@@ -87,7 +87,7 @@ export class RegistroSalida implements OnInit {
     // Crear request con los datos del registro activo
     const request = {
       ci: Number(registroActivo.docente_ci),
-      turno_id: 1, // Usar turno default ya que LlaveActual no incluye turno_id
+      // Sin turno_id: el backend usa el turno del ingreso abierto del docente
       llave_id: registroActivo.llave_id
     };
 
@@ -72,7 +72,7 @@ export class RegistroEntrada implements OnInit, OnDestroy {
     this.registroForm = this.fb.group({
       docente_ci: ['', [Validators.required, Validators.pattern(/^\d+$/)]],
       llave_search: ['', Validators.required],
-      turno_id: ['', Validators.required]
+      turno_id: ['']
     });
   }
 
@@ -335,7 +335,8 @@ export class RegistroEntrada implements OnInit, OnDestroy {
     const request = {
       ci: Number(formValue.docente_ci),
       llave_id: this.llaveEncontrada()!.id,
-      turno_id: Number(formValue.turno_id)
+      // Sin turno seleccionado, el backend detecta el turno y lo informa en turno_seleccion
+      turno_id: formValue.turno_id ? Number(formValue.turno_id) : null
     };
 
     this.registroService.registrarEntrada(request).subscribe({
@@ -343,7 +344,10 @@ export class RegistroEntrada implements OnInit, OnDestroy {
         this.loading.set(false);
 
         // Mostrar animacion de exito
-        this.successAnimationMessage.set(`Llave ${this.llaveEncontrada()!.codigo} entregada a ${this.docenteEncontrado()!.nombre}`);
+        const turno = response?.turno_seleccion?.turno_nombre;
+        this.successAnimationMessage.set(
+          `Llave ${this.llaveEncontrada()!.codigo} entregada a ${this.docenteEncontrado()!.nombre}` + (turno ? ` (turno ${turno})` : '')
+        );
         this.showSuccessAnimation.set(true);
 
         this.registroForm.reset();
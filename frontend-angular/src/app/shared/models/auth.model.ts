@@ -12,12 +12,70 @@ export interface LoginResponse {
     username: string;
     rol: Rol;
     nombre_completo?: string;
+    must_change_password: boolean;
+    enrolar_2fa: boolean;
   };
 }
 
+// Respuesta de /login cuando la cuenta tiene autenticación en dos pasos
+export interface DesafioDosFactoresResponse {
+  requiere_2fa: true;
+  desafio_token: string;
+  expira_en: number;
+}
+
+export interface VerificarDosFactoresRequest {
+  desafio_token: string;
+  codigo: string;
+}
+
 export interface AuthUser {
   id: number;
   username: string;
   rol: Rol;
   nombre_completo?: string;
+  must_change_password?: boolean;
+  enrolar_2fa?: boolean;
+}
+
+export interface CambiarPasswordRequest {
+  current_password: string;
+  new_password: string;
+}
+
+export interface OlvidoPasswordRequest {
+  username: string;
+}
+
+export interface RestablecerPasswordRequest {
+  token: string;
+  new_password: string;
+}
+
+export interface EstadoDosFactores {
+  activo: boolean;
+  requerido: boolean;
+  activado_at?: string;
+  codigos_recuperacion: number;
+}
+
+export interface EnrolamientoDosFactores {
+  secreto: string;
+  otpauth_url: string;
+  qr_code: string;
+}
+
+export interface ActivacionDosFactoresResponse extends LoginResponse {
+  codigos_recuperacion: string[];
+}
+
+export interface CodigoDosFactoresRequest {
+  codigo: string;
+}
+
+export interface PoliticaDosFactores {
+  rol: Rol;
+  requerido: boolean;
+  actualizado_por?: number;
+  updated_at: string;
 }
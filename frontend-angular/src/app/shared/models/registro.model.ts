@@ -1,3 +1,5 @@
+export type ClasificacionRegistro = 'puntual' | 'tarde' | 'falta' | 'salida_anticipada' | 'justificada';
+
 export interface Registro {
   id: number;
   docente_id: number;
@@ -17,10 +19,61 @@ export interface Registro {
   hora_salida?: string;
   minutos_retraso?: number;
   minutos_extra?: number;
+  clasificacion?: ClasificacionRegistro;
+  ingreso_id?: number;  // Solo salidas: ingreso que cierran
+  suplencia_id?: number;  // Suplencia que cubre el docente
   es_excepcional?: boolean;
   observaciones?: string;
   created_at?: string;
   updated_at?: string;
+  // Solo en la respuesta de registrar ingreso/salida
+  turno_seleccion?: SeleccionTurno;
+  // Solo en la respuesta de registrar salida
+  sesion?: Sesion;
+  // Solo en la respuesta de registrar ingreso, si el docente tiene una licencia vigente
+  licencia?: Licencia;
+  advertencias?: string[];
+}
+
+export interface Licencia {
+  id: number;
+  docente_id: number;
+  docente_nombre?: string;
+  docente_ci?: string;
+  tipo: 'enfermedad' | 'vacaciones' | 'comision';
+  fecha_inicio: string;
+  fecha_fin: string;
+  descripcion?: string;
+}
+
+export interface Sesion {
+  ingreso_id: number;
+  salida_id?: number;
+  docente_id: number;
+  docente_nombre: string;
+  docente_ci: string;
+  turno_id: number;
+  turno_nombre: string;
+  llave_id?: number;
+  llave_codigo?: string;
+  aula_codigo?: string;
+  aula_nombre?: string;
+  hora_ingreso: string;
+  hora_salida?: string;
+  duracion_minutos?: number;
+  minutos_retraso: number;
+  minutos_extra: number;
+  clasificacion_ingreso: ClasificacionRegistro;
+  clasificacion_salida?: ClasificacionRegistro;
+  abierta: boolean;
+  suplencia_id?: number;
+}
+
+export interface SeleccionTurno {
+  metodo: 'manual' | 'ingreso_abierto' | 'proximo' | 'en_curso';
+  turno_id: number;
+  turno_nombre: string;
+  detalle: string;
 }
 
 export interface RegistroIngresoRequest {
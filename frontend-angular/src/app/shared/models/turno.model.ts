@@ -2,8 +2,14 @@ export interface Turno {
   id: number;
   nombre: string;
   hora_inicio: string;
-  hora_fin: string;
+  hora_fin: string;  // si no es posterior a hora_inicio, el turno cruza la medianoche
+  dias_semana: number[];  // 0 = domingo ... 6 = sábado
+  vigente_desde: string | null;
+  vigente_hasta: string | null;
   descripcion?: string;
+  tolerancia_ingreso_min: number;
+  retraso_maximo_min: number;
+  salida_anticipada_min: number;
   activo: boolean;
   created_at: string;
   updated_at: string;
@@ -13,11 +19,23 @@ export interface TurnoCreate {
   nombre: string;
   hora_inicio: string;
   hora_fin: string;
+  dias_semana?: number[];
+  vigente_desde?: string | null;
+  vigente_hasta?: string | null;
+  tolerancia_ingreso_min?: number;
+  retraso_maximo_min?: number;
+  salida_anticipada_min?: number;
 }
 
 export interface TurnoUpdate {
   nombre?: string;
   hora_inicio?: string;
   hora_fin?: string;
+  dias_semana?: number[];
+  vigente_desde?: string | null;
+  vigente_hasta?: string | null;
+  tolerancia_ingreso_min?: number;
+  retraso_maximo_min?: number;
+  salida_anticipada_min?: number;
   activo?: boolean;
 }
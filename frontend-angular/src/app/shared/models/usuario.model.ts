@@ -1,5 +1,7 @@
 export type Rol = 'administrador' | 'jefe_carrera' | 'bibliotecario' | 'becario' | 'docente';
 
+export type OrigenUsuario = 'local' | 'ldap';
+
 export interface Usuario {
   id: number;
   username: string;
@@ -8,6 +10,7 @@ export interface Usuario {
   nombre_completo?: string;
   email?: string;
   activo: boolean;
+  origen: OrigenUsuario;
   created_at: string;
   updated_at: string;
 }
@@ -19,6 +22,7 @@ export interface UsuarioCreate {
   docente_id?: number;
   nombre_completo?: string;
   email?: string;
+  origen?: OrigenUsuario;
 }
 
 export interface UsuarioUpdate {
@@ -29,6 +33,7 @@ export interface UsuarioUpdate {
   nombre_completo?: string;
   email?: string;
   activo?: boolean;
+  origen?: OrigenUsuario;
 }
 
 export interface ChangePasswordRequest {